@@ -0,0 +1,78 @@
+// Command live-replay renders a live session recording as a timeline.
+//
+// Usage:
+//
+//	go run ./cmd/live-replay [-v] <recording-dir>
+//	go run ./cmd/live-replay -replay [-speed 2] [-v] <recording-dir>
+//
+// By default the whole timeline is printed at once. With -replay, entries
+// are printed at the pace they happened, optionally sped up; play the
+// bundle's audio.wav alongside to hear the call (user left, agent right).
+//
+// Recordings are written by setting live.SessionConfig.Recorder to a
+// recording.Recorder.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/live/recording"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "include audio chunks, text deltas and debug messages")
+	replay := flag.Bool("replay", false, "print entries at the pace they happened")
+	speed := flag.Float64("speed", 1, "replay speed multiplier")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: live-replay [-replay] [-speed N] [-v] <recording-dir>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || *speed <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	bundle, err := recording.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open recording: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := recording.TimelineOptions{Verbose: *verbose}
+	if !*replay {
+		if err := bundle.WriteTimeline(os.Stdout, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write timeline: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if path := bundle.AudioPath(); path != "" {
+		fmt.Printf("Audio: %s\n", path)
+	}
+	runReplay(ctx, bundle.Timeline(opts), *speed)
+}
+
+// runReplay prints entries at their recorded times, scaled by speed.
+func runReplay(ctx context.Context, entries []recording.Entry, speed float64) {
+	start := time.Now()
+	for _, entry := range entries {
+		at := time.Duration(float64(entry.TimeMs)/speed) * time.Millisecond
+		select {
+		case <-time.After(time.Until(start.Add(at))):
+		case <-ctx.Done():
+			return
+		}
+		fmt.Println(recording.FormatEntry(entry))
+	}
+}
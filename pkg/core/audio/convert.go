@@ -0,0 +1,115 @@
+package audio
+
+import "fmt"
+
+// Converter converts a stream of raw audio from one format to another:
+// decoding, channel mixing, resampling and encoding. Chunks may split
+// frames; the remainder is kept for the next call. A Converter is not
+// safe for concurrent use.
+type Converter struct {
+	from, to  Format
+	resampler *Resampler
+	pending   []byte
+}
+
+// NewConverter creates a converter between two complete formats.
+// Converting between channel counts is supported to and from mono.
+func NewConverter(from, to Format) (*Converter, error) {
+	if err := from.Validate(); err != nil {
+		return nil, err
+	}
+	if err := to.Validate(); err != nil {
+		return nil, err
+	}
+	if from.Channels != to.Channels && from.Channels != 1 && to.Channels != 1 {
+		return nil, fmt.Errorf("audio: cannot convert %d channels to %d", from.Channels, to.Channels)
+	}
+
+	c := &Converter{from: from, to: to}
+	if from.SampleRate != to.SampleRate {
+		c.resampler = NewResampler(from.SampleRate, to.SampleRate, to.Channels)
+	}
+	return c, nil
+}
+
+// From returns the input format.
+func (c *Converter) From() Format { return c.from }
+
+// To returns the output format.
+func (c *Converter) To() Format { return c.to }
+
+// Convert converts the next chunk of audio. Between identical formats the
+// audio passes through, but still in whole frames.
+func (c *Converter) Convert(data []byte) ([]byte, error) {
+	if len(c.pending) > 0 {
+		data = append(c.pending, data...)
+		c.pending = nil
+	}
+	frameSize := c.from.FrameSize()
+	if rem := len(data) % frameSize; rem != 0 {
+		c.pending = append([]byte(nil), data[len(data)-rem:]...)
+		data = data[:len(data)-rem]
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if c.from == c.to {
+		return data, nil
+	}
+
+	samples, err := Decode(data, c.from.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.from.Channels == c.to.Channels:
+	case c.to.Channels == 1:
+		samples = Downmix(samples, c.from.Channels)
+	default:
+		samples = Upmix(samples, c.to.Channels)
+	}
+	if c.resampler != nil {
+		samples = c.resampler.Process(samples)
+	}
+	return Encode(samples, c.to.Encoding)
+}
+
+// Flush returns the audio still held by the resampler at the end of a
+// stream and resets the converter. Buffered partial frames are dropped.
+func (c *Converter) Flush() ([]byte, error) {
+	c.pending = nil
+	if c.resampler == nil {
+		return nil, nil
+	}
+	samples := c.resampler.Flush()
+	if len(samples) == 0 {
+		return nil, nil
+	}
+	return Encode(samples, c.to.Encoding)
+}
+
+// Reset discards buffered partial frames and resampler state,
+// for example when playback is flushed.
+func (c *Converter) Reset() {
+	c.pending = nil
+	if c.resampler != nil {
+		c.resampler.Reset()
+	}
+}
+
+// Convert converts a complete buffer of audio between formats.
+func Convert(data []byte, from, to Format) ([]byte, error) {
+	c, err := NewConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.Convert(data)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := c.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append(out, tail...), nil
+}
@@ -0,0 +1,167 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func sine(n, sampleRate int, freq float64) []float32 {
+	out := make([]float32, n)
+	for i := range out {
+		out[i] = float32(0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+	}
+	return out
+}
+
+func TestResampler_Length(t *testing.T) {
+	tests := []struct{ from, to int }{
+		{48000, 16000},
+		{8000, 24000},
+		{44100, 24000},
+	}
+	for _, tt := range tests {
+		in := sine(tt.from, tt.from, 440) // one second
+		out := Resample(in, tt.from, tt.to, 1)
+		if diff := len(out) - tt.to; diff < -1 || diff > 1 {
+			t.Errorf("%d -> %d: got %d samples, want about %d", tt.from, tt.to, len(out), tt.to)
+		}
+	}
+}
+
+func TestResampler_ChunkedMatchesWhole(t *testing.T) {
+	in := sine(4800, 48000, 300)
+	whole := Resample(in, 48000, 16000, 1)
+
+	r := NewResampler(48000, 16000, 1)
+	var chunked []float32
+	for i := 0; i < len(in); i += 317 {
+		chunked = append(chunked, r.Process(in[i:min(i+317, len(in))])...)
+	}
+	chunked = append(chunked, r.Flush()...)
+
+	if len(chunked) != len(whole) {
+		t.Fatalf("chunked output has %d samples, whole has %d", len(chunked), len(whole))
+	}
+	for i := range whole {
+		if math.Abs(float64(chunked[i]-whole[i])) > 1e-4 {
+			t.Fatalf("sample %d differs: %f vs %f", i, chunked[i], whole[i])
+		}
+	}
+}
+
+func TestResampler_PreservesSignal(t *testing.T) {
+	out := Resample(sine(8000, 8000, 200), 8000, 24000, 1)
+	want := sine(len(out), 24000, 200)
+	// The last frames hold the final input sample
+	for i := range out[:len(out)-3] {
+		if math.Abs(float64(out[i]-want[i])) > 0.01 {
+			t.Fatalf("sample %d = %f, want %f", i, out[i], want[i])
+		}
+	}
+}
+
+func TestConverter_BrowserFloatToPCM16(t *testing.T) {
+	from := Format{Encoding: EncodingFloat32, SampleRate: 48000, Channels: 2}
+	to := PCM16(16000, 1)
+	c, err := NewConverter(from, to)
+	if err != nil {
+		t.Fatalf("NewConverter() error = %v", err)
+	}
+
+	// Left and right channels average to a constant 0.25
+	stereo := make([]float32, 2*4800)
+	for i := 0; i < len(stereo); i += 2 {
+		stereo[i], stereo[i+1] = 0.5, 0
+	}
+	data := EncodeFloat32(stereo)
+
+	// Split mid-frame to check partial frame handling
+	var out []byte
+	for _, chunk := range [][]byte{data[:1001], data[1001:5003], data[5003:]} {
+		b, err := c.Convert(chunk)
+		if err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		out = append(out, b...)
+	}
+
+	samples := DecodePCM16(out)
+	if n := len(samples); n < 1599 || n > 1600 {
+		t.Errorf("got %d samples, want about 1600", n)
+	}
+	for i, s := range samples {
+		if s != 8192 {
+			t.Fatalf("sample %d = %d, want 8192", i, s)
+		}
+	}
+}
+
+func TestConverter_PassthroughKeepsFrames(t *testing.T) {
+	c, err := NewConverter(PCM16(24000, 1), PCM16(24000, 1))
+	if err != nil {
+		t.Fatalf("NewConverter() error = %v", err)
+	}
+	data := EncodePCM16([]int16{1, -2, 300, -400, 5000})
+
+	var out []byte
+	for _, chunk := range [][]byte{data[:3], data[3:4], data[4:9], data[9:]} {
+		b, err := c.Convert(chunk)
+		if err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		if len(b)%2 != 0 {
+			t.Fatalf("Convert() returned %d bytes, want whole samples", len(b))
+		}
+		out = append(out, b...)
+	}
+	if string(out) != string(data) {
+		t.Errorf("got %v, want %v", out, data)
+	}
+}
+
+func TestConvert_MulawToPCM16(t *testing.T) {
+	pcm := EncodePCM16(Float32ToInt16(sine(800, 8000, 400)))
+	ulaw, err := Convert(pcm, PCM16(8000, 1), Format{Encoding: EncodingMulaw, SampleRate: 8000, Channels: 1})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(ulaw) != 800 {
+		t.Fatalf("got %d μ-law bytes, want 800", len(ulaw))
+	}
+
+	back, err := Convert(ulaw, Format{Encoding: EncodingMulaw, SampleRate: 8000, Channels: 1}, PCM16(24000, 1))
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if n := len(back) / 2; n < 2397 || n > 2400 {
+		t.Errorf("got %d samples at 24kHz, want about 2400", n)
+	}
+}
+
+func TestNewConverter_Errors(t *testing.T) {
+	if _, err := NewConverter(Format{Encoding: "opus", SampleRate: 48000, Channels: 1}, PCM16(16000, 1)); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+	if _, err := NewConverter(PCM16(16000, 2), PCM16(16000, 6)); err == nil {
+		t.Error("expected error for stereo to 5.1")
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	tests := map[string]Encoding{
+		"pcm":       EncodingPCM16,
+		"linear16":  EncodingPCM16,
+		"FLOAT32":   EncodingFloat32,
+		"ulaw":      EncodingMulaw,
+		"pcm_mulaw": EncodingMulaw,
+		"pcma":      EncodingAlaw,
+	}
+	for name, want := range tests {
+		if got, err := ParseEncoding(name); err != nil || got != want {
+			t.Errorf("ParseEncoding(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseEncoding("mp3"); err == nil {
+		t.Error("expected error for mp3")
+	}
+}
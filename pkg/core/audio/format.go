@@ -0,0 +1,127 @@
+// Package audio converts between the audio formats used by voice clients,
+// speech providers and live sessions: WAV parsing, sample-rate conversion,
+// channel down-mixing, 16-bit and float PCM, and the G.711 μ-law and A-law
+// telephony codecs.
+//
+// Samples are handled internally as interleaved float32 values in [-1, 1].
+package audio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encoding identifies how samples are stored. The names match the
+// encodings used by Cartesia and most streaming speech APIs.
+type Encoding string
+
+const (
+	// EncodingPCM16 is signed 16-bit little-endian PCM.
+	EncodingPCM16 Encoding = "pcm_s16le"
+	// EncodingFloat32 is 32-bit little-endian IEEE float PCM.
+	EncodingFloat32 Encoding = "pcm_f32le"
+	// EncodingMulaw is 8-bit G.711 μ-law, used by North American telephony.
+	EncodingMulaw Encoding = "pcm_mulaw"
+	// EncodingAlaw is 8-bit G.711 A-law, used by European telephony.
+	EncodingAlaw Encoding = "pcm_alaw"
+)
+
+// ParseEncoding returns the encoding for a name or common alias,
+// such as "pcm", "linear16", "float32", "ulaw" or "pcma".
+func ParseEncoding(name string) (Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "pcm_s16le", "pcm", "pcm16", "s16le", "linear16", "l16", "raw":
+		return EncodingPCM16, nil
+	case "pcm_f32le", "f32le", "float32", "float":
+		return EncodingFloat32, nil
+	case "pcm_mulaw", "mulaw", "ulaw", "mu-law", "pcmu", "g711_ulaw":
+		return EncodingMulaw, nil
+	case "pcm_alaw", "alaw", "a-law", "pcma", "g711_alaw":
+		return EncodingAlaw, nil
+	default:
+		return "", fmt.Errorf("audio: unknown encoding %q", name)
+	}
+}
+
+// BytesPerSample returns the size of one sample, or 0 for an unknown encoding.
+func (e Encoding) BytesPerSample() int {
+	switch e {
+	case EncodingPCM16:
+		return 2
+	case EncodingFloat32:
+		return 4
+	case EncodingMulaw, EncodingAlaw:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Format describes a stream of raw audio.
+type Format struct {
+	Encoding   Encoding `json:"encoding,omitempty"`
+	SampleRate int      `json:"sample_rate,omitempty"`
+	Channels   int      `json:"channels,omitempty"`
+}
+
+// PCM16 returns a 16-bit PCM format.
+func PCM16(sampleRate, channels int) Format {
+	return Format{Encoding: EncodingPCM16, SampleRate: sampleRate, Channels: channels}
+}
+
+// IsZero reports whether no field of the format is set.
+func (f Format) IsZero() bool {
+	return f == Format{}
+}
+
+// WithDefaults fills in the zero fields of f from def.
+func (f Format) WithDefaults(def Format) Format {
+	if f.Encoding == "" {
+		f.Encoding = def.Encoding
+	}
+	if f.SampleRate == 0 {
+		f.SampleRate = def.SampleRate
+	}
+	if f.Channels == 0 {
+		f.Channels = def.Channels
+	}
+	return f
+}
+
+// Validate checks that the format is complete and supported.
+func (f Format) Validate() error {
+	if f.Encoding.BytesPerSample() == 0 {
+		return fmt.Errorf("audio: unsupported encoding %q", f.Encoding)
+	}
+	if f.SampleRate <= 0 {
+		return fmt.Errorf("audio: invalid sample rate %d", f.SampleRate)
+	}
+	if f.Channels <= 0 {
+		return fmt.Errorf("audio: invalid channel count %d", f.Channels)
+	}
+	return nil
+}
+
+// FrameSize returns the size in bytes of one sample for every channel.
+func (f Format) FrameSize() int {
+	return f.Encoding.BytesPerSample() * f.Channels
+}
+
+// BytesForDurationMs returns the number of bytes in durationMs of audio.
+func (f Format) BytesForDurationMs(durationMs int) int {
+	return f.SampleRate * durationMs / 1000 * f.FrameSize()
+}
+
+// DurationMs returns the duration in milliseconds of n bytes of audio.
+func (f Format) DurationMs(n int) int {
+	bytesPerSecond := f.SampleRate * f.FrameSize()
+	if bytesPerSecond == 0 {
+		return 0
+	}
+	return n * 1000 / bytesPerSecond
+}
+
+// String returns a description such as "pcm_s16le/24000Hz/1ch".
+func (f Format) String() string {
+	return fmt.Sprintf("%s/%dHz/%dch", f.Encoding, f.SampleRate, f.Channels)
+}
@@ -0,0 +1,112 @@
+package audio
+
+// G.711 codecs, following the reference implementation published by
+// Sun Microsystems (g711.c).
+
+const (
+	mulawBias = 0x84
+	mulawClip = 8159
+)
+
+var (
+	mulawSegmentEnds = [8]int{0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF, 0x1FFF}
+	alawSegmentEnds  = [8]int{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+)
+
+// segment returns the index of the first segment end at or above v, or 8.
+func segment(v int, ends *[8]int) int {
+	for i, end := range ends {
+		if v <= end {
+			return i
+		}
+	}
+	return 8
+}
+
+// MulawEncode compresses a 16-bit sample to μ-law.
+func MulawEncode(sample int16) byte {
+	v := int(sample) >> 2
+	mask := 0xFF
+	if v < 0 {
+		v = -v
+		mask = 0x7F
+	}
+	if v > mulawClip {
+		v = mulawClip
+	}
+	v += mulawBias >> 2
+
+	seg := segment(v, &mulawSegmentEnds)
+	if seg >= 8 {
+		return byte(0x7F ^ mask)
+	}
+	return byte((seg<<4 | (v>>(seg+1))&0x0F) ^ mask)
+}
+
+// MulawDecode expands a μ-law byte to a 16-bit sample.
+func MulawDecode(b byte) int16 {
+	u := ^b
+	t := (int(u&0x0F) << 3) + mulawBias
+	t <<= (u & 0x70) >> 4
+	if u&0x80 != 0 {
+		return int16(mulawBias - t)
+	}
+	return int16(t - mulawBias)
+}
+
+// AlawEncode compresses a 16-bit sample to A-law.
+func AlawEncode(sample int16) byte {
+	v := int(sample) >> 3
+	mask := 0xD5
+	if v < 0 {
+		mask = 0x55
+		v = -v - 1
+	}
+
+	seg := segment(v, &alawSegmentEnds)
+	if seg >= 8 {
+		return byte(0x7F ^ mask)
+	}
+	a := seg << 4
+	if seg < 2 {
+		a |= (v >> 1) & 0x0F
+	} else {
+		a |= (v >> seg) & 0x0F
+	}
+	return byte(a ^ mask)
+}
+
+// AlawDecode expands an A-law byte to a 16-bit sample.
+func AlawDecode(b byte) int16 {
+	a := b ^ 0x55
+	t := int(a&0x0F) << 4
+	switch seg := int(a&0x70) >> 4; seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+	if a&0x80 != 0 {
+		return int16(t)
+	}
+	return int16(-t)
+}
+
+func decodeG711(data []byte, decode func(byte) int16) []float32 {
+	out := make([]float32, len(data))
+	for i, b := range data {
+		out[i] = float32(decode(b)) / 32768
+	}
+	return out
+}
+
+func encodeG711(samples []float32, encode func(int16) byte) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		out[i] = encode(floatToInt16(s))
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMulaw_KnownValues(t *testing.T) {
+	tests := []struct {
+		sample int16
+		code   byte
+	}{
+		{0, 0xFF},
+		{-1, 0x7E},
+		{32767, 0x80},
+		{-32768, 0x00},
+	}
+	for _, tt := range tests {
+		if got := MulawEncode(tt.sample); got != tt.code {
+			t.Errorf("MulawEncode(%d) = %#x, want %#x", tt.sample, got, tt.code)
+		}
+	}
+	if got := MulawDecode(0xFF); got != 0 {
+		t.Errorf("MulawDecode(0xFF) = %d, want 0", got)
+	}
+	if got := MulawDecode(0x80); got != 32124 {
+		t.Errorf("MulawDecode(0x80) = %d, want 32124", got)
+	}
+}
+
+func TestAlaw_KnownValues(t *testing.T) {
+	tests := []struct {
+		sample int16
+		code   byte
+	}{
+		{0, 0xD5},
+		{-1, 0x55},
+		{32767, 0xAA},
+		{-32768, 0x2A},
+	}
+	for _, tt := range tests {
+		if got := AlawEncode(tt.sample); got != tt.code {
+			t.Errorf("AlawEncode(%d) = %#x, want %#x", tt.sample, got, tt.code)
+		}
+	}
+	if got := AlawDecode(0xD5); got != 8 {
+		t.Errorf("AlawDecode(0xD5) = %d, want 8", got)
+	}
+	if got := AlawDecode(0xAA); got != 32256 {
+		t.Errorf("AlawDecode(0xAA) = %d, want 32256", got)
+	}
+}
+
+func TestG711_RoundTrip(t *testing.T) {
+	codecs := []struct {
+		name   string
+		encode func(int16) byte
+		decode func(byte) int16
+	}{
+		{"mulaw", MulawEncode, MulawDecode},
+		{"alaw", AlawEncode, AlawDecode},
+	}
+	for _, c := range codecs {
+		t.Run(c.name, func(t *testing.T) {
+			// Every code decodes to a value that encodes back to the same code
+			for code := 0; code < 256; code++ {
+				b := byte(code)
+				if c.name == "mulaw" && b == 0x7F {
+					continue // negative zero encodes as positive zero
+				}
+				if got := c.encode(c.decode(b)); got != b {
+					t.Errorf("encode(decode(%#x)) = %#x", b, got)
+				}
+			}
+
+			// Quantization error stays within a few percent of the signal
+			for _, s := range []int16{100, -100, 1000, -5000, 12345, -30000} {
+				got := c.decode(c.encode(s))
+				if diff := math.Abs(float64(got - s)); diff > math.Abs(float64(s))*0.07+16 {
+					t.Errorf("round trip of %d = %d", s, got)
+				}
+			}
+		})
+	}
+}
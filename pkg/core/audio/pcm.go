@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Int16ToFloat32 converts 16-bit samples to floats in [-1, 1).
+func Int16ToFloat32(samples []int16) []float32 {
+	out := make([]float32, len(samples))
+	for i, s := range samples {
+		out[i] = float32(s) / 32768
+	}
+	return out
+}
+
+// Float32ToInt16 converts float samples to 16 bits, clipping values outside [-1, 1].
+func Float32ToInt16(samples []float32) []int16 {
+	out := make([]int16, len(samples))
+	for i, s := range samples {
+		out[i] = floatToInt16(s)
+	}
+	return out
+}
+
+func floatToInt16(s float32) int16 {
+	v := math.Round(float64(s) * 32768)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+// DecodePCM16 reads little-endian 16-bit samples. A trailing odd byte is ignored.
+func DecodePCM16(data []byte) []int16 {
+	out := make([]int16, len(data)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return out
+}
+
+// EncodePCM16 writes little-endian 16-bit samples.
+func EncodePCM16(samples []int16) []byte {
+	out := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(s))
+	}
+	return out
+}
+
+// DecodeFloat32 reads little-endian 32-bit float samples.
+// Trailing bytes that do not form a full sample are ignored.
+func DecodeFloat32(data []byte) []float32 {
+	out := make([]float32, len(data)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return out
+}
+
+// EncodeFloat32 writes little-endian 32-bit float samples.
+func EncodeFloat32(samples []float32) []byte {
+	out := make([]byte, len(samples)*4)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(out[i*4:], math.Float32bits(s))
+	}
+	return out
+}
+
+// Decode converts raw audio in the given encoding to float samples.
+func Decode(data []byte, enc Encoding) ([]float32, error) {
+	switch enc {
+	case EncodingPCM16:
+		return Int16ToFloat32(DecodePCM16(data)), nil
+	case EncodingFloat32:
+		return DecodeFloat32(data), nil
+	case EncodingMulaw:
+		return decodeG711(data, MulawDecode), nil
+	case EncodingAlaw:
+		return decodeG711(data, AlawDecode), nil
+	default:
+		return nil, fmt.Errorf("audio: unsupported encoding %q", enc)
+	}
+}
+
+// Encode converts float samples to raw audio in the given encoding.
+func Encode(samples []float32, enc Encoding) ([]byte, error) {
+	switch enc {
+	case EncodingPCM16:
+		return EncodePCM16(Float32ToInt16(samples)), nil
+	case EncodingFloat32:
+		return EncodeFloat32(samples), nil
+	case EncodingMulaw:
+		return encodeG711(samples, MulawEncode), nil
+	case EncodingAlaw:
+		return encodeG711(samples, AlawEncode), nil
+	default:
+		return nil, fmt.Errorf("audio: unsupported encoding %q", enc)
+	}
+}
+
+// Downmix averages interleaved multi-channel samples into mono.
+func Downmix(samples []float32, channels int) []float32 {
+	if channels <= 1 {
+		return samples
+	}
+	out := make([]float32, len(samples)/channels)
+	for i := range out {
+		var sum float32
+		for c := 0; c < channels; c++ {
+			sum += samples[i*channels+c]
+		}
+		out[i] = sum / float32(channels)
+	}
+	return out
+}
+
+// Upmix copies mono samples to every channel of an interleaved stream.
+func Upmix(samples []float32, channels int) []float32 {
+	if channels <= 1 {
+		return samples
+	}
+	out := make([]float32, len(samples)*channels)
+	for i, s := range samples {
+		for c := 0; c < channels; c++ {
+			out[i*channels+c] = s
+		}
+	}
+	return out
+}
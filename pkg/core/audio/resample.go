@@ -0,0 +1,96 @@
+package audio
+
+// Resampler converts interleaved float samples between sample rates using
+// linear interpolation. It keeps the last frame of each chunk, so audio
+// can be fed in pieces of any size without clicks at chunk boundaries.
+//
+// Linear interpolation is cheap and adequate for speech; it does not apply
+// an anti-aliasing filter when downsampling.
+type Resampler struct {
+	from, to int
+	channels int
+	step     float64
+
+	// pos is the input position of the next output frame, relative to the
+	// start of the next chunk. -1 refers to the last frame of the previous chunk.
+	pos  float64
+	last []float32
+}
+
+// NewResampler creates a resampler from one sample rate to another.
+func NewResampler(from, to, channels int) *Resampler {
+	if channels < 1 {
+		channels = 1
+	}
+	return &Resampler{
+		from:     from,
+		to:       to,
+		channels: channels,
+		step:     float64(from) / float64(to),
+	}
+}
+
+// Process resamples the next chunk of interleaved samples.
+func (r *Resampler) Process(samples []float32) []float32 {
+	if r.from == r.to {
+		return samples
+	}
+	n := len(samples) / r.channels
+	if n == 0 {
+		return nil
+	}
+
+	frame := func(i, c int) float32 {
+		if i < 0 {
+			return r.last[c]
+		}
+		return samples[i*r.channels+c]
+	}
+
+	out := make([]float32, 0, int(float64(n)/r.step+1)*r.channels)
+	for r.pos < float64(n-1) {
+		i := int(r.pos)
+		if r.pos < 0 {
+			i = -1
+		}
+		frac := float32(r.pos - float64(i))
+		for c := 0; c < r.channels; c++ {
+			a := frame(i, c)
+			out = append(out, a+(frame(i+1, c)-a)*frac)
+		}
+		r.pos += r.step
+	}
+
+	r.pos -= float64(n)
+	r.last = append(r.last[:0], samples[(n-1)*r.channels:n*r.channels]...)
+	return out
+}
+
+// Flush returns the output frames that fall after the last input frame,
+// holding its value, and resets the resampler. Call it at the end of a stream.
+func (r *Resampler) Flush() []float32 {
+	var out []float32
+	if len(r.last) > 0 {
+		for ; r.pos < 0; r.pos += r.step {
+			out = append(out, r.last...)
+		}
+	}
+	r.Reset()
+	return out
+}
+
+// Reset discards the state carried over from previous chunks.
+func (r *Resampler) Reset() {
+	r.pos = 0
+	r.last = r.last[:0]
+}
+
+// Resample converts a complete buffer of interleaved samples between sample rates.
+func Resample(samples []float32, from, to, channels int) []float32 {
+	r := NewResampler(from, to, channels)
+	out := r.Process(samples)
+	if from == to {
+		return out
+	}
+	return append(out, r.Flush()...)
+}
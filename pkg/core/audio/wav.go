@@ -0,0 +1,201 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// WAV format codes from the fmt chunk.
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatAlaw       = 6
+	wavFormatMulaw      = 7
+	wavFormatExtensible = 0xFFFE
+)
+
+// ErrNotWAV is returned by ParseWAV for data without a RIFF/WAVE header.
+var ErrNotWAV = errors.New("audio: not a WAV file")
+
+// WAV is a parsed WAV file.
+type WAV struct {
+	// Format describes Data. 8-, 24- and 32-bit integer PCM is converted
+	// to 16-bit PCM when parsed.
+	Format Format
+
+	// Data is the raw sample data.
+	Data []byte
+}
+
+// IsWAV reports whether data starts with a RIFF/WAVE header.
+func IsWAV(data []byte) bool {
+	return len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE"))
+}
+
+// ParseWAV parses a WAV file. Streamed files whose header does not record
+// the data length are read to the end.
+func ParseWAV(data []byte) (*WAV, error) {
+	if !IsWAV(data) {
+		return nil, ErrNotWAV
+	}
+
+	var (
+		formatCode    uint16
+		channels      int
+		sampleRate    int
+		bitsPerSample int
+		haveFormat    bool
+	)
+
+	pos := 12
+	for pos+8 <= len(data) {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := pos + 8
+		end := body + size
+		if end > len(data) || (id == "data" && size == 0) {
+			end = len(data)
+		}
+
+		switch id {
+		case "fmt ":
+			if end-body < 16 {
+				return nil, fmt.Errorf("audio: WAV fmt chunk too short")
+			}
+			chunk := data[body:end]
+			formatCode = binary.LittleEndian.Uint16(chunk[0:2])
+			channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(chunk[14:16]))
+			if formatCode == wavFormatExtensible && len(chunk) >= 26 {
+				// The sub-format GUID starts with the real format code
+				formatCode = binary.LittleEndian.Uint16(chunk[24:26])
+			}
+			haveFormat = true
+
+		case "data":
+			if !haveFormat {
+				return nil, fmt.Errorf("audio: WAV data chunk before fmt chunk")
+			}
+			return newWAV(formatCode, bitsPerSample, sampleRate, channels, data[body:end])
+		}
+
+		// Chunks are padded to an even size
+		pos = end + (end-body)%2
+	}
+
+	return nil, fmt.Errorf("audio: WAV file has no data chunk")
+}
+
+func newWAV(formatCode uint16, bitsPerSample, sampleRate, channels int, data []byte) (*WAV, error) {
+	w := &WAV{Format: Format{SampleRate: sampleRate, Channels: channels}, Data: data}
+
+	switch {
+	case formatCode == wavFormatPCM && bitsPerSample == 16:
+		w.Format.Encoding = EncodingPCM16
+	case formatCode == wavFormatPCM && (bitsPerSample == 8 || bitsPerSample == 24 || bitsPerSample == 32):
+		w.Format.Encoding = EncodingPCM16
+		w.Data = intPCMToPCM16(data, bitsPerSample/8)
+	case formatCode == wavFormatFloat && bitsPerSample == 32:
+		w.Format.Encoding = EncodingFloat32
+	case formatCode == wavFormatMulaw && bitsPerSample == 8:
+		w.Format.Encoding = EncodingMulaw
+	case formatCode == wavFormatAlaw && bitsPerSample == 8:
+		w.Format.Encoding = EncodingAlaw
+	default:
+		return nil, fmt.Errorf("audio: unsupported WAV format %d with %d bits per sample", formatCode, bitsPerSample)
+	}
+
+	if err := w.Format.Validate(); err != nil {
+		return nil, err
+	}
+	// Drop a trailing partial frame
+	w.Data = w.Data[:len(w.Data)-len(w.Data)%w.Format.FrameSize()]
+	return w, nil
+}
+
+// intPCMToPCM16 converts 8-bit unsigned or 24/32-bit signed PCM to 16-bit PCM.
+func intPCMToPCM16(data []byte, width int) []byte {
+	n := len(data) / width
+	out := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		s := data[i*width : (i+1)*width]
+		var v int16
+		switch width {
+		case 1:
+			v = int16(int(s[0])-128) << 8
+		default:
+			// Keep the two most significant bytes
+			v = int16(binary.LittleEndian.Uint16(s[width-2:]))
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(v))
+	}
+	return out
+}
+
+// EncodeWAV wraps raw audio in a WAV header.
+func EncodeWAV(data []byte, f Format) ([]byte, error) {
+	header, err := WAVHeader(f, len(data))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, len(header)+len(data)+1)
+	buf = append(buf, header...)
+	buf = append(buf, data...)
+	if len(data)%2 == 1 {
+		buf = append(buf, 0)
+	}
+	return buf, nil
+}
+
+// WAVHeader returns the header of a WAV file holding dataSize bytes of
+// audio, for writing audio that does not fit in memory. Pad odd-sized
+// data with a zero byte.
+func WAVHeader(f Format, dataSize int) ([]byte, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	var formatCode uint16
+	switch f.Encoding {
+	case EncodingPCM16:
+		formatCode = wavFormatPCM
+	case EncodingFloat32:
+		formatCode = wavFormatFloat
+	case EncodingMulaw:
+		formatCode = wavFormatMulaw
+	case EncodingAlaw:
+		formatCode = wavFormatAlaw
+	}
+
+	// Non-PCM formats carry an empty extension size field
+	fmtSize := 16
+	if formatCode != wavFormatPCM {
+		fmtSize = 18
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(20 + fmtSize + 8)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+fmtSize+8+dataSize+dataSize%2))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(fmtSize))
+	binary.Write(&buf, binary.LittleEndian, formatCode)
+	binary.Write(&buf, binary.LittleEndian, uint16(f.Channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(f.SampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(f.SampleRate*f.FrameSize()))
+	binary.Write(&buf, binary.LittleEndian, uint16(f.FrameSize()))
+	binary.Write(&buf, binary.LittleEndian, uint16(f.Encoding.BytesPerSample()*8))
+	if fmtSize == 18 {
+		binary.Write(&buf, binary.LittleEndian, uint16(0))
+	}
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	return buf.Bytes(), nil
+}
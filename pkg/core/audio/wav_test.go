@@ -0,0 +1,114 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestWAV_RoundTrip(t *testing.T) {
+	formats := []Format{
+		PCM16(24000, 1),
+		{Encoding: EncodingFloat32, SampleRate: 48000, Channels: 2},
+		{Encoding: EncodingMulaw, SampleRate: 8000, Channels: 1},
+		{Encoding: EncodingAlaw, SampleRate: 8000, Channels: 1},
+	}
+	for _, f := range formats {
+		t.Run(string(f.Encoding), func(t *testing.T) {
+			data := bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 10)
+			file, err := EncodeWAV(data, f)
+			if err != nil {
+				t.Fatalf("EncodeWAV() error = %v", err)
+			}
+			w, err := ParseWAV(file)
+			if err != nil {
+				t.Fatalf("ParseWAV() error = %v", err)
+			}
+			if w.Format != f {
+				t.Errorf("Format = %v, want %v", w.Format, f)
+			}
+			if !bytes.Equal(w.Data, data) {
+				t.Error("Data does not match")
+			}
+		})
+	}
+}
+
+// rawWAV builds a WAV file by hand with the given fmt fields.
+func rawWAV(formatCode uint16, channels, sampleRate, bits int, extra []byte, data []byte, dataSize uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.WriteString("WAVE")
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.Write([]byte{'a', 'b', 'c', 0})
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16+len(extra)))
+	binary.Write(&buf, binary.LittleEndian, formatCode)
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(bits))
+	buf.Write(extra)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestParseWAV_ConvertsIntegerPCM(t *testing.T) {
+	// 24-bit samples: 0x7FFFFF (max) and 0x800000 (min)
+	file := rawWAV(1, 1, 16000, 24, nil, []byte{0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80}, 6)
+	w, err := ParseWAV(file)
+	if err != nil {
+		t.Fatalf("ParseWAV() error = %v", err)
+	}
+	if w.Format != PCM16(16000, 1) {
+		t.Errorf("Format = %v", w.Format)
+	}
+	if got := DecodePCM16(w.Data); len(got) != 2 || got[0] != 32767 || got[1] != -32768 {
+		t.Errorf("samples = %v", got)
+	}
+
+	// 8-bit samples are unsigned
+	file = rawWAV(1, 1, 8000, 8, nil, []byte{128, 255, 0}, 3)
+	w, err = ParseWAV(file)
+	if err != nil {
+		t.Fatalf("ParseWAV() error = %v", err)
+	}
+	if got := DecodePCM16(w.Data); len(got) != 3 || got[0] != 0 || got[1] != 127<<8 || got[2] != -128<<8 {
+		t.Errorf("samples = %v", got)
+	}
+}
+
+func TestParseWAV_ExtensibleAndStreamed(t *testing.T) {
+	// WAVE_FORMAT_EXTENSIBLE wrapping float samples, with an unknown data length
+	extra := make([]byte, 24)
+	binary.LittleEndian.PutUint16(extra[0:2], 22)
+	binary.LittleEndian.PutUint16(extra[8:10], 3)
+	samples := EncodeFloat32([]float32{0.5, -0.5})
+	file := rawWAV(0xFFFE, 1, 48000, 32, extra, samples, 0xFFFFFFFF)
+
+	w, err := ParseWAV(file)
+	if err != nil {
+		t.Fatalf("ParseWAV() error = %v", err)
+	}
+	if w.Format.Encoding != EncodingFloat32 {
+		t.Errorf("Encoding = %q", w.Format.Encoding)
+	}
+	if got := DecodeFloat32(w.Data); len(got) != 2 || got[0] != 0.5 {
+		t.Errorf("samples = %v", got)
+	}
+}
+
+func TestParseWAV_Errors(t *testing.T) {
+	if _, err := ParseWAV([]byte("not audio at all")); !errors.Is(err, ErrNotWAV) {
+		t.Errorf("expected ErrNotWAV, got %v", err)
+	}
+	if _, err := ParseWAV(rawWAV(2, 1, 8000, 4, nil, []byte{1, 2}, 2)); err == nil {
+		t.Error("expected error for ADPCM")
+	}
+}
@@ -0,0 +1,325 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// CapabilityMode controls how the Engine treats requests that use features
+// the target provider does not support.
+type CapabilityMode string
+
+const (
+	// CapabilityStrict rejects unsupported features with an invalid_request_error.
+	CapabilityStrict CapabilityMode = "strict"
+	// CapabilityDegrade drops, transforms or emulates unsupported features and
+	// records each change in the response metadata.
+	CapabilityDegrade CapabilityMode = "degrade"
+	// CapabilityOff skips capability checks and sends requests as-is.
+	CapabilityOff CapabilityMode = "off"
+)
+
+// ErrCodeUnsupportedFeature is the Error.Code used for capability violations.
+const ErrCodeUnsupportedFeature = "unsupported_feature"
+
+// MetadataDegradations is the metadata key holding the []Degradation applied to a request.
+const MetadataDegradations = "degradations"
+
+// Degradation actions.
+const (
+	DegradeDropped     = "dropped"
+	DegradeTransformed = "transformed"
+	DegradeEmulated    = "emulated"
+)
+
+// Degradation records one change made to fit a request to a provider.
+type Degradation struct {
+	Param   string `json:"param"`   // e.g. "messages[0].content[1]", "tools[2]"
+	Feature string `json:"feature"` // e.g. "video", "web_search", "structured_output"
+	Action  string `json:"action"`  // "dropped", "transformed" or "emulated"
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ValidateRequest checks req against the capabilities of provider.
+// It returns an invalid_request_error with Param pointing at the first unsupported feature.
+func ValidateRequest(provider string, caps ProviderCapabilities, req *types.MessageRequest) error {
+	for i, msg := range req.Messages {
+		blocks, ok := msg.Content.([]types.ContentBlock)
+		if !ok {
+			continue
+		}
+		for j, block := range blocks {
+			param := fmt.Sprintf("messages[%d].content[%d]", i, j)
+			if feature := unsupportedBlock(caps, block); feature != "" {
+				return unsupportedError(provider, feature, param)
+			}
+			if result, ok := block.(types.ToolResultBlock); ok {
+				for k, inner := range result.Content {
+					if feature := unsupportedBlock(caps, inner); feature != "" {
+						return unsupportedError(provider, feature, fmt.Sprintf("%s.content[%d]", param, k))
+					}
+				}
+			}
+		}
+	}
+
+	for i, tool := range req.Tools {
+		if feature := unsupportedTool(caps, tool); feature != "" {
+			return unsupportedError(provider, feature, fmt.Sprintf("tools[%d]", i))
+		}
+	}
+
+	if req.OutputFormat != nil && !caps.StructuredOutput {
+		return unsupportedError(provider, "structured_output", "output_format")
+	}
+
+	if req.Output != nil && slices.Contains(req.Output.Modalities, "audio") && !caps.AudioOutput {
+		return unsupportedError(provider, "audio_output", "output.modalities")
+	}
+
+	return nil
+}
+
+// DegradeRequest returns a copy of req with every feature unsupported by caps
+// removed, transformed or emulated, along with the list of changes.
+// The original request is never modified. If nothing changes, req itself is returned.
+func DegradeRequest(caps ProviderCapabilities, req *types.MessageRequest) (*types.MessageRequest, []Degradation) {
+	var changes []Degradation
+	out := *req
+
+	// Content blocks
+	var messages []types.Message
+	for i, msg := range req.Messages {
+		blocks, ok := msg.Content.([]types.ContentBlock)
+		if !ok {
+			continue
+		}
+		newBlocks, blockChanges := degradeBlocks(caps, blocks, fmt.Sprintf("messages[%d].content", i))
+		if len(blockChanges) == 0 {
+			continue
+		}
+		if messages == nil {
+			messages = slices.Clone(req.Messages)
+		}
+		messages[i].Content = newBlocks
+		changes = append(changes, blockChanges...)
+	}
+	if messages != nil {
+		out.Messages = messages
+	}
+
+	// Tools
+	var tools []types.Tool
+	toolsChanged := false
+	for i, tool := range req.Tools {
+		if feature := unsupportedTool(caps, tool); feature != "" {
+			toolsChanged = true
+			changes = append(changes, Degradation{
+				Param:   fmt.Sprintf("tools[%d]", i),
+				Feature: feature,
+				Action:  DegradeDropped,
+			})
+			continue
+		}
+		tools = append(tools, tool)
+	}
+	if toolsChanged {
+		out.Tools = tools
+		if req.ToolChoice != nil && (len(tools) == 0 || !hasToolNamed(tools, req.ToolChoice.Name)) {
+			out.ToolChoice = nil
+			changes = append(changes, Degradation{
+				Param:   "tool_choice",
+				Feature: "tool_choice",
+				Action:  DegradeDropped,
+				Detail:  "referenced tools were removed",
+			})
+		}
+	}
+
+	// Structured output is emulated with a system prompt instruction
+	if req.OutputFormat != nil && !caps.StructuredOutput {
+		out.OutputFormat = nil
+		out.System = appendSystemInstruction(req.System, structuredOutputInstruction(req.OutputFormat))
+		changes = append(changes, Degradation{
+			Param:   "output_format",
+			Feature: "structured_output",
+			Action:  DegradeEmulated,
+			Detail:  "schema moved into the system prompt",
+		})
+	}
+
+	if req.Output != nil && slices.Contains(req.Output.Modalities, "audio") && !caps.AudioOutput {
+		output := *req.Output
+		output.Modalities = slices.DeleteFunc(slices.Clone(output.Modalities), func(m string) bool { return m == "audio" })
+		out.Output = &output
+		changes = append(changes, Degradation{
+			Param:   "output.modalities",
+			Feature: "audio_output",
+			Action:  DegradeDropped,
+		})
+	}
+
+	if len(changes) == 0 {
+		return req, nil
+	}
+	return &out, changes
+}
+
+// degradeBlocks rewrites a content block list for caps.
+func degradeBlocks(caps ProviderCapabilities, blocks []types.ContentBlock, param string) ([]types.ContentBlock, []Degradation) {
+	var changes []Degradation
+	out := make([]types.ContentBlock, 0, len(blocks))
+
+	for j, block := range blocks {
+		blockParam := fmt.Sprintf("%s[%d]", param, j)
+
+		if result, ok := block.(types.ToolResultBlock); ok {
+			inner, innerChanges := degradeBlocks(caps, result.Content, blockParam+".content")
+			if len(innerChanges) > 0 {
+				result.Content = inner
+				changes = append(changes, innerChanges...)
+			}
+			out = append(out, result)
+			continue
+		}
+
+		// Reasoning from another provider is history only; drop it quietly
+		if _, ok := block.(types.ThinkingBlock); ok && !caps.Thinking {
+			changes = append(changes, Degradation{Param: blockParam, Feature: "thinking", Action: DegradeDropped})
+			continue
+		}
+
+		feature := unsupportedBlock(caps, block)
+		if feature == "" {
+			out = append(out, block)
+			continue
+		}
+
+		// Audio with a transcript can be emulated as text
+		if audio, ok := block.(types.AudioBlock); ok && audio.Transcript != nil && *audio.Transcript != "" {
+			out = append(out, types.TextBlock{Type: "text", Text: *audio.Transcript})
+			changes = append(changes, Degradation{
+				Param:   blockParam,
+				Feature: feature,
+				Action:  DegradeEmulated,
+				Detail:  "replaced with transcript",
+			})
+			continue
+		}
+
+		out = append(out, types.TextBlock{Type: "text", Text: fmt.Sprintf("[%s omitted]", block.BlockType())})
+		changes = append(changes, Degradation{
+			Param:   blockParam,
+			Feature: feature,
+			Action:  DegradeTransformed,
+			Detail:  "replaced with a text placeholder",
+		})
+	}
+
+	return out, changes
+}
+
+// unsupportedBlock returns the capability a content block needs, if caps lacks it.
+func unsupportedBlock(caps ProviderCapabilities, block types.ContentBlock) string {
+	switch block.(type) {
+	case types.ImageBlock:
+		if !caps.Vision {
+			return "vision"
+		}
+	case types.AudioBlock:
+		if !caps.AudioInput {
+			return "audio_input"
+		}
+	case types.VideoBlock:
+		if !caps.Video {
+			return "video"
+		}
+	}
+	return ""
+}
+
+// unsupportedTool returns the capability a tool needs, if caps lacks it.
+func unsupportedTool(caps ProviderCapabilities, tool types.Tool) string {
+	if tool.Type == "" || tool.Type == types.ToolTypeFunction {
+		if !caps.Tools {
+			return "tools"
+		}
+		return ""
+	}
+	if !supportsNativeTool(caps, tool.Type) {
+		return tool.Type
+	}
+	return ""
+}
+
+// nativeToolAliases lists provider names that implement the same native tool.
+var nativeToolAliases = map[string][]string{
+	types.ToolTypeCodeExecution: {"code_interpreter"},
+}
+
+// supportsNativeTool reports whether caps lists the native tool or one of its aliases.
+func supportsNativeTool(caps ProviderCapabilities, toolType string) bool {
+	if slices.Contains(caps.NativeTools, toolType) {
+		return true
+	}
+	for _, alias := range nativeToolAliases[toolType] {
+		if slices.Contains(caps.NativeTools, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasToolNamed reports whether tools contains a tool with the given name.
+func hasToolNamed(tools []types.Tool, name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, tool := range tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// structuredOutputInstruction builds a system prompt instruction describing the schema.
+func structuredOutputInstruction(format *types.OutputFormat) string {
+	instruction := "Respond only with a single JSON object and no other text."
+	if format.JSONSchema != nil {
+		if schema, err := json.Marshal(format.JSONSchema); err == nil {
+			instruction += " The JSON must match this schema: " + string(schema)
+		}
+	}
+	return instruction
+}
+
+// appendSystemInstruction adds text to a system prompt that may be a string or content blocks.
+func appendSystemInstruction(system any, text string) any {
+	switch s := system.(type) {
+	case nil:
+		return text
+	case string:
+		if s == "" {
+			return text
+		}
+		return s + "\n\n" + text
+	case []types.ContentBlock:
+		return append(slices.Clone(s), types.TextBlock{Type: "text", Text: text})
+	default:
+		return system
+	}
+}
+
+// unsupportedError builds the error returned for a capability violation.
+func unsupportedError(provider, feature, param string) *Error {
+	return &Error{
+		Type:    ErrInvalidRequest,
+		Message: fmt.Sprintf("%s does not support %s", provider, feature),
+		Param:   param,
+		Code:    ErrCodeUnsupportedFeature,
+	}
+}
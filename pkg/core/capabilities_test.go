@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// groqLikeCaps mirrors a text-and-tools provider without native tools.
+var groqLikeCaps = ProviderCapabilities{Vision: true, Tools: true, StructuredOutput: true}
+
+func videoRequest() *types.MessageRequest {
+	return &types.MessageRequest{
+		Model: "test/model",
+		Messages: []types.Message{
+			{Role: "user", Content: "hi"},
+			{Role: "user", Content: []types.ContentBlock{
+				types.TextBlock{Type: "text", Text: "what happens here?"},
+				types.VideoBlock{Type: "video", Source: types.VideoSource{Type: "base64", MediaType: "video/mp4", Data: "AAAA"}},
+			}},
+		},
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		caps  ProviderCapabilities
+		req   *types.MessageRequest
+		param string
+	}{
+		{
+			name:  "video block",
+			caps:  groqLikeCaps,
+			req:   videoRequest(),
+			param: "messages[1].content[1]",
+		},
+		{
+			name: "image inside tool result",
+			caps: ProviderCapabilities{Tools: true},
+			req: &types.MessageRequest{Messages: []types.Message{{Role: "user", Content: []types.ContentBlock{
+				types.ToolResultBlock{Type: "tool_result", ToolUseID: "t1", Content: []types.ContentBlock{
+					types.ImageBlock{Type: "image", Source: types.ImageSource{Type: "url", URL: "https://example.com/a.png"}},
+				}},
+			}}}},
+			param: "messages[0].content[0].content[0]",
+		},
+		{
+			name: "native tool",
+			caps: groqLikeCaps,
+			req: &types.MessageRequest{Tools: []types.Tool{
+				types.NewFunctionTool("lookup", "", nil),
+				types.NewWebSearchTool(nil),
+			}},
+			param: "tools[1]",
+		},
+		{
+			name:  "function tools",
+			caps:  ProviderCapabilities{},
+			req:   &types.MessageRequest{Tools: []types.Tool{types.NewFunctionTool("lookup", "", nil)}},
+			param: "tools[0]",
+		},
+		{
+			name:  "structured output",
+			caps:  ProviderCapabilities{},
+			req:   &types.MessageRequest{OutputFormat: &types.OutputFormat{Type: "json_schema"}},
+			param: "output_format",
+		},
+		{
+			name:  "audio output",
+			caps:  ProviderCapabilities{},
+			req:   &types.MessageRequest{Output: &types.OutputConfig{Modalities: []string{"text", "audio"}}},
+			param: "output.modalities",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequest("groq", tt.caps, tt.req)
+			var coreErr *Error
+			if !errors.As(err, &coreErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if coreErr.Type != ErrInvalidRequest || coreErr.Code != ErrCodeUnsupportedFeature {
+				t.Errorf("Type/Code = %s/%s", coreErr.Type, coreErr.Code)
+			}
+			if coreErr.Param != tt.param {
+				t.Errorf("Param = %q, want %q", coreErr.Param, tt.param)
+			}
+			if !strings.HasPrefix(coreErr.Message, "groq does not support") {
+				t.Errorf("Message = %q", coreErr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateRequest_Supported(t *testing.T) {
+	caps := ProviderCapabilities{Tools: true, NativeTools: []string{"code_interpreter"}}
+	req := &types.MessageRequest{
+		Messages: []types.Message{{Role: "user", Content: "hi"}},
+		Tools:    []types.Tool{types.NewFunctionTool("lookup", "", nil), types.NewCodeExecutionTool(nil)},
+	}
+	if err := ValidateRequest("openai", caps, req); err != nil {
+		t.Errorf("ValidateRequest() error = %v", err)
+	}
+}
+
+func TestDegradeRequest(t *testing.T) {
+	transcript := "hello there"
+	req := &types.MessageRequest{
+		System: "Be brief.",
+		Messages: []types.Message{
+			{Role: "user", Content: []types.ContentBlock{
+				types.AudioBlock{Type: "audio", Transcript: &transcript},
+				types.VideoBlock{Type: "video"},
+			}},
+			{Role: "assistant", Content: []types.ContentBlock{
+				types.ThinkingBlock{Type: "thinking", Thinking: "hmm"},
+				types.TextBlock{Type: "text", Text: "Hi"},
+			}},
+		},
+		Tools:        []types.Tool{types.NewFunctionTool("lookup", "", nil), types.NewWebSearchTool(nil)},
+		ToolChoice:   types.ToolChoiceTool("web_search"),
+		OutputFormat: &types.OutputFormat{Type: "json_schema", JSONSchema: &types.JSONSchema{Type: "object"}},
+	}
+
+	out, changes := DegradeRequest(ProviderCapabilities{Tools: true}, req)
+
+	got := make(map[string]string)
+	for _, c := range changes {
+		got[c.Param] = c.Feature + ":" + c.Action
+	}
+	want := map[string]string{
+		"messages[0].content[0]": "audio_input:emulated",
+		"messages[0].content[1]": "video:transformed",
+		"messages[1].content[0]": "thinking:dropped",
+		"tools[1]":               "web_search:dropped",
+		"tool_choice":            "tool_choice:dropped",
+		"output_format":          "structured_output:emulated",
+	}
+	for param, w := range want {
+		if got[param] != w {
+			t.Errorf("change for %s = %q, want %q", param, got[param], w)
+		}
+	}
+
+	blocks := out.Messages[0].Content.([]types.ContentBlock)
+	if text, ok := blocks[0].(types.TextBlock); !ok || text.Text != transcript {
+		t.Errorf("audio not replaced with transcript: %#v", blocks[0])
+	}
+	if len(out.Messages[1].Content.([]types.ContentBlock)) != 1 {
+		t.Error("thinking block not dropped")
+	}
+	if len(out.Tools) != 1 || out.ToolChoice != nil {
+		t.Errorf("Tools = %v, ToolChoice = %v", out.Tools, out.ToolChoice)
+	}
+	if out.OutputFormat != nil || !strings.Contains(out.System.(string), `{"type":"object"}`) {
+		t.Errorf("structured output not emulated: %v", out.System)
+	}
+
+	// The caller's request is untouched
+	if len(req.Tools) != 2 || req.OutputFormat == nil {
+		t.Error("original request was modified")
+	}
+	if _, ok := req.Messages[0].Content.([]types.ContentBlock)[1].(types.VideoBlock); !ok {
+		t.Error("original message content was modified")
+	}
+}
+
+func TestDegradeRequest_NoChanges(t *testing.T) {
+	req := &types.MessageRequest{Messages: []types.Message{{Role: "user", Content: "hi"}}}
+	out, changes := DegradeRequest(ProviderCapabilities{}, req)
+	if out != req || changes != nil {
+		t.Errorf("expected request to pass through unchanged")
+	}
+}
+
+func TestEngine_CapabilityModes(t *testing.T) {
+	e := NewEngine(nil)
+	p := &routeTestProvider{name: "test"}
+	e.RegisterProvider(p)
+
+	// Strict is the default
+	_, err := e.CreateMessage(context.Background(), videoRequest())
+	var coreErr *Error
+	if !errors.As(err, &coreErr) || coreErr.Param != "messages[1].content[1]" {
+		t.Fatalf("expected capability error, got %v", err)
+	}
+	if p.calls != 0 {
+		t.Error("provider should not be called for an invalid request")
+	}
+
+	e.SetCapabilityMode(CapabilityDegrade)
+	resp, err := e.CreateMessage(context.Background(), videoRequest())
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	changes, ok := resp.Metadata[MetadataDegradations].([]Degradation)
+	if !ok || len(changes) != 1 || changes[0].Feature != "video" {
+		t.Errorf("degradations = %v", resp.Metadata[MetadataDegradations])
+	}
+
+	e.SetCapabilityMode(CapabilityOff)
+	resp, err = e.CreateMessage(context.Background(), videoRequest())
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if _, ok := resp.Metadata[MetadataDegradations]; ok {
+		t.Error("no degradations expected with checks off")
+	}
+}
+
+func TestEngine_CapabilityFailover(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&routeTestProvider{name: "text"})
+	e.RegisterProvider(&capsProvider{routeTestProvider{name: "video"}, ProviderCapabilities{Video: true}})
+	e.SetModelRoute("any", "text/model", "video/model")
+
+	resp, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "any", Messages: videoRequest().Messages})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.Metadata[MetadataRoutedModel] != "video/model" {
+		t.Errorf("routed_model = %v, want video/model", resp.Metadata[MetadataRoutedModel])
+	}
+}
+
+// capsProvider overrides the capabilities of a routeTestProvider.
+type capsProvider struct {
+	routeTestProvider
+	caps ProviderCapabilities
+}
+
+func (p *capsProvider) Capabilities() ProviderCapabilities { return p.caps }
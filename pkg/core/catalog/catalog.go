@@ -0,0 +1,324 @@
+// Package catalog provides the built-in model catalog: context windows,
+// output limits, capabilities and pricing for every supported provider.
+//
+// The default catalog is embedded in the binary and can be extended or
+// overridden at runtime with Add, LoadJSON or LoadFile.
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+//go:embed models.json
+var embeddedCatalog []byte
+
+// Model describes a model available through a provider.
+type Model struct {
+	ID           string                    `json:"id"`           // "anthropic/claude-sonnet-4"
+	Provider     string                    `json:"provider"`     // "anthropic"
+	Name         string                    `json:"name"`         // "claude-sonnet-4"
+	DisplayName  string                    `json:"display_name"` // "Claude Sonnet 4"
+	Description  string                    `json:"description,omitempty"`
+	Context      int                       `json:"context"`    // Max context window
+	MaxOutput    int                       `json:"max_output"` // Max output tokens
+	Capabilities core.ProviderCapabilities `json:"capabilities"`
+	Pricing      *ModelPricing             `json:"pricing,omitempty"`
+}
+
+// ModelPricing contains pricing information for a model.
+type ModelPricing struct {
+	InputPerMillion      float64 `json:"input_per_million"`
+	OutputPerMillion     float64 `json:"output_per_million"`
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty"`
+	Currency             string  `json:"currency"` // "USD"
+}
+
+// Cost returns the USD cost of usage. InputTokens must exclude cache reads
+// and writes, which are billed at their own rates when set. Cache rates of
+// zero fall back to the input rate.
+func (p ModelPricing) Cost(usage types.Usage) float64 {
+	cacheRead := p.CacheReadPerMillion
+	if cacheRead == 0 {
+		cacheRead = p.InputPerMillion
+	}
+	cacheWrite := p.CacheWritePerMillion
+	if cacheWrite == 0 {
+		cacheWrite = p.InputPerMillion
+	}
+
+	cost := float64(usage.InputTokens)*p.InputPerMillion +
+		float64(usage.OutputTokens)*p.OutputPerMillion
+	if usage.CacheReadTokens != nil {
+		cost += float64(*usage.CacheReadTokens) * cacheRead
+	}
+	if usage.CacheWriteTokens != nil {
+		cost += float64(*usage.CacheWriteTokens) * cacheWrite
+	}
+	return cost / 1_000_000
+}
+
+// Catalog is a concurrency-safe set of models keyed by "provider/model" ID.
+type Catalog struct {
+	mu     sync.RWMutex
+	models map[string]Model
+}
+
+// New creates a catalog containing the given models.
+func New(models ...Model) *Catalog {
+	c := &Catalog{models: make(map[string]Model)}
+	c.Add(models...)
+	return c
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultModels []Model
+)
+
+// Default returns a new catalog populated with the embedded model list.
+// Each call returns an independent copy that can be modified freely.
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		models, err := parse(embeddedCatalog)
+		if err != nil {
+			panic(fmt.Sprintf("catalog: invalid embedded catalog: %v", err))
+		}
+		defaultModels = models
+	})
+	return New(defaultModels...)
+}
+
+// Add inserts models, replacing any existing entries with the same ID.
+// Missing IDs are derived from Provider and Name.
+func (c *Catalog) Add(models ...Model) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range models {
+		m = normalize(m)
+		c.models[m.ID] = m
+	}
+}
+
+// Remove deletes a model from the catalog.
+func (c *Catalog) Remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.models, id)
+}
+
+// LoadJSON merges models from a catalog document into c.
+// The document uses the same format as the embedded catalog.
+func (c *Catalog) LoadJSON(data []byte) error {
+	models, err := parse(data)
+	if err != nil {
+		return err
+	}
+	c.Add(models...)
+	return nil
+}
+
+// LoadFile merges models from a JSON catalog file into c.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read catalog: %w", err)
+	}
+	return c.LoadJSON(data)
+}
+
+// List returns all models sorted by ID.
+func (c *Catalog) List() []Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	models := make([]Model, 0, len(c.models))
+	for _, m := range c.models {
+		models = append(models, clone(m))
+	}
+	sortModels(models)
+	return models
+}
+
+// ListByProvider returns the models of a single provider sorted by ID.
+func (c *Catalog) ListByProvider(provider string) []Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var models []Model
+	for _, m := range c.models {
+		if m.Provider == provider {
+			models = append(models, clone(m))
+		}
+	}
+	sortModels(models)
+	return models
+}
+
+// Get returns a model by its "provider/model" ID.
+// Dated snapshots such as "anthropic/claude-sonnet-4-20250514" resolve to
+// the longest catalog ID they extend.
+func (c *Catalog) Get(id string) (Model, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if m, ok := c.models[id]; ok {
+		return clone(m), true
+	}
+
+	var best Model
+	found := false
+	for key, m := range c.models {
+		if strings.HasPrefix(id, key+"-") && len(key) > len(best.ID) {
+			best, found = m, true
+		}
+	}
+	return clone(best), found
+}
+
+// Pricing returns the pricing for a model, if known.
+func (c *Catalog) Pricing(id string) (*ModelPricing, bool) {
+	m, ok := c.Get(id)
+	if !ok || m.Pricing == nil {
+		return nil, false
+	}
+	return m.Pricing, true
+}
+
+// Cost returns the USD cost of usage on a model, or false if the model has no
+// pricing. It implements core.PricingTable.
+func (c *Catalog) Cost(id string, usage types.Usage) (float64, bool) {
+	m, ok := c.Get(id)
+	if !ok || m.Pricing == nil {
+		return 0, false
+	}
+	return m.Pricing.Cost(billableUsage(m.Provider, usage)), true
+}
+
+// separateCacheUsage lists providers whose input token counts already exclude
+// cached tokens. Everyone else includes cache reads in the input count.
+var separateCacheUsage = map[string]bool{
+	"anthropic": true,
+}
+
+// billableUsage converts usage reported by provider into the form expected
+// by ModelPricing.Cost.
+func billableUsage(provider string, usage types.Usage) types.Usage {
+	if separateCacheUsage[provider] || usage.CacheReadTokens == nil {
+		return usage
+	}
+	usage.InputTokens = max(usage.InputTokens-*usage.CacheReadTokens, 0)
+	return usage
+}
+
+// document is the on-disk catalog format. Models inherit their provider's
+// capabilities unless they declare their own.
+type document struct {
+	Providers []struct {
+		ID           string                     `json:"id"`
+		Description  string                     `json:"description,omitempty"`
+		Capabilities *core.ProviderCapabilities `json:"capabilities,omitempty"`
+		Models       []struct {
+			Name         string                     `json:"name"`
+			DisplayName  string                     `json:"display_name"`
+			Description  string                     `json:"description,omitempty"`
+			Context      int                        `json:"context"`
+			MaxOutput    int                        `json:"max_output"`
+			Capabilities *core.ProviderCapabilities `json:"capabilities,omitempty"`
+			Pricing      *ModelPricing              `json:"pricing,omitempty"`
+		} `json:"models"`
+	} `json:"providers"`
+}
+
+// parse decodes a catalog document into models.
+func parse(data []byte) ([]Model, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse catalog: %w", err)
+	}
+
+	var models []Model
+	for _, p := range doc.Providers {
+		if p.ID == "" {
+			return nil, fmt.Errorf("parse catalog: provider without id")
+		}
+		for _, pm := range p.Models {
+			if pm.Name == "" {
+				return nil, fmt.Errorf("parse catalog: %s model without name", p.ID)
+			}
+			m := Model{
+				Provider:    p.ID,
+				Name:        pm.Name,
+				DisplayName: pm.DisplayName,
+				Description: pm.Description,
+				Context:     pm.Context,
+				MaxOutput:   pm.MaxOutput,
+				Pricing:     pm.Pricing,
+			}
+			if m.Description == "" {
+				m.Description = p.Description
+			}
+			switch {
+			case pm.Capabilities != nil:
+				m.Capabilities = *pm.Capabilities
+			case p.Capabilities != nil:
+				m.Capabilities = *p.Capabilities
+			}
+			models = append(models, m)
+		}
+	}
+	return models, nil
+}
+
+// normalize fills in derived fields.
+func normalize(m Model) Model {
+	if m.ID == "" {
+		m.ID = m.Provider + "/" + m.Name
+	}
+	if m.Provider == "" || m.Name == "" {
+		if provider, name, err := core.ParseModelString(m.ID); err == nil {
+			if m.Provider == "" {
+				m.Provider = provider
+			}
+			if m.Name == "" {
+				m.Name = name
+			}
+		}
+	}
+	if m.DisplayName == "" {
+		m.DisplayName = m.Name
+	}
+	if m.Capabilities.NativeTools == nil {
+		m.Capabilities.NativeTools = []string{}
+	}
+	if m.Pricing != nil {
+		pricing := *m.Pricing
+		if pricing.Currency == "" {
+			pricing.Currency = "USD"
+		}
+		m.Pricing = &pricing
+	}
+	return m
+}
+
+// clone returns a copy of m that shares no mutable state with the catalog.
+func clone(m Model) Model {
+	m.Capabilities.NativeTools = append([]string{}, m.Capabilities.NativeTools...)
+	if m.Pricing != nil {
+		pricing := *m.Pricing
+		m.Pricing = &pricing
+	}
+	return m
+}
+
+// sortModels orders models by ID.
+func sortModels(models []Model) {
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+}
@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+var _ core.PricingTable = (*Catalog)(nil)
+
+func TestDefault_CoversProviders(t *testing.T) {
+	c := Default()
+	for _, provider := range []string{"anthropic", "openai", "oai-resp", "groq", "cerebras", "gemini", "gemini-oauth"} {
+		if len(c.ListByProvider(provider)) == 0 {
+			t.Errorf("no models for provider %q", provider)
+		}
+	}
+
+	for _, m := range c.List() {
+		if m.Context == 0 || m.MaxOutput == 0 {
+			t.Errorf("%s: missing context window or max output", m.ID)
+		}
+		if m.Pricing != nil && m.Pricing.Currency != "USD" {
+			t.Errorf("%s: currency = %q, want USD", m.ID, m.Pricing.Currency)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	c := Default()
+
+	m, ok := c.Get("anthropic/claude-sonnet-4")
+	if !ok {
+		t.Fatal("claude-sonnet-4 not found")
+	}
+	if m.Provider != "anthropic" || m.Name != "claude-sonnet-4" || m.DisplayName != "Claude Sonnet 4" {
+		t.Errorf("model = %+v", m)
+	}
+	if !m.Capabilities.Vision || !m.Capabilities.Tools {
+		t.Errorf("capabilities = %+v", m.Capabilities)
+	}
+	if m.Pricing == nil || m.Pricing.InputPerMillion != 3 || m.Pricing.CacheReadPerMillion != 0.3 {
+		t.Errorf("pricing = %+v", m.Pricing)
+	}
+
+	// Model-level capabilities replace the provider defaults
+	haiku, _ := c.Get("anthropic/claude-3-5-haiku")
+	if haiku.Capabilities.Thinking {
+		t.Error("claude-3-5-haiku should not support thinking")
+	}
+
+	if _, ok := c.Get("anthropic/unknown-model"); ok {
+		t.Error("expected unknown model to be missing")
+	}
+}
+
+func TestGet_DatedSnapshot(t *testing.T) {
+	c := Default()
+
+	m, ok := c.Get("anthropic/claude-sonnet-4-20250514")
+	if !ok || m.ID != "anthropic/claude-sonnet-4" {
+		t.Errorf("Get(dated sonnet) = %v, %v", m.ID, ok)
+	}
+	m, ok = c.Get("anthropic/claude-sonnet-4-5-20250929")
+	if !ok || m.ID != "anthropic/claude-sonnet-4-5" {
+		t.Errorf("Get(dated sonnet 4.5) = %v, %v; want the longest matching ID", m.ID, ok)
+	}
+}
+
+func TestGet_ReturnsCopy(t *testing.T) {
+	c := Default()
+	m, _ := c.Get("openai/gpt-4o")
+	m.Pricing.InputPerMillion = 999
+	m.Capabilities.NativeTools[0] = "changed"
+
+	again, _ := c.Get("openai/gpt-4o")
+	if again.Pricing.InputPerMillion == 999 || again.Capabilities.NativeTools[0] == "changed" {
+		t.Error("catalog entry was modified through a returned copy")
+	}
+}
+
+func TestAdd_Override(t *testing.T) {
+	c := Default()
+	c.Add(Model{ID: "groq/llama-3.3-70b-versatile", Context: 1, MaxOutput: 1, Pricing: &ModelPricing{InputPerMillion: 0.5}})
+	c.Add(Model{Provider: "local", Name: "llama"})
+
+	m, _ := c.Get("groq/llama-3.3-70b-versatile")
+	if m.Pricing.InputPerMillion != 0.5 || m.Provider != "groq" || m.Pricing.Currency != "USD" {
+		t.Errorf("override = %+v", m)
+	}
+	if _, ok := c.Get("local/llama"); !ok {
+		t.Error("expected local/llama to be added")
+	}
+
+	// Default returns fresh copies
+	if m, _ := Default().Get("groq/llama-3.3-70b-versatile"); m.Pricing.InputPerMillion == 0.5 {
+		t.Error("override leaked into Default()")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.json")
+	data := `{"providers":[{"id":"groq","capabilities":{"tools":true},"models":[
+		{"name":"custom-model","display_name":"Custom","context":8192,"max_output":1024,
+		 "pricing":{"input_per_million":1,"output_per_million":2}}]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := Default()
+	if err := c.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	m, ok := c.Get("groq/custom-model")
+	if !ok || !m.Capabilities.Tools || m.Pricing.OutputPerMillion != 2 {
+		t.Errorf("loaded model = %+v", m)
+	}
+
+	if err := c.LoadJSON([]byte(`{"providers":[{"models":[{"name":"x"}]}]}`)); err == nil {
+		t.Error("expected error for provider without id")
+	}
+}
+
+func TestCost(t *testing.T) {
+	c := New(
+		Model{ID: "anthropic/claude-test", Pricing: &ModelPricing{InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75}},
+		Model{ID: "openai/gpt-test", Pricing: &ModelPricing{InputPerMillion: 2, OutputPerMillion: 8, CacheReadPerMillion: 0.5}},
+		Model{ID: "local/free"},
+	)
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name  string
+		model string
+		usage types.Usage
+		want  float64
+	}{
+		{
+			name:  "input and output",
+			model: "anthropic/claude-test",
+			usage: types.Usage{InputTokens: 1000, OutputTokens: 500},
+			want:  (1000*3 + 500*15) / 1e6,
+		},
+		{
+			// Anthropic reports cache tokens separately from input tokens
+			name:  "separate cache counts",
+			model: "anthropic/claude-test-20250101",
+			usage: types.Usage{InputTokens: 100, OutputTokens: 10, CacheReadTokens: intPtr(2000), CacheWriteTokens: intPtr(400)},
+			want:  (100*3 + 10*15 + 2000*0.3 + 400*3.75) / 1e6,
+		},
+		{
+			// OpenAI includes cached tokens in the input count
+			name:  "inclusive cache counts",
+			model: "openai/gpt-test",
+			usage: types.Usage{InputTokens: 1000, OutputTokens: 100, CacheReadTokens: intPtr(800)},
+			want:  (200*2 + 100*8 + 800*0.5) / 1e6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.Cost(tt.model, tt.usage)
+			if !ok {
+				t.Fatal("Cost() found no pricing")
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := c.Cost("local/free", types.Usage{InputTokens: 10}); ok {
+		t.Error("expected no pricing for a model without prices")
+	}
+	if _, ok := c.Cost("unknown/model", types.Usage{InputTokens: 10}); ok {
+		t.Error("expected no pricing for an unknown model")
+	}
+}
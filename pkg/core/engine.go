@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/vango-go/vai/pkg/core/types"
 )
@@ -14,6 +15,12 @@ import (
 type Engine struct {
 	registry     ProviderRegistry
 	providerKeys map[string]string
+
+	mu         sync.RWMutex
+	routes     map[string][]string // logical model name -> ordered "provider/model" targets
+	middleware []Middleware
+	capMode    CapabilityMode
+	pricing    PricingTable
 }
 
 // NewEngine creates a new Engine with the given provider keys.
@@ -25,6 +32,8 @@ func NewEngine(providerKeys map[string]string) *Engine {
 	return &Engine{
 		registry:     NewProviderRegistry(),
 		providerKeys: providerKeys,
+		routes:       make(map[string][]string),
+		capMode:      CapabilityStrict,
 	}
 }
 
@@ -38,42 +47,149 @@ func (e *Engine) GetProvider(name string) (Provider, bool) {
 	return e.registry.Get(name)
 }
 
-// CreateMessage routes the request to the appropriate provider.
+// SetCapabilityMode sets how requests using features the provider does not
+// support are handled. The default is CapabilityStrict.
+func (e *Engine) SetCapabilityMode(mode CapabilityMode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.capMode = mode
+}
+
+// CapabilityMode returns the current capability mode.
+func (e *Engine) CapabilityMode() CapabilityMode {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.capMode
+}
+
+// Use appends middleware to the engine. Middleware runs in the order it was
+// added, around every CreateMessage and StreamMessage call.
+func (e *Engine) Use(middleware ...Middleware) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.middleware = append(e.middleware, middleware...)
+}
+
+// Middleware returns the registered middleware in order.
+func (e *Engine) Middleware() []Middleware {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Middleware(nil), e.middleware...)
+}
+
+// CreateMessage runs the middleware chain and routes the request to the appropriate provider.
+// If req.Model names a model route, targets are tried in order.
 func (e *Engine) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
-	providerName, modelName, err := ParseModelString(req.Model)
+	return ChainCreateMessage(e.dispatchCreateMessage, e.Middleware()...)(ctx, req)
+}
+
+// StreamMessage runs the middleware chain and routes the streaming request to the appropriate provider.
+// If req.Model names a model route, targets are tried in order.
+func (e *Engine) StreamMessage(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
+	return ChainStreamMessage(e.dispatchStreamMessage, e.Middleware()...)(ctx, req)
+}
+
+// dispatchCreateMessage sends a non-streaming request to its route or provider.
+func (e *Engine) dispatchCreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	if targets, ok := e.route(req.Model); ok {
+		return e.createMessageRouted(ctx, req, req.Model, targets)
+	}
+	return e.createMessage(ctx, req, req.Model)
+}
+
+// dispatchStreamMessage opens a stream on its route or provider.
+func (e *Engine) dispatchStreamMessage(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
+	if targets, ok := e.route(req.Model); ok {
+		return e.streamMessageRouted(ctx, req, req.Model, targets)
+	}
+	return e.streamMessage(ctx, req, req.Model)
+}
+
+// createMessage sends the request to the provider named in model.
+func (e *Engine) createMessage(ctx context.Context, req *types.MessageRequest, model string) (*types.MessageResponse, error) {
+	provider, providerReq, degradations, err := e.prepareRequest(req, model)
 	if err != nil {
 		return nil, err
 	}
 
-	provider, ok := e.registry.Get(providerName)
-	if !ok {
-		return nil, NewProviderError(providerName, fmt.Errorf("provider not registered"))
+	resp, err := provider.CreateMessage(ctx, providerReq)
+	if err != nil {
+		return nil, err
 	}
+	if len(degradations) > 0 {
+		if resp.Metadata == nil {
+			resp.Metadata = make(map[string]any)
+		}
+		resp.Metadata[MetadataDegradations] = degradations
+	}
+	e.priceUsage(&resp.Usage, model, resp.Model)
+	return resp, nil
+}
 
-	// Create a copy of the request with just the model name
-	reqCopy := *req
-	reqCopy.Model = modelName
+// streamMessage opens a stream on the provider named in model.
+func (e *Engine) streamMessage(ctx context.Context, req *types.MessageRequest, model string) (EventStream, error) {
+	provider, providerReq, degradations, err := e.prepareRequest(req, model)
+	if err != nil {
+		return nil, err
+	}
 
-	return provider.CreateMessage(ctx, &reqCopy)
+	stream, err := provider.StreamMessage(ctx, providerReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(degradations) > 0 {
+		stream = MapEventStream(stream, func(event types.StreamEvent) (types.StreamEvent, error) {
+			if start, ok := event.(types.MessageStartEvent); ok {
+				if start.Message.Metadata == nil {
+					start.Message.Metadata = make(map[string]any)
+				}
+				start.Message.Metadata[MetadataDegradations] = degradations
+				return start, nil
+			}
+			return event, nil
+		})
+	}
+	return e.pricedEventStream(stream, model), nil
 }
 
-// StreamMessage routes the streaming request to the appropriate provider.
-func (e *Engine) StreamMessage(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
-	providerName, modelName, err := ParseModelString(req.Model)
+// prepareRequest resolves the provider for model and builds the request it
+// receives, applying the capability mode.
+func (e *Engine) prepareRequest(req *types.MessageRequest, model string) (Provider, *types.MessageRequest, []Degradation, error) {
+	provider, modelName, err := e.resolveProvider(model)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	provider, ok := e.registry.Get(providerName)
-	if !ok {
-		return nil, NewProviderError(providerName, fmt.Errorf("provider not registered"))
+	var degradations []Degradation
+	switch e.CapabilityMode() {
+	case CapabilityDegrade:
+		req, degradations = DegradeRequest(provider.Capabilities(), req)
+	case CapabilityOff:
+	default:
+		if err := ValidateRequest(provider.Name(), provider.Capabilities(), req); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	// Create a copy of the request with just the model name
 	reqCopy := *req
 	reqCopy.Model = modelName
 
-	return provider.StreamMessage(ctx, &reqCopy)
+	return provider, &reqCopy, degradations, nil
+}
+
+// resolveProvider parses a "provider/model" string and looks up the provider.
+func (e *Engine) resolveProvider(model string) (Provider, string, error) {
+	providerName, modelName, err := ParseModelString(model)
+	if err != nil {
+		return nil, "", err
+	}
+
+	provider, ok := e.registry.Get(providerName)
+	if !ok {
+		return nil, "", NewProviderError(providerName, fmt.Errorf("provider not registered"))
+	}
+	return provider, modelName, nil
 }
 
 // GetAPIKey returns the API key for a provider.
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"time"
 )
 
 // Error represents an API error.
@@ -129,3 +131,34 @@ func (e *Error) Unwrap() error {
 	}
 	return nil
 }
+
+// retryableError is implemented by provider-specific error types.
+type retryableError interface {
+	IsRetryable() bool
+}
+
+// IsRetryableError reports whether err is worth retrying.
+// It understands *Error as well as provider errors that expose IsRetryable.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var coreErr *Error
+	if errors.As(err, &coreErr) {
+		return coreErr.IsRetryable()
+	}
+	var re retryableError
+	if errors.As(err, &re) {
+		return re.IsRetryable()
+	}
+	return false
+}
+
+// RetryAfterFromError returns the server-suggested delay before retrying, if any.
+func RetryAfterFromError(err error) (time.Duration, bool) {
+	var coreErr *Error
+	if errors.As(err, &coreErr) && coreErr.RetryAfter != nil && *coreErr.RetryAfter >= 0 {
+		return time.Duration(*coreErr.RetryAfter) * time.Second, true
+	}
+	return 0, false
+}
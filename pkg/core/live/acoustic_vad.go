@@ -0,0 +1,145 @@
+package live
+
+import "sync"
+
+// AcousticVAD detects the start and end of speech in 16-bit PCM audio.
+// Audio is split into fixed-size frames, each classified by RMS energy and
+// zero-crossing rate. Speech starts after MinSpeechMs of consecutive speech
+// frames and ends after HangoverMs of consecutive silence, so short pauses
+// between words do not end the utterance.
+type AcousticVAD struct {
+	config          AcousticVADConfig
+	energyThreshold float64
+	audioConfig     AudioConfig
+	frameBytes      int
+	startFrames     int
+	hangoverFrames  int
+
+	mu            sync.Mutex
+	pending       []byte
+	speaking      bool
+	speechFrames  int
+	silenceFrames int
+
+	onSpeechStart func()
+	onSpeechEnd   func(silenceMs int)
+}
+
+// NewAcousticVAD creates an acoustic VAD. Frames with RMS energy below
+// energyThreshold are silence.
+func NewAcousticVAD(config AcousticVADConfig, energyThreshold float64, audioConfig AudioConfig) *AcousticVAD {
+	config = config.withDefaults()
+	frameBytes := audioConfig.BytesForDurationMs(config.FrameMs)
+	if frameBytes < 4 {
+		frameBytes = 4
+	}
+	return &AcousticVAD{
+		config:          config,
+		energyThreshold: energyThreshold,
+		audioConfig:     audioConfig,
+		frameBytes:      frameBytes,
+		startFrames:     framesFor(config.MinSpeechMs, config.FrameMs),
+		hangoverFrames:  framesFor(config.HangoverMs, config.FrameMs),
+	}
+}
+
+// framesFor returns the number of frames covering durationMs, at least one.
+func framesFor(durationMs, frameMs int) int {
+	n := (durationMs + frameMs - 1) / frameMs
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// SetCallbacks sets the speech transition callbacks. They are called
+// synchronously from Process.
+func (a *AcousticVAD) SetCallbacks(onSpeechStart func(), onSpeechEnd func(silenceMs int)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onSpeechStart = onSpeechStart
+	a.onSpeechEnd = onSpeechEnd
+}
+
+// Process analyzes a chunk of audio. Partial frames are kept until the next call.
+func (a *AcousticVAD) Process(pcm []byte) {
+	a.mu.Lock()
+	a.pending = append(a.pending, pcm...)
+
+	var transitions []func()
+	for len(a.pending) >= a.frameBytes {
+		frame := a.pending[:a.frameBytes]
+		a.pending = a.pending[a.frameBytes:]
+		if t := a.processFrame(frame); t != nil {
+			transitions = append(transitions, t)
+		}
+	}
+	// Keep the leftover from growing the backing array indefinitely
+	a.pending = append([]byte(nil), a.pending...)
+	a.mu.Unlock()
+
+	for _, t := range transitions {
+		t()
+	}
+}
+
+// processFrame updates the speech state for one frame and returns the
+// callback for a transition, if any. Must be called with the mutex held.
+func (a *AcousticVAD) processFrame(frame []byte) func() {
+	if a.IsSpeechFrame(frame) {
+		a.silenceFrames = 0
+		a.speechFrames++
+		if !a.speaking && a.speechFrames >= a.startFrames {
+			a.speaking = true
+			if cb := a.onSpeechStart; cb != nil {
+				return cb
+			}
+		}
+		return nil
+	}
+
+	a.speechFrames = 0
+	if !a.speaking {
+		return nil
+	}
+	a.silenceFrames++
+	if a.silenceFrames >= a.hangoverFrames {
+		a.speaking = false
+		silenceMs := a.silenceFrames * a.config.FrameMs
+		a.silenceFrames = 0
+		if cb := a.onSpeechEnd; cb != nil {
+			return func() { cb(silenceMs) }
+		}
+	}
+	return nil
+}
+
+// IsSpeechFrame classifies a single frame of PCM audio.
+func (a *AcousticVAD) IsSpeechFrame(frame []byte) bool {
+	energy := CalculateRMSEnergy(frame)
+	if energy < a.energyThreshold {
+		return false
+	}
+	// Loud frames are speech even when fricatives push the crossing rate up
+	if energy >= 3*a.energyThreshold {
+		return true
+	}
+	return CalculateZeroCrossingRate(frame) <= a.config.MaxZeroCrossingRate
+}
+
+// IsSpeaking reports whether an utterance is in progress.
+func (a *AcousticVAD) IsSpeaking() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.speaking
+}
+
+// Reset clears the speech state and any buffered partial frame.
+func (a *AcousticVAD) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pending = nil
+	a.speaking = false
+	a.speechFrames = 0
+	a.silenceFrames = 0
+}
@@ -0,0 +1,272 @@
+package live
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tonePCM generates durationMs of a sine tone as 16-bit PCM.
+func tonePCM(config AudioConfig, durationMs int, freq, amplitude float64) []byte {
+	n := config.SampleRate * durationMs / 1000
+	pcm := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		s := int16(amplitude * 32767 * math.Sin(2*math.Pi*freq*float64(i)/float64(config.SampleRate)))
+		pcm[i*2] = byte(s)
+		pcm[i*2+1] = byte(s >> 8)
+	}
+	return pcm
+}
+
+// noisePCM generates durationMs of uniform white noise as 16-bit PCM.
+func noisePCM(config AudioConfig, durationMs int, amplitude float64) []byte {
+	rng := rand.New(rand.NewSource(1))
+	n := config.SampleRate * durationMs / 1000
+	pcm := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		s := int16(amplitude * 32767 * (2*rng.Float64() - 1))
+		pcm[i*2] = byte(s)
+		pcm[i*2+1] = byte(s >> 8)
+	}
+	return pcm
+}
+
+func TestCalculateZeroCrossingRate(t *testing.T) {
+	config := DefaultAudioConfig()
+
+	if zcr := CalculateZeroCrossingRate(make([]byte, 960)); zcr != 0 {
+		t.Errorf("silence: expected 0, got %.3f", zcr)
+	}
+
+	// A 200 Hz tone crosses zero 400 times per second
+	want := 400.0 / float64(config.SampleRate)
+	if zcr := CalculateZeroCrossingRate(tonePCM(config, 100, 200, 0.3)); math.Abs(zcr-want) > 0.002 {
+		t.Errorf("200 Hz tone: expected %.4f, got %.4f", want, zcr)
+	}
+
+	// White noise changes sign about half the time
+	if zcr := CalculateZeroCrossingRate(noisePCM(config, 100, 0.05)); zcr < 0.4 {
+		t.Errorf("white noise: expected rate near 0.5, got %.3f", zcr)
+	}
+}
+
+type speechRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *speechRecorder) start()            { r.add("start") }
+func (r *speechRecorder) end(silenceMs int) { r.add("end") }
+
+func (r *speechRecorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *speechRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func TestAcousticVAD_SpeechStartAndEnd(t *testing.T) {
+	config := DefaultAudioConfig()
+	vad := NewAcousticVAD(AcousticVADConfig{Enabled: true}, 0.02, config)
+	rec := &speechRecorder{}
+	vad.SetCallbacks(rec.start, rec.end)
+
+	// Feed in odd-sized chunks to exercise partial frame handling
+	feed := func(pcm []byte) {
+		for len(pcm) > 0 {
+			n := min(len(pcm), 1234)
+			vad.Process(pcm[:n])
+			pcm = pcm[n:]
+		}
+	}
+
+	feed(make([]byte, config.BytesForDurationMs(200)))
+	if vad.IsSpeaking() {
+		t.Fatal("silence detected as speech")
+	}
+
+	feed(tonePCM(config, 300, 200, 0.3))
+	if !vad.IsSpeaking() {
+		t.Fatal("expected speech after 300ms of tone")
+	}
+
+	// A pause shorter than the hangover does not end the utterance
+	feed(make([]byte, config.BytesForDurationMs(200)))
+	feed(tonePCM(config, 200, 200, 0.3))
+	if !vad.IsSpeaking() {
+		t.Fatal("short pause ended the utterance")
+	}
+
+	feed(make([]byte, config.BytesForDurationMs(600)))
+	if vad.IsSpeaking() {
+		t.Fatal("expected speech to end after the hangover")
+	}
+
+	got := rec.list()
+	if len(got) != 2 || got[0] != "start" || got[1] != "end" {
+		t.Errorf("expected [start end], got %v", got)
+	}
+}
+
+func TestAcousticVAD_IgnoresNoise(t *testing.T) {
+	config := DefaultAudioConfig()
+	vad := NewAcousticVAD(AcousticVADConfig{Enabled: true}, 0.02, config)
+
+	// Hiss above the energy threshold but with a high crossing rate
+	noise := noisePCM(config, 500, 0.05)
+	if energy := CalculateRMSEnergy(noise); energy < 0.02 {
+		t.Fatalf("test noise too quiet: %.3f", energy)
+	}
+	vad.Process(noise)
+	if vad.IsSpeaking() {
+		t.Error("broadband noise detected as speech")
+	}
+
+	// Short clicks do not reach MinSpeechMs
+	vad.Process(tonePCM(config, 20, 200, 0.3))
+	vad.Process(make([]byte, config.BytesForDurationMs(40)))
+	if vad.IsSpeaking() {
+		t.Error("20ms click detected as speech")
+	}
+}
+
+func TestHybridVAD_AcousticEndOfSpeech(t *testing.T) {
+	config := VADConfig{
+		PunctuationTrigger:  ".!?",
+		NoActivityTimeoutMs: 3000,
+		SemanticCheck:       true,
+		MinWordsForCheck:    1,
+		EnergyThreshold:     0.02,
+		Acoustic:            AcousticVADConfig{Enabled: true, TranscriptSettleMs: 50},
+	}
+	audioConfig := DefaultAudioConfig()
+	checker := &mockSemanticChecker{response: true}
+	vad := NewHybridVAD(config, audioConfig, checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vad.Start(ctx)
+	defer vad.Stop()
+
+	commits := make(chan bool, 1)
+	silences := make(chan int, 1)
+	vad.SetCallbacks(
+		func(durationMs int) { silences <- durationMs },
+		nil,
+		func(transcript string, forced bool) { commits <- forced },
+		nil,
+	)
+
+	vad.ProcessAudio(tonePCM(audioConfig, 300, 200, 0.3))
+	vad.AddTranscript("book a table for two")
+	vad.ProcessAudio(make([]byte, audioConfig.BytesForDurationMs(500)))
+
+	select {
+	case forced := <-commits:
+		if forced {
+			t.Error("acoustic end of speech should not be a forced commit")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected commit well before NoActivityTimeoutMs")
+	}
+	if !checker.wasCalled() {
+		t.Error("expected semantic check on end of speech")
+	}
+
+	select {
+	case ms := <-silences:
+		if ms < 400 {
+			t.Errorf("expected silence of at least the hangover, got %dms", ms)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected onSilence callback")
+	}
+}
+
+func TestHybridVAD_AcousticSpeechSuppressesTimeout(t *testing.T) {
+	config := VADConfig{
+		PunctuationTrigger:  ".!?",
+		NoActivityTimeoutMs: 100,
+		SemanticCheck:       false,
+		MinWordsForCheck:    1,
+		EnergyThreshold:     0.02,
+		Acoustic:            AcousticVADConfig{Enabled: true},
+	}
+	audioConfig := DefaultAudioConfig()
+	vad := NewHybridVAD(config, audioConfig, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vad.Start(ctx)
+	defer vad.Stop()
+
+	commits := make(chan string, 1)
+	vad.SetCallbacks(nil, nil, func(transcript string, forced bool) { commits <- transcript }, nil)
+
+	// The user keeps talking long after the transcript stopped updating
+	vad.ProcessAudio(tonePCM(audioConfig, 100, 200, 0.3))
+	vad.AddTranscript("so what I was thinking")
+	time.Sleep(400 * time.Millisecond)
+
+	select {
+	case <-commits:
+		t.Fatal("committed while the user was still speaking")
+	default:
+	}
+
+	vad.ProcessAudio(make([]byte, audioConfig.BytesForDurationMs(500)))
+	select {
+	case transcript := <-commits:
+		if transcript != "so what I was thinking" {
+			t.Errorf("unexpected transcript %q", transcript)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected commit after speech ended")
+	}
+}
+
+func TestHybridVAD_EndOfSpeechDoesNotBlockAudio(t *testing.T) {
+	config := VADConfig{
+		PunctuationTrigger:  ".!?",
+		NoActivityTimeoutMs: 3000,
+		SemanticCheck:       true,
+		MinWordsForCheck:    1,
+		EnergyThreshold:     0.02,
+		Acoustic:            AcousticVADConfig{Enabled: true, TranscriptSettleMs: 1},
+	}
+	audioConfig := DefaultAudioConfig()
+	checker := &mockSemanticChecker{response: true, delay: 500 * time.Millisecond}
+	vad := NewHybridVAD(config, audioConfig, checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vad.Start(ctx)
+	defer vad.Stop()
+
+	commits := make(chan bool, 1)
+	vad.SetCallbacks(nil, nil, func(transcript string, forced bool) { commits <- forced }, nil)
+
+	vad.ProcessAudio(tonePCM(audioConfig, 300, 200, 0.3))
+	vad.AddTranscript("book a table for two")
+	time.Sleep(10 * time.Millisecond)
+
+	start := time.Now()
+	vad.ProcessAudio(make([]byte, audioConfig.BytesForDurationMs(500)))
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("ProcessAudio() took %v, want it not to wait for the semantic check", elapsed)
+	}
+
+	select {
+	case <-commits:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected commit after the semantic check")
+	}
+}
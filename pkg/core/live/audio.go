@@ -46,6 +46,28 @@ func CalculatePeakAmplitude(pcm []byte) float64 {
 	return maxAbs / 32768.0
 }
 
+// CalculateZeroCrossingRate returns the fraction of adjacent 16-bit PCM samples
+// that change sign. Voiced speech has a low rate; hiss and broadband noise a
+// high one. Returns a value between 0.0 and 1.0.
+func CalculateZeroCrossingRate(pcm []byte) float64 {
+	samples := len(pcm) / 2
+	if samples < 2 {
+		return 0
+	}
+
+	crossings := 0
+	prev := int16(pcm[0]) | int16(pcm[1])<<8
+	for i := 2; i < len(pcm)-1; i += 2 {
+		sample := int16(pcm[i]) | int16(pcm[i+1])<<8
+		if (prev >= 0) != (sample >= 0) {
+			crossings++
+		}
+		prev = sample
+	}
+
+	return float64(crossings) / float64(samples-1)
+}
+
 // AudioBuffer accumulates PCM audio chunks with a configurable maximum size.
 type AudioBuffer struct {
 	mu       sync.Mutex
@@ -139,6 +161,13 @@ func (b *AudioBuffer) PeakAmplitude() float64 {
 	return CalculatePeakAmplitude(b.data)
 }
 
+// ZeroCrossingRate returns the zero-crossing rate of the buffered audio.
+func (b *AudioBuffer) ZeroCrossingRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return CalculateZeroCrossingRate(b.data)
+}
+
 // RingBuffer is a fixed-size circular buffer for audio data.
 // It automatically overwrites old data when full.
 type RingBuffer struct {
@@ -3,6 +3,8 @@ package live
 import (
 	"math"
 	"testing"
+
+	"github.com/vango-go/vai/pkg/core/audio"
 )
 
 func TestCalculateRMSEnergy(t *testing.T) {
@@ -179,3 +181,39 @@ func TestRingBuffer(t *testing.T) {
 		t.Errorf("expected 0 filled after clear, got %d", ring.Filled())
 	}
 }
+
+func TestSession_SendAudioConvertsInputFormat(t *testing.T) {
+	ulaw := audio.Format{Encoding: audio.EncodingMulaw, SampleRate: 8000, Channels: 1}
+	s := newToolTestSession(t, SessionConfig{SampleRate: 16000, InputFormat: ulaw}, nil, nil)
+
+	// 20ms of μ-law becomes 20ms of 16kHz 16-bit PCM
+	if err := s.SendAudio(make([]byte, 160)); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := len(<-s.audio); got < 620 || got > 640 {
+		t.Errorf("converted chunk has %d bytes, want about 640", got)
+	}
+
+	// WAV input is converted using its header
+	float := audio.Format{Encoding: audio.EncodingFloat32, SampleRate: 48000, Channels: 2}
+	wav, err := audio.EncodeWAV(make([]byte, float.BytesForDurationMs(20)), float)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendAudio(wav); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := len(<-s.audio); got != 640 {
+		t.Errorf("converted WAV has %d bytes, want 640", got)
+	}
+}
+
+func TestSession_InvalidInputFormat(t *testing.T) {
+	s := NewSession(SessionConfig{
+		VAD:         DefaultVADConfig(),
+		InputFormat: audio.Format{Encoding: "opus"},
+	}, nil, nil, nil)
+	if err := s.initComponents(); err == nil {
+		t.Error("initComponents() accepted an unsupported input encoding")
+	}
+}
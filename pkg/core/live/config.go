@@ -1,7 +1,10 @@
 package live
 
 import (
+	"github.com/vango-go/vai/pkg/core/audio"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 )
 
 // SessionState represents the current state of the live session.
@@ -57,6 +60,13 @@ type SessionConfig struct {
 	// Tools are the available tools for the agent.
 	Tools []types.Tool `json:"tools,omitempty"`
 
+	// ToolHandlers execute the agent's tool calls by tool name.
+	// Results are fed back to the model and the response continues.
+	ToolHandlers map[string]ToolHandler `json:"-"`
+
+	// MaxToolRounds limits tool call rounds per response. Default: 5.
+	MaxToolRounds int `json:"max_tool_rounds,omitempty"`
+
 	// Messages are any pre-existing conversation history.
 	Messages []types.Message `json:"messages,omitempty"`
 
@@ -78,11 +88,38 @@ type SessionConfig struct {
 	// Channels is the number of audio channels. Default: 1 (mono).
 	Channels int `json:"channels"`
 
+	// InputFormat is the format of raw audio passed to SendAudio, such as
+	// 48kHz float from a browser or 8kHz μ-law from a phone line. Audio is
+	// converted to 16-bit PCM at SampleRate and Channels. Zero fields default
+	// to the session format, so the zero value means no conversion.
+	// WAV data is always converted using its own header.
+	InputFormat audio.Format `json:"input_format,omitempty"`
+
 	// MaxTokens is the maximum tokens for LLM responses.
 	MaxTokens int `json:"max_tokens,omitempty"`
 
 	// Temperature controls LLM response randomness.
 	Temperature *float64 `json:"temperature,omitempty"`
+
+	// Chunking controls how response text is split up for TTS. It takes
+	// precedence over Voice.Output.Chunking. Default: DefaultTTSChunking.
+	Chunking *types.TextChunking `json:"chunking,omitempty"`
+
+	// Chunker, if set, creates the chunker for each response in place of
+	// the built-in strategies.
+	Chunker func() chunk.Chunker `json:"-"`
+
+	// Normalizer, if set, creates the normalizer that rewrites each
+	// response for speech. By default it is built from Voice.Output.Normalization.
+	Normalizer func() normalize.Normalizer `json:"-"`
+
+	// Fillers are spoken while the agent waits on a slow model or tool. They
+	// take precedence over Voice.Output.Fillers. Off unless configured.
+	Fillers *types.FillerConfig `json:"fillers,omitempty"`
+
+	// Recorder, if set, records the session's audio, events and LLM calls.
+	// It is closed when the session closes.
+	Recorder Recorder `json:"-"`
 }
 
 // DefaultSessionConfig returns a SessionConfig with sensible defaults.
@@ -133,6 +170,80 @@ type VADConfig struct {
 	// PrefixPadding is audio to keep before speech detection (for context).
 	// Default: 300ms
 	PrefixPaddingMs int `json:"prefix_padding_ms"`
+
+	// TurnCompletePrompts override the semantic turn check prompt by
+	// language, keyed by code ("es") or tag ("es-MX"). A prompt has %s for
+	// the transcript and asks for YES or NO. Built-in prompts cover en, es,
+	// de and fr; other languages use the English prompt.
+	TurnCompletePrompts map[string]string `json:"turn_complete_prompts,omitempty"`
+
+	// Acoustic configures local speech/silence detection on the input audio.
+	// When enabled, the end of speech triggers the turn check without waiting
+	// for NoActivityTimeoutMs, and timeouts are suppressed while the user is
+	// still speaking. Disabled by default.
+	Acoustic AcousticVADConfig `json:"acoustic"`
+}
+
+// AcousticVADConfig configures the frame-based acoustic VAD.
+// A frame is speech when its RMS energy reaches VADConfig.EnergyThreshold
+// and its zero-crossing rate is low enough to rule out broadband noise.
+// Zero values use the defaults below.
+type AcousticVADConfig struct {
+	// Enabled turns on acoustic speech detection.
+	Enabled bool `json:"enabled"`
+
+	// FrameMs is the analysis frame length. Default: 20
+	FrameMs int `json:"frame_ms,omitempty"`
+
+	// MinSpeechMs is how much consecutive speech starts an utterance.
+	// Default: 60
+	MinSpeechMs int `json:"min_speech_ms,omitempty"`
+
+	// HangoverMs is how much consecutive silence ends an utterance.
+	// Short pauses between words are bridged by the hangover. Default: 400
+	HangoverMs int `json:"hangover_ms,omitempty"`
+
+	// MaxZeroCrossingRate is the highest zero-crossing rate (crossings per
+	// sample, 0.0 to 1.0) accepted as speech. Frames louder than three times
+	// the energy threshold count as speech regardless. Default: 0.35
+	MaxZeroCrossingRate float64 `json:"max_zero_crossing_rate,omitempty"`
+
+	// TranscriptSettleMs is how long to wait for late STT results after the
+	// end of speech before checking the turn. Default: 250
+	TranscriptSettleMs int `json:"transcript_settle_ms,omitempty"`
+}
+
+// DefaultAcousticVADConfig returns an enabled AcousticVADConfig with sensible defaults.
+func DefaultAcousticVADConfig() AcousticVADConfig {
+	return AcousticVADConfig{
+		Enabled:             true,
+		FrameMs:             20,
+		MinSpeechMs:         60,
+		HangoverMs:          400,
+		MaxZeroCrossingRate: 0.35,
+		TranscriptSettleMs:  250,
+	}
+}
+
+// withDefaults fills in zero fields from DefaultAcousticVADConfig.
+func (c AcousticVADConfig) withDefaults() AcousticVADConfig {
+	d := DefaultAcousticVADConfig()
+	if c.FrameMs <= 0 {
+		c.FrameMs = d.FrameMs
+	}
+	if c.MinSpeechMs <= 0 {
+		c.MinSpeechMs = d.MinSpeechMs
+	}
+	if c.HangoverMs <= 0 {
+		c.HangoverMs = d.HangoverMs
+	}
+	if c.MaxZeroCrossingRate <= 0 {
+		c.MaxZeroCrossingRate = d.MaxZeroCrossingRate
+	}
+	if c.TranscriptSettleMs <= 0 {
+		c.TranscriptSettleMs = d.TranscriptSettleMs
+	}
+	return c
 }
 
 // DefaultVADConfig returns a VADConfig with sensible defaults.
@@ -182,6 +293,7 @@ const (
 )
 
 // PartialSaveMode specifies how partial assistant messages are handled on interrupt.
+// Saved messages are cut at the last word the user heard.
 type PartialSaveMode string
 
 const (
@@ -221,6 +333,76 @@ type InterruptConfig struct {
 	// SavePartial controls how partial assistant messages are saved on interrupt.
 	// Default: "marked"
 	SavePartial PartialSaveMode `json:"save_partial"`
+
+	// Backchannels add to the built-in backchannels of a language, keyed
+	// by code ("de") or tag ("de-AT"), or "*" for every language. A
+	// transcript that is a backchannel, ignoring case and punctuation,
+	// never interrupts. See DefaultBackchannels.
+	Backchannels map[string][]string `json:"backchannels,omitempty"`
+
+	// InterruptCheckPrompts override the semantic interrupt check prompt
+	// by language, keyed by code or tag. A prompt has %s for the
+	// transcript and asks for INTERRUPT or BACKCHANNEL.
+	InterruptCheckPrompts map[string]string `json:"interrupt_check_prompts,omitempty"`
+
+	// EchoSuppression removes the agent's own voice from the input when it
+	// leaks from the client's speaker back into the microphone, so that it
+	// neither triggers interrupts nor reaches STT. Disabled by default.
+	EchoSuppression EchoSuppressionConfig `json:"echo_suppression"`
+}
+
+// EchoSuppressionConfig configures echo suppression and double-talk
+// detection. The audio sent to the client is the reference: input frames no
+// louder than the echo expected from it are silenced, and louder ones are
+// the user talking over the agent. Zero values use the defaults below.
+type EchoSuppressionConfig struct {
+	// Enabled turns on echo suppression. It needs PCM output audio.
+	Enabled bool `json:"enabled"`
+
+	// FrameMs is the analysis frame length. Default: 10
+	FrameMs int `json:"frame_ms,omitempty"`
+
+	// MaxDelayMs is the longest time from the agent's audio playing to its
+	// echo reaching the session, including network and client buffering.
+	// Default: 500
+	MaxDelayMs int `json:"max_delay_ms,omitempty"`
+
+	// DoubleTalkRatio is how many times louder than the expected echo a
+	// frame must be to count as the user's speech. Default: 2.0 (6 dB)
+	DoubleTalkRatio float64 `json:"double_talk_ratio,omitempty"`
+
+	// HangoverMs is how long input keeps passing after the user's speech
+	// was last detected, so that quieter syllables are not cut. Default: 200
+	HangoverMs int `json:"hangover_ms,omitempty"`
+}
+
+// DefaultEchoSuppressionConfig returns an enabled EchoSuppressionConfig with sensible defaults.
+func DefaultEchoSuppressionConfig() EchoSuppressionConfig {
+	return EchoSuppressionConfig{
+		Enabled:         true,
+		FrameMs:         10,
+		MaxDelayMs:      500,
+		DoubleTalkRatio: 2.0,
+		HangoverMs:      200,
+	}
+}
+
+// withDefaults fills in zero fields from DefaultEchoSuppressionConfig.
+func (c EchoSuppressionConfig) withDefaults() EchoSuppressionConfig {
+	d := DefaultEchoSuppressionConfig()
+	if c.FrameMs <= 0 {
+		c.FrameMs = d.FrameMs
+	}
+	if c.MaxDelayMs <= 0 {
+		c.MaxDelayMs = d.MaxDelayMs
+	}
+	if c.DoubleTalkRatio <= 0 {
+		c.DoubleTalkRatio = d.DoubleTalkRatio
+	}
+	if c.HangoverMs <= 0 {
+		c.HangoverMs = d.HangoverMs
+	}
+	return c
 }
 
 // DefaultInterruptConfig returns an InterruptConfig with sensible defaults.
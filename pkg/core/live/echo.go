@@ -0,0 +1,198 @@
+package live
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// echoFloor is the RMS energy below which reference or input audio is
+	// too quiet to matter.
+	echoFloor = 0.003
+
+	// initialEchoCoupling is the echo level, relative to the audio sent,
+	// assumed until it has been measured: no louder than the audio itself.
+	initialEchoCoupling = 1.0
+
+	// minEchoCoupling bounds the measured echo level at -40 dB.
+	minEchoCoupling = 0.01
+
+	// echoAdaptRate is how quickly the echo level follows each measurement.
+	echoAdaptRate = 0.1
+)
+
+// EchoSuppressor removes the agent's own voice from 16-bit PCM input when
+// it leaks from the client's speaker back into the microphone.
+//
+// The audio sent to the client is the reference, laid out on the timeline
+// it plays on. The echo of a frame arrives after an unknown delay of up to
+// MaxDelayMs, so each input frame is compared with the loudest reference
+// played within that window. An input frame louder than DoubleTalkRatio
+// times the expected echo is near-end speech, and passes along with
+// HangoverMs of input after it. Anything else is echo: the frame is
+// silenced, and its level refines the measured echo coupling.
+type EchoSuppressor struct {
+	config         EchoSuppressionConfig
+	audioConfig    AudioConfig
+	frameBytes     int
+	refFrameBytes  int
+	refRate        int // Reference bytes per second
+	maxDelay       time.Duration
+	hangoverFrames int
+	now            func() time.Time
+
+	mu       sync.Mutex
+	ref      []echoFrame // Reference levels in playing order
+	refEnd   time.Time   // When the reference sent so far finishes playing
+	coupling float64     // Echo level relative to the reference
+	hangover int         // Frames left to pass after near-end speech
+}
+
+// echoFrame is the level of a reference frame and when it plays.
+type echoFrame struct {
+	at    time.Time
+	level float64
+}
+
+// NewEchoSuppressor creates an echo suppressor for input in audioConfig,
+// with mono 16-bit PCM at referenceRate as the reference.
+func NewEchoSuppressor(config EchoSuppressionConfig, audioConfig AudioConfig, referenceRate int) *EchoSuppressor {
+	config = config.withDefaults()
+	block := max(audioConfig.Channels*audioConfig.BitsPerSample/8, 2)
+	frameBytes := max(audioConfig.BytesForDurationMs(config.FrameMs)/block*block, block)
+	refRate := referenceRate * 2
+	return &EchoSuppressor{
+		config:         config,
+		audioConfig:    audioConfig,
+		frameBytes:     frameBytes,
+		refFrameBytes:  max((refRate*config.FrameMs/1000)&^1, 2),
+		refRate:        refRate,
+		maxDelay:       time.Duration(config.MaxDelayMs) * time.Millisecond,
+		hangoverFrames: framesFor(config.HangoverMs, config.FrameMs),
+		now:            time.Now,
+		coupling:       initialEchoCoupling,
+	}
+}
+
+// AddReference adds audio sent to the client. It plays after the reference
+// already sent, or now if that is over.
+func (e *EchoSuppressor) AddReference(pcm []byte) {
+	if e == nil || e.refRate <= 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if now := e.now(); e.refEnd.Before(now) {
+		e.refEnd = now
+	}
+	for len(pcm) > 0 {
+		n := min(e.refFrameBytes, len(pcm))
+		e.ref = append(e.ref, echoFrame{at: e.refEnd, level: CalculateRMSEnergy(pcm[:n])})
+		e.refEnd = e.refEnd.Add(time.Duration(int64(n) * int64(time.Second) / int64(e.refRate)))
+		pcm = pcm[n:]
+	}
+}
+
+// Flush drops the reference that has yet to play, such as when the client
+// is told to discard its buffered audio.
+func (e *EchoSuppressor) Flush() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	i := sort.Search(len(e.ref), func(i int) bool { return e.ref[i].at.After(now) })
+	e.ref = e.ref[:i]
+	if e.refEnd.After(now) {
+		e.refEnd = now
+	}
+}
+
+// Process returns pcm with its echo frames silenced. The input is taken to
+// have just arrived. pcm is returned as is when nothing was suppressed, and
+// is never modified.
+func (e *EchoSuppressor) Process(pcm []byte) []byte {
+	if e == nil || len(pcm) == 0 {
+		return pcm
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	start := now.Add(-e.duration(len(pcm)))
+	e.prune(start.Add(-e.maxDelay))
+	if len(e.ref) == 0 || e.ref[0].at.After(now) {
+		// Nothing has played that could echo
+		e.hangover = 0
+		return pcm
+	}
+
+	out, copied := pcm, false
+	for off := 0; off < len(pcm); off += e.frameBytes {
+		end := min(off+e.frameBytes, len(pcm))
+		if !e.isEcho(pcm[off:end], e.level(start.Add(e.duration(end)))) {
+			continue
+		}
+		if !copied {
+			out, copied = slices.Clone(pcm), true
+		}
+		clear(out[off:end])
+	}
+	return out
+}
+
+// isEcho reports whether an input frame is echo of a reference at level
+// ref, detecting double talk and learning the echo coupling.
+func (e *EchoSuppressor) isEcho(frame []byte, ref float64) bool {
+	if ref < echoFloor {
+		return false
+	}
+	level := CalculateRMSEnergy(frame)
+	if level > e.config.DoubleTalkRatio*e.coupling*ref {
+		e.hangover = e.hangoverFrames
+		return false
+	}
+	if e.hangover > 0 {
+		e.hangover--
+		return false
+	}
+	// Silence, such as before the echo arrives, says nothing about the coupling
+	if level >= echoFloor {
+		e.coupling += echoAdaptRate * (level/ref - e.coupling)
+		e.coupling = min(max(e.coupling, minEchoCoupling), initialEchoCoupling)
+	}
+	return true
+}
+
+// level returns the loudest reference played in the MaxDelayMs up to t.
+func (e *EchoSuppressor) level(t time.Time) float64 {
+	from := t.Add(-e.maxDelay)
+	i := sort.Search(len(e.ref), func(i int) bool { return !e.ref[i].at.Before(from) })
+	var level float64
+	for ; i < len(e.ref) && !e.ref[i].at.After(t); i++ {
+		level = max(level, e.ref[i].level)
+	}
+	return level
+}
+
+// prune drops reference frames played before t.
+func (e *EchoSuppressor) prune(t time.Time) {
+	i := sort.Search(len(e.ref), func(i int) bool { return !e.ref[i].at.Before(t) })
+	if i > 0 {
+		e.ref = slices.Delete(e.ref, 0, i)
+	}
+}
+
+// duration returns how long n bytes of input last.
+func (e *EchoSuppressor) duration(n int) time.Duration {
+	bytesPerSecond := e.audioConfig.BytesPerSecond()
+	if bytesPerSecond == 0 {
+		return 0
+	}
+	return time.Duration(int64(n) * int64(time.Second) / int64(bytesPerSecond))
+}
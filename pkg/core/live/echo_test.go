@@ -0,0 +1,154 @@
+package live
+
+import (
+	"testing"
+	"time"
+)
+
+// mixPCM adds two 16-bit PCM signals of the same length.
+func mixPCM(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := 0; i+1 < len(a); i += 2 {
+		s := int16(a[i]) | int16(a[i+1])<<8
+		s += int16(b[i]) | int16(b[i+1])<<8
+		out[i] = byte(s)
+		out[i+1] = byte(s >> 8)
+	}
+	return out
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func newTestEchoSuppressor(clock *fakeClock) *EchoSuppressor {
+	config := DefaultAudioConfig()
+	e := NewEchoSuppressor(EchoSuppressionConfig{Enabled: true}, config, config.SampleRate)
+	e.now = clock.now
+	return e
+}
+
+func TestEchoSuppressor_SuppressesEcho(t *testing.T) {
+	config := DefaultAudioConfig()
+	clock := newFakeClock()
+	e := newTestEchoSuppressor(clock)
+
+	// The agent's voice, and its echo 100ms later at -8 dB
+	e.AddReference(tonePCM(config, 1000, 440, 0.3))
+	clock.advance(100)
+	for i := 0; i < 8; i++ {
+		clock.advance(100)
+		if energy := CalculateRMSEnergy(e.Process(tonePCM(config, 100, 440, 0.12))); energy > 0.01 {
+			t.Fatalf("chunk %d: energy after suppression = %.3f, want silence", i, energy)
+		}
+	}
+	if e.coupling < 0.2 || e.coupling > 0.5 {
+		t.Errorf("coupling = %.2f, want about the echo level of 0.4", e.coupling)
+	}
+}
+
+func TestEchoSuppressor_PassesDoubleTalk(t *testing.T) {
+	config := DefaultAudioConfig()
+	clock := newFakeClock()
+	e := newTestEchoSuppressor(clock)
+
+	e.AddReference(tonePCM(config, 2000, 440, 0.3))
+	echo := tonePCM(config, 100, 440, 0.12)
+	for i := 0; i < 5; i++ {
+		clock.advance(100)
+		e.Process(echo)
+	}
+
+	// The user talks over the agent
+	speech := mixPCM(echo, tonePCM(config, 100, 180, 0.25))
+	clock.advance(100)
+	out := e.Process(speech)
+	if energy := CalculateRMSEnergy(out); energy < DefaultInterruptConfig().EnergyThreshold {
+		t.Errorf("energy of double talk = %.3f, want it kept", energy)
+	}
+
+	// The hangover carries over the gap after a word
+	clock.advance(100)
+	if energy := CalculateRMSEnergy(e.Process(echo[:960])); energy == 0 {
+		t.Error("input right after speech was suppressed, want the hangover to pass it")
+	}
+}
+
+func TestEchoSuppressor_NoReference(t *testing.T) {
+	config := DefaultAudioConfig()
+	clock := newFakeClock()
+	e := newTestEchoSuppressor(clock)
+
+	input := tonePCM(config, 100, 440, 0.12)
+	if out := e.Process(input); &out[0] != &input[0] {
+		t.Error("Process() without a reference changed the input")
+	}
+
+	// Once the echo of the reference has died away, input passes again
+	e.AddReference(tonePCM(config, 200, 440, 0.3))
+	clock.advance(300)
+	if energy := CalculateRMSEnergy(e.Process(input)); energy > 0.01 {
+		t.Errorf("energy = %.3f, want the echo suppressed", energy)
+	}
+	clock.advance(600)
+	if energy := CalculateRMSEnergy(e.Process(input)); energy < 0.08 {
+		t.Errorf("energy = %.3f after the reference, want the input kept", energy)
+	}
+}
+
+func TestEchoSuppressor_FlushDropsUnplayedReference(t *testing.T) {
+	config := DefaultAudioConfig()
+	clock := newFakeClock()
+	e := newTestEchoSuppressor(clock)
+
+	e.AddReference(tonePCM(config, 3000, 440, 0.3))
+	clock.advance(100)
+	e.Flush()
+	clock.advance(700)
+	if energy := CalculateRMSEnergy(e.Process(tonePCM(config, 100, 440, 0.12))); energy < 0.08 {
+		t.Errorf("energy = %.3f after a flush, want the input kept", energy)
+	}
+}
+
+func TestEchoSuppressor_Nil(t *testing.T) {
+	var e *EchoSuppressor
+	e.AddReference([]byte{1, 2})
+	e.Flush()
+	if out := e.Process([]byte{1, 2}); len(out) != 2 {
+		t.Errorf("Process() on a nil EchoSuppressor = %v", out)
+	}
+}
+
+func TestSession_EchoDoesNotInterrupt(t *testing.T) {
+	config := DefaultAudioConfig()
+	for _, enabled := range []bool{false, true} {
+		interrupt := DefaultInterruptConfig()
+		interrupt.EchoSuppression.Enabled = enabled
+		s := newToolTestSession(t, SessionConfig{Model: "test/model", Interrupt: interrupt}, nil, speakingTTS{})
+		clock := newFakeClock()
+		if s.echo != nil {
+			s.echo.now = clock.now
+		}
+
+		s.emitAudio(tonePCM(config, 1000, 440, 0.3))
+		clock.advance(100)
+		s.setState(StateSpeaking)
+		echo := tonePCM(config, 100, 440, 0.12)
+		for i := 0; i < 5 && s.State() == StateSpeaking; i++ {
+			clock.advance(100)
+			s.processAudio(echo)
+		}
+		if got := s.State(); (got == StateSpeaking) != enabled {
+			t.Fatalf("echo suppression %v: state after echo = %s", enabled, got)
+		}
+		if !enabled {
+			continue
+		}
+
+		clock.advance(100)
+		s.processAudio(mixPCM(echo, tonePCM(config, 100, 180, 0.25)))
+		if got := s.State(); got != StateInterruptCapturing {
+			t.Errorf("state after the user spoke = %s, want %s", got, StateInterruptCapturing)
+		}
+	}
+}
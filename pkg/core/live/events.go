@@ -167,6 +167,7 @@ func (e *ToolUseEvent) EventType() string { return "tool_use" }
 // ToolResultEvent is emitted when a tool returns a result.
 type ToolResultEvent struct {
 	ID      string `json:"id"`
+	Name    string `json:"name"`
 	Content string `json:"content"`
 	IsError bool   `json:"is_error,omitempty"`
 }
@@ -195,6 +196,18 @@ type AudioFlushEvent struct{}
 
 func (e *AudioFlushEvent) EventType() string { return "audio.flush" }
 
+// FillerStartedEvent is emitted when filler audio starts while the agent is
+// waiting on the model or a tool. The filler audio follows as AudioDeltaEvents;
+// it is not part of the response. An AudioFlushEvent is emitted before the
+// response audio if filler audio may still be playing.
+type FillerStartedEvent struct {
+	Kind string `json:"kind"`           // "phrase", "tool" or "hold"
+	Text string `json:"text,omitempty"` // The words spoken
+	Tool string `json:"tool,omitempty"` // The tool being waited on
+}
+
+func (e *FillerStartedEvent) EventType() string { return "filler.started" }
+
 // InterruptDetectingEvent is emitted when potential interrupt audio is detected.
 type InterruptDetectingEvent struct{}
 
@@ -245,6 +258,24 @@ type TTSCancelledEvent struct {
 
 func (e *TTSCancelledEvent) EventType() string { return "tts.cancelled" }
 
+// TurnLatencyEvent is emitted when the first audio of a response is sent,
+// with how long each stage of the turn took. Stages that did not run in
+// this turn are zero.
+type TurnLatencyEvent struct {
+	SpeechEndedAt time.Time `json:"speech_ended_at,omitzero"` // Zero for text input
+	CommittedAt   time.Time `json:"committed_at"`
+
+	EndpointingMs        int `json:"endpointing_ms,omitempty"`         // End of speech to VAD commit
+	SemanticCheckMs      int `json:"semantic_check_ms,omitempty"`      // Turn-completion check
+	GracePeriodMs        int `json:"grace_period_ms,omitempty"`        // First commit to final commit when the user continued
+	InterruptDetectionMs int `json:"interrupt_detection_ms,omitempty"` // Interrupt capture to confirmation
+	LLMFirstTokenMs      int `json:"llm_first_token_ms"`               // Response start to first text token
+	TTSFirstAudioMs      int `json:"tts_first_audio_ms"`               // First text sent to TTS to first audio
+	MouthToEarMs         int `json:"mouth_to_ear_ms"`                  // End of speech (or commit, for text input) to first audio
+}
+
+func (e *TurnLatencyEvent) EventType() string { return "turn.latency" }
+
 // ErrorEvent is emitted when an error occurs.
 type ErrorEvent struct {
 	Code    string `json:"code"`
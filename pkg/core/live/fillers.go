@@ -0,0 +1,75 @@
+package live
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/filler"
+)
+
+// fillerConfig returns the config of the fillers played while the agent waits.
+func (s *Session) fillerConfig() filler.Config {
+	c := s.config.Fillers
+	if c == nil && s.config.Voice != nil && s.config.Voice.Output != nil {
+		c = s.config.Voice.Output.Fillers
+	}
+	return voice.FillerConfig(c)
+}
+
+// initFillers creates the filler player, if fillers are configured, and
+// synthesizes the fillers in the background. Filler audio is sent like
+// response audio, but bypasses playback tracking, so it never reaches the
+// conversation history.
+func (s *Session) initFillers() error {
+	cfg := s.fillerConfig()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var bytesPerSecond int
+	if opts := s.ttsOptions(); opts.Format == "pcm" {
+		bytesPerSecond = opts.SampleRate * 2
+	}
+	s.fillers = filler.New(s.ctx, cfg, s.synthesizeFiller, filler.Output{
+		BytesPerSecond: bytesPerSecond,
+		Started: func(f filler.Filler) {
+			s.debug("FILLER", fmt.Sprintf("Playing %s filler %q", f.Kind, f.Text))
+			s.emit(&FillerStartedEvent{Kind: string(f.Kind), Text: f.Text, Tool: f.Tool})
+		},
+		Audio: func(data []byte) {
+			s.emitAudio(data)
+		},
+	})
+	go s.fillers.Prepare()
+	return nil
+}
+
+// synthesizeFiller synthesizes filler text on a TTS context of its own, so
+// that the response's context and its word timings are left alone.
+func (s *Session) synthesizeFiller(ctx context.Context, text string) ([]byte, error) {
+	ttsCtx, err := s.ttsClient.NewStreamingContext(ctx, s.ttsOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer ttsCtx.Close()
+
+	if err := ttsCtx.SendText(text, true); err != nil {
+		return nil, err
+	}
+	var data []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case chunk, ok := <-ttsCtx.Audio():
+			if !ok {
+				return data, ttsCtx.Err()
+			}
+			data = append(data, chunk...)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+// speakingTTS returns 100ms of 24kHz PCM for each piece of text.
+type speakingTTS struct{}
+
+func (speakingTTS) NewStreamingContext(ctx context.Context, opts tts.StreamingContextOptions) (*tts.StreamingContext, error) {
+	sc := tts.NewStreamingContext()
+	sc.SendFunc = func(text string, isFinal bool) error {
+		if text != "" {
+			sc.PushAudio(make([]byte, 4800))
+		}
+		if isFinal {
+			sc.FinishAudio()
+		}
+		return nil
+	}
+	return sc, nil
+}
+
+func TestSession_FillerWhileToolRuns(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("One moment.", types.StopReasonToolUse, toolUseEvents(1, "call_1", "lookup_order")...),
+		textResponse("It shipped yesterday.", types.StopReasonEndTurn),
+	}}
+	s := newToolTestSession(t, SessionConfig{
+		Model: "test/model",
+		ToolHandlers: map[string]ToolHandler{
+			"lookup_order": func(ctx context.Context, input json.RawMessage) (any, error) {
+				time.Sleep(400 * time.Millisecond)
+				return "shipped", nil
+			},
+		},
+		Fillers: &types.FillerConfig{
+			DelayMs: 20,
+			Tools:   map[string]string{"lookup_order": "Checking your order."},
+		},
+	}, llm, speakingTTS{})
+
+	s.runAgent(s.ctx, []types.Message{{Role: "user", Content: "Where is my order?"}})
+	events := collectUntil[*AudioCommittedEvent](t, s)
+
+	var filler *FillerStartedEvent
+	flushed := false
+	fillerAudio := 0
+	for _, event := range events {
+		switch e := event.(type) {
+		case *FillerStartedEvent:
+			filler = e
+		case *AudioFlushEvent:
+			flushed = filler != nil
+		case *AudioDeltaEvent:
+			if filler != nil && !flushed {
+				fillerAudio++
+			}
+		}
+	}
+	if filler == nil || filler.Kind != "tool" || filler.Text != "Checking your order." || filler.Tool != "lookup_order" {
+		t.Fatalf("FillerStartedEvent = %+v", filler)
+	}
+	if fillerAudio == 0 {
+		t.Error("no filler audio was sent")
+	}
+	if !flushed {
+		t.Error("filler audio was not flushed before the response audio")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, msg := range s.messages {
+		if text, _ := json.Marshal(msg.Content); strings.Contains(string(text), "Checking your order") {
+			t.Errorf("filler in history: %+v", msg)
+		}
+	}
+}
+
+func TestSession_NoFillerForQuickResponse(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Hello there.", types.StopReasonEndTurn),
+	}}
+	s := newToolTestSession(t, SessionConfig{
+		Model:   "test/model",
+		Fillers: &types.FillerConfig{DelayMs: 200, Phrases: []string{"Let me think."}},
+	}, llm, speakingTTS{})
+
+	s.setState(StateProcessing)
+	s.runAgent(s.ctx, []types.Message{{Role: "user", Content: "Hi"}})
+	events := collectUntil[*AudioCommittedEvent](t, s)
+	time.Sleep(300 * time.Millisecond)
+
+	for _, event := range append(events, drainEvents(s)...) {
+		if e, ok := event.(*FillerStartedEvent); ok {
+			t.Errorf("unexpected filler %+v", e)
+		}
+	}
+}
+
+func TestSession_InvalidFillerConfig(t *testing.T) {
+	s := NewSession(SessionConfig{Fillers: &types.FillerConfig{DelayMs: -1}}, nil, speakingTTS{}, nil)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	defer s.cancel()
+	if err := s.initComponents(); err == nil {
+		t.Error("initComponents() with a negative filler delay should fail")
+	}
+}
+
+// drainEvents returns the events already emitted.
+func drainEvents(s *Session) []Event {
+	var events []Event
+	for {
+		select {
+		case event := <-s.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
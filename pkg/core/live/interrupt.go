@@ -61,6 +61,7 @@ type InterruptDetector struct {
 	captureStart  time.Time
 	captureBuffer *AudioBuffer
 	transcript    string
+	language      string // Language of the user's speech, for backchannels
 
 	// Callbacks
 	onDetecting func()
@@ -97,6 +98,14 @@ func (d *InterruptDetector) SetCallbacks(
 	d.onDebug = onDebug
 }
 
+// SetLanguage sets the language of the user's speech, such as "es" or
+// "de-DE", which selects the backchannels recognized. Default: English.
+func (d *InterruptDetector) SetLanguage(language string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.language = language
+}
+
 // StartCapture begins the interrupt capture window.
 // This should be called when audio is detected during bot speech.
 func (d *InterruptDetector) StartCapture() {
@@ -270,29 +279,13 @@ func (d *InterruptDetector) analyzeAuto(ctx context.Context, transcript string)
 	return InterruptReal
 }
 
-// isLikelyBackchannel does a quick check for common backchannel phrases.
+// isLikelyBackchannel does a quick check for common backchannel phrases
+// in the user's language.
 func (d *InterruptDetector) isLikelyBackchannel(transcript string) bool {
-	lower := strings.ToLower(strings.TrimSpace(transcript))
-
-	// Common backchannels
-	backchannels := []string{
-		"uh huh", "uh-huh", "uhuh",
-		"mm hmm", "mm-hmm", "mmhmm", "mhm",
-		"yeah", "yep", "yup",
-		"okay", "ok", "k",
-		"right", "i see", "got it",
-		"sure", "alright", "all right",
-		"hmm", "hm", "ah",
-		"oh", "oh okay", "oh ok",
-	}
-
-	for _, bc := range backchannels {
-		if lower == bc {
-			return true
-		}
-	}
-
-	return false
+	d.mu.Lock()
+	language := d.language
+	d.mu.Unlock()
+	return d.config.isBackchannel(language, transcript)
 }
 
 // Reset clears the interrupt detector state.
@@ -0,0 +1,229 @@
+package live
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// turnLanguage holds the built-in turn-taking rules of a language.
+type turnLanguage struct {
+	// backchannels acknowledge the agent without taking the turn
+	backchannels []string
+
+	// turnComplete and interruptCheck are the prompt templates of the
+	// semantic checks, in the language itself. Replies stay YES/NO and
+	// INTERRUPT/BACKCHANNEL so that they parse the same everywhere.
+	turnComplete   string
+	interruptCheck string
+
+	// yes are words other than YES that models answer turn checks with
+	yes []string
+}
+
+// turnLanguages are the built-in languages by language code. Others use English.
+var turnLanguages = map[string]*turnLanguage{
+	"en": {
+		backchannels: []string{
+			"uh huh", "uhuh",
+			"mm hmm", "mmhmm", "mhm",
+			"yeah", "yep", "yup",
+			"okay", "ok", "k",
+			"right", "i see", "got it",
+			"sure", "alright", "all right",
+			"hmm", "hm", "ah",
+			"oh", "oh okay", "oh ok",
+		},
+		turnComplete:   TurnCompletePrompt,
+		interruptCheck: InterruptCheckPrompt,
+	},
+	"es": {
+		backchannels: []string{
+			"sí", "si", "sí sí", "si si",
+			"vale", "vale vale", "ah vale",
+			"ok", "okay", "ah ok",
+			"claro", "ya", "ya veo", "bueno",
+			"ajá", "aja", "mhm", "mm hmm", "eh", "ah", "oh",
+			"de acuerdo", "entiendo", "entendido",
+			"perfecto", "exacto", "bien", "muy bien",
+		},
+		turnComplete: `Transcripción de voz: "%s"
+
+Formas parte de un agente de voz con IA en modo en vivo. Tu tarea es leer la transcripción de lo que el usuario ha dicho desde la última vez que habló el agente y decidir si el usuario ha terminado de hablar y, por tanto, el agente debe responder, o si todavía no ha terminado y el agente debe esperar.
+
+YES = El usuario ha terminado de hablar
+NO = El usuario no ha terminado de hablar
+
+Responde solo: YES o NO`,
+		interruptCheck: `El usuario dijo: "%s"
+
+El asistente de IA estaba hablando cuando el usuario dijo esto. ¿Intenta el usuario interrumpir para cambiar de tema o detener al asistente? ¿O es solo una señal de que escucha (como "vale", "ajá", "claro") que no requiere detenerse?
+
+Responde solo: INTERRUPT o BACKCHANNEL`,
+		yes: []string{"SÍ", "SI"},
+	},
+	"de": {
+		backchannels: []string{
+			"ja", "ja ja", "ja genau", "ja klar", "jo", "jawohl",
+			"genau", "stimmt", "richtig",
+			"okay", "ok", "gut", "super", "prima",
+			"alles klar", "klar", "na klar",
+			"aha", "ach so", "achso", "ah", "oh", "hm", "mhm", "mm hmm",
+			"verstehe", "ich verstehe", "verstanden",
+		},
+		turnComplete: `Sprachtranskript: "%s"
+
+Du bist Teil eines KI-Sprachagenten im Live-Modus. Deine Aufgabe ist es, anhand der Transkription dessen, was der Nutzer gesagt hat, seit der Agent zuletzt gesprochen hat, zu entscheiden, ob der Nutzer fertig ist und der Agent deshalb antworten soll, oder ob der Nutzer noch nicht fertig ist und der Agent warten soll.
+
+YES = Der Nutzer ist fertig
+NO = Der Nutzer ist noch nicht fertig
+
+Antworte nur: YES oder NO`,
+		interruptCheck: `Der Nutzer sagte: "%s"
+
+Der KI-Assistent sprach gerade, als der Nutzer das sagte. Will der Nutzer den Assistenten unterbrechen, um das Thema zu wechseln oder ihn zu stoppen? Oder ist es nur eine Rückmeldung, dass er zuhört (wie "ja", "genau", "mhm"), bei der der Assistent nicht anhalten muss?
+
+Antworte nur: INTERRUPT oder BACKCHANNEL`,
+		yes: []string{"JA"},
+	},
+	"fr": {
+		backchannels: []string{
+			"oui", "oui oui", "ouais", "ah oui",
+			"d'accord", "ok", "okay", "bon", "ah bon",
+			"je vois", "entendu", "voilà", "exactement", "c'est ça",
+			"bien sûr", "très bien",
+			"ah", "oh", "hm", "mhm", "mm hmm",
+		},
+		turnComplete: `Transcription vocale : "%s"
+
+Tu fais partie d'un agent vocal IA en mode direct. Ta tâche est de lire la transcription de ce que l'utilisateur a dit depuis la dernière prise de parole de l'agent et de déterminer si l'utilisateur a fini de parler, auquel cas l'agent doit répondre, ou s'il n'a pas fini et que l'agent doit attendre.
+
+YES = L'utilisateur a fini de parler
+NO = L'utilisateur n'a pas fini de parler
+
+Réponds uniquement : YES ou NO`,
+		interruptCheck: `L'utilisateur a dit : "%s"
+
+L'assistant IA parlait quand l'utilisateur a dit cela. L'utilisateur essaie-t-il d'interrompre l'assistant pour changer de sujet ou l'arrêter ? Ou s'agit-il simplement d'une marque d'écoute (comme "oui", "d'accord", "je vois") qui ne demande pas de s'arrêter ?
+
+Réponds uniquement : INTERRUPT ou BACKCHANNEL`,
+		yes: []string{"OUI"},
+	},
+}
+
+// languageCode returns the lowercase language of a tag such as "en",
+// "es-MX" or "de_DE".
+func languageCode(tag string) string {
+	code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	code, _, _ = strings.Cut(code, "_")
+	return code
+}
+
+// lookupTurnLanguage returns the built-in rules for a language tag, falling
+// back to English.
+func lookupTurnLanguage(tag string) *turnLanguage {
+	if l, ok := turnLanguages[languageCode(tag)]; ok {
+		return l
+	}
+	return turnLanguages["en"]
+}
+
+// byLanguage looks up a language tag in m, keyed by tag or by language code.
+func byLanguage[T any](m map[string]T, tag string) (T, bool) {
+	if v, ok := m[tag]; ok {
+		return v, true
+	}
+	v, ok := m[languageCode(tag)]
+	return v, ok
+}
+
+// DefaultBackchannels returns the built-in backchannels of a language tag
+// such as "es" or "de-DE", or the English ones if it has none.
+func DefaultBackchannels(language string) []string {
+	return slices.Clone(lookupTurnLanguage(language).backchannels)
+}
+
+// normalizeBackchannel lowercases a phrase and drops its punctuation, so
+// that "Ja, genau." matches "ja genau" and "uh-huh" matches "uh huh".
+func normalizeBackchannel(s string) string {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(s, "-", " ")))
+	kept := words[:0]
+	for _, w := range words {
+		if w = strings.TrimFunc(w, unicode.IsPunct); w != "" {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// turnCompletePrompt returns the turn check prompt for a language:
+// VADConfig.TurnCompletePrompts, else the built-in one.
+func (c VADConfig) turnCompletePrompt(language string) string {
+	if prompt, ok := byLanguage(c.TurnCompletePrompts, language); ok && prompt != "" {
+		return prompt
+	}
+	return lookupTurnLanguage(language).turnComplete
+}
+
+// interruptCheckPrompt returns the interrupt check prompt for a language:
+// InterruptConfig.InterruptCheckPrompts, else the built-in one.
+func (c InterruptConfig) interruptCheckPrompt(language string) string {
+	if prompt, ok := byLanguage(c.InterruptCheckPrompts, language); ok && prompt != "" {
+		return prompt
+	}
+	return lookupTurnLanguage(language).interruptCheck
+}
+
+// isBackchannel reports whether a transcript is one of the backchannels of
+// a language, built-in or from InterruptConfig.Backchannels.
+func (c InterruptConfig) isBackchannel(language, transcript string) bool {
+	phrase := normalizeBackchannel(transcript)
+	if phrase == "" {
+		return false
+	}
+	extra, _ := byLanguage(c.Backchannels, language)
+	for _, bc := range slices.Concat(lookupTurnLanguage(language).backchannels, extra, c.Backchannels["*"]) {
+		if normalizeBackchannel(bc) == phrase {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTurnComplete parses a turn check reply in a language. Besides YES,
+// it accepts the language's own word for yes.
+func parseTurnComplete(language, response string) bool {
+	if ParseTurnCompleteResponse(response) {
+		return true
+	}
+	word, _, _ := strings.Cut(strings.TrimSpace(response), " ")
+	word = strings.ToUpper(strings.TrimFunc(word, unicode.IsPunct))
+	return word != "" && slices.Contains(lookupTurnLanguage(language).yes, word)
+}
+
+// language returns the language the user speaks: as detected by STT, else
+// Voice.Input.Language, else English.
+func (s *Session) language() string {
+	s.mu.RLock()
+	detected := s.sttLanguage
+	s.mu.RUnlock()
+	if detected != "" {
+		return detected
+	}
+	if s.config.Voice != nil && s.config.Voice.Input != nil && s.config.Voice.Input.Language != "" {
+		return s.config.Voice.Input.Language
+	}
+	return "en"
+}
+
+// setLanguage records the language STT detected.
+func (s *Session) setLanguage(language string) {
+	s.mu.Lock()
+	changed := s.sttLanguage != language
+	s.sttLanguage = language
+	s.mu.Unlock()
+	if changed {
+		s.debug("STT", "Detected language: "+language)
+		s.interrupt.SetLanguage(language)
+	}
+}
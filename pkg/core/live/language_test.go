@@ -0,0 +1,156 @@
+package live
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+)
+
+func TestInterruptConfig_IsBackchannel(t *testing.T) {
+	config := InterruptConfig{Backchannels: map[string][]string{
+		"es":    {"venga"},
+		"de-AT": {"passt"},
+		"*":     {"okey dokey"},
+	}}
+	tests := []struct {
+		language, transcript string
+		want                 bool
+	}{
+		{"en", "Uh-huh.", true},
+		{"en", "Mm-hmm", true},
+		{"en", "Wait, stop", false},
+		{"es", "Vale.", true},
+		{"es-ES", "¡Sí, sí!", true},
+		{"es", "No, espera", false},
+		{"es", "venga", true},
+		{"es", "yeah", false},
+		{"de", "Ja.", true},
+		{"de-DE", "Genau!", true},
+		{"de", "Ja, genau", true},
+		{"de", "Nein, warte mal", false},
+		{"de-AT", "Passt.", true},
+		{"de", "passt", false},
+		{"fr", "D'accord.", true},
+		{"fr", "Okey dokey", true},
+		{"ja", "okay", true}, // No built-in lexicon, so English
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := config.isBackchannel(tt.language, tt.transcript); got != tt.want {
+			t.Errorf("isBackchannel(%q, %q) = %v, want %v", tt.language, tt.transcript, got, tt.want)
+		}
+	}
+}
+
+func TestInterruptDetector_Analyze_LanguageBackchannels(t *testing.T) {
+	for _, tt := range []struct{ language, transcript string }{
+		{"es", "vale"},
+		{"de", "ja"},
+		{"de", "genau"},
+	} {
+		detector := NewInterruptDetector(InterruptConfig{Mode: InterruptModeAuto}, DefaultAudioConfig(), nil)
+		detector.StartCapture()
+		detector.AddTranscript(tt.transcript)
+		if got := detector.Analyze(context.Background()); got != InterruptReal {
+			t.Errorf("%q without a language: got %v, want %v", tt.transcript, got, InterruptReal)
+		}
+
+		detector.SetLanguage(tt.language)
+		detector.StartCapture()
+		detector.AddTranscript(tt.transcript)
+		if got := detector.Analyze(context.Background()); got != InterruptBackchannel {
+			t.Errorf("%q in %s: got %v, want %v", tt.transcript, tt.language, got, InterruptBackchannel)
+		}
+	}
+}
+
+func TestPrompts_ByLanguage(t *testing.T) {
+	vad := VADConfig{TurnCompletePrompts: map[string]string{"es-MX": "¿Terminó? %s"}}
+	if got := vad.turnCompletePrompt("es-MX"); got != "¿Terminó? %s" {
+		t.Errorf("turnCompletePrompt(es-MX) = %q, want the configured prompt", got)
+	}
+	if got := vad.turnCompletePrompt("es"); !strings.HasPrefix(got, "Transcripción de voz") {
+		t.Errorf("turnCompletePrompt(es) = %q, want the built-in Spanish prompt", got)
+	}
+	if got := vad.turnCompletePrompt("ja"); got != TurnCompletePrompt {
+		t.Errorf("turnCompletePrompt(ja) = %q, want the English prompt", got)
+	}
+
+	interrupt := InterruptConfig{InterruptCheckPrompts: map[string]string{"de": "Unterbrechung? %s"}}
+	if got := interrupt.interruptCheckPrompt("de-CH"); got != "Unterbrechung? %s" {
+		t.Errorf("interruptCheckPrompt(de-CH) = %q, want the configured prompt", got)
+	}
+	if got := interrupt.interruptCheckPrompt("fr"); !strings.Contains(got, "INTERRUPT ou BACKCHANNEL") {
+		t.Errorf("interruptCheckPrompt(fr) = %q, want the built-in French prompt", got)
+	}
+}
+
+func TestParseTurnComplete(t *testing.T) {
+	tests := []struct {
+		language, response string
+		want               bool
+	}{
+		{"en", "YES", true},
+		{"en", "NO", false},
+		{"es", "Sí.", true},
+		{"es", "si", true},
+		{"es", "NO", false},
+		{"de", "Ja", true},
+		{"de", "Nein", false},
+		{"fr", "Oui", true},
+		{"en", "Ja", false},
+	}
+	for _, tt := range tests {
+		if got := parseTurnComplete(tt.language, tt.response); got != tt.want {
+			t.Errorf("parseTurnComplete(%q, %q) = %v, want %v", tt.language, tt.response, got, tt.want)
+		}
+	}
+}
+
+// replyLLM answers every message request with the same text.
+type replyLLM struct {
+	scriptedLLM
+	reply   string
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (l *replyLLM) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prompts = append(l.prompts, req.Messages[0].Content.(string))
+	return &types.MessageResponse{Content: []types.ContentBlock{types.TextBlock{Type: "text", Text: l.reply}}}, nil
+}
+
+func TestSession_TurnCheckFollowsLanguage(t *testing.T) {
+	llm := &replyLLM{reply: "Ja."}
+	s := newToolTestSession(t, SessionConfig{
+		Model: "test/model",
+		Voice: &types.VoiceConfig{Input: &types.VoiceInputConfig{Language: "de-DE"}},
+	}, llm, speakingTTS{})
+
+	done, err := s.checkTurnComplete(s.ctx, "Ich möchte einen Termin")
+	if err != nil || !done {
+		t.Fatalf("checkTurnComplete() = %v, %v; want true", done, err)
+	}
+	if !strings.HasPrefix(llm.prompts[0], `Sprachtranskript: "Ich möchte einen Termin"`) {
+		t.Errorf("prompt = %q, want the German prompt", llm.prompts[0])
+	}
+
+	// STT hears Spanish instead
+	s.setState(StateSpeaking)
+	s.processTranscriptDelta(stt.TranscriptDelta{Text: "", Language: "es"})
+	if _, err := s.checkInterrupt(s.ctx, "vale"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(llm.prompts[1], `El usuario dijo: "vale"`) {
+		t.Errorf("prompt = %q, want the Spanish prompt", llm.prompts[1])
+	}
+	if !s.interrupt.isLikelyBackchannel("Vale.") {
+		t.Error(`"Vale." is not a backchannel after STT detected Spanish`)
+	}
+}
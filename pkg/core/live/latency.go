@@ -0,0 +1,207 @@
+package live
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyTracker times each turn from the end of the user's speech to the
+// first audio of the response, and reports it as a TurnLatencyEvent.
+//
+// Signals about the user's speech (end of speech, semantic checks,
+// interrupt detection) accumulate until the turn is committed. The response
+// started for the commit then collects LLM and TTS timings, and is reported
+// once, when its first audio is sent.
+type latencyTracker struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	// Signals for the turn the user is speaking
+	speechEndAt      time.Time
+	transcriptAt     time.Time
+	semantic         time.Duration
+	interruptStartAt time.Time
+	interrupt        time.Duration
+	graceFrom        time.Time // first commit of a turn the user continued
+
+	// The committed turn and its response
+	turn      turnLatency
+	committed bool // committed, response not started yet
+}
+
+// turnLatency holds the timings of one turn.
+type turnLatency struct {
+	speechEndAt  time.Time
+	committedAt  time.Time
+	semantic     time.Duration
+	grace        time.Duration
+	interrupt    time.Duration
+	responseAt   time.Time
+	firstTokenAt time.Time
+	ttsTextAt    time.Time
+	reported     bool
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{now: time.Now}
+}
+
+// speechEnded records the acoustic end of speech, silenceMs ago.
+func (t *latencyTracker) speechEnded(silenceMs int) {
+	t.mu.Lock()
+	t.speechEndAt = t.now().Add(-time.Duration(silenceMs) * time.Millisecond)
+	t.mu.Unlock()
+}
+
+// transcribed records the arrival of transcribed speech. It stands in for
+// the end of speech when the acoustic VAD is off.
+func (t *latencyTracker) transcribed() {
+	t.mu.Lock()
+	t.transcriptAt = t.now()
+	t.mu.Unlock()
+}
+
+// semanticChecked records the duration of a turn-completion check.
+func (t *latencyTracker) semanticChecked(d time.Duration) {
+	t.mu.Lock()
+	t.semantic = d
+	t.mu.Unlock()
+}
+
+// interruptStarted records that interrupt capture began.
+func (t *latencyTracker) interruptStarted() {
+	t.mu.Lock()
+	t.interruptStartAt = t.now()
+	t.mu.Unlock()
+}
+
+// interruptConfirmed records how long confirming the interrupt took. The
+// next turn, which answers the interruption, reports it.
+func (t *latencyTracker) interruptConfirmed() {
+	t.mu.Lock()
+	if !t.interruptStartAt.IsZero() {
+		t.interrupt = t.now().Sub(t.interruptStartAt)
+	}
+	t.interruptStartAt = time.Time{}
+	t.mu.Unlock()
+}
+
+// interruptDismissed forgets an interrupt capture that was not a real
+// interrupt.
+func (t *latencyTracker) interruptDismissed() {
+	t.mu.Lock()
+	t.interruptStartAt = time.Time{}
+	t.mu.Unlock()
+}
+
+// continued records that the user kept talking during the grace period.
+// The current response is dropped, and the turn's final commit reports the
+// time since its first commit as grace-period wait.
+func (t *latencyTracker) continued() {
+	t.mu.Lock()
+	if t.graceFrom.IsZero() {
+		t.graceFrom = t.turn.committedAt
+	}
+	t.turn.reported = true
+	t.mu.Unlock()
+}
+
+// commit starts a turn from the signals collected since the last one.
+func (t *latencyTracker) commit() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	turn := turnLatency{
+		speechEndAt: t.speechEndAt,
+		committedAt: now,
+		semantic:    t.semantic,
+		interrupt:   t.interrupt,
+	}
+	if turn.speechEndAt.IsZero() {
+		turn.speechEndAt = t.transcriptAt
+	}
+	if !t.graceFrom.IsZero() {
+		turn.grace = now.Sub(t.graceFrom)
+	}
+
+	t.turn = turn
+	t.committed = true
+	t.speechEndAt, t.transcriptAt = time.Time{}, time.Time{}
+	t.semantic, t.interrupt = 0, 0
+	t.graceFrom = time.Time{}
+}
+
+// startResponse starts timing a response. A response without a commit,
+// such as one to text input, is timed from when it starts, and discards
+// the signals of any speech in progress.
+func (t *latencyTracker) startResponse() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if !t.committed {
+		t.turn = turnLatency{committedAt: now}
+		t.speechEndAt, t.transcriptAt = time.Time{}, time.Time{}
+		t.semantic = 0
+	}
+	t.committed = false
+	t.turn.responseAt = now
+}
+
+// firstToken records the first text token of the response.
+func (t *latencyTracker) firstToken() {
+	t.mu.Lock()
+	if t.turn.firstTokenAt.IsZero() {
+		t.turn.firstTokenAt = t.now()
+	}
+	t.mu.Unlock()
+}
+
+// ttsText records text being sent to TTS.
+func (t *latencyTracker) ttsText() {
+	t.mu.Lock()
+	if t.turn.ttsTextAt.IsZero() {
+		t.turn.ttsTextAt = t.now()
+	}
+	t.mu.Unlock()
+}
+
+// firstAudio records audio being sent to the client. It returns the turn's
+// latency on the response's first audio, and nil after that.
+func (t *latencyTracker) firstAudio() *TurnLatencyEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	turn := &t.turn
+	if turn.reported || turn.responseAt.IsZero() {
+		return nil
+	}
+	turn.reported = true
+
+	now := t.now()
+	event := &TurnLatencyEvent{
+		SpeechEndedAt:        turn.speechEndAt,
+		CommittedAt:          turn.committedAt,
+		SemanticCheckMs:      int(turn.semantic.Milliseconds()),
+		GracePeriodMs:        int(turn.grace.Milliseconds()),
+		InterruptDetectionMs: int(turn.interrupt.Milliseconds()),
+	}
+	from := turn.committedAt
+	if !turn.speechEndAt.IsZero() {
+		from = turn.speechEndAt
+		event.EndpointingMs = msBetween(turn.speechEndAt, turn.committedAt)
+	}
+	event.MouthToEarMs = msBetween(from, now)
+	if !turn.firstTokenAt.IsZero() {
+		event.LLMFirstTokenMs = msBetween(turn.responseAt, turn.firstTokenAt)
+	}
+	if !turn.ttsTextAt.IsZero() {
+		event.TTSFirstAudioMs = msBetween(turn.ttsTextAt, now)
+	}
+	return event
+}
+
+func msBetween(from, to time.Time) int {
+	return int(to.Sub(from).Milliseconds())
+}
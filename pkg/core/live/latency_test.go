@@ -0,0 +1,175 @@
+package live
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// fakeClock is advanced by tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+func (c *fakeClock) advance(ms int) { c.t = c.t.Add(time.Duration(ms) * time.Millisecond) }
+
+func newTestLatencyTracker() (*latencyTracker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	t := newLatencyTracker()
+	t.now = clock.now
+	return t, clock
+}
+
+func TestLatencyTracker_SpokenTurn(t *testing.T) {
+	tracker, clock := newTestLatencyTracker()
+	start := clock.t
+
+	// Speech ends; the VAD reports it after 300ms of silence
+	clock.advance(300)
+	tracker.speechEnded(300)
+	clock.advance(50)
+	tracker.transcribed()
+	tracker.semanticChecked(150 * time.Millisecond)
+	clock.advance(150)
+	tracker.commit()
+	tracker.startResponse()
+
+	clock.advance(400)
+	tracker.firstToken()
+	tracker.ttsText()
+	clock.advance(200)
+	event := tracker.firstAudio()
+	if event == nil {
+		t.Fatal("firstAudio() = nil, want the turn's latency")
+	}
+
+	want := TurnLatencyEvent{
+		SpeechEndedAt:   start,
+		CommittedAt:     start.Add(500 * time.Millisecond),
+		EndpointingMs:   500,
+		SemanticCheckMs: 150,
+		LLMFirstTokenMs: 400,
+		TTSFirstAudioMs: 200,
+		MouthToEarMs:    1100,
+	}
+	if *event != want {
+		t.Errorf("latency = %+v\nwant %+v", *event, want)
+	}
+
+	// Reported once per response
+	clock.advance(100)
+	if event := tracker.firstAudio(); event != nil {
+		t.Errorf("second firstAudio() = %+v, want nil", event)
+	}
+}
+
+func TestLatencyTracker_TranscriptStandsInForSpeechEnd(t *testing.T) {
+	tracker, clock := newTestLatencyTracker()
+
+	tracker.transcribed()
+	clock.advance(600)
+	tracker.commit()
+	tracker.startResponse()
+	tracker.firstToken()
+	tracker.ttsText()
+	clock.advance(100)
+
+	event := tracker.firstAudio()
+	if event.EndpointingMs != 600 || event.MouthToEarMs != 700 {
+		t.Errorf("latency = %+v, want 600ms endpointing and 700ms mouth-to-ear", event)
+	}
+}
+
+func TestLatencyTracker_GracePeriodContinuation(t *testing.T) {
+	tracker, clock := newTestLatencyTracker()
+
+	tracker.transcribed()
+	tracker.commit()
+	tracker.startResponse()
+	first := clock.t
+
+	// The user keeps talking before any audio plays
+	clock.advance(300)
+	tracker.continued()
+	clock.advance(500)
+	tracker.transcribed()
+	clock.advance(200)
+	tracker.commit()
+	tracker.startResponse()
+	tracker.ttsText()
+	clock.advance(100)
+
+	event := tracker.firstAudio()
+	if event == nil {
+		t.Fatal("firstAudio() = nil")
+	}
+	if event.GracePeriodMs != 1000 || !event.CommittedAt.Equal(first.Add(time.Second)) {
+		t.Errorf("latency = %+v, want 1000ms grace period", event)
+	}
+	if event.MouthToEarMs != 300 {
+		t.Errorf("mouth-to-ear = %dms, want 300ms from the continuation", event.MouthToEarMs)
+	}
+}
+
+func TestLatencyTracker_Interrupt(t *testing.T) {
+	tracker, clock := newTestLatencyTracker()
+
+	// A dismissed capture is not reported
+	tracker.interruptStarted()
+	clock.advance(100)
+	tracker.interruptDismissed()
+	tracker.interruptConfirmed()
+
+	tracker.interruptStarted()
+	clock.advance(250)
+	tracker.interruptConfirmed()
+	tracker.commit()
+	tracker.startResponse()
+
+	if event := tracker.firstAudio(); event == nil || event.InterruptDetectionMs != 250 {
+		t.Errorf("latency = %+v, want 250ms interrupt detection", event)
+	}
+}
+
+func TestLatencyTracker_TextInput(t *testing.T) {
+	tracker, clock := newTestLatencyTracker()
+
+	// Signals from speech that was never committed are not used
+	tracker.speechEnded(0)
+	clock.advance(1000)
+	tracker.startResponse()
+	tracker.firstToken()
+	clock.advance(300)
+	tracker.ttsText()
+	clock.advance(200)
+
+	event := tracker.firstAudio()
+	if !event.SpeechEndedAt.IsZero() || event.EndpointingMs != 0 {
+		t.Errorf("latency = %+v, want no speech", event)
+	}
+	if event.LLMFirstTokenMs != 0 || event.TTSFirstAudioMs != 200 || event.MouthToEarMs != 500 {
+		t.Errorf("latency = %+v", event)
+	}
+}
+
+func TestSession_EmitsTurnLatency(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Hello there.", types.StopReasonEndTurn),
+	}}
+	s := newToolTestSession(t, SessionConfig{Model: "test/model", SampleRate: 24000}, llm, &timedTTS{wordMs: 100, sampleRate: 24000})
+
+	s.latency.transcribed()
+	s.onVADCommit("Hi", false)
+
+	events := collectUntil[*TurnLatencyEvent](t, s)
+	if _, ok := events[len(events)-2].(*AudioDeltaEvent); !ok {
+		t.Errorf("turn latency follows %T, want the first audio", events[len(events)-2])
+	}
+	latency := events[len(events)-1].(*TurnLatencyEvent)
+	if latency.SpeechEndedAt.IsZero() || latency.CommittedAt.Before(latency.SpeechEndedAt) {
+		t.Errorf("latency times = %v, %v", latency.SpeechEndedAt, latency.CommittedAt)
+	}
+	if latency.MouthToEarMs < latency.EndpointingMs+latency.LLMFirstTokenMs {
+		t.Errorf("mouth-to-ear %dms is shorter than its stages: %+v", latency.MouthToEarMs, latency)
+	}
+}
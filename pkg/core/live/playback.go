@@ -0,0 +1,272 @@
+package live
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+// defaultMsPerChar estimates speech duration when the TTS provider gives no
+// word timing and synthesis has not finished. Conversational speech runs at
+// about 15 characters per second.
+const defaultMsPerChar = 65.0
+
+// interruptedMarker is appended to partial responses saved with PartialSaveMarked.
+const interruptedMarker = " [interrupted]"
+
+// playbackTracker follows how much of the current response the user has
+// heard. It records the text sent to TTS, which is normalized for speech
+// and so matches the provider's word timestamps, how much audio has been
+// synthesized, and how much the client reports having played.
+type playbackTracker struct {
+	mu            sync.Mutex
+	text          strings.Builder
+	roundEnds     []int // End of the spoken text of each tool round
+	synthesizedMs int
+	playedMs      int // -1 until the client acknowledges playback
+	complete      bool
+}
+
+func newPlaybackTracker() *playbackTracker {
+	return &playbackTracker{playedMs: -1}
+}
+
+// reset starts tracking a new response.
+func (p *playbackTracker) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.text.Reset()
+	p.roundEnds = nil
+	p.synthesizedMs = 0
+	p.playedMs = -1
+	p.complete = false
+}
+
+// addText records a chunk of text sent to TTS. Chunks are separated by a
+// space, as the chunker trims them.
+func (p *playbackTracker) addText(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if text == "" {
+		return
+	}
+	if s := p.text.String(); s != "" && !unicode.IsSpace(rune(s[len(s)-1])) && !unicode.IsSpace(rune(text[0])) {
+		p.text.WriteByte(' ')
+	}
+	p.text.WriteString(text)
+}
+
+// endRound records that the text of a tool round has all been sent to TTS.
+func (p *playbackTracker) endRound() {
+	p.mu.Lock()
+	p.roundEnds = append(p.roundEnds, len(strings.TrimRightFunc(p.text.String(), unicode.IsSpace)))
+	p.mu.Unlock()
+}
+
+// rounds returns the end of the spoken text of each tool round so far.
+func (p *playbackTracker) rounds() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.roundEnds)
+}
+
+// addAudio records synthesized audio sent to the client.
+func (p *playbackTracker) addAudio(ms int) {
+	p.mu.Lock()
+	p.synthesizedMs += ms
+	p.mu.Unlock()
+}
+
+// finish records that all response text has been synthesized.
+func (p *playbackTracker) finish() {
+	p.mu.Lock()
+	p.complete = true
+	p.mu.Unlock()
+}
+
+// ack records the client's playback position within the response.
+func (p *playbackTracker) ack(ms int) {
+	p.mu.Lock()
+	if ms > p.playedMs {
+		p.playedMs = ms
+	}
+	p.mu.Unlock()
+}
+
+// position returns how far playback has got, in milliseconds. Without
+// acknowledgments, all audio sent to the client is assumed to have played.
+func (p *playbackTracker) position() int {
+	if p.playedMs >= 0 {
+		return min(p.playedMs, p.synthesizedMs)
+	}
+	return p.synthesizedMs
+}
+
+// heard returns the response text up to the last word played, and the
+// playback position it was cut at. Word timestamps from the TTS provider
+// are used when available; otherwise the cut is estimated from the audio
+// duration.
+func (p *playbackTracker) heard(words []tts.WordTimestamp) (string, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	text := p.text.String()
+	positionMs := p.position()
+	if len(words) > 0 {
+		return heardFromWords(text, words, positionMs), positionMs
+	}
+
+	msPerChar := defaultMsPerChar
+	if p.complete && len(text) > 0 && p.synthesizedMs > 0 {
+		msPerChar = float64(p.synthesizedMs) / float64(len(text))
+	}
+	return cutAtWord(text, int(float64(positionMs)/msPerChar)), positionMs
+}
+
+// heardFromWords returns text up to the end of the last word that finished
+// playing by positionMs. Words are matched in order so that punctuation
+// and spacing come from the original text.
+func heardFromWords(text string, words []tts.WordTimestamp, positionMs int) string {
+	end := 0
+	for _, w := range words {
+		if w.EndMs > positionMs {
+			break
+		}
+		i := indexWord(text[end:], w.Word)
+		if i < 0 {
+			break
+		}
+		end += i
+	}
+	// Keep punctuation attached to the last word
+	for end > 0 && end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !unicode.IsPunct(r) {
+			break
+		}
+		end += size
+	}
+	return text[:end]
+}
+
+// indexWord finds the first whole word of text that matches word, ignoring
+// case and surrounding punctuation, and returns where it ends, or -1.
+func indexWord(text, word string) int {
+	word = strings.TrimFunc(word, unicode.IsPunct)
+	if word == "" {
+		return 0
+	}
+	for off := 0; off < len(text); {
+		start := strings.IndexFunc(text[off:], func(r rune) bool { return !unicode.IsSpace(r) })
+		if start < 0 {
+			break
+		}
+		start += off
+		stop := strings.IndexFunc(text[start:], unicode.IsSpace)
+		if stop < 0 {
+			stop = len(text)
+		} else {
+			stop += start
+		}
+		field := strings.TrimRightFunc(text[start:stop], unicode.IsPunct)
+		if strings.EqualFold(strings.TrimLeftFunc(field, unicode.IsPunct), word) {
+			return start + len(field)
+		}
+		off = stop
+	}
+	return -1
+}
+
+// cutAtWord returns the first n bytes of text, shortened to end on a whole word.
+func cutAtWord(text string, n int) string {
+	if n >= len(text) {
+		return text
+	}
+	if n <= 0 {
+		return ""
+	}
+	if !unicode.IsSpace(rune(text[n])) {
+		n = strings.LastIndexFunc(text[:n], unicode.IsSpace)
+		if n < 0 {
+			return ""
+		}
+	}
+	return strings.TrimRightFunc(text[:n], unicode.IsSpace)
+}
+
+// truncateResponse cuts the assistant messages of an interrupted response,
+// which starts at messages[start], to the text the user heard. heard is the
+// spoken text, and roundEnds where the spoken text of each tool round ends
+// in it. Tool rounds heard in full keep their text as written; the round
+// the user was cut off in keeps the spoken text heard. Tool calls are kept,
+// since the tools ran, but text that was never played is removed. The final
+// round's text, which may not be recorded yet, is saved according to mode.
+func truncateResponse(messages []types.Message, start int, heard string, roundEnds []int, mode PartialSaveMode) []types.Message {
+	if start > len(messages) {
+		start = len(messages)
+	}
+	response := messages[start:]
+
+	// Drop the final round's text; it is re-added below, cut to what was heard
+	if n := len(response); n > 0 {
+		if last := response[n-1]; last.Role == "assistant" {
+			if _, ok := last.Content.(string); ok {
+				response = response[:n-1]
+			}
+		}
+	}
+
+	out := append([]types.Message(nil), messages[:start]...)
+	offset, round := 0, 0
+	for _, msg := range response {
+		if msg.Role == "assistant" {
+			if blocks, ok := msg.Content.([]types.ContentBlock); ok {
+				end := len(heard) + 1
+				if round < len(roundEnds) {
+					end = roundEnds[round]
+				}
+				round++
+				if end > len(heard) {
+					msg.Content = replaceText(blocks, strings.TrimSpace(heard[offset:]))
+					end = len(heard)
+				}
+				offset = end
+			}
+		}
+		out = append(out, msg)
+	}
+
+	tail := strings.TrimSpace(heard[offset:])
+	if tail == "" {
+		return out
+	}
+	switch mode {
+	case PartialSaveNone:
+		return out
+	case PartialSaveMarked:
+		tail += interruptedMarker
+	}
+	return append(out, types.Message{Role: "assistant", Content: tail})
+}
+
+// replaceText replaces the text blocks of a round with text, in place of
+// the first, dropping them all if text is empty.
+func replaceText(blocks []types.ContentBlock, text string) []types.ContentBlock {
+	out := make([]types.ContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		if _, ok := block.(types.TextBlock); !ok {
+			out = append(out, block)
+			continue
+		}
+		if text != "" {
+			out = append(out, types.TextBlock{Type: "text", Text: text})
+			text = ""
+		}
+	}
+	return out
+}
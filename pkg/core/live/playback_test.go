@@ -0,0 +1,326 @@
+package live
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+// timedTTS speaks every word for wordMs, pushing audio and word timestamps
+// as text arrives. It never finishes, so the response stays interruptible.
+type timedTTS struct {
+	wordMs     int
+	sampleRate int
+
+	mu     sync.Mutex
+	offset int
+}
+
+func (f *timedTTS) NewStreamingContext(ctx context.Context, opts tts.StreamingContextOptions) (*tts.StreamingContext, error) {
+	sc := tts.NewStreamingContext()
+	sc.SendFunc = func(text string, isFinal bool) error {
+		for _, word := range strings.Fields(text) {
+			f.mu.Lock()
+			start := f.offset
+			f.offset += f.wordMs
+			f.mu.Unlock()
+			sc.PushWordTimestamps(tts.WordTimestamp{Word: word, StartMs: start, EndMs: start + f.wordMs})
+			sc.PushAudio(make([]byte, f.sampleRate*2*f.wordMs/1000))
+		}
+		return nil
+	}
+	return sc, nil
+}
+
+func TestSession_InterruptKeepsHeardWords(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Sure, the store opens at nine and closes at five.", types.StopReasonEndTurn),
+		textResponse("Go ahead.", types.StopReasonEndTurn),
+	}}
+	interrupt := DefaultInterruptConfig()
+	interrupt.SavePartial = PartialSaveMarked
+	s := newToolTestSession(t, SessionConfig{
+		Model:      "test/model",
+		SampleRate: 24000,
+		Interrupt:  interrupt,
+	}, llm, &timedTTS{wordMs: 200, sampleRate: 24000})
+
+	s.startAgentProcessing("What are your hours?")
+
+	// Wait until audio for all ten words has been sent
+	for range 10 {
+		collectUntil[*AudioDeltaEvent](t, s)
+	}
+
+	// The client has played 900ms: four words, and part of the fifth
+	s.AckPlayback(900)
+	if err := s.Interrupt("wait, which store?"); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+
+	s.mu.Lock()
+	history := append([]types.Message(nil), s.messages[:3]...)
+	s.mu.Unlock()
+	if history[1].Role != "assistant" || history[1].Content != "Sure, the store opens [interrupted]" {
+		t.Errorf("assistant message = %#v", history[1])
+	}
+	if history[2].Content != "wait, which store?" {
+		t.Errorf("message after interrupt = %#v, want the interrupt transcript", history[2])
+	}
+
+	var interrupted *ResponseInterruptedEvent
+	for _, event := range collectUntil[*ResponseInterruptedEvent](t, s) {
+		interrupted, _ = event.(*ResponseInterruptedEvent)
+	}
+	if interrupted.PartialText != "Sure, the store opens" || interrupted.AudioPositionMs != 900 {
+		t.Errorf("ResponseInterruptedEvent = %+v", interrupted)
+	}
+}
+
+func TestSession_InterruptTextInputKeepsHistory(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Sure, the store opens at nine and closes at five.", types.StopReasonEndTurn),
+		textResponse("Go ahead.", types.StopReasonEndTurn),
+	}}
+	history := []types.Message{
+		{Role: "user", Content: "Where is my order?"},
+		{Role: "assistant", Content: []types.ContentBlock{types.TextBlock{Type: "text", Text: "It shipped yesterday."}}},
+	}
+	s := newToolTestSession(t, SessionConfig{
+		Model:      "test/model",
+		SampleRate: 24000,
+		Messages:   history,
+	}, llm, &timedTTS{wordMs: 200, sampleRate: 24000})
+
+	s.setState(StateListening)
+	if err := s.SendText("What are your hours?"); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		collectUntil[*AudioDeltaEvent](t, s)
+	}
+	s.AckPlayback(900)
+	if err := s.Interrupt("wait, which store?"); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+	collectUntil[*ResponseInterruptedEvent](t, s)
+
+	s.mu.Lock()
+	messages := append([]types.Message(nil), s.messages...)
+	s.mu.Unlock()
+	if !reflect.DeepEqual(messages[:2], history) {
+		t.Errorf("history = %#v, want it unchanged", messages[:2])
+	}
+	if messages[3].Role != "assistant" || messages[3].Content != "Sure, the store opens" {
+		t.Errorf("assistant message = %#v, want the words heard", messages[3])
+	}
+}
+
+func TestSession_InterruptKeepsSpokenText(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("It costs **$5** per month, billed yearly.", types.StopReasonEndTurn),
+		textResponse("Go ahead.", types.StopReasonEndTurn),
+	}}
+	s := newToolTestSession(t, SessionConfig{
+		Model:      "test/model",
+		SampleRate: 24000,
+		Voice:      &types.VoiceConfig{Output: &types.VoiceOutputConfig{Voice: "v"}},
+	}, llm, &timedTTS{wordMs: 200, sampleRate: 24000})
+
+	s.startAgentProcessing("How much is it?")
+
+	// "It costs five dollars per month, billed yearly."
+	for range 8 {
+		collectUntil[*AudioDeltaEvent](t, s)
+	}
+
+	// The word timestamps are of the spoken text, not the markdown
+	s.AckPlayback(1000)
+	if err := s.Interrupt("is that per user?"); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+
+	var interrupted *ResponseInterruptedEvent
+	for _, event := range collectUntil[*ResponseInterruptedEvent](t, s) {
+		interrupted, _ = event.(*ResponseInterruptedEvent)
+	}
+	if interrupted.PartialText != "It costs five dollars per" {
+		t.Errorf("PartialText = %q, want the words spoken", interrupted.PartialText)
+	}
+}
+
+func TestPlaybackTracker_Heard(t *testing.T) {
+	text := "Sure, the store opens at nine."
+	words := []tts.WordTimestamp{
+		{Word: "Sure", StartMs: 0, EndMs: 300},
+		{Word: "the", StartMs: 300, EndMs: 450},
+		{Word: "store", StartMs: 450, EndMs: 800},
+		{Word: "opens", StartMs: 800, EndMs: 1200},
+	}
+
+	tests := []struct {
+		name     string
+		audioMs  int
+		ackMs    int
+		complete bool
+		words    []tts.WordTimestamp
+		want     string
+	}{
+		{"words up to synthesized audio", 500, -1, false, words, "Sure, the"},
+		{"words up to acknowledged playback", 2000, 850, false, words, "Sure, the store"},
+		{"ack beyond synthesized audio", 350, 5000, false, words, "Sure,"},
+		{"nothing played", 1000, 0, false, words, ""},
+		{"estimated mid-word", 650, -1, false, nil, "Sure, the"},
+		{"estimated from complete synthesis", 1500, 1050, true, nil, "Sure, the store opens"},
+		{"everything played", 1500, -1, true, nil, text},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPlaybackTracker()
+			p.addText(text)
+			p.addAudio(tt.audioMs)
+			if tt.ackMs >= 0 {
+				p.ack(tt.ackMs)
+			}
+			if tt.complete {
+				p.finish()
+			}
+			if got, _ := p.heard(tt.words); got != tt.want {
+				t.Errorf("heard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeardFromWords(t *testing.T) {
+	tests := []struct {
+		text  string
+		words []string
+		want  string
+	}{
+		{"That is a test.", []string{"That", "is", "a"}, "That is a"},
+		{"That is a test.", []string{"that", "is", "a", "test"}, "That is a test."},
+		{"What about that? A fix.", []string{"What", "a"}, "What about that? A"},
+		{"Sure, the store opens.", []string{"Sure,", "the"}, "Sure, the"},
+		{"¿Qué tal? Bien…", []string{"Qué", "tal"}, "¿Qué tal?"},
+		{"Über «alles»", []string{"über", "alles"}, "Über «alles»"},
+		{"Hello there", []string{"Goodbye"}, ""},
+	}
+	for _, tt := range tests {
+		var words []tts.WordTimestamp
+		for i, w := range tt.words {
+			words = append(words, tts.WordTimestamp{Word: w, StartMs: i * 100, EndMs: (i + 1) * 100})
+		}
+		if got := heardFromWords(tt.text, words, 10000); got != tt.want {
+			t.Errorf("heardFromWords(%q, %q) = %q, want %q", tt.text, tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestCutAtWord(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{3, ""},
+		{5, "Hello"},
+		{6, "Hello"},
+		{9, "Hello"},
+		{11, "Hello there"},
+		{100, "Hello there"},
+	}
+	for _, tt := range tests {
+		if got := cutAtWord("Hello there", tt.n); got != tt.want {
+			t.Errorf("cutAtWord(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateResponse(t *testing.T) {
+	toolCall := types.ToolUseBlock{Type: "tool_use", ID: "call_1", Name: "lookup_order"}
+	history := []types.Message{
+		{Role: "user", Content: "Where is my order?"},
+		{Role: "assistant", Content: []types.ContentBlock{
+			types.TextBlock{Type: "text", Text: "Let me check that order. "},
+			toolCall,
+		}},
+		{Role: "user", Content: []types.ContentBlock{types.ToolResultBlock{Type: "tool_result", ToolUseID: "call_1"}}},
+		{Role: "assistant", Content: "It shipped yesterday and arrives Friday."},
+	}
+	rounds := []int{len("Let me check that order.")}
+
+	t.Run("cut in the final round", func(t *testing.T) {
+		got := truncateResponse(history, 1, "Let me check that order. It shipped yesterday", rounds, PartialSaveFull)
+		if len(got) != 4 || got[3].Content != "It shipped yesterday" {
+			t.Fatalf("got %#v", got)
+		}
+		blocks := got[1].Content.([]types.ContentBlock)
+		if len(blocks) != 2 || blocks[0].(types.TextBlock).Text != "Let me check that order. " {
+			t.Errorf("tool round = %#v", blocks)
+		}
+	})
+
+	t.Run("cut in a tool round", func(t *testing.T) {
+		got := truncateResponse(history, 1, "Let me check", rounds, PartialSaveMarked)
+		if len(got) != 3 {
+			t.Fatalf("got %d messages, want 3: %#v", len(got), got)
+		}
+		blocks := got[1].Content.([]types.ContentBlock)
+		if len(blocks) != 2 || blocks[0].(types.TextBlock).Text != "Let me check" || blocks[1].(types.ToolUseBlock).ID != "call_1" {
+			t.Errorf("tool round = %#v", blocks)
+		}
+	})
+
+	t.Run("nothing heard keeps tool calls", func(t *testing.T) {
+		got := truncateResponse(history, 1, "", rounds, PartialSaveMarked)
+		if len(got) != 3 {
+			t.Fatalf("got %d messages, want 3", len(got))
+		}
+		if blocks := got[1].Content.([]types.ContentBlock); len(blocks) != 1 || blocks[0].(types.ToolUseBlock).ID != "call_1" {
+			t.Errorf("tool round = %#v", blocks)
+		}
+	})
+
+	t.Run("none discards the final round", func(t *testing.T) {
+		got := truncateResponse(history, 1, "Let me check that order. It shipped", rounds, PartialSaveNone)
+		if len(got) != 3 {
+			t.Errorf("got %d messages, want 3", len(got))
+		}
+	})
+
+	t.Run("response not recorded yet", func(t *testing.T) {
+		got := truncateResponse(history[:1], 1, "Sure, the store", nil, PartialSaveMarked)
+		if len(got) != 2 || got[1].Content != "Sure, the store"+interruptedMarker {
+			t.Errorf("got %#v", got)
+		}
+	})
+
+	t.Run("cut in a tool round keeps the spoken text", func(t *testing.T) {
+		history := []types.Message{
+			{Role: "user", Content: "What does it cost?"},
+			{Role: "assistant", Content: []types.ContentBlock{
+				types.TextBlock{Type: "text", Text: "It's **$5** a month. Let me check for offers."},
+				toolCall,
+			}},
+		}
+		spoken := "It's five dollars a month. Let me check for offers."
+		got := truncateResponse(history, 1, "It's five dollars", []int{len(spoken)}, PartialSaveMarked)
+		if blocks := got[1].Content.([]types.ContentBlock); blocks[0].(types.TextBlock).Text != "It's five dollars" {
+			t.Errorf("tool round = %#v", blocks)
+		}
+		got = truncateResponse(history, 1, spoken+" None", []int{len(spoken)}, PartialSaveMarked)
+		if blocks := got[1].Content.([]types.ContentBlock); blocks[0].(types.TextBlock).Text != "It's **$5** a month. Let me check for offers." {
+			t.Errorf("tool round heard in full = %#v, want the text as written", blocks)
+		}
+	})
+
+	if history[3].Content != "It shipped yesterday and arrives Friday." {
+		t.Error("truncateResponse modified its input")
+	}
+}
@@ -0,0 +1,56 @@
+package live
+
+import (
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// Recorder captures everything a session hears, says and does, for
+// debugging calls after the fact. Set SessionConfig.Recorder to record a
+// session; the recording package writes recordings to disk and reads them
+// back. Methods are called from multiple goroutines.
+type Recorder interface {
+	// RecordInput records user audio as 16-bit PCM at the session sample rate.
+	RecordInput(data []byte)
+
+	// RecordEvent records each session event as it is emitted, including
+	// events dropped because the Events channel was full. Agent audio
+	// arrives as AudioDeltaEvents.
+	RecordEvent(event Event)
+
+	// RecordLLM records one agent LLM request and the response it streamed.
+	RecordLLM(exchange *LLMExchange)
+
+	// Close finishes the recording. The session calls it when it closes.
+	Close() error
+}
+
+// LLMExchange is one streamed agent LLM request and its response. A
+// response with tool calls is followed by another exchange with the results.
+type LLMExchange struct {
+	Request      *types.MessageRequest `json:"request"`
+	Text         string                `json:"text,omitempty"`
+	ToolCalls    []types.ToolUseBlock  `json:"tool_calls,omitempty"`
+	StopReason   types.StopReason      `json:"stop_reason,omitempty"`
+	Error        string                `json:"error,omitempty"`
+	Cancelled    bool                  `json:"cancelled,omitempty"`
+	StartedAt    time.Time             `json:"started_at"`
+	FirstTokenAt time.Time             `json:"first_token_at,omitzero"`
+	EndedAt      time.Time             `json:"ended_at"`
+}
+
+// recordInput passes user audio to the recorder, if any.
+func (s *Session) recordInput(data []byte) {
+	if s.config.Recorder != nil {
+		s.config.Recorder.RecordInput(data)
+	}
+}
+
+// recordLLM passes an LLM exchange to the recorder, if any.
+func (s *Session) recordLLM(exchange *LLMExchange) {
+	if s.config.Recorder != nil {
+		exchange.EndedAt = time.Now()
+		s.config.Recorder.RecordLLM(exchange)
+	}
+}
@@ -0,0 +1,79 @@
+package live
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// memoryRecorder keeps a session recording in memory.
+type memoryRecorder struct {
+	mu       sync.Mutex
+	input    int
+	events   []string
+	llm      []*LLMExchange
+	isClosed bool
+}
+
+func (r *memoryRecorder) RecordInput(data []byte) {
+	r.mu.Lock()
+	r.input += len(data)
+	r.mu.Unlock()
+}
+
+func (r *memoryRecorder) RecordEvent(event Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event.EventType())
+	r.mu.Unlock()
+}
+
+func (r *memoryRecorder) RecordLLM(exchange *LLMExchange) {
+	r.mu.Lock()
+	r.llm = append(r.llm, exchange)
+	r.mu.Unlock()
+}
+
+func (r *memoryRecorder) Close() error {
+	r.mu.Lock()
+	r.isClosed = true
+	r.mu.Unlock()
+	return nil
+}
+
+func TestSession_Recorder(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Hello there.", types.StopReasonEndTurn),
+	}}
+	rec := &memoryRecorder{}
+	s := newToolTestSession(t, SessionConfig{Model: "test/model", Recorder: rec}, llm, &recordingTTS{})
+
+	if err := s.SendAudio(make([]byte, 960)); err != nil {
+		t.Fatal(err)
+	}
+	s.startAgentProcessing("Hi")
+	collectUntil[*AudioCommittedEvent](t, s)
+	s.Close()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.input != 960 {
+		t.Errorf("recorded %d bytes of input, want 960", rec.input)
+	}
+	if len(rec.llm) != 1 {
+		t.Fatalf("recorded %d LLM exchanges, want 1", len(rec.llm))
+	}
+	exchange := rec.llm[0]
+	if exchange.Request.Model != "test/model" || exchange.Text != "Hello there." || exchange.StopReason != types.StopReasonEndTurn {
+		t.Errorf("LLM exchange = %+v", exchange)
+	}
+	if exchange.FirstTokenAt.IsZero() || exchange.EndedAt.Before(exchange.StartedAt) {
+		t.Errorf("LLM exchange times = %v, %v, %v", exchange.StartedAt, exchange.FirstTokenAt, exchange.EndedAt)
+	}
+	if len(rec.events) == 0 || rec.events[len(rec.events)-1] != "session.closed" {
+		t.Errorf("recorded events = %v, want session.closed last", rec.events)
+	}
+	if !rec.isClosed {
+		t.Error("recorder was not closed with the session")
+	}
+}
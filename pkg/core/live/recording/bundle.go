@@ -0,0 +1,239 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vango-go/vai/pkg/core/live"
+)
+
+// Bundle is a recording read back from disk.
+type Bundle struct {
+	Dir    string
+	Info   Info
+	Events []EventRecord
+	LLM    []live.LLMExchange
+}
+
+// Open reads the recording in dir. A recording whose session did not close
+// cleanly has no session file or audio; its events and LLM calls are still
+// read.
+func Open(dir string) (*Bundle, error) {
+	b := &Bundle{Dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, SessionFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &b.Info); err != nil {
+			return nil, fmt.Errorf("recording: %s: %w", SessionFile, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("recording: %w", err)
+	}
+
+	if err := readLines(filepath.Join(dir, EventsFile), func(line []byte) error {
+		var rec EventRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return err
+		}
+		b.Events = append(b.Events, rec)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := readLines(filepath.Join(dir, LLMFile), func(line []byte) error {
+		var exchange live.LLMExchange
+		if err := json.Unmarshal(line, &exchange); err != nil {
+			return err
+		}
+		b.LLM = append(b.LLM, exchange)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// AudioPath returns the path of the stereo recording, or "" if there is none.
+func (b *Bundle) AudioPath() string {
+	path := filepath.Join(b.Dir, AudioFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+func readLines(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("recording: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("recording: %s line %d: %w", filepath.Base(path), n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("recording: %w", err)
+	}
+	return nil
+}
+
+// Entry is one line of a timeline.
+type Entry struct {
+	TimeMs  int64
+	Type    string
+	Summary string
+}
+
+// TimelineOptions controls which entries a timeline includes.
+type TimelineOptions struct {
+	// Verbose includes audio chunks, energy levels, text deltas and debug
+	// messages, and does not shorten summaries.
+	Verbose bool
+}
+
+// noisyEvents are left out of timelines unless they are verbose.
+var noisyEvents = map[string]bool{
+	"audio_delta":         true,
+	"energy.level":        true,
+	"content_block_delta": true,
+	"transcript.delta":    true,
+	"debug":               true,
+}
+
+// maxSummary is the length summaries are shortened to.
+const maxSummary = 160
+
+// Timeline merges the events and LLM calls into one list in time order.
+func (b *Bundle) Timeline(opts TimelineOptions) []Entry {
+	var entries []Entry
+	for _, rec := range b.Events {
+		if noisyEvents[rec.Type] && !opts.Verbose {
+			continue
+		}
+		entries = append(entries, Entry{
+			TimeMs:  rec.TimeMs,
+			Type:    rec.Type,
+			Summary: summarizeEvent(rec.Event),
+		})
+	}
+
+	start := b.Info.StartedAt
+	for _, exchange := range b.LLM {
+		model, messages := "", 0
+		if exchange.Request != nil {
+			model, messages = exchange.Request.Model, len(exchange.Request.Messages)
+		}
+		entries = append(entries, Entry{
+			TimeMs:  sinceMs(start, exchange.StartedAt),
+			Type:    "llm.request",
+			Summary: fmt.Sprintf("%s, %d messages", model, messages),
+		})
+		entries = append(entries, Entry{
+			TimeMs:  sinceMs(start, exchange.EndedAt),
+			Type:    "llm.response",
+			Summary: summarizeExchange(exchange),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].TimeMs < entries[j].TimeMs })
+
+	if !opts.Verbose {
+		for i := range entries {
+			entries[i].Summary = shorten(entries[i].Summary, maxSummary)
+		}
+	}
+	return entries
+}
+
+// WriteTimeline writes the timeline as text, one entry per line.
+func (b *Bundle) WriteTimeline(w io.Writer, opts TimelineOptions) error {
+	if b.Info.SessionID != "" {
+		if _, err := fmt.Fprintf(w, "session %s, started %s, %d Hz\n", b.Info.SessionID, b.Info.StartedAt.Format(time.RFC3339), b.Info.SampleRate); err != nil {
+			return err
+		}
+	}
+	for _, entry := range b.Timeline(opts) {
+		if _, err := io.WriteString(w, FormatEntry(entry)+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatEntry formats an entry as a timeline line.
+func FormatEntry(entry Entry) string {
+	if entry.Summary == "" {
+		return fmt.Sprintf("%9.3fs  %s", float64(entry.TimeMs)/1000, entry.Type)
+	}
+	return fmt.Sprintf("%9.3fs  %-22s  %s", float64(entry.TimeMs)/1000, entry.Type, entry.Summary)
+}
+
+func summarizeEvent(data json.RawMessage) string {
+	s := strings.TrimSpace(string(data))
+	if s == "" || s == "{}" || s == "null" {
+		return ""
+	}
+	return s
+}
+
+func summarizeExchange(exchange live.LLMExchange) string {
+	var parts []string
+	if exchange.StopReason != "" {
+		parts = append(parts, string(exchange.StopReason))
+	}
+	if !exchange.FirstTokenAt.IsZero() {
+		parts = append(parts, fmt.Sprintf("first token %dms", exchange.FirstTokenAt.Sub(exchange.StartedAt).Milliseconds()))
+	}
+	if exchange.Cancelled {
+		parts = append(parts, "cancelled")
+	}
+	if exchange.Error != "" {
+		parts = append(parts, "error: "+exchange.Error)
+	}
+	for _, call := range exchange.ToolCalls {
+		input, _ := json.Marshal(call.Input)
+		parts = append(parts, fmt.Sprintf("tool %s%s", call.Name, input))
+	}
+	if exchange.Text != "" {
+		parts = append(parts, fmt.Sprintf("%q", exchange.Text))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sinceMs(start, t time.Time) int64 {
+	if start.IsZero() || t.IsZero() {
+		return 0
+	}
+	return t.Sub(start).Milliseconds()
+}
+
+func shorten(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// Avoid splitting a multi-byte character
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
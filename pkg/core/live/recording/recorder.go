@@ -0,0 +1,345 @@
+// Package recording writes live session recordings to disk and reads them
+// back for debugging.
+//
+// A recording is a directory bundle:
+//
+//	session.json  session ID, start and end time, sample rate and config
+//	events.jsonl  every session event, with its time since the start
+//	llm.jsonl     every agent LLM request and the response it streamed
+//	audio.wav     stereo 16-bit PCM: the user on the left channel and the
+//	              agent on the right, aligned to wall-clock time
+//
+// Agent audio is placed where the client would have played it: TTS usually
+// runs ahead of real time, so its audio is queued behind what was already
+// sent, and audio discarded by a flush or interrupt is cut from the track.
+//
+// Usage:
+//
+//	rec, err := recording.New("recordings/" + callID)
+//	if err != nil {
+//	    return err
+//	}
+//	cfg.Recorder = rec
+//	session := live.NewSession(cfg, llm, ttsClient, sttClient)
+//
+// The live-replay command renders a bundle as a timeline.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/live"
+)
+
+// Bundle file names.
+const (
+	SessionFile = "session.json"
+	EventsFile  = "events.jsonl"
+	LLMFile     = "llm.jsonl"
+	AudioFile   = "audio.wav"
+
+	userTrackFile  = "user.pcm"
+	agentTrackFile = "agent.pcm"
+)
+
+// DefaultSampleRate is used until the session reports its sample rate.
+const DefaultSampleRate = 24000
+
+// Info describes a recorded session.
+type Info struct {
+	SessionID  string              `json:"session_id"`
+	StartedAt  time.Time           `json:"started_at"`
+	EndedAt    time.Time           `json:"ended_at,omitzero"`
+	SampleRate int                 `json:"sample_rate"`
+	Config     *live.SessionConfig `json:"config,omitempty"`
+}
+
+// EventRecord is one recorded session event.
+type EventRecord struct {
+	TimeMs int64           `json:"time_ms"`
+	Type   string          `json:"type"`
+	Event  json.RawMessage `json:"event,omitempty"`
+}
+
+// audioDeltaRecord replaces the audio in recorded audio_delta events,
+// which is kept in the agent track instead.
+type audioDeltaRecord struct {
+	Format string `json:"format,omitempty"`
+	Bytes  int    `json:"bytes"`
+}
+
+// Recorder writes a session recording to a directory. It implements
+// live.Recorder.
+type Recorder struct {
+	dir   string
+	start time.Time
+	now   func() time.Time
+
+	mu     sync.Mutex
+	info   Info
+	events *bufio.Writer
+	llm    *bufio.Writer
+	files  []*os.File
+	user   *track
+	agent  *track
+	err    error
+	closed bool
+}
+
+var _ live.Recorder = (*Recorder)(nil)
+
+// New creates the directory and starts a recording in it.
+func New(dir string) (*Recorder, error) {
+	return newRecorder(dir, time.Now)
+}
+
+func newRecorder(dir string, now func() time.Time) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("recording: %w", err)
+	}
+
+	r := &Recorder{dir: dir, start: now(), now: now}
+	r.info = Info{StartedAt: r.start, SampleRate: DefaultSampleRate}
+
+	create := func(name string) (*os.File, error) {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			r.closeFiles()
+			return nil, fmt.Errorf("recording: %w", err)
+		}
+		r.files = append(r.files, f)
+		return f, nil
+	}
+
+	events, err := create(EventsFile)
+	if err != nil {
+		return nil, err
+	}
+	llm, err := create(LLMFile)
+	if err != nil {
+		return nil, err
+	}
+	user, err := create(userTrackFile)
+	if err != nil {
+		return nil, err
+	}
+	agent, err := create(agentTrackFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.events = bufio.NewWriter(events)
+	r.llm = bufio.NewWriter(llm)
+	r.user = &track{f: user}
+	r.agent = &track{f: agent}
+	return r, nil
+}
+
+// Dir returns the bundle directory.
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// RecordInput records user audio at the current time.
+func (r *Recorder) RecordInput(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.setErr(r.user.write(data, r.elapsedSamples()))
+}
+
+// RecordEvent records a session event. Agent audio goes to the agent
+// track, and flushes cut queued agent audio that was never played.
+func (r *Recorder) RecordEvent(event live.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+
+	var payload any = event
+	switch e := event.(type) {
+	case *live.SessionCreatedEvent:
+		r.info.SessionID = e.SessionID
+		r.info.Config = e.Config
+		if e.SampleRate > 0 {
+			r.info.SampleRate = e.SampleRate
+		}
+	case *live.AudioDeltaEvent:
+		r.setErr(r.agent.write(e.Data, r.elapsedSamples()))
+		payload = audioDeltaRecord{Format: e.Format, Bytes: len(e.Data)}
+	case *live.AudioFlushEvent, *live.ResponseInterruptedEvent:
+		r.setErr(r.agent.cut(r.elapsedSamples()))
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		r.setErr(err)
+		return
+	}
+	r.writeLine(r.events, EventRecord{
+		TimeMs: r.elapsed().Milliseconds(),
+		Type:   event.EventType(),
+		Event:  data,
+	})
+}
+
+// RecordLLM records an LLM request and its response.
+func (r *Recorder) RecordLLM(exchange *live.LLMExchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.writeLine(r.llm, exchange)
+}
+
+// Close writes the audio and session files and finishes the recording.
+// It returns the first error that occurred while recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	r.info.EndedAt = r.now()
+
+	r.setErr(r.events.Flush())
+	r.setErr(r.llm.Flush())
+	r.setErr(r.writeAudio())
+	r.setErr(r.closeFiles())
+	if r.err == nil {
+		os.Remove(filepath.Join(r.dir, userTrackFile))
+		os.Remove(filepath.Join(r.dir, agentTrackFile))
+	}
+
+	info, err := json.MarshalIndent(r.info, "", "  ")
+	if err != nil {
+		r.setErr(err)
+		return r.err
+	}
+	r.setErr(os.WriteFile(filepath.Join(r.dir, SessionFile), info, 0o644))
+	return r.err
+}
+
+// writeAudio interleaves the two tracks into a stereo WAV file.
+func (r *Recorder) writeAudio() error {
+	frames := max(r.user.samples, r.agent.samples)
+	header, err := audio.WAVHeader(audio.PCM16(r.info.SampleRate, 2), int(frames*4))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(r.dir, AudioFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	w.Write(header)
+
+	const chunk = 4096 // samples per track per read
+	left := make([]byte, chunk*2)
+	right := make([]byte, chunk*2)
+	frame := make([]byte, chunk*4)
+	for off := int64(0); off < frames; off += chunk {
+		n := min(chunk, frames-off)
+		if err := r.user.read(left[:n*2], off); err != nil {
+			return err
+		}
+		if err := r.agent.read(right[:n*2], off); err != nil {
+			return err
+		}
+		for i := range n {
+			copy(frame[i*4:], left[i*2:i*2+2])
+			copy(frame[i*4+2:], right[i*2:i*2+2])
+		}
+		w.Write(frame[:n*4])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (r *Recorder) writeLine(w *bufio.Writer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.setErr(err)
+		return
+	}
+	w.Write(data)
+	r.setErr(w.WriteByte('\n'))
+}
+
+func (r *Recorder) elapsed() time.Duration {
+	return r.now().Sub(r.start)
+}
+
+func (r *Recorder) elapsedSamples() int64 {
+	return int64(r.elapsed().Seconds() * float64(r.info.SampleRate))
+}
+
+func (r *Recorder) setErr(err error) {
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("recording: %w", err)
+	}
+}
+
+func (r *Recorder) closeFiles() error {
+	var errs []error
+	for _, f := range r.files {
+		errs = append(errs, f.Close())
+	}
+	r.files = nil
+	return errors.Join(errs...)
+}
+
+// track is one party's mono 16-bit PCM, positioned in time.
+type track struct {
+	f       *os.File
+	samples int64 // samples written, including silence
+}
+
+// write places data at the given time, or straight after earlier audio
+// if that has not finished yet. Gaps are left as silence.
+func (t *track) write(data []byte, at int64) error {
+	pos := max(t.samples, at)
+	if _, err := t.f.WriteAt(data, pos*2); err != nil {
+		return err
+	}
+	t.samples = pos + int64(len(data)/2)
+	return nil
+}
+
+// cut removes audio queued after the given time.
+func (t *track) cut(at int64) error {
+	if t.samples <= at {
+		return nil
+	}
+	t.samples = at
+	return t.f.Truncate(at * 2)
+}
+
+// read fills buf with the samples at off, padding past the end with silence.
+func (t *track) read(buf []byte, off int64) error {
+	n, err := t.f.ReadAt(buf, off*2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	clear(buf[n:])
+	return nil
+}
@@ -0,0 +1,200 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/live"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// fakeClock is advanced by tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+func (c *fakeClock) advance(ms int) { c.t = c.t.Add(time.Duration(ms) * time.Millisecond) }
+
+// tone returns ms of constant-valued PCM.
+func tone(value int16, ms, sampleRate int) []byte {
+	return pcm(value, ms*sampleRate/1000)
+}
+
+func pcm(value int16, n int) []byte {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = value
+	}
+	return audio.EncodePCM16(samples)
+}
+
+func TestRecorder(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "call")
+	clock := &fakeClock{t: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	start := clock.t
+	rec, err := newRecorder(dir, clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const rate = 16000
+	rec.RecordEvent(&live.SessionCreatedEvent{SessionID: "live_1", SampleRate: rate, Config: &live.SessionConfig{Model: "test/model"}})
+
+	// The user speaks for 100ms
+	rec.RecordInput(tone(1000, 100, rate))
+
+	// At 50ms the agent starts; 200ms of TTS arrives at once
+	clock.advance(50)
+	rec.RecordLLM(&live.LLMExchange{
+		Request:    &types.MessageRequest{Model: "test/model", Messages: []types.Message{{Role: "user", Content: "hi"}}},
+		Text:       "Hello there.",
+		StopReason: types.StopReasonEndTurn,
+		StartedAt:  start,
+		EndedAt:    clock.t,
+	})
+	rec.RecordEvent(&live.AudioDeltaEvent{Data: tone(2000, 200, rate), Format: "pcm_s16le"})
+
+	// At 150ms the user interrupts, discarding the agent's last 100ms
+	clock.advance(100)
+	rec.RecordEvent(&live.ResponseInterruptedEvent{PartialText: "Hello"})
+	clock.advance(10)
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for _, name := range []string{userTrackFile, agentTrackFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", name)
+		}
+	}
+
+	b, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if b.Info.SessionID != "live_1" || b.Info.SampleRate != rate || !b.Info.EndedAt.Equal(clock.t) {
+		t.Errorf("Info = %+v", b.Info)
+	}
+	if b.Info.Config == nil || b.Info.Config.Model != "test/model" {
+		t.Errorf("config not recorded: %+v", b.Info.Config)
+	}
+	if len(b.Events) != 3 || b.Events[1].Type != "audio_delta" || b.Events[2].TimeMs != 150 {
+		t.Fatalf("Events = %+v", b.Events)
+	}
+	if !strings.Contains(string(b.Events[1].Event), `"bytes":6400`) || strings.Contains(string(b.Events[1].Event), `"data"`) {
+		t.Errorf("audio_delta recorded as %s", b.Events[1].Event)
+	}
+	if len(b.LLM) != 1 || b.LLM[0].Text != "Hello there." || b.LLM[0].Request.Messages[0].Content != "hi" {
+		t.Errorf("LLM = %+v", b.LLM)
+	}
+
+	data, err := os.ReadFile(b.AudioPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav, err := audio.ParseWAV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wav.Format != audio.PCM16(rate, 2) {
+		t.Fatalf("audio format = %v", wav.Format)
+	}
+	if frames := len(wav.Data) / 4; frames != 150*rate/1000 {
+		t.Errorf("audio is %d frames, want 150ms", frames)
+	}
+	sample := func(ms, channel int) int16 {
+		i := (ms*rate/1000)*4 + channel*2
+		return int16(binary.LittleEndian.Uint16(wav.Data[i:]))
+	}
+	for _, tt := range []struct {
+		ms          int
+		user, agent int16
+	}{
+		{25, 1000, 0},
+		{75, 1000, 2000},
+		{125, 0, 2000},
+	} {
+		if u, a := sample(tt.ms, 0), sample(tt.ms, 1); u != tt.user || a != tt.agent {
+			t.Errorf("at %dms got user %d agent %d, want %d and %d", tt.ms, u, a, tt.user, tt.agent)
+		}
+	}
+}
+
+func TestRecorder_QueuesAgentAudio(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	rec, err := newRecorder(t.TempDir(), clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.info.SampleRate = 1000
+
+	// Chunks arriving faster than real time play back to back
+	rec.RecordEvent(&live.AudioDeltaEvent{Data: pcm(1, 100)})
+	clock.advance(10)
+	rec.RecordEvent(&live.AudioDeltaEvent{Data: pcm(2, 100)})
+	if rec.agent.samples != 200 {
+		t.Errorf("agent track has %d samples, want 200", rec.agent.samples)
+	}
+
+	// A chunk after a pause starts at its arrival time
+	clock.advance(290)
+	rec.RecordEvent(&live.AudioDeltaEvent{Data: pcm(3, 100)})
+	if rec.agent.samples != 400 {
+		t.Errorf("agent track has %d samples, want 400", rec.agent.samples)
+	}
+
+	// A flush keeps only what has played
+	clock.advance(50)
+	rec.RecordEvent(&live.AudioFlushEvent{})
+	if rec.agent.samples != 350 {
+		t.Errorf("agent track has %d samples after flush, want 350", rec.agent.samples)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBundle_Timeline(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := &Bundle{
+		Info: Info{SessionID: "live_1", StartedAt: start, SampleRate: 24000},
+		Events: []EventRecord{
+			{TimeMs: 0, Type: "session.created", Event: []byte(`{"session_id":"live_1"}`)},
+			{TimeMs: 900, Type: "vad.committed", Event: []byte(`{"transcript":"What time is it?"}`)},
+			{TimeMs: 1500, Type: "audio_delta", Event: []byte(`{"bytes":960}`)},
+			{TimeMs: 2500, Type: "vad.listening", Event: []byte(`{}`)},
+		},
+		LLM: []live.LLMExchange{{
+			Request:      &types.MessageRequest{Model: "test/model", Messages: make([]types.Message, 3)},
+			Text:         "It's noon.",
+			StopReason:   types.StopReasonEndTurn,
+			StartedAt:    start.Add(1000 * time.Millisecond),
+			FirstTokenAt: start.Add(1250 * time.Millisecond),
+			EndedAt:      start.Add(1400 * time.Millisecond),
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := b.WriteTimeline(&buf, TimelineOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `session live_1, started 2026-01-02T03:04:05Z, 24000 Hz
+    0.000s  session.created         {"session_id":"live_1"}
+    0.900s  vad.committed           {"transcript":"What time is it?"}
+    1.000s  llm.request             test/model, 3 messages
+    1.400s  llm.response            end_turn, first token 250ms, "It's noon."
+    2.500s  vad.listening
+`
+	if buf.String() != want {
+		t.Errorf("timeline:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if entries := b.Timeline(TimelineOptions{Verbose: true}); len(entries) != 6 {
+		t.Errorf("verbose timeline has %d entries, want 6", len(entries))
+	}
+}
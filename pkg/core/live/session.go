@@ -10,7 +10,12 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/vango-go/vai/pkg/core/audio"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/filler"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 	"github.com/vango-go/vai/pkg/core/voice/stt"
 	"github.com/vango-go/vai/pkg/core/voice/tts"
 )
@@ -68,7 +73,12 @@ type Session struct {
 	sessionID         string
 	messages          []types.Message
 	currentTranscript string
-	partialResponse   string
+	responseStart     int    // index in messages where the current response begins
+	sttLanguage       string // language detected by STT, if it reports one
+
+	// Input conversion for SendAudio
+	inputConverter *audio.Converter
+	inputMu        sync.Mutex
 
 	// STT session
 	sttSession *stt.StreamingSTT
@@ -80,6 +90,18 @@ type Session struct {
 	ttsPaused   bool
 	ttsPosition int
 
+	// Playback of the current response, for truncating it on interrupt
+	playback *playbackTracker
+
+	// Fillers played while the agent waits; nil when none are configured
+	fillers *filler.Player
+
+	// Echo suppression of the input; nil when disabled
+	echo *EchoSuppressor
+
+	// Per-turn latency
+	latency *latencyTracker
+
 	// Channels
 	events chan Event
 	audio  chan []byte
@@ -126,6 +148,8 @@ func NewSession(
 		events:      make(chan Event, 100),
 		audio:       make(chan []byte, 100),
 		done:        make(chan struct{}),
+		playback:    newPlaybackTracker(),
+		latency:     newLatencyTracker(),
 	}
 
 	// Copy initial messages if provided
@@ -196,8 +220,27 @@ func (s *Session) Start(ctx context.Context) error {
 	return nil
 }
 
-// initComponents initializes VAD, grace period, and interrupt detector.
+// initComponents initializes input conversion, VAD, grace period, and interrupt detector.
 func (s *Session) initComponents() error {
+	// Create input converter for non-PCM clients
+	target := s.audioFormat()
+	if input := s.config.InputFormat.WithDefaults(target); input != target {
+		converter, err := audio.NewConverter(input, target)
+		if err != nil {
+			return fmt.Errorf("input format: %w", err)
+		}
+		s.inputConverter = converter
+		s.debug("AUDIO", fmt.Sprintf("Converting input audio from %s to %s", input, target))
+	}
+
+	if err := s.chunkConfig().Validate(); err != nil {
+		return err
+	}
+	if err := s.initFillers(); err != nil {
+		return err
+	}
+	s.initEcho()
+
 	// Create semantic checker for VAD
 	vadChecker := NewDefaultSemanticChecker(func(ctx context.Context, transcript string) (bool, error) {
 		return s.checkTurnComplete(ctx, transcript)
@@ -206,7 +249,10 @@ func (s *Session) initComponents() error {
 	// Create VAD
 	s.vad = NewHybridVAD(s.config.VAD, s.audioConfig, vadChecker)
 	s.vad.SetCallbacks(
-		nil, // onSilence - not used in punctuation-based VAD
+		func(durationMs int) {
+			s.latency.speechEnded(durationMs)
+			s.emit(&VADSilenceEvent{DurationMs: durationMs})
+		},
 		func(transcript string) { s.emit(&VADAnalyzingEvent{Transcript: transcript}) },
 		func(transcript string, forced bool) { s.onVADCommit(transcript, forced) },
 		func(category, message string) { s.debug(category, message) },
@@ -236,6 +282,7 @@ func (s *Session) initComponents() error {
 		nil, // Removed: onConfirmed - logic moved to handleInterruptResult
 		func(category, message string) { s.debug(category, message) },
 	)
+	s.interrupt.SetLanguage(s.language())
 
 	return nil
 }
@@ -246,7 +293,6 @@ func (s *Session) startSTT() error {
 	defer s.sttMu.Unlock()
 
 	opts := stt.TranscribeOptions{
-		Model:      "ink-whisper",
 		Language:   "en",
 		Format:     "pcm_s16le",
 		SampleRate: s.audioConfig.SampleRate,
@@ -271,11 +317,22 @@ func (s *Session) startSTT() error {
 }
 
 // SendAudio sends audio data to the session for processing.
+// Audio is converted from SessionConfig.InputFormat, or from the format in
+// its header if it is a WAV file.
 func (s *Session) SendAudio(data []byte) error {
 	if s.closed.Load() {
 		return fmt.Errorf("session closed")
 	}
 
+	data, err := s.normalizeInput(data)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	s.recordInput(data)
+
 	select {
 	case s.audio <- data:
 		return nil
@@ -288,6 +345,29 @@ func (s *Session) SendAudio(data []byte) error {
 	}
 }
 
+// audioFormat returns the 16-bit PCM format used inside the session.
+func (s *Session) audioFormat() audio.Format {
+	return audio.PCM16(s.audioConfig.SampleRate, s.audioConfig.Channels)
+}
+
+// normalizeInput converts client audio to the session format.
+func (s *Session) normalizeInput(data []byte) ([]byte, error) {
+	if audio.IsWAV(data) {
+		wav, err := audio.ParseWAV(data)
+		if err != nil {
+			return nil, err
+		}
+		return audio.Convert(wav.Data, wav.Format, s.audioFormat())
+	}
+
+	s.inputMu.Lock()
+	defer s.inputMu.Unlock()
+	if s.inputConverter == nil {
+		return data, nil
+	}
+	return s.inputConverter.Convert(data)
+}
+
 // Commit forces the VAD to commit the current turn.
 // Useful for push-to-talk style interaction.
 func (s *Session) Commit() error {
@@ -312,43 +392,24 @@ func (s *Session) Commit() error {
 func (s *Session) Interrupt(transcript string) error {
 	s.mu.Lock()
 	state := s.state
-	partial := s.partialResponse
 	s.mu.Unlock()
 
 	if state != StateSpeaking && state != StateProcessing {
 		return fmt.Errorf("nothing to interrupt")
 	}
 
-	// Save partial assistant response to conversation history
-	s.mu.Lock()
-	if partial != "" {
-		s.messages = append(s.messages, types.Message{
-			Role:    "assistant",
-			Content: partial,
-		})
-	}
-	s.mu.Unlock()
-
-	// Emit interrupt event
-	s.emit(&ResponseInterruptedEvent{
-		PartialText:         partial,
-		InterruptTranscript: transcript,
-		AudioPositionMs:     s.ttsPosition,
-	})
-
-	// Cancel agent/TTS and flush audio
-	s.cancelAgent()
-	s.cancelTTS()
-	s.emit(&AudioFlushEvent{})
-	s.setState(StateListening)
-
-	// Process interrupt through normal VAD commit flow
-	s.vad.SetTranscript(transcript)
-	s.onVADCommit(transcript, false)
-
+	s.interruptResponse(transcript)
 	return nil
 }
 
+// AckPlayback reports how much of the current response's audio the client
+// has played, in milliseconds from the start of the response. When the user
+// interrupts, only the words played by then are kept in the conversation
+// history. Without acknowledgments, all audio sent is assumed to be heard.
+func (s *Session) AckPlayback(positionMs int) {
+	s.playback.ack(positionMs)
+}
+
 // SendText sends a discrete text message as a complete user turn.
 // This bypasses VAD and grace period - the text is processed as a complete turn.
 // If the session is speaking or processing, it waits for the response to complete.
@@ -398,7 +459,7 @@ func (s *Session) processDiscreteInput(content []types.ContentBlock) error {
 		s.gracePeriod.Cancel()
 		s.cancelAgent()
 		s.cancelTTS()
-		s.emit(&AudioFlushEvent{})
+		s.flushAudio()
 		s.processDiscreteInputNow(content)
 
 	case StateProcessing, StateSpeaking:
@@ -431,10 +492,8 @@ func (s *Session) processDiscreteInputNow(content []types.ContentBlock) {
 
 	// Add to conversation and trigger agent
 	s.mu.Lock()
-	s.messages = append(s.messages, types.Message{
-		Role:    "user",
-		Content: content,
-	})
+	s.appendUserTurn(content)
+	s.responseStart = len(s.messages)
 	messages := make([]types.Message, len(s.messages))
 	copy(messages, s.messages)
 	s.mu.Unlock()
@@ -443,6 +502,7 @@ func (s *Session) processDiscreteInputNow(content []types.ContentBlock) {
 	agentCtx, agentCancel := context.WithCancel(s.ctx)
 	s.agentCancel = agentCancel
 
+	s.latency.startResponse()
 	s.setState(StateProcessing)
 	s.emit(&InputCommittedEvent{
 		Transcript: contentToString(content),
@@ -545,6 +605,12 @@ func (s *Session) Close() error {
 	// Close events channel
 	close(s.events)
 
+	if s.config.Recorder != nil {
+		if err := s.config.Recorder.Close(); err != nil {
+			return fmt.Errorf("close recorder: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -564,6 +630,9 @@ func (s *Session) audioLoop() {
 
 // processAudio handles a single audio chunk based on current state.
 func (s *Session) processAudio(data []byte) {
+	// Only the user's own speech counts from here on
+	data = s.echo.Process(data)
+
 	s.mu.RLock()
 	state := s.state
 	s.mu.RUnlock()
@@ -578,8 +647,9 @@ func (s *Session) processAudio(data []byte) {
 		}
 		s.sttMu.Unlock()
 
-		// Note: VAD turn detection is now handled via punctuation triggers in processTranscriptDelta
-		// No need to call vad.ProcessAudio - the timeout checker runs in background
+		// Turn detection is driven by punctuation triggers in processTranscriptDelta.
+		// The acoustic VAD, when enabled, adds speech start/end signals from the audio.
+		s.vad.ProcessAudio(data)
 
 	case StateProcessing:
 		// During processing (e.g., waiting for server-side tool execution like web_search),
@@ -591,7 +661,7 @@ func (s *Session) processAudio(data []byte) {
 			s.debug("INTERRUPT", "Speech detected during PROCESSING state")
 			// Cancel the agent immediately - user wants to interrupt before response starts
 			s.cancelAgent()
-			s.emit(&AudioFlushEvent{})
+			s.flushAudio()
 			s.setState(StateListening)
 
 			// Send audio to STT to capture what the user said
@@ -612,7 +682,7 @@ func (s *Session) processAudio(data []byte) {
 				s.cancelAgent()
 				// Also cancel TTS if it somehow started
 				s.cancelTTS()
-				s.emit(&AudioFlushEvent{})
+				s.flushAudio()
 			}
 		}
 
@@ -639,7 +709,7 @@ func (s *Session) processAudio(data []byte) {
 				s.debug("GRACE", "User speech detected (energy), cancelling TTS immediately")
 				s.cancelAgent()
 				s.cancelTTS()
-				s.emit(&AudioFlushEvent{})
+				s.flushAudio()
 			}
 
 			// Send audio to STT to capture the transcript
@@ -728,6 +798,13 @@ func (s *Session) processTranscriptDelta(delta stt.TranscriptDelta) {
 
 	s.debug("STT", fmt.Sprintf("Transcribed: %q (final: %v)", delta.Text, delta.IsFinal))
 
+	if delta.Text != "" {
+		s.latency.transcribed()
+	}
+	if delta.Language != "" {
+		s.setLanguage(delta.Language)
+	}
+
 	switch state {
 	case StateListening:
 		// Check if grace period is still active (e.g., TTS finished but grace window still open)
@@ -768,6 +845,7 @@ func (s *Session) handlePotentialInterrupt(audioData []byte) {
 	s.pauseTTS()
 
 	// Start capture
+	s.latency.interruptStarted()
 	s.interrupt.StartCapture()
 	s.interrupt.AddAudio(audioData)
 
@@ -781,50 +859,61 @@ func (s *Session) handleInterruptResult(result InterruptResult) {
 	case InterruptNone, InterruptBackchannel:
 		// Resume TTS
 		s.debug("INTERRUPT", "Resuming TTS after "+result.String())
+		s.latency.interruptDismissed()
 		s.resumeTTS()
 		s.setState(StateSpeaking)
 
 	case InterruptReal:
 		s.debug("INTERRUPT", "Real interrupt confirmed")
+		s.latency.interruptConfirmed()
 
 		// Get the interrupt transcript
 		transcript := s.interrupt.GetCapturedTranscript()
+		s.interruptResponse(transcript)
+	}
+}
 
-		// Save partial assistant response to conversation history
-		s.mu.Lock()
-		partial := s.partialResponse
-		if partial != "" {
-			s.messages = append(s.messages, types.Message{
-				Role:    "assistant",
-				Content: partial,
-			})
-		}
-		s.mu.Unlock()
+// interruptResponse stops the current response, keeps the part the user
+// heard in the conversation history, and responds to the interruption.
+func (s *Session) interruptResponse(transcript string) {
+	// Stop the agent first so it records nothing more for this response
+	s.cancelAgent()
 
-		// Emit interrupt event
-		s.emit(&ResponseInterruptedEvent{
-			PartialText:         partial,
-			InterruptTranscript: transcript,
-			AudioPositionMs:     s.ttsPosition,
-		})
+	var words []tts.WordTimestamp
+	s.ttsMu.Lock()
+	if s.ttsContext != nil {
+		words = s.ttsContext.WordTimestamps()
+	}
+	s.ttsMu.Unlock()
+	heard, positionMs := s.playback.heard(words)
 
-		// Cancel agent/TTS and flush audio
-		s.cancelAgent()
-		s.cancelTTS()
-		s.emit(&AudioFlushEvent{})
-		s.setState(StateListening)
+	// Save the partial assistant response to conversation history
+	s.mu.Lock()
+	s.messages = truncateResponse(s.messages, s.responseStart, heard, s.playback.rounds(), s.config.Interrupt.SavePartial)
+	s.mu.Unlock()
 
-		// Process interrupt through normal VAD commit flow
-		// This starts agent processing + grace period for continuation
-		s.vad.SetTranscript(transcript)
-		s.onVADCommit(transcript, false)
-	}
+	s.emit(&ResponseInterruptedEvent{
+		PartialText:         heard,
+		InterruptTranscript: transcript,
+		AudioPositionMs:     positionMs,
+	})
+
+	// Cancel TTS and flush audio
+	s.cancelTTS()
+	s.flushAudio()
+	s.setState(StateListening)
+
+	// Process interrupt through normal VAD commit flow
+	// This starts agent processing + grace period for continuation
+	s.vad.SetTranscript(transcript)
+	s.onVADCommit(transcript, false)
 }
 
 // onVADCommit is called when VAD commits a turn.
 func (s *Session) onVADCommit(transcript string, forced bool) {
 	// Note: Don't debug log here - VADCommittedEvent conveys the same info
 
+	s.latency.commit()
 	s.emit(&VADCommittedEvent{
 		Transcript: transcript,
 		Forced:     forced,
@@ -868,13 +957,14 @@ func (s *Session) onGracePeriodContinuation(combined string) {
 
 	// Cancel any pending agent request
 	s.cancelAgent()
+	s.latency.continued()
 
 	// Cancel TTS if it's running
 	s.cancelTTS()
 
 	// Signal client to flush audio buffers immediately
 	// This ensures any audio already sent to the speaker is discarded
-	s.emit(&AudioFlushEvent{})
+	s.flushAudio()
 
 	s.emit(&GracePeriodExtendedEvent{
 		PreviousTranscript: s.currentTranscript,
@@ -909,14 +999,14 @@ func (s *Session) startAgentProcessing(transcript string) {
 
 	// Add user message
 	s.mu.Lock()
-	s.messages = append(s.messages, types.Message{
-		Role:    "user",
-		Content: transcript,
-	})
+	s.appendUserTurn(transcript)
+	s.responseStart = len(s.messages)
 	messages := make([]types.Message, len(s.messages))
 	copy(messages, s.messages)
 	s.mu.Unlock()
 
+	s.latency.startResponse()
+
 	// Create agent context
 	agentCtx, agentCancel := context.WithCancel(s.ctx)
 	s.agentCancel = agentCancel
@@ -925,9 +1015,117 @@ func (s *Session) startAgentProcessing(transcript string) {
 }
 
 // runAgent executes the agent with streaming and pipes to TTS incrementally.
+// Tool calls are executed between rounds and their results fed back to the
+// model, with speech continuing across rounds on the same TTS context.
 func (s *Session) runAgent(ctx context.Context, messages []types.Message) {
-	s.debug("LLM", "Sending to "+s.config.Model+" (streaming)")
+	var ttsCtx *tts.StreamingContext
+	buffer := NewChunkingTTSBuffer(s.newChunker(), s.newNormalizer())
+	firstChunk := true
+
+	for round := 1; ; round++ {
+		s.debug("LLM", "Sending to "+s.config.Model+" (streaming)")
+
+		// Start streaming LLM request
+		req := s.buildAgentRequest(messages)
+		exchange := &LLMExchange{Request: req, StartedAt: time.Now()}
+		stream, err := s.llmClient.StreamMessage(ctx, req)
+		if err != nil {
+			exchange.Error = err.Error()
+			exchange.Cancelled = ctx.Err() != nil
+			s.recordLLM(exchange)
+			if ctx.Err() != nil {
+				return
+			}
+			s.emit(&ErrorEvent{Code: "llm_error", Message: err.Error()})
+			if ttsCtx != nil {
+				// Let speech from earlier rounds finish
+				ttsCtx.Flush()
+			} else {
+				s.setState(StateListening)
+			}
+			return
+		}
+
+		// Create TTS context upfront for streaming
+		if ttsCtx == nil {
+			ttsCtx, err = s.createTTSContext(ctx)
+			if err != nil {
+				stream.Close()
+				s.debug("TTS", "Failed to create context: "+err.Error())
+				s.emit(&ErrorEvent{Code: "tts_error", Message: err.Error()})
+				s.setState(StateListening)
+				return
+			}
+
+			// Start audio streaming in background
+			go s.streamTTSAudio(ctx, ttsCtx)
+		}
 
+		turn, ok := s.readAgentStream(ctx, stream, ttsCtx, buffer, &firstChunk)
+		stream.Close()
+		exchange.Text = turn.text
+		exchange.ToolCalls = turn.toolCalls
+		exchange.StopReason = turn.stopReason
+		exchange.FirstTokenAt = turn.firstTokenAt
+		exchange.Cancelled = !ok
+		if turn.err != nil {
+			exchange.Error = turn.err.Error()
+		}
+		s.recordLLM(exchange)
+		if !ok {
+			return
+		}
+
+		if turn.stopReason != types.StopReasonToolUse || len(turn.toolCalls) == 0 {
+			s.finishAgent(ttsCtx, buffer, turn.text)
+			return
+		}
+		if round >= s.maxToolRounds() {
+			s.debug("TOOL", fmt.Sprintf("Tool round limit (%d) reached", round))
+			s.finishAgent(ttsCtx, buffer, turn.text)
+			return
+		}
+
+		// Speak what we have so far while the tools run
+		if remaining := buffer.Flush(); remaining != "" {
+			s.debug("TTS", "Sending chunk: "+remaining)
+			s.speak(ttsCtx, remaining, false)
+		}
+		s.playback.endRound()
+
+		// Fill the silence if the tools are slow
+		tools := make([]string, len(turn.toolCalls))
+		for i, call := range turn.toolCalls {
+			tools[i] = call.Name
+		}
+		s.fillers.Wait(tools...)
+
+		results := s.executeTools(ctx, turn.toolCalls)
+		if ctx.Err() != nil {
+			return
+		}
+
+		content := make([]types.ContentBlock, 0, len(turn.toolCalls)+1)
+		if turn.text != "" {
+			content = append(content, types.TextBlock{Type: "text", Text: turn.text})
+		}
+		for _, call := range turn.toolCalls {
+			content = append(content, call)
+		}
+		toolMessages := []types.Message{
+			{Role: "assistant", Content: content},
+			{Role: "user", Content: results},
+		}
+		messages = append(messages, toolMessages...)
+
+		s.mu.Lock()
+		s.messages = append(s.messages, toolMessages...)
+		s.mu.Unlock()
+	}
+}
+
+// buildAgentRequest builds the LLM request for the conversation so far.
+func (s *Session) buildAgentRequest(messages []types.Message) *types.MessageRequest {
 	req := &types.MessageRequest{
 		Model:     s.config.Model,
 		Messages:  messages,
@@ -943,35 +1141,24 @@ func (s *Session) runAgent(ctx context.Context, messages []types.Message) {
 	if s.config.Temperature != nil {
 		req.Temperature = s.config.Temperature
 	}
+	return req
+}
 
-	// Start streaming LLM request
-	stream, err := s.llmClient.StreamMessage(ctx, req)
-	if err != nil {
-		if ctx.Err() != nil {
-			return
-		}
-		s.emit(&ErrorEvent{Code: "llm_error", Message: err.Error()})
-		s.setState(StateListening)
-		return
-	}
-	defer stream.Close()
-
-	// Create TTS context upfront for streaming
-	ttsCtx, err := s.createTTSContext(ctx)
-	if err != nil {
-		s.debug("TTS", "Failed to create context: "+err.Error())
-		s.emit(&ErrorEvent{Code: "tts_error", Message: err.Error()})
-		s.setState(StateListening)
-		return
-	}
-
-	// Start audio streaming in background
-	go s.streamTTSAudio(ctx, ttsCtx)
+// agentTurn is the result of reading one streamed LLM response.
+type agentTurn struct {
+	text         string
+	toolCalls    []types.ToolUseBlock
+	stopReason   types.StopReason
+	firstTokenAt time.Time
+	err          error // stream error, after which the turn is cut short
+}
 
-	// Process LLM stream and pipe to TTS via buffer
-	buffer := NewTTSBuffer()
-	var fullText strings.Builder
-	firstChunk := true
+// readAgentStream pipes text deltas from one LLM response to TTS and collects
+// its tool calls. It returns false if the agent was cancelled.
+func (s *Session) readAgentStream(ctx context.Context, stream EventStream, ttsCtx *tts.StreamingContext, buffer *TTSBuffer, firstChunk *bool) (agentTurn, bool) {
+	var turn agentTurn
+	var text strings.Builder
+	calls := make(map[int]*toolCall)
 
 	for {
 		event, err := stream.Next()
@@ -982,85 +1169,172 @@ func (s *Session) runAgent(ctx context.Context, messages []types.Message) {
 			if ctx.Err() != nil {
 				s.debug("LLM", "Stream cancelled")
 				buffer.Reset()
-				return
+				turn.text = text.String()
+				return turn, false
 			}
 			s.debug("LLM", "Stream error: "+err.Error())
+			turn.err = err
 			break
 		}
 
-		// Handle content block delta events (check both value and pointer types)
-		var textDelta types.TextDelta
-		var deltaIndex int
-		var hasTextDelta bool
-
+		// Providers may yield events as values or pointers
 		switch e := event.(type) {
-		case types.ContentBlockDeltaEvent:
-			deltaIndex = e.Index
-			if td, ok := e.Delta.(types.TextDelta); ok {
-				textDelta = td
-				hasTextDelta = true
-			}
+		case *types.ContentBlockStartEvent:
+			event = *e
 		case *types.ContentBlockDeltaEvent:
-			deltaIndex = e.Index
-			if td, ok := e.Delta.(types.TextDelta); ok {
-				textDelta = td
-				hasTextDelta = true
-			}
+			event = *e
+		case *types.ContentBlockStopEvent:
+			event = *e
+		case *types.MessageDeltaEvent:
+			event = *e
 		}
 
-		if hasTextDelta {
-			text := textDelta.Text
-			fullText.WriteString(text)
+		switch e := event.(type) {
+		case types.ContentBlockStartEvent:
+			if tu, ok := e.ContentBlock.(types.ToolUseBlock); ok {
+				calls[e.Index] = &toolCall{block: tu}
+			}
 
-			// Emit delta event
-			s.emit(&ContentBlockDeltaEvent{Index: deltaIndex, Delta: text})
+		case types.ContentBlockDeltaEvent:
+			switch delta := e.Delta.(type) {
+			case types.TextDelta:
+				if turn.firstTokenAt.IsZero() {
+					turn.firstTokenAt = time.Now()
+					s.latency.firstToken()
+					// The response is coming; any filler playing ends with its audio
+					s.fillers.Done()
+				}
+				text.WriteString(delta.Text)
 
-			// Log first chunk for latency tracking
-			if firstChunk {
-				s.debug("LLM", "First token received")
-				firstChunk = false
-				s.setState(StateSpeaking)
-			}
+				// Emit delta event
+				s.emit(&ContentBlockDeltaEvent{Index: e.Index, Delta: delta.Text})
+
+				// Log first chunk for latency tracking
+				if *firstChunk {
+					s.debug("LLM", "First token received")
+					*firstChunk = false
+					s.setState(StateSpeaking)
+				}
 
-			// Buffer and send to TTS when ready
-			if chunk := buffer.Add(text); chunk != "" {
-				s.debug("TTS", "Sending chunk: "+chunk)
-				if err := ttsCtx.SendText(chunk, false); err != nil {
-					s.debug("TTS", "Send error: "+err.Error())
+				// Buffer and send to TTS when ready
+				if chunk := buffer.Add(delta.Text); chunk != "" {
+					s.debug("TTS", "Sending chunk: "+chunk)
+					s.speak(ttsCtx, chunk, false)
 				}
+			case types.InputJSONDelta:
+				if call, ok := calls[e.Index]; ok {
+					call.input.WriteString(delta.PartialJSON)
+				}
+			}
+
+		case types.ContentBlockStopEvent:
+			if call, ok := calls[e.Index]; ok {
+				tu := call.finish()
+				turn.toolCalls = append(turn.toolCalls, tu)
+				delete(calls, e.Index)
+				s.debug("TOOL", "Tool call: "+tu.Name)
+				s.emit(&ToolUseEvent{ID: tu.ID, Name: tu.Name, Input: tu.Input})
+			}
+
+		case types.MessageDeltaEvent:
+			if e.Delta.StopReason != "" {
+				turn.stopReason = e.Delta.StopReason
 			}
 		}
 	}
 
+	turn.text = text.String()
+	return turn, true
+}
+
+// finishAgent flushes the remaining speech and records the final response.
+// lastText is the text of the final round; earlier rounds already recorded
+// theirs alongside their tool calls.
+func (s *Session) finishAgent(ttsCtx *tts.StreamingContext, buffer *TTSBuffer, lastText string) {
 	// Flush remaining text to TTS
 	if remaining := buffer.Flush(); remaining != "" {
 		s.debug("TTS", "Sending final chunk: "+remaining)
-		if err := ttsCtx.SendText(remaining, true); err != nil {
-			s.debug("TTS", "Final send error: "+err.Error())
-		}
+		s.speak(ttsCtx, remaining, true)
 	} else {
 		// No remaining text, just flush TTS
 		ttsCtx.Flush()
 	}
 
 	// Update conversation history
-	finalText := fullText.String()
-	if finalText != "" {
-		s.mu.Lock()
-		s.partialResponse = finalText
+	s.mu.Lock()
+	if lastText != "" {
 		s.messages = append(s.messages, types.Message{
 			Role:    "assistant",
-			Content: finalText,
+			Content: lastText,
 		})
-		s.mu.Unlock()
 	}
+	s.mu.Unlock()
 
 	s.debug("LLM", "Stream complete")
 	s.emit(&MessageStopEvent{})
 }
 
-// createTTSContext creates a TTS streaming context.
-func (s *Session) createTTSContext(ctx context.Context) (*tts.StreamingContext, error) {
+// noReplyText stands in for a response that left nothing in the history.
+const noReplyText = "[no reply]"
+
+// appendUserTurn adds a user message to the history. A response that
+// produced no text after a tool round, or was interrupted before any of it
+// was heard, leaves the history ending on a user message; a placeholder
+// assistant message is added first so roles keep alternating.
+// Must be called with s.mu held.
+func (s *Session) appendUserTurn(content any) {
+	if n := len(s.messages); n > 0 && s.messages[n-1].Role == "user" {
+		s.messages = append(s.messages, types.Message{
+			Role:    "assistant",
+			Content: noReplyText,
+		})
+	}
+	s.messages = append(s.messages, types.Message{
+		Role:    "user",
+		Content: content,
+	})
+}
+
+// speak sends a chunk of response text to TTS, recording it as the text
+// the user hears.
+func (s *Session) speak(ttsCtx *tts.StreamingContext, text string, isFinal bool) {
+	s.playback.addText(text)
+	s.latency.ttsText()
+	if err := ttsCtx.SendText(text, isFinal); err != nil {
+		s.debug("TTS", "Send error: "+err.Error())
+	}
+}
+
+// chunkConfig returns the chunking config of the built-in strategies.
+func (s *Session) chunkConfig() chunk.Config {
+	c := s.config.Chunking
+	if c == nil && s.config.Voice != nil && s.config.Voice.Output != nil {
+		c = s.config.Voice.Output.Chunking
+	}
+	return voice.ChunkConfig(c, DefaultTTSChunking)
+}
+
+// newChunker creates the chunker for one response. The config was checked
+// when the session started.
+func (s *Session) newChunker() chunk.Chunker {
+	if s.config.Chunker != nil {
+		return s.config.Chunker()
+	}
+	c, _ := chunk.New(s.chunkConfig())
+	return c
+}
+
+// newNormalizer creates the normalizer for one response, or nil if text is
+// spoken as written.
+func (s *Session) newNormalizer() normalize.Normalizer {
+	if s.config.Normalizer != nil {
+		return s.config.Normalizer()
+	}
+	return voice.NewNormalizer(s.config.Voice)
+}
+
+// ttsOptions returns the options for the session's TTS contexts.
+func (s *Session) ttsOptions() tts.StreamingContextOptions {
 	opts := tts.StreamingContextOptions{
 		SampleRate: s.audioConfig.SampleRate,
 		Format:     "pcm",
@@ -1076,11 +1350,13 @@ func (s *Session) createTTSContext(ctx context.Context) (*tts.StreamingContext,
 			opts.SampleRate = s.config.Voice.Output.SampleRate
 		}
 	}
+	return opts
+}
 
-	if opts.Voice == "" {
-		s.debug("TTS", "No voice ID configured, using default")
-		opts.Voice = "98a34ef2-2140-4c28-9c71-663dc4dd7022"
-	}
+// createTTSContext creates a TTS streaming context.
+func (s *Session) createTTSContext(ctx context.Context) (*tts.StreamingContext, error) {
+	opts := s.ttsOptions()
+	opts.WordTimestamps = true
 
 	s.debug("TTS", fmt.Sprintf("Creating TTS context (voice: %s, rate: %d, format: %s)", opts.Voice, opts.SampleRate, opts.Format))
 
@@ -1102,6 +1378,7 @@ func (s *Session) createTTSContext(ctx context.Context) (*tts.StreamingContext,
 	s.ttsContext = ttsCtx
 	s.ttsPaused = false
 	s.ttsPosition = 0
+	s.playback.reset()
 
 	return ttsCtx, nil
 }
@@ -1111,28 +1388,8 @@ func (s *Session) startTTS(ctx context.Context, text string) {
 	s.setState(StateSpeaking)
 	s.debug("TTS", "Synthesizing: "+text)
 
-	// Build TTS options with sensible defaults
-	opts := tts.StreamingContextOptions{
-		SampleRate: s.audioConfig.SampleRate,
-		Format:     "pcm", // Raw PCM for lowest latency
-	}
-	if s.config.Voice != nil && s.config.Voice.Output != nil {
-		opts.Voice = s.config.Voice.Output.Voice
-		opts.Speed = s.config.Voice.Output.Speed
-		opts.Volume = s.config.Voice.Output.Volume
-		if s.config.Voice.Output.Format != "" {
-			opts.Format = s.config.Voice.Output.Format
-		}
-		if s.config.Voice.Output.SampleRate > 0 {
-			opts.SampleRate = s.config.Voice.Output.SampleRate
-		}
-	}
-
-	// Ensure we have a voice ID
-	if opts.Voice == "" {
-		s.debug("TTS", "No voice ID configured, using default")
-		opts.Voice = "98a34ef2-2140-4c28-9c71-663dc4dd7022" // Sonic default
-	}
+	// An empty voice leaves the choice to the provider
+	opts := s.ttsOptions()
 
 	s.debug("TTS", fmt.Sprintf("Creating TTS context (voice: %s, rate: %d, format: %s)", opts.Voice, opts.SampleRate, opts.Format))
 
@@ -1213,6 +1470,7 @@ func (s *Session) streamTTSAudio(ctx context.Context, ttsCtx *tts.StreamingConte
 				}
 				s.debug("TTS", fmt.Sprintf("Synthesis complete (%d chunks, %dms)", audioChunks, s.ttsPosition))
 				s.emit(&AudioCommittedEvent{DurationMs: s.ttsPosition})
+				s.playback.finish()
 
 				s.setState(StateListening)
 				s.emit(&VADListeningEvent{})
@@ -1233,16 +1491,23 @@ func (s *Session) streamTTSAudio(ctx context.Context, ttsCtx *tts.StreamingConte
 				continue
 			}
 
+			// Stop any filler, discarding what the client has buffered of it
+			durationMs := s.audioConfig.DurationMs(len(audioData))
+			if s.fillers.Speak(time.Duration(durationMs) * time.Millisecond) {
+				s.flushAudio()
+			}
+
 			// Emit audio
-			s.emit(&AudioDeltaEvent{
-				Data:   audioData,
-				Format: "pcm_s16le",
-			})
+			s.emitAudio(audioData)
+			if latency := s.latency.firstAudio(); latency != nil {
+				s.emit(latency)
+			}
 
 			// Update position
 			s.ttsMu.Lock()
-			s.ttsPosition += s.audioConfig.DurationMs(len(audioData))
+			s.ttsPosition += durationMs
 			s.ttsMu.Unlock()
+			s.playback.addAudio(durationMs)
 		}
 	}
 }
@@ -1281,8 +1546,36 @@ func (s *Session) cancelTTS() {
 	}
 }
 
+// initEcho creates the echo suppressor, if enabled. The audio sent to the
+// client is its reference, so it needs PCM output.
+func (s *Session) initEcho() {
+	if !s.config.Interrupt.EchoSuppression.Enabled {
+		return
+	}
+	opts := s.ttsOptions()
+	if opts.Format != "pcm" {
+		s.debug("ECHO", fmt.Sprintf("Echo suppression needs pcm output, not %s; disabled", opts.Format))
+		return
+	}
+	s.echo = NewEchoSuppressor(s.config.Interrupt.EchoSuppression, s.audioConfig, opts.SampleRate)
+}
+
+// emitAudio sends audio to the client, and to echo suppression as the
+// reference for its echo.
+func (s *Session) emitAudio(data []byte) {
+	s.echo.AddReference(data)
+	s.emit(&AudioDeltaEvent{Data: data, Format: "pcm_s16le"})
+}
+
+// flushAudio tells the client to discard the audio it has buffered.
+func (s *Session) flushAudio() {
+	s.echo.Flush()
+	s.emit(&AudioFlushEvent{})
+}
+
 // cancelAgent cancels the current agent request.
 func (s *Session) cancelAgent() {
+	s.fillers.Stop()
 	if s.agentCancel != nil {
 		s.agentCancel()
 		s.agentCancel = nil
@@ -1291,7 +1584,11 @@ func (s *Session) cancelAgent() {
 
 // checkTurnComplete performs semantic turn completion check.
 func (s *Session) checkTurnComplete(ctx context.Context, transcript string) (bool, error) {
-	prompt := fmt.Sprintf(TurnCompletePrompt, transcript)
+	start := time.Now()
+	defer func() { s.latency.semanticChecked(time.Since(start)) }()
+
+	language := s.language()
+	prompt := fmt.Sprintf(s.config.VAD.turnCompletePrompt(language), transcript)
 
 	// Use VAD-specific model or fall back to main model
 	model := s.config.VAD.Model
@@ -1312,12 +1609,12 @@ func (s *Session) checkTurnComplete(ctx context.Context, transcript string) (boo
 		return false, err
 	}
 
-	return ParseTurnCompleteResponse(resp.TextContent()), nil
+	return parseTurnComplete(language, resp.TextContent()), nil
 }
 
 // checkInterrupt performs semantic interrupt check.
 func (s *Session) checkInterrupt(ctx context.Context, transcript string) (bool, error) {
-	prompt := fmt.Sprintf(InterruptCheckPrompt, transcript)
+	prompt := fmt.Sprintf(s.config.Interrupt.interruptCheckPrompt(s.language()), transcript)
 
 	// Use interrupt-specific model or fall back to main model
 	model := s.config.Interrupt.SemanticModel
@@ -1351,11 +1648,22 @@ func (s *Session) setState(newState SessionState) {
 	if oldState != newState {
 		s.debug("SESSION", fmt.Sprintf("State: %s -> %s", oldState, newState))
 		s.emit(&StateChangedEvent{From: oldState, To: newState})
+
+		// Fillers cover the wait for a response, until its audio arrives
+		switch newState {
+		case StateProcessing:
+			s.fillers.Wait()
+		case StateListening, StateClosed:
+			s.fillers.Stop()
+		}
 	}
 }
 
 // emit sends an event to the events channel.
 func (s *Session) emit(event Event) {
+	if s.config.Recorder != nil {
+		s.config.Recorder.RecordEvent(event)
+	}
 	select {
 	case s.events <- event:
 	case <-s.done:
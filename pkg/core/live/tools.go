@@ -0,0 +1,129 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// ToolHandler executes a tool call made by the agent during a live session.
+// It has the same signature as the SDK's ToolHandler, so SDK handlers can be
+// converted directly.
+type ToolHandler func(ctx context.Context, input json.RawMessage) (any, error)
+
+// DefaultMaxToolRounds is the number of tool call rounds allowed per response
+// when SessionConfig.MaxToolRounds is zero.
+const DefaultMaxToolRounds = 5
+
+// toolCall tracks a tool_use block while its input is streamed.
+type toolCall struct {
+	block types.ToolUseBlock
+	input strings.Builder
+}
+
+// finish parses the streamed input into the tool_use block.
+// Blocks without input deltas keep the input from content_block_start.
+func (c *toolCall) finish() types.ToolUseBlock {
+	if c.input.Len() > 0 {
+		var input map[string]any
+		if err := json.Unmarshal([]byte(c.input.String()), &input); err == nil {
+			c.block.Input = input
+		}
+	}
+	if c.block.Input == nil {
+		c.block.Input = map[string]any{}
+	}
+	return c.block
+}
+
+// maxToolRounds returns the configured tool round limit.
+func (s *Session) maxToolRounds() int {
+	if s.config.MaxToolRounds > 0 {
+		return s.config.MaxToolRounds
+	}
+	return DefaultMaxToolRounds
+}
+
+// executeTools runs tool calls in order and returns their results.
+// Calls without a registered handler produce an error result so the model
+// can recover.
+func (s *Session) executeTools(ctx context.Context, calls []types.ToolUseBlock) []types.ContentBlock {
+	results := make([]types.ContentBlock, 0, len(calls))
+	for _, call := range calls {
+		content, err := s.executeTool(ctx, call)
+		result := types.ToolResultBlock{
+			Type:      "tool_result",
+			ToolUseID: call.ID,
+			Content:   content,
+			IsError:   err != nil,
+		}
+		s.emit(&ToolResultEvent{
+			ID:      call.ID,
+			Name:    call.Name,
+			Content: toolResultText(content),
+			IsError: err != nil,
+		})
+		results = append(results, result)
+	}
+	return results
+}
+
+// executeTool runs a single tool call.
+func (s *Session) executeTool(ctx context.Context, call types.ToolUseBlock) ([]types.ContentBlock, error) {
+	handler, ok := s.config.ToolHandlers[call.Name]
+	if !ok {
+		err := fmt.Errorf("no handler registered for tool %q", call.Name)
+		return []types.ContentBlock{types.TextBlock{Type: "text", Text: err.Error()}}, err
+	}
+
+	input, err := json.Marshal(call.Input)
+	if err != nil {
+		return []types.ContentBlock{types.TextBlock{
+			Type: "text",
+			Text: fmt.Sprintf("Error marshaling tool input: %v", err),
+		}}, err
+	}
+
+	s.debug("TOOL", "Executing "+call.Name)
+	output, err := handler(ctx, input)
+	if err != nil {
+		s.debug("TOOL", call.Name+" failed: "+err.Error())
+		return []types.ContentBlock{types.TextBlock{
+			Type: "text",
+			Text: fmt.Sprintf("Error executing tool: %v", err),
+		}}, err
+	}
+	return toolOutputContent(output), nil
+}
+
+// toolOutputContent converts a handler's output to tool result content.
+func toolOutputContent(output any) []types.ContentBlock {
+	switch v := output.(type) {
+	case string:
+		return []types.ContentBlock{types.TextBlock{Type: "text", Text: v}}
+	case []types.ContentBlock:
+		return v
+	case types.ContentBlock:
+		return []types.ContentBlock{v}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return []types.ContentBlock{types.TextBlock{Type: "text", Text: fmt.Sprintf("%v", v)}}
+		}
+		return []types.ContentBlock{types.TextBlock{Type: "text", Text: string(data)}}
+	}
+}
+
+// toolResultText returns the text parts of tool result content.
+func toolResultText(content []types.ContentBlock) string {
+	var parts []string
+	for _, block := range content {
+		if text, ok := block.(types.TextBlock); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,336 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+// scriptedLLM streams one scripted response per request.
+type scriptedLLM struct {
+	mu        sync.Mutex
+	responses [][]types.StreamEvent
+	requests  []*types.MessageRequest
+}
+
+func (l *scriptedLLM) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (l *scriptedLLM) StreamMessage(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.requests) >= len(l.responses) {
+		return nil, errors.New("no scripted response")
+	}
+	l.requests = append(l.requests, req)
+	return &sliceEventStream{events: l.responses[len(l.requests)-1]}, nil
+}
+
+type sliceEventStream struct {
+	events []types.StreamEvent
+}
+
+func (s *sliceEventStream) Next() (types.StreamEvent, error) {
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func (s *sliceEventStream) Close() error { return nil }
+
+// recordingTTS records the text sent to it and finishes on the final chunk.
+type recordingTTS struct {
+	mu   sync.Mutex
+	text []string
+}
+
+func (r *recordingTTS) NewStreamingContext(ctx context.Context, opts tts.StreamingContextOptions) (*tts.StreamingContext, error) {
+	sc := tts.NewStreamingContext()
+	sc.SendFunc = func(text string, isFinal bool) error {
+		r.mu.Lock()
+		if text != "" {
+			r.text = append(r.text, text)
+		}
+		r.mu.Unlock()
+		if isFinal {
+			sc.FinishAudio()
+		}
+		return nil
+	}
+	return sc, nil
+}
+
+func (r *recordingTTS) spoken() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.text, " ")
+}
+
+func textResponse(text string, stop types.StopReason, tools ...types.StreamEvent) []types.StreamEvent {
+	events := []types.StreamEvent{
+		types.MessageStartEvent{Type: "message_start"},
+		types.ContentBlockStartEvent{Type: "content_block_start", Index: 0, ContentBlock: types.TextBlock{Type: "text"}},
+		types.ContentBlockDeltaEvent{Type: "content_block_delta", Index: 0, Delta: types.TextDelta{Type: "text_delta", Text: text}},
+		types.ContentBlockStopEvent{Type: "content_block_stop", Index: 0},
+	}
+	events = append(events, tools...)
+	delta := &types.MessageDeltaEvent{Type: "message_delta"}
+	delta.Delta.StopReason = stop
+	return append(events, delta, types.MessageStopEvent{Type: "message_stop"})
+}
+
+func toolUseEvents(index int, id, name string, fragments ...string) []types.StreamEvent {
+	events := []types.StreamEvent{
+		types.ContentBlockStartEvent{Type: "content_block_start", Index: index, ContentBlock: types.ToolUseBlock{
+			Type: "tool_use", ID: id, Name: name, Input: map[string]any{},
+		}},
+	}
+	for _, f := range fragments {
+		events = append(events, &types.ContentBlockDeltaEvent{
+			Type: "content_block_delta", Index: index, Delta: types.InputJSONDelta{Type: "input_json_delta", PartialJSON: f},
+		})
+	}
+	return append(events, types.ContentBlockStopEvent{Type: "content_block_stop", Index: index})
+}
+
+func newToolTestSession(t *testing.T, config SessionConfig, llm LLMClient, ttsClient TTSClient) *Session {
+	t.Helper()
+	config.VAD = DefaultVADConfig()
+	s := NewSession(config, llm, ttsClient, nil)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	t.Cleanup(s.cancel)
+	if err := s.initComponents(); err != nil {
+		t.Fatalf("initComponents() error = %v", err)
+	}
+	return s
+}
+
+// collectUntil gathers session events until one of type T arrives.
+func collectUntil[T Event](t *testing.T, s *Session) []Event {
+	t.Helper()
+	var events []Event
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-s.events:
+			events = append(events, event)
+			if _, ok := event.(T); ok {
+				return events
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %T", *new(T))
+			return nil
+		}
+	}
+}
+
+func TestSession_DefaultVoiceOfProvider(t *testing.T) {
+	var mu sync.Mutex
+	var voices []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Voice string }
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		voices = append(voices, req.Voice)
+		mu.Unlock()
+		w.Write(make([]byte, 4800))
+	}))
+	defer srv.Close()
+
+	// OpenAI comes first when it is the only TTS key
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{textResponse("Hello there.", types.StopReasonEndTurn)}}
+	registry := tts.NewRegistry(tts.NewOpenAI("sk-test", tts.WithBaseURL(srv.URL)))
+	s := newToolTestSession(t, SessionConfig{Model: "test/model", SampleRate: 24000}, llm, registry)
+
+	s.startAgentProcessing("Hi")
+	collectUntil[*AudioDeltaEvent](t, s)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(voices) == 0 || voices[0] != "alloy" {
+		t.Errorf("voices = %q, want OpenAI's default voice", voices)
+	}
+}
+
+func TestSession_RunsToolLoop(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Let me check that order.", types.StopReasonToolUse,
+			toolUseEvents(1, "call_1", "lookup_order", `{"order_`, `id": "A17"}`)...),
+		textResponse("Your order shipped yesterday.", types.StopReasonEndTurn),
+	}}
+	ttsClient := &recordingTTS{}
+
+	var gotInput map[string]any
+	s := newToolTestSession(t, SessionConfig{
+		Model: "test/model",
+		Tools: []types.Tool{{Type: types.ToolTypeFunction, Name: "lookup_order"}},
+		ToolHandlers: map[string]ToolHandler{
+			"lookup_order": func(ctx context.Context, input json.RawMessage) (any, error) {
+				if err := json.Unmarshal(input, &gotInput); err != nil {
+					return nil, err
+				}
+				return map[string]string{"status": "shipped"}, nil
+			},
+		},
+	}, llm, ttsClient)
+
+	messages := []types.Message{{Role: "user", Content: "Where is order A17?"}}
+	s.messages = append(s.messages, messages...)
+	s.runAgent(s.ctx, messages)
+	events := collectUntil[*AudioCommittedEvent](t, s)
+
+	var toolUse *ToolUseEvent
+	var toolResult *ToolResultEvent
+	for _, event := range events {
+		switch e := event.(type) {
+		case *ToolUseEvent:
+			toolUse = e
+		case *ToolResultEvent:
+			toolResult = e
+		}
+	}
+	if toolUse == nil || toolUse.ID != "call_1" || toolUse.Name != "lookup_order" {
+		t.Fatalf("ToolUseEvent = %+v", toolUse)
+	}
+	if gotInput["order_id"] != "A17" {
+		t.Errorf("handler input = %v, want order_id A17", gotInput)
+	}
+	if toolResult == nil || toolResult.Content != `{"status":"shipped"}` || toolResult.IsError {
+		t.Fatalf("ToolResultEvent = %+v", toolResult)
+	}
+
+	if len(llm.requests) != 2 {
+		t.Fatalf("got %d LLM requests, want 2", len(llm.requests))
+	}
+	followUp := llm.requests[1].Messages
+	if len(followUp) != 3 {
+		t.Fatalf("follow-up request has %d messages, want 3", len(followUp))
+	}
+	results, ok := followUp[2].Content.([]types.ContentBlock)
+	if !ok || len(results) != 1 {
+		t.Fatalf("tool result message content = %#v", followUp[2].Content)
+	}
+	if tr, ok := results[0].(types.ToolResultBlock); !ok || tr.ToolUseID != "call_1" {
+		t.Errorf("tool result block = %#v", results[0])
+	}
+
+	if got := ttsClient.spoken(); got != "Let me check that order. Your order shipped yesterday." {
+		t.Errorf("spoken text = %q", got)
+	}
+
+	s.mu.RLock()
+	history := s.messages
+	s.mu.RUnlock()
+	if len(history) != 4 {
+		t.Fatalf("history has %d messages, want 4", len(history))
+	}
+	if history[3].Role != "assistant" || history[3].Content != "Your order shipped yesterday." {
+		t.Errorf("final history message = %+v", history[3])
+	}
+}
+
+func TestSession_SilentToolFollowUpKeepsRolesAlternating(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("Cancelling it now.", types.StopReasonToolUse,
+			toolUseEvents(1, "call_1", "cancel_order", `{"order_id": "A17"}`)...),
+		textResponse("", types.StopReasonEndTurn),
+		textResponse("You're welcome.", types.StopReasonEndTurn),
+	}}
+	s := newToolTestSession(t, SessionConfig{
+		Model: "test/model",
+		ToolHandlers: map[string]ToolHandler{
+			"cancel_order": func(ctx context.Context, input json.RawMessage) (any, error) {
+				return "cancelled", nil
+			},
+		},
+	}, llm, &recordingTTS{})
+
+	s.startAgentProcessing("Cancel order A17.")
+	collectUntil[*MessageStopEvent](t, s)
+	s.startAgentProcessing("Thanks.")
+	collectUntil[*MessageStopEvent](t, s)
+
+	if len(llm.requests) != 3 {
+		t.Fatalf("got %d LLM requests, want 3", len(llm.requests))
+	}
+	messages := llm.requests[2].Messages
+	for i := 1; i < len(messages); i++ {
+		if messages[i].Role == messages[i-1].Role {
+			t.Fatalf("messages %d and %d are both %q: %#v", i-1, i, messages[i].Role, messages)
+		}
+	}
+	if last := messages[len(messages)-1]; last.Role != "user" || last.Content != "Thanks." {
+		t.Errorf("last message = %#v, want the new user turn", last)
+	}
+}
+
+func TestSession_ToolErrors(t *testing.T) {
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{
+		textResponse("One moment.", types.StopReasonToolUse,
+			append(toolUseEvents(1, "call_1", "missing"), toolUseEvents(2, "call_2", "fails")...)...),
+		textResponse("Sorry, I couldn't find it.", types.StopReasonEndTurn),
+	}}
+
+	s := newToolTestSession(t, SessionConfig{
+		Model: "test/model",
+		ToolHandlers: map[string]ToolHandler{
+			"fails": func(ctx context.Context, input json.RawMessage) (any, error) {
+				return nil, errors.New("database unavailable")
+			},
+		},
+	}, llm, &recordingTTS{})
+
+	s.runAgent(s.ctx, []types.Message{{Role: "user", Content: "hi"}})
+	events := collectUntil[*MessageStopEvent](t, s)
+
+	var results []*ToolResultEvent
+	for _, event := range events {
+		if e, ok := event.(*ToolResultEvent); ok {
+			results = append(results, e)
+		}
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d tool results, want 2", len(results))
+	}
+	for _, r := range results {
+		if !r.IsError {
+			t.Errorf("result for %s: IsError = false", r.Name)
+		}
+	}
+	if !strings.Contains(results[1].Content, "database unavailable") {
+		t.Errorf("result content = %q", results[1].Content)
+	}
+}
+
+func TestSession_MaxToolRounds(t *testing.T) {
+	loop := textResponse("Checking.", types.StopReasonToolUse, toolUseEvents(1, "call_1", "lookup")...)
+	llm := &scriptedLLM{responses: [][]types.StreamEvent{loop, loop, loop}}
+
+	s := newToolTestSession(t, SessionConfig{
+		Model:         "test/model",
+		MaxToolRounds: 2,
+		ToolHandlers: map[string]ToolHandler{
+			"lookup": func(ctx context.Context, input json.RawMessage) (any, error) { return "nothing yet", nil },
+		},
+	}, llm, &recordingTTS{})
+
+	s.runAgent(s.ctx, []types.Message{{Role: "user", Content: "hi"}})
+	collectUntil[*MessageStopEvent](t, s)
+
+	if len(llm.requests) != 2 {
+		t.Errorf("got %d LLM requests, want 2", len(llm.requests))
+	}
+}
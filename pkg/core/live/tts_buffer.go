@@ -3,74 +3,54 @@ package live
 import (
 	"strings"
 	"sync"
+
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 )
 
-// TTSBuffer accumulates LLM text deltas and emits chunks suitable for TTS.
-// It sends text on:
-// 1. Punctuation: . , ! ?
+// DefaultTTSChunking is how live sessions chunk text by default. It sends
+// text on:
+// 1. Punctuation: . , ! ? ; :
 // 2. Word count threshold (5 words) when at a word boundary
+var DefaultTTSChunking = chunk.Config{Strategy: chunk.Clause, Words: 5}
+
+// TTSBuffer accumulates LLM text deltas and emits chunks suitable for TTS.
+// A Chunker decides where chunks end; by default DefaultTTSChunking.
 type TTSBuffer struct {
-	mu          sync.Mutex
-	text        strings.Builder
-	minWords    int
-	punctuation string
+	mu         sync.Mutex
+	chunker    chunk.Chunker
+	normalizer normalize.Normalizer // Rewrites deltas for speech, if set
 }
 
 // NewTTSBuffer creates a new TTS buffer with default settings.
 func NewTTSBuffer() *TTSBuffer {
-	return &TTSBuffer{
-		minWords:    5,
-		punctuation: ",.!?",
+	return NewChunkingTTSBuffer(nil, nil)
+}
+
+// NewChunkingTTSBuffer creates a TTS buffer that splits text with c and
+// passes deltas through a normalizer n, so that chunks hold text as it
+// should be spoken. A nil chunker uses DefaultTTSChunking, and a nil
+// normalizer leaves text as written.
+func NewChunkingTTSBuffer(c chunk.Chunker, n normalize.Normalizer) *TTSBuffer {
+	if c == nil {
+		c, _ = chunk.New(DefaultTTSChunking)
 	}
+	return &TTSBuffer{chunker: c, normalizer: n}
 }
 
 // Add adds a text delta and returns text to send to TTS (if any).
 // Returns empty string if more text should be buffered.
 func (b *TTSBuffer) Add(delta string) string {
-	if delta == "" {
-		return ""
-	}
-
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	// Check if delta starts with space (confirms previous word is complete)
-	startsWithSpace := delta[0] == ' ' || delta[0] == '\n'
-
-	// Get previous buffer state before adding
-	prevContent := b.text.String()
-	prevWordCount := len(strings.Fields(prevContent))
-
-	// Add delta to buffer
-	b.text.WriteString(delta)
-	content := b.text.String()
-
-	// Priority 1: Punctuation triggers immediate send
-	if strings.ContainsAny(delta, b.punctuation) {
-		// Find last punctuation in buffer
-		lastPunct := strings.LastIndexAny(content, b.punctuation)
-		if lastPunct >= 0 {
-			toSend := strings.TrimSpace(content[:lastPunct+1])
-			remainder := strings.TrimSpace(content[lastPunct+1:])
-			b.text.Reset()
-			if remainder != "" {
-				b.text.WriteString(remainder)
-			}
-			return toSend
-		}
+	if b.normalizer != nil {
+		delta = b.normalizer.Write(delta)
 	}
-
-	// Priority 2: Word count threshold + confirmed word boundary
-	// Send if previous buffer had 5+ words AND this delta starts with space
-	if prevWordCount >= b.minWords && startsWithSpace {
-		toSend := strings.TrimSpace(prevContent)
-		b.text.Reset()
-		// Keep the new delta (without leading space) in buffer
-		b.text.WriteString(strings.TrimLeft(delta, " \n"))
-		return toSend
+	if delta == "" {
+		return ""
 	}
-
-	return ""
+	return b.chunker.Add(delta)
 }
 
 // Flush returns any remaining buffered text and resets the buffer.
@@ -79,9 +59,12 @@ func (b *TTSBuffer) Flush() string {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	result := strings.TrimSpace(b.text.String())
-	b.text.Reset()
-	return result
+	var text []string
+	if b.normalizer != nil {
+		text = append(text, b.chunker.Add(b.normalizer.Flush()))
+	}
+	text = append(text, b.chunker.Flush())
+	return strings.TrimSpace(strings.Join(text, " "))
 }
 
 // Reset clears the buffer without returning content.
@@ -89,12 +72,18 @@ func (b *TTSBuffer) Flush() string {
 func (b *TTSBuffer) Reset() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.text.Reset()
+	if b.normalizer != nil {
+		b.normalizer.Flush()
+	}
+	b.chunker.Flush()
 }
 
-// Len returns the current buffer length.
+// Len returns the current buffer length, if the chunker reports it.
 func (b *TTSBuffer) Len() int {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	return b.text.Len()
+	if c, ok := b.chunker.(interface{ Len() int }); ok {
+		return c.Len()
+	}
+	return 0
 }
@@ -1,7 +1,13 @@
 package live
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 )
 
 func TestTTSBuffer_Punctuation(t *testing.T) {
@@ -178,3 +184,48 @@ func TestTTSBuffer_LongSentenceNoPunctuation(t *testing.T) {
 		t.Error("expected some remainder")
 	}
 }
+
+func TestTTSBuffer_Normalizer(t *testing.T) {
+	b := NewChunkingTTSBuffer(nil, normalize.New(normalize.Config{}))
+
+	deltas := []string{"**Total", ":** $1", ",234", " due", " today", " at 3", ":30."}
+	var results []string
+	for _, d := range deltas {
+		if r := b.Add(d); r != "" {
+			results = append(results, r)
+		}
+	}
+	if r := b.Flush(); r != "" {
+		results = append(results, r)
+	}
+
+	got := strings.Join(results, " ")
+	want := "Total: one thousand two hundred thirty-four dollars due today at three thirty."
+	if got != want {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+}
+
+func TestTTSBuffer_Chunker(t *testing.T) {
+	c, err := chunk.New(voice.ChunkConfig(&types.TextChunking{Strategy: "words", Words: 3}, DefaultTTSChunking))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewChunkingTTSBuffer(c, nil)
+
+	var results []string
+	for _, d := range []string{"Well,", " I", " think", " so.", " Maybe", " not"} {
+		if r := b.Add(d); r != "" {
+			results = append(results, r)
+		}
+	}
+	if r := b.Flush(); r != "" {
+		results = append(results, r)
+	}
+
+	// Punctuation does not end chunks with the words strategy
+	want := []string{"Well, I think", "so. Maybe not"}
+	if strings.Join(results, "|") != strings.Join(want, "|") {
+		t.Errorf("sent %q, want %q", results, want)
+	}
+}
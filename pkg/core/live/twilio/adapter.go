@@ -0,0 +1,401 @@
+// Package twilio connects live sessions to phone calls over the Twilio
+// Media Streams WebSocket protocol.
+//
+// The carrier streams the caller's audio as base64 8 kHz μ-law frames and
+// plays back media messages sent on the same connection. The Adapter
+// converts audio in both directions, sends "clear" when the session flushes
+// or is interrupted, and tracks playback with marks. Sessions that accept
+// playback acknowledgments, like live.Session, are told how much of each
+// response the caller heard, so interrupted turns keep only those words.
+//
+// Usage:
+//
+//	upgrader := websocket.Upgrader{}
+//	http.HandleFunc("/media", func(w http.ResponseWriter, r *http.Request) {
+//	    conn, err := upgrader.Upgrade(w, r, nil)
+//	    if err != nil {
+//	        return
+//	    }
+//	    session := live.NewSession(cfg, llm, ttsClient, sttClient)
+//	    session.Start(r.Context())
+//	    defer session.Close()
+//
+//	    adapter := twilio.NewAdapter(conn, session, twilio.Config{SampleRate: cfg.SampleRate})
+//	    adapter.Serve(r.Context())
+//	})
+package twilio
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/live"
+)
+
+// Session is the part of live.Session used by the adapter.
+type Session interface {
+	SendAudio(data []byte) error
+	SendText(text string) error
+	Events() <-chan live.Event
+}
+
+// playbackAcker is implemented by sessions that track how much of the
+// current response has been played, such as live.Session.
+type playbackAcker interface {
+	AckPlayback(positionMs int)
+}
+
+// Config configures an Adapter.
+type Config struct {
+	// SampleRate is the sample rate of the session's 16-bit mono PCM audio.
+	// Default: 24000.
+	SampleRate int
+
+	// OnStart is called with the call details when the stream starts.
+	OnStart func(start *Start)
+
+	// OnDTMF is called for each key the caller presses. If nil, key presses
+	// are sent to the session as text input.
+	OnDTMF func(digit string)
+
+	// OnEvent is called for every session event, after the adapter has
+	// handled it. The adapter consumes Session.Events, so use this for
+	// transcripts, logging and metrics.
+	OnEvent func(event live.Event)
+}
+
+// DefaultSampleRate is the session sample rate used when Config.SampleRate is zero.
+const DefaultSampleRate = 24000
+
+// Adapter relays audio between a Media Streams connection and a live session.
+type Adapter struct {
+	conn    *websocket.Conn
+	session Session
+	config  Config
+	pcm     audio.Format
+
+	writeMu sync.Mutex
+
+	mu         sync.Mutex
+	streamSID  string
+	inbound    *audio.Converter
+	outbound   *audio.Converter
+	callFormat audio.Format
+	markSeq    int
+	marks      map[string]int // mark name → ms of audio sent before it
+	sentMs     int
+	playedMs   int
+
+	// Start of the current response in sent audio, for playback acks
+	responseMs  int
+	newResponse bool
+}
+
+// NewAdapter creates an adapter for an upgraded Media Streams connection.
+func NewAdapter(conn *websocket.Conn, session Session, config Config) *Adapter {
+	if config.SampleRate == 0 {
+		config.SampleRate = DefaultSampleRate
+	}
+	return &Adapter{
+		conn:    conn,
+		session: session,
+		config:  config,
+		pcm:     audio.PCM16(config.SampleRate, 1),
+		marks:   make(map[string]int),
+
+		newResponse: true,
+	}
+}
+
+// Serve relays audio until the stream stops, the connection closes or ctx
+// is cancelled. It closes the connection before returning. A normal end of
+// the call returns nil.
+func (a *Adapter) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		// Unblock reads when the context is cancelled
+		<-ctx.Done()
+		a.conn.Close()
+	}()
+
+	if err := a.waitForStart(); err != nil {
+		return a.readError(ctx, err)
+	}
+
+	go a.forwardEvents(ctx)
+
+	return a.readError(ctx, a.readLoop())
+}
+
+// StreamSID returns the stream identifier, or "" before the stream starts.
+func (a *Adapter) StreamSID() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.streamSID
+}
+
+// PlayedMs returns how much outbound audio the carrier has confirmed
+// playing, in milliseconds. Audio discarded by Clear is not counted.
+func (a *Adapter) PlayedMs() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.playedMs
+}
+
+// Clear tells the carrier to discard audio it has not played yet.
+func (a *Adapter) Clear() error {
+	a.mu.Lock()
+	streamSID := a.streamSID
+	if a.outbound != nil {
+		a.outbound.Reset()
+	}
+	// Marks for cleared audio are echoed back without being played
+	clear(a.marks)
+	a.sentMs = a.playedMs
+	a.newResponse = true
+	a.mu.Unlock()
+
+	if streamSID == "" {
+		return nil
+	}
+	return a.write(&Message{Event: EventClear, StreamSID: streamSID})
+}
+
+// waitForStart reads messages until the stream starts.
+func (a *Adapter) waitForStart() error {
+	for {
+		var msg Message
+		if err := a.conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		switch msg.Event {
+		case EventStart:
+			if msg.Start == nil {
+				return fmt.Errorf("twilio: start message without start details")
+			}
+			return a.start(msg)
+		case EventStop:
+			return errStopped
+		}
+	}
+}
+
+// start sets up audio conversion for the call's media format.
+func (a *Adapter) start(msg Message) error {
+	callFormat, err := parseMediaFormat(msg.Start.MediaFormat)
+	if err != nil {
+		return err
+	}
+	inbound, err := audio.NewConverter(callFormat, a.pcm)
+	if err != nil {
+		return err
+	}
+	outbound, err := audio.NewConverter(a.pcm, callFormat)
+	if err != nil {
+		return err
+	}
+
+	streamSID := msg.StreamSID
+	if streamSID == "" {
+		streamSID = msg.Start.StreamSID
+	}
+
+	a.mu.Lock()
+	a.streamSID = streamSID
+	a.inbound = inbound
+	a.outbound = outbound
+	a.callFormat = callFormat
+	a.mu.Unlock()
+
+	if a.config.OnStart != nil {
+		a.config.OnStart(msg.Start)
+	}
+	return nil
+}
+
+// readLoop handles carrier messages until the stream stops.
+func (a *Adapter) readLoop() error {
+	for {
+		var msg Message
+		if err := a.conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+
+		switch msg.Event {
+		case EventMedia:
+			if msg.Media == nil || (msg.Media.Track != "" && msg.Media.Track != "inbound") {
+				continue
+			}
+			if err := a.handleMedia(msg.Media.Payload); err != nil {
+				return err
+			}
+
+		case EventMark:
+			if msg.Mark != nil {
+				a.markPlayed(msg.Mark.Name)
+			}
+
+		case EventDTMF:
+			if msg.DTMF != nil {
+				if err := a.handleDTMF(msg.DTMF.Digit); err != nil {
+					return err
+				}
+			}
+
+		case EventStop:
+			return errStopped
+		}
+	}
+}
+
+func (a *Adapter) handleMedia(payload string) error {
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("twilio: decode media: %w", err)
+	}
+	// Only the read loop uses the inbound converter
+	pcm, err := a.inbound.Convert(data)
+	if err != nil {
+		return err
+	}
+	if len(pcm) == 0 {
+		return nil
+	}
+	return a.session.SendAudio(pcm)
+}
+
+func (a *Adapter) handleDTMF(digit string) error {
+	if a.config.OnDTMF != nil {
+		a.config.OnDTMF(digit)
+		return nil
+	}
+	return a.session.SendText(fmt.Sprintf("The caller pressed %s on the keypad.", digit))
+}
+
+// forwardEvents sends session audio to the carrier and clears playback
+// when the session flushes or is interrupted.
+func (a *Adapter) forwardEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-a.session.Events():
+			if !ok {
+				return
+			}
+
+			var err error
+			switch e := event.(type) {
+			case *live.AudioDeltaEvent:
+				err = a.sendAudio(e.Data)
+			case *live.AudioFlushEvent, *live.ResponseInterruptedEvent:
+				err = a.Clear()
+			case *live.AudioCommittedEvent:
+				a.mu.Lock()
+				a.newResponse = true
+				a.mu.Unlock()
+			}
+			if a.config.OnEvent != nil {
+				a.config.OnEvent(event)
+			}
+			if err != nil {
+				// The connection is gone; the read loop will return
+				return
+			}
+		}
+	}
+}
+
+// sendAudio converts session PCM to the call format and sends it,
+// followed by a mark to track playback.
+func (a *Adapter) sendAudio(pcm []byte) error {
+	a.mu.Lock()
+	data, err := a.outbound.Convert(pcm)
+	if err != nil || len(data) == 0 {
+		a.mu.Unlock()
+		return err
+	}
+	if a.newResponse {
+		a.responseMs = a.sentMs
+		a.newResponse = false
+	}
+	a.markSeq++
+	name := "audio-" + strconv.Itoa(a.markSeq)
+	a.sentMs += a.callFormat.DurationMs(len(data))
+	a.marks[name] = a.sentMs
+	streamSID := a.streamSID
+	a.mu.Unlock()
+
+	if err := a.write(&Message{
+		Event:     EventMedia,
+		StreamSID: streamSID,
+		Media:     &Media{Payload: base64.StdEncoding.EncodeToString(data)},
+	}); err != nil {
+		return err
+	}
+	return a.write(&Message{Event: EventMark, StreamSID: streamSID, Mark: &Mark{Name: name}})
+}
+
+func (a *Adapter) markPlayed(name string) {
+	a.mu.Lock()
+	ms, ok := a.marks[name]
+	if ok {
+		a.playedMs = ms
+		delete(a.marks, name)
+	}
+	responseMs := a.responseMs
+	a.mu.Unlock()
+
+	// Marks from an earlier response say nothing about the current one
+	if acker, isAcker := a.session.(playbackAcker); ok && isAcker && ms >= responseMs {
+		acker.AckPlayback(ms - responseMs)
+	}
+}
+
+func (a *Adapter) write(msg *Message) error {
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	return a.conn.WriteJSON(msg)
+}
+
+var errStopped = errors.New("twilio: stream stopped")
+
+// readError maps the error that ended the read loop to Serve's result.
+func (a *Adapter) readError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, errStopped):
+		return nil
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		return err
+	}
+}
+
+// parseMediaFormat converts a media format such as "audio/x-mulaw" at 8000 Hz.
+func parseMediaFormat(mf MediaFormat) (audio.Format, error) {
+	name := strings.TrimPrefix(strings.ToLower(mf.Encoding), "audio/")
+	name = strings.TrimPrefix(name, "x-")
+	if name == "" {
+		name = "mulaw"
+	}
+	enc, err := audio.ParseEncoding(name)
+	if err != nil {
+		return audio.Format{}, fmt.Errorf("twilio: unsupported media encoding %q", mf.Encoding)
+	}
+
+	f := audio.Format{Encoding: enc, SampleRate: mf.SampleRate, Channels: mf.Channels}
+	return f.WithDefaults(audio.Format{SampleRate: 8000, Channels: 1}), nil
+}
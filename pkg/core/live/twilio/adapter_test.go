@@ -0,0 +1,318 @@
+package twilio
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/live"
+)
+
+type fakeSession struct {
+	audio  chan []byte
+	text   chan string
+	events chan live.Event
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{
+		audio:  make(chan []byte, 10),
+		text:   make(chan string, 10),
+		events: make(chan live.Event, 10),
+	}
+}
+
+func (s *fakeSession) SendAudio(data []byte) error { s.audio <- data; return nil }
+func (s *fakeSession) SendText(text string) error  { s.text <- text; return nil }
+func (s *fakeSession) Events() <-chan live.Event   { return s.events }
+
+// startCall runs an adapter behind a local WebSocket server and returns
+// the carrier side of the connection, after the stream has started.
+func startCall(t *testing.T, session Session, config Config) (*websocket.Conn, *Adapter, <-chan error) {
+	t.Helper()
+
+	adapters := make(chan *Adapter, 1)
+	served := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		adapter := NewAdapter(conn, session, config)
+		adapters <- adapter
+		served <- adapter.Serve(context.Background())
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	send(t, conn, &Message{Event: EventConnected})
+	send(t, conn, &Message{
+		Event:     EventStart,
+		StreamSID: "MZ123",
+		Start: &Start{
+			StreamSID:   "MZ123",
+			CallSID:     "CA123",
+			MediaFormat: MediaFormat{Encoding: "audio/x-mulaw", SampleRate: 8000, Channels: 1},
+		},
+	})
+	return conn, <-adapters, served
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg *Message) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("write %s: %v", msg.Event, err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) Message {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg Message
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func TestAdapter_RelaysAudio(t *testing.T) {
+	session := newFakeSession()
+	var started *Start
+	conn, adapter, served := startCall(t, session, Config{
+		SampleRate: 16000,
+		OnStart:    func(s *Start) { started = s },
+	})
+
+	// 20ms of μ-law silence from the caller
+	send(t, conn, &Message{
+		Event:     EventMedia,
+		StreamSID: "MZ123",
+		Media:     &Media{Track: "inbound", Payload: base64.StdEncoding.EncodeToString(make([]byte, 160))},
+	})
+	select {
+	case pcm := <-session.audio:
+		// 20ms at 16kHz, less the samples the resampler holds back
+		if len(pcm) < 620 || len(pcm) > 640 {
+			t.Errorf("session received %d bytes of PCM, want about 640", len(pcm))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("session received no audio")
+	}
+	if started == nil || started.CallSID != "CA123" {
+		t.Errorf("OnStart got %+v", started)
+	}
+	if adapter.StreamSID() != "MZ123" {
+		t.Errorf("StreamSID() = %q", adapter.StreamSID())
+	}
+
+	// 20ms of agent speech at 16kHz
+	session.events <- &live.AudioDeltaEvent{Data: audio.EncodePCM16(make([]int16, 320)), Format: "pcm_s16le"}
+
+	media := receive(t, conn)
+	if media.Event != EventMedia || media.StreamSID != "MZ123" || media.Media == nil {
+		t.Fatalf("got %+v, want media", media)
+	}
+	ulaw, err := base64.StdEncoding.DecodeString(media.Media.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ulaw) < 155 || len(ulaw) > 160 {
+		t.Errorf("carrier received %d bytes of μ-law, want about 160", len(ulaw))
+	}
+
+	mark := receive(t, conn)
+	if mark.Event != EventMark || mark.Mark == nil {
+		t.Fatalf("got %+v, want mark", mark)
+	}
+	if adapter.PlayedMs() != 0 {
+		t.Errorf("PlayedMs() = %d before the mark was played", adapter.PlayedMs())
+	}
+
+	// Echo the mark once the audio has played
+	send(t, conn, &Message{Event: EventMark, StreamSID: "MZ123", Mark: mark.Mark})
+	deadline := time.Now().Add(2 * time.Second)
+	for adapter.PlayedMs() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ms := adapter.PlayedMs(); ms < 19 || ms > 20 {
+		t.Errorf("PlayedMs() = %d, want 20", ms)
+	}
+
+	send(t, conn, &Message{Event: EventStop, StreamSID: "MZ123", Stop: &Stop{CallSID: "CA123"}})
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after stop")
+	}
+}
+
+func TestAdapter_ClearsOnFlushAndInterrupt(t *testing.T) {
+	session := newFakeSession()
+	adapterEvents := make(chan live.Event, 10)
+	conn, adapter, _ := startCall(t, session, Config{OnEvent: func(e live.Event) { adapterEvents <- e }})
+
+	session.events <- &live.AudioDeltaEvent{Data: make([]byte, 960)}
+	receive(t, conn) // media
+	mark := receive(t, conn)
+	<-adapterEvents
+
+	var seen []string
+	for _, event := range []live.Event{&live.AudioFlushEvent{}, &live.ResponseInterruptedEvent{}} {
+		session.events <- event
+		msg := receive(t, conn)
+		if msg.Event != EventClear || msg.StreamSID != "MZ123" {
+			t.Errorf("after %s got %+v, want clear", event.EventType(), msg)
+		}
+		seen = append(seen, (<-adapterEvents).EventType())
+	}
+	if strings.Join(seen, ",") != "audio.flush,response.interrupted" {
+		t.Errorf("OnEvent saw %v", seen)
+	}
+
+	// Marks echoed for cleared audio do not count as played
+	send(t, conn, &Message{Event: EventMark, StreamSID: "MZ123", Mark: mark.Mark})
+	time.Sleep(50 * time.Millisecond)
+	if adapter.PlayedMs() != 0 {
+		t.Errorf("PlayedMs() = %d after clear, want 0", adapter.PlayedMs())
+	}
+}
+
+// ackingSession records playback acknowledgments.
+type ackingSession struct {
+	*fakeSession
+	acks chan int
+}
+
+func (s *ackingSession) AckPlayback(positionMs int) { s.acks <- positionMs }
+
+func TestAdapter_AcksPlaybackPerResponse(t *testing.T) {
+	session := &ackingSession{fakeSession: newFakeSession(), acks: make(chan int, 10)}
+	conn, _, _ := startCall(t, session, Config{SampleRate: 16000})
+
+	// playResponse sends 20ms of agent speech and echoes its mark
+	playResponse := func() int {
+		session.events <- &live.AudioDeltaEvent{Data: audio.EncodePCM16(make([]int16, 320))}
+		receive(t, conn) // media
+		mark := receive(t, conn)
+		send(t, conn, &Message{Event: EventMark, StreamSID: "MZ123", Mark: mark.Mark})
+		select {
+		case ms := <-session.acks:
+			return ms
+		case <-time.After(2 * time.Second):
+			t.Fatal("playback was not acknowledged")
+			return 0
+		}
+	}
+
+	if ms := playResponse(); ms < 19 || ms > 20 {
+		t.Errorf("first response ack = %dms, want 20", ms)
+	}
+
+	// Positions restart with each response
+	session.events <- &live.AudioCommittedEvent{DurationMs: 20}
+	if ms := playResponse(); ms < 19 || ms > 20 {
+		t.Errorf("second response ack = %dms, want 20", ms)
+	}
+}
+
+func TestAdapter_DTMF(t *testing.T) {
+	session := newFakeSession()
+	conn, _, _ := startCall(t, session, Config{})
+
+	send(t, conn, &Message{Event: EventDTMF, StreamSID: "MZ123", DTMF: &DTMF{Track: "inbound_track", Digit: "5"}})
+	select {
+	case text := <-session.text:
+		if !strings.Contains(text, "5") {
+			t.Errorf("session text = %q, want the digit", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("DTMF was not sent to the session")
+	}
+
+	digits := make(chan string, 1)
+	session = newFakeSession()
+	conn, _, _ = startCall(t, session, Config{OnDTMF: func(d string) { digits <- d }})
+	send(t, conn, &Message{Event: EventDTMF, StreamSID: "MZ123", DTMF: &DTMF{Digit: "#"}})
+	select {
+	case d := <-digits:
+		if d != "#" {
+			t.Errorf("OnDTMF got %q, want #", d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnDTMF was not called")
+	}
+	if len(session.text) != 0 {
+		t.Error("DTMF was sent to the session despite OnDTMF")
+	}
+}
+
+func TestAdapter_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		served <- NewAdapter(conn, newFakeSession(), Config{}).Serve(ctx)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != context.Canceled {
+			t.Errorf("Serve() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+}
+
+func TestParseMediaFormat(t *testing.T) {
+	tests := []struct {
+		in   MediaFormat
+		want audio.Format
+	}{
+		{MediaFormat{Encoding: "audio/x-mulaw", SampleRate: 8000, Channels: 1}, audio.Format{Encoding: audio.EncodingMulaw, SampleRate: 8000, Channels: 1}},
+		{MediaFormat{Encoding: "audio/x-alaw"}, audio.Format{Encoding: audio.EncodingAlaw, SampleRate: 8000, Channels: 1}},
+		{MediaFormat{Encoding: "audio/l16", SampleRate: 16000}, audio.Format{Encoding: audio.EncodingPCM16, SampleRate: 16000, Channels: 1}},
+		{MediaFormat{}, audio.Format{Encoding: audio.EncodingMulaw, SampleRate: 8000, Channels: 1}},
+	}
+	for _, tt := range tests {
+		got, err := parseMediaFormat(tt.in)
+		if err != nil {
+			t.Errorf("parseMediaFormat(%+v) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMediaFormat(%+v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := parseMediaFormat(MediaFormat{Encoding: "audio/opus"}); err == nil {
+		t.Error("parseMediaFormat accepted opus")
+	}
+}
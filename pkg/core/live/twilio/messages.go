@@ -0,0 +1,69 @@
+package twilio
+
+// Message is a Media Streams WebSocket message. The Event field selects
+// which of the other fields is set.
+type Message struct {
+	Event          string `json:"event"`
+	SequenceNumber string `json:"sequenceNumber,omitempty"`
+	StreamSID      string `json:"streamSid,omitempty"`
+
+	Start *Start `json:"start,omitempty"`
+	Media *Media `json:"media,omitempty"`
+	Stop  *Stop  `json:"stop,omitempty"`
+	Mark  *Mark  `json:"mark,omitempty"`
+	DTMF  *DTMF  `json:"dtmf,omitempty"`
+}
+
+// Message events.
+const (
+	EventConnected = "connected"
+	EventStart     = "start"
+	EventMedia     = "media"
+	EventStop      = "stop"
+	EventMark      = "mark"
+	EventDTMF      = "dtmf"
+	EventClear     = "clear"
+)
+
+// Start describes the call when the stream begins.
+type Start struct {
+	AccountSID       string            `json:"accountSid,omitempty"`
+	StreamSID        string            `json:"streamSid,omitempty"`
+	CallSID          string            `json:"callSid,omitempty"`
+	Tracks           []string          `json:"tracks,omitempty"`
+	CustomParameters map[string]string `json:"customParameters,omitempty"`
+	MediaFormat      MediaFormat       `json:"mediaFormat"`
+}
+
+// MediaFormat is the format of the call audio, e.g. "audio/x-mulaw" at 8000 Hz.
+type MediaFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+}
+
+// Media carries base64-encoded call audio.
+type Media struct {
+	Track     string `json:"track,omitempty"`
+	Chunk     string `json:"chunk,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Payload   string `json:"payload"`
+}
+
+// Stop is sent when the stream ends.
+type Stop struct {
+	AccountSID string `json:"accountSid,omitempty"`
+	CallSID    string `json:"callSid,omitempty"`
+}
+
+// Mark labels a point in the outbound audio. The carrier echoes the mark
+// back once the audio sent before it has been played.
+type Mark struct {
+	Name string `json:"name"`
+}
+
+// DTMF is a key press on the caller's keypad.
+type DTMF struct {
+	Track string `json:"track,omitempty"`
+	Digit string `json:"digit"`
+}
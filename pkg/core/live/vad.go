@@ -42,10 +42,15 @@ type SemanticChecker interface {
 // 1. Punctuation triggers (. ! ?) → immediate semantic check
 // 2. Timeout fallback (3 seconds no activity) → force semantic check
 // 3. Semantic check confirms turn completion before commit
+//
+// With VADConfig.Acoustic enabled, audio passed to ProcessAudio also drives
+// an AcousticVAD: the end of speech triggers the check once the transcript
+// settles, and the timeout fallback waits while the user is still speaking.
 type HybridVAD struct {
 	config        VADConfig
 	semanticCheck SemanticChecker
 	audioConfig   AudioConfig
+	acoustic      *AcousticVAD
 
 	mu                       sync.Mutex
 	ctx                      context.Context
@@ -55,9 +60,11 @@ type HybridVAD struct {
 	pendingCheck             bool
 	committed                bool // Prevents double commits before Reset is called
 	lastCheckedTranscriptLen int  // Prevents re-checking same transcript on timeout
+	speaking                 bool // Acoustic VAD hears speech
+	speechEnded              bool // Acoustic VAD heard speech end since the last check
 
 	// Callbacks for events
-	onSilence   func(durationMs int) // Kept for API compatibility
+	onSilence   func(durationMs int) // Called when acoustic VAD detects end of speech
 	onAnalyzing func(transcript string)
 	onCommit    func(transcript string, forced bool)
 	onDebug     func(category, message string)
@@ -65,11 +72,48 @@ type HybridVAD struct {
 
 // NewHybridVAD creates a new hybrid VAD with the given configuration.
 func NewHybridVAD(config VADConfig, audioConfig AudioConfig, checker SemanticChecker) *HybridVAD {
-	return &HybridVAD{
+	v := &HybridVAD{
 		config:        config,
 		audioConfig:   audioConfig,
 		semanticCheck: checker,
 	}
+	if config.Acoustic.Enabled {
+		v.config.Acoustic = config.Acoustic.withDefaults()
+		v.acoustic = NewAcousticVAD(v.config.Acoustic, config.EnergyThreshold, audioConfig)
+		v.acoustic.SetCallbacks(v.onSpeechStart, v.onSpeechEnd)
+	}
+	return v
+}
+
+// ProcessAudio feeds input audio to the acoustic VAD, if enabled.
+func (v *HybridVAD) ProcessAudio(pcm []byte) {
+	if v.acoustic != nil {
+		v.acoustic.Process(pcm)
+	}
+}
+
+// onSpeechStart is called by the acoustic VAD when the user starts speaking.
+func (v *HybridVAD) onSpeechStart() {
+	v.mu.Lock()
+	v.speaking = true
+	v.speechEnded = false
+	v.mu.Unlock()
+	v.debug("VAD", "Speech started")
+}
+
+// onSpeechEnd is called by the acoustic VAD when the user stops speaking.
+func (v *HybridVAD) onSpeechEnd(silenceMs int) {
+	v.mu.Lock()
+	v.speaking = false
+	v.speechEnded = true
+	onSilence := v.onSilence
+	v.mu.Unlock()
+
+	v.debug("VAD", fmt.Sprintf("Speech ended (%dms silence)", silenceMs))
+	if onSilence != nil {
+		go onSilence(silenceMs)
+	}
+	v.checkTimeout()
 }
 
 // SetCallbacks sets the event callbacks for the VAD.
@@ -125,8 +169,8 @@ func (v *HybridVAD) timeoutLoop() {
 func (v *HybridVAD) checkTimeout() {
 	v.mu.Lock()
 
-	// Skip if already committed, pending check, or no transcript
-	if v.committed || v.pendingCheck || v.transcript.Len() == 0 {
+	// Skip if already committed, pending check, no transcript, or the user is still speaking
+	if v.committed || v.pendingCheck || v.transcript.Len() == 0 || v.speaking {
 		v.mu.Unlock()
 		return
 	}
@@ -137,15 +181,21 @@ func (v *HybridVAD) checkTimeout() {
 		return
 	}
 
-	// Check if timeout exceeded
-	timeout := time.Duration(v.config.NoActivityTimeoutMs) * time.Millisecond
-	if time.Since(v.lastTranscriptTime) < timeout {
+	transcript := v.transcript.String()
+	transcriptLen := len(transcript)
+
+	// Check if timeout exceeded. After the end of speech, only wait for the
+	// transcript to settle, unless this transcript was already checked.
+	timeoutMs := v.config.NoActivityTimeoutMs
+	acousticEnd := v.speechEnded && transcriptLen > v.lastCheckedTranscriptLen
+	if acousticEnd {
+		timeoutMs = v.config.Acoustic.TranscriptSettleMs
+	}
+	if time.Since(v.lastTranscriptTime) < time.Duration(timeoutMs)*time.Millisecond {
 		v.mu.Unlock()
 		return
 	}
 
-	transcript := v.transcript.String()
-	transcriptLen := len(transcript)
 	words := strings.Fields(transcript)
 
 	// Check minimum word count
@@ -166,13 +216,19 @@ func (v *HybridVAD) checkTimeout() {
 		return
 	}
 
-	v.debug("VAD", fmt.Sprintf("Timeout reached (%dms), triggering semantic check", v.config.NoActivityTimeoutMs))
-
 	// Release lock during semantic check to not block AddTranscript
 	v.pendingCheck = true
+	v.speechEnded = false
 	v.mu.Unlock()
 
-	v.triggerSemanticCheck(transcript, true)
+	if acousticEnd {
+		v.debug("VAD", "End of speech detected, triggering semantic check")
+		go v.triggerSemanticCheck(transcript, false)
+		return
+	}
+
+	v.debug("VAD", fmt.Sprintf("Timeout reached (%dms), triggering semantic check", v.config.NoActivityTimeoutMs))
+	go v.triggerSemanticCheck(transcript, true)
 }
 
 // AddTranscript adds text to the accumulated transcript and checks for punctuation triggers.
@@ -206,7 +262,7 @@ func (v *HybridVAD) AddTranscript(text string) {
 			v.debug("VAD", fmt.Sprintf("Punctuation detected in %q, triggering semantic check", fullText))
 			v.pendingCheck = true
 			v.mu.Unlock()
-			v.triggerSemanticCheck(fullText, false)
+			go v.triggerSemanticCheck(fullText, false)
 			return
 		}
 	}
@@ -225,7 +281,9 @@ func (v *HybridVAD) endsWithPunctuation(text string) bool {
 }
 
 // triggerSemanticCheck performs the LLM semantic check.
-// Must be called WITHOUT the mutex held.
+// Must be called WITHOUT the mutex held and with pendingCheck set. Callers
+// run it on its own goroutine, so that the LLM call blocks neither the audio
+// loop nor incoming transcripts.
 func (v *HybridVAD) triggerSemanticCheck(transcript string, forced bool) {
 	if v.onAnalyzing != nil {
 		go v.onAnalyzing(transcript)
@@ -293,6 +351,11 @@ func (v *HybridVAD) Reset() {
 	v.pendingCheck = false
 	v.committed = false
 	v.lastCheckedTranscriptLen = 0
+	v.speaking = false
+	v.speechEnded = false
+	if v.acoustic != nil {
+		v.acoustic.Reset()
+	}
 }
 
 // GetTranscript returns the current accumulated transcript.
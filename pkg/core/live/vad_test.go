@@ -3,6 +3,7 @@ package live
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -51,11 +52,11 @@ func TestHybridVAD_PunctuationTrigger(t *testing.T) {
 	vad.Start(ctx)
 	defer vad.Stop()
 
-	commitCalled := false
+	var commitCalled atomic.Bool
 	vad.SetCallbacks(
 		nil, // onSilence
 		nil, // onAnalyzing
-		func(transcript string, forced bool) { commitCalled = true },
+		func(transcript string, forced bool) { commitCalled.Store(true) },
 		nil, // onDebug
 	)
 
@@ -68,7 +69,7 @@ func TestHybridVAD_PunctuationTrigger(t *testing.T) {
 	if !checker.wasCalled() {
 		t.Error("Expected semantic checker to be called on punctuation")
 	}
-	if !commitCalled {
+	if !commitCalled.Load() {
 		t.Error("Expected commit callback when semantic check returns true")
 	}
 }
@@ -92,11 +93,11 @@ func TestHybridVAD_NoPunctuationNoTrigger(t *testing.T) {
 	vad.Start(ctx)
 	defer vad.Stop()
 
-	commitCalled := false
+	var commitCalled atomic.Bool
 	vad.SetCallbacks(
 		nil,
 		nil,
-		func(transcript string, forced bool) { commitCalled = true },
+		func(transcript string, forced bool) { commitCalled.Store(true) },
 		nil,
 	)
 
@@ -109,7 +110,7 @@ func TestHybridVAD_NoPunctuationNoTrigger(t *testing.T) {
 	if checker.wasCalled() {
 		t.Error("Expected semantic checker NOT to be called without punctuation")
 	}
-	if commitCalled {
+	if commitCalled.Load() {
 		t.Error("Expected NO commit without punctuation")
 	}
 }
@@ -191,11 +192,11 @@ func TestHybridVAD_SemanticCheckIncomplete(t *testing.T) {
 	vad.Start(ctx)
 	defer vad.Stop()
 
-	commitCalled := false
+	var commitCalled atomic.Bool
 	vad.SetCallbacks(
 		nil,
 		nil,
-		func(transcript string, forced bool) { commitCalled = true },
+		func(transcript string, forced bool) { commitCalled.Store(true) },
 		nil,
 	)
 
@@ -208,7 +209,7 @@ func TestHybridVAD_SemanticCheckIncomplete(t *testing.T) {
 	if !checker.wasCalled() {
 		t.Error("Expected semantic checker to be called on punctuation")
 	}
-	if commitCalled {
+	if commitCalled.Load() {
 		t.Error("Expected NO commit when semantic check says incomplete")
 	}
 }
@@ -311,11 +312,11 @@ func TestHybridVAD_SemanticCheckDisabled(t *testing.T) {
 	vad.Start(ctx)
 	defer vad.Stop()
 
-	commitCalled := false
+	var commitCalled atomic.Bool
 	vad.SetCallbacks(
 		nil,
 		nil,
-		func(transcript string, forced bool) { commitCalled = true },
+		func(transcript string, forced bool) { commitCalled.Store(true) },
 		nil,
 	)
 
@@ -328,7 +329,7 @@ func TestHybridVAD_SemanticCheckDisabled(t *testing.T) {
 	if checker.wasCalled() {
 		t.Error("Expected semantic checker NOT to be called when disabled")
 	}
-	if !commitCalled {
+	if !commitCalled.Load() {
 		t.Error("Expected commit callback when semantic check is disabled")
 	}
 }
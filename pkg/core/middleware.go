@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// CreateMessageFunc sends a non-streaming request.
+type CreateMessageFunc func(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error)
+
+// StreamMessageFunc opens a streaming request.
+type StreamMessageFunc func(ctx context.Context, req *types.MessageRequest) (EventStream, error)
+
+// Middleware intercepts requests passing through the Engine.
+// Implementations call next to continue the chain; they may modify the
+// request, short-circuit with their own response, or wrap the returned
+// EventStream to observe or rewrite events.
+type Middleware interface {
+	// WrapCreateMessage wraps a non-streaming call.
+	WrapCreateMessage(next CreateMessageFunc) CreateMessageFunc
+
+	// WrapStreamMessage wraps a streaming call.
+	WrapStreamMessage(next StreamMessageFunc) StreamMessageFunc
+}
+
+// MiddlewareFuncs builds a Middleware from plain functions.
+// Nil fields pass calls through unchanged.
+type MiddlewareFuncs struct {
+	// CreateMessage intercepts non-streaming calls.
+	CreateMessage func(ctx context.Context, req *types.MessageRequest, next CreateMessageFunc) (*types.MessageResponse, error)
+
+	// StreamMessage intercepts the opening of streaming calls.
+	StreamMessage func(ctx context.Context, req *types.MessageRequest, next StreamMessageFunc) (EventStream, error)
+
+	// Event is applied to every event of a stream. Returning a nil event drops it.
+	Event func(ctx context.Context, req *types.MessageRequest, event types.StreamEvent) (types.StreamEvent, error)
+}
+
+// WrapCreateMessage implements Middleware.
+func (m MiddlewareFuncs) WrapCreateMessage(next CreateMessageFunc) CreateMessageFunc {
+	if m.CreateMessage == nil {
+		return next
+	}
+	return func(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+		return m.CreateMessage(ctx, req, next)
+	}
+}
+
+// WrapStreamMessage implements Middleware.
+func (m MiddlewareFuncs) WrapStreamMessage(next StreamMessageFunc) StreamMessageFunc {
+	if m.StreamMessage == nil && m.Event == nil {
+		return next
+	}
+	return func(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
+		var stream EventStream
+		var err error
+		if m.StreamMessage != nil {
+			stream, err = m.StreamMessage(ctx, req, next)
+		} else {
+			stream, err = next(ctx, req)
+		}
+		if err != nil || stream == nil || m.Event == nil {
+			return stream, err
+		}
+		return MapEventStream(stream, func(event types.StreamEvent) (types.StreamEvent, error) {
+			return m.Event(ctx, req, event)
+		}), nil
+	}
+}
+
+// ChainCreateMessage applies middleware to fn. The first middleware is the outermost.
+func ChainCreateMessage(fn CreateMessageFunc, middleware ...Middleware) CreateMessageFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		fn = middleware[i].WrapCreateMessage(fn)
+	}
+	return fn
+}
+
+// ChainStreamMessage applies middleware to fn. The first middleware is the outermost.
+func ChainStreamMessage(fn StreamMessageFunc, middleware ...Middleware) StreamMessageFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		fn = middleware[i].WrapStreamMessage(fn)
+	}
+	return fn
+}
+
+// MapEventStream returns a stream that passes every event of stream through fn.
+// Events for which fn returns nil are dropped; an error from fn ends the stream.
+func MapEventStream(stream EventStream, fn func(types.StreamEvent) (types.StreamEvent, error)) EventStream {
+	return &mappedEventStream{EventStream: stream, fn: fn}
+}
+
+// mappedEventStream applies a function to each event of the wrapped stream.
+type mappedEventStream struct {
+	EventStream
+	fn func(types.StreamEvent) (types.StreamEvent, error)
+}
+
+// Next returns the next mapped event.
+func (s *mappedEventStream) Next() (types.StreamEvent, error) {
+	for {
+		event, err := s.EventStream.Next()
+		if err != nil || event == nil {
+			return event, err
+		}
+		mapped, err := s.fn(event)
+		if err != nil {
+			return nil, err
+		}
+		if mapped != nil {
+			return mapped, nil
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// recordingMiddleware appends its name to a shared log on every call.
+func recordingMiddleware(name string, log *[]string) Middleware {
+	return MiddlewareFuncs{
+		CreateMessage: func(ctx context.Context, req *types.MessageRequest, next CreateMessageFunc) (*types.MessageResponse, error) {
+			*log = append(*log, name+":before")
+			resp, err := next(ctx, req)
+			*log = append(*log, name+":after")
+			return resp, err
+		},
+		StreamMessage: func(ctx context.Context, req *types.MessageRequest, next StreamMessageFunc) (EventStream, error) {
+			*log = append(*log, name+":stream")
+			return next(ctx, req)
+		},
+	}
+}
+
+func TestEngine_Use_Order(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&routeTestProvider{name: "test"})
+
+	var log []string
+	e.Use(recordingMiddleware("outer", &log), recordingMiddleware("inner", &log))
+
+	if _, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "test/model"}); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+
+	want := "outer:before,inner:before,inner:after,outer:after"
+	if got := strings.Join(log, ","); got != want {
+		t.Errorf("call order = %s, want %s", got, want)
+	}
+}
+
+func TestEngine_Use_ModifiesRequest(t *testing.T) {
+	e := NewEngine(nil)
+	p := &routeTestProvider{name: "test"}
+	e.RegisterProvider(p)
+	e.SetModelAlias("fast", "test/small")
+
+	// Middleware sees the logical name and may rewrite it
+	var seen string
+	e.Use(MiddlewareFuncs{
+		CreateMessage: func(ctx context.Context, req *types.MessageRequest, next CreateMessageFunc) (*types.MessageResponse, error) {
+			seen = req.Model
+			reqCopy := *req
+			reqCopy.Model = "test/large"
+			return next(ctx, &reqCopy)
+		},
+	})
+
+	if _, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "fast"}); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if seen != "fast" {
+		t.Errorf("middleware saw model %q, want fast", seen)
+	}
+	if p.models[0] != "large" {
+		t.Errorf("provider got model %q, want large", p.models[0])
+	}
+}
+
+func TestEngine_Use_ShortCircuit(t *testing.T) {
+	e := NewEngine(nil)
+	p := &routeTestProvider{name: "test"}
+	e.RegisterProvider(p)
+
+	cached := &types.MessageResponse{ID: "msg_cached"}
+	e.Use(MiddlewareFuncs{
+		CreateMessage: func(ctx context.Context, req *types.MessageRequest, next CreateMessageFunc) (*types.MessageResponse, error) {
+			return cached, nil
+		},
+	})
+
+	resp, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "test/model"})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.ID != "msg_cached" || p.calls != 0 {
+		t.Errorf("expected cached response without provider call, got %s (calls=%d)", resp.ID, p.calls)
+	}
+}
+
+func TestEngine_Use_WrapsEventStream(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&routeTestProvider{name: "test", events: []types.StreamEvent{
+		types.ContentBlockDeltaEvent{Type: "content_block_delta", Delta: types.TextDelta{Type: "text_delta", Text: "my secret"}},
+		types.PingEvent{Type: "ping"},
+		types.MessageStopEvent{Type: "message_stop"},
+	}})
+
+	var log []string
+	e.Use(
+		recordingMiddleware("log", &log),
+		MiddlewareFuncs{
+			Event: func(ctx context.Context, req *types.MessageRequest, event types.StreamEvent) (types.StreamEvent, error) {
+				switch ev := event.(type) {
+				case types.PingEvent:
+					return nil, nil
+				case types.ContentBlockDeltaEvent:
+					if d, ok := ev.Delta.(types.TextDelta); ok {
+						d.Text = strings.ReplaceAll(d.Text, "secret", "[redacted]")
+						ev.Delta = d
+					}
+					return ev, nil
+				}
+				return event, nil
+			},
+		},
+	)
+
+	stream, err := e.StreamMessage(context.Background(), &types.MessageRequest{Model: "test/model"})
+	if err != nil {
+		t.Fatalf("StreamMessage() error = %v", err)
+	}
+	defer stream.Close()
+
+	var got []string
+	for {
+		event, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if delta, ok := event.(types.ContentBlockDeltaEvent); ok {
+			got = append(got, delta.Delta.(types.TextDelta).Text)
+		} else {
+			got = append(got, event.EventType())
+		}
+	}
+
+	if strings.Join(got, ",") != "my [redacted],message_stop" {
+		t.Errorf("events = %v", got)
+	}
+	if strings.Join(log, ",") != "log:stream" {
+		t.Errorf("log = %v", log)
+	}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// PricingTable prices token usage for "provider/model" IDs.
+// catalog.Catalog implements it using the embedded model pricing.
+type PricingTable interface {
+	// Cost returns the USD cost of usage on model, or false if the model has no pricing.
+	Cost(model string, usage types.Usage) (float64, bool)
+}
+
+// SetPricing sets the table used to fill in Usage.CostUSD on responses.
+// Responses whose provider already reported a cost are left untouched.
+// A nil table disables cost calculation.
+func (e *Engine) SetPricing(pricing PricingTable) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pricing = pricing
+}
+
+// Pricing returns the current pricing table, or nil if none is set.
+func (e *Engine) Pricing() PricingTable {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.pricing
+}
+
+// priceUsage sets usage.CostUSD from the first of models that has pricing.
+func (e *Engine) priceUsage(usage *types.Usage, models ...string) {
+	pricing := e.Pricing()
+	if pricing == nil || usage.CostUSD != nil || usage.IsEmpty() {
+		return
+	}
+	for _, model := range models {
+		if model == "" {
+			continue
+		}
+		if cost, ok := pricing.Cost(model, *usage); ok {
+			usage.CostUSD = &cost
+			return
+		}
+	}
+}
+
+// pricedEventStream fills in the cost of a streamed message. Input and cache
+// counts arrive on message_start and output counts on message_delta, so the
+// cost of the whole message is attached to the message_delta usage.
+func (e *Engine) pricedEventStream(stream EventStream, model string) EventStream {
+	if e.Pricing() == nil {
+		return stream
+	}
+
+	var start types.Usage
+	var served string
+	return MapEventStream(stream, func(event types.StreamEvent) (types.StreamEvent, error) {
+		switch ev := event.(type) {
+		case types.MessageStartEvent:
+			start = ev.Message.Usage
+			served = ev.Message.Model
+		case types.MessageDeltaEvent:
+			usage := ev.Usage
+			if usage.InputTokens == 0 {
+				usage.InputTokens = start.InputTokens
+			}
+			if usage.CacheReadTokens == nil {
+				usage.CacheReadTokens = start.CacheReadTokens
+			}
+			if usage.CacheWriteTokens == nil {
+				usage.CacheWriteTokens = start.CacheWriteTokens
+			}
+			e.priceUsage(&usage, model, served)
+			if usage.CostUSD != nil {
+				ev.Usage.CostUSD = usage.CostUSD
+				return ev, nil
+			}
+		}
+		return event, nil
+	})
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// perTokenPricing charges a flat rate per input and output token for known models.
+type perTokenPricing map[string]float64
+
+func (p perTokenPricing) Cost(model string, usage types.Usage) (float64, bool) {
+	rate, ok := p[model]
+	if !ok {
+		return 0, false
+	}
+	tokens := usage.InputTokens + usage.OutputTokens
+	if usage.CacheReadTokens != nil {
+		tokens += *usage.CacheReadTokens
+	}
+	return float64(tokens) * rate, true
+}
+
+// usageProvider reports fixed usage on every response.
+type usageProvider struct {
+	routeTestProvider
+	usage types.Usage
+}
+
+func (p *usageProvider) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	resp, err := p.routeTestProvider.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Usage = p.usage
+	return resp, nil
+}
+
+func TestEngine_PricesResponses(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&usageProvider{routeTestProvider{name: "test"}, types.Usage{InputTokens: 10, OutputTokens: 5}})
+	req := &types.MessageRequest{Model: "test/model", Messages: []types.Message{{Role: "user", Content: "hi"}}}
+
+	// No pricing table, no cost
+	resp, err := e.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.Usage.CostUSD != nil {
+		t.Errorf("CostUSD = %v, want nil", *resp.Usage.CostUSD)
+	}
+
+	e.SetPricing(perTokenPricing{"test/model": 0.5})
+	resp, err = e.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.Usage.CostUSD == nil || *resp.Usage.CostUSD != 7.5 {
+		t.Errorf("CostUSD = %v, want 7.5", resp.Usage.CostUSD)
+	}
+
+	// Unpriced models are left alone
+	e.RegisterProvider(&usageProvider{routeTestProvider{name: "other"}, types.Usage{InputTokens: 10}})
+	resp, err = e.CreateMessage(context.Background(), &types.MessageRequest{Model: "other/model"})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.Usage.CostUSD != nil {
+		t.Errorf("CostUSD = %v, want nil", *resp.Usage.CostUSD)
+	}
+}
+
+func TestEngine_PricesStreams(t *testing.T) {
+	cached := 4
+	e := NewEngine(nil)
+	e.SetPricing(perTokenPricing{"test/model": 1})
+	e.RegisterProvider(&routeTestProvider{name: "test", events: []types.StreamEvent{
+		types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{
+			Usage: types.Usage{InputTokens: 10, CacheReadTokens: &cached},
+		}},
+		types.MessageDeltaEvent{Type: "message_delta", Usage: types.Usage{OutputTokens: 6}},
+		types.MessageStopEvent{Type: "message_stop"},
+	}})
+
+	stream, err := e.StreamMessage(context.Background(), &types.MessageRequest{Model: "test/model"})
+	if err != nil {
+		t.Fatalf("StreamMessage() error = %v", err)
+	}
+	defer stream.Close()
+
+	var delta *types.MessageDeltaEvent
+	for {
+		event, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if d, ok := event.(types.MessageDeltaEvent); ok {
+			delta = &d
+		}
+	}
+
+	if delta == nil {
+		t.Fatal("no message_delta event")
+	}
+	if delta.Usage.CostUSD == nil || *delta.Usage.CostUSD != 20 {
+		t.Errorf("CostUSD = %v, want 20", delta.Usage.CostUSD)
+	}
+	if delta.Usage.InputTokens != 0 {
+		t.Errorf("delta usage was modified: %+v", delta.Usage)
+	}
+}
@@ -533,3 +533,32 @@ func TestError_IsRetryable(t *testing.T) {
 		}
 	}
 }
+
+func TestParseResponse_CacheUsage(t *testing.T) {
+	resp, err := parseResponse([]byte(`{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4","content":[],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":200,"cache_creation_input_tokens":50}}`))
+	if err != nil {
+		t.Fatalf("parseResponse() error = %v", err)
+	}
+	usage := resp.Usage
+	if usage.InputTokens != 10 || usage.OutputTokens != 5 {
+		t.Errorf("tokens = %d/%d, want 10/5", usage.InputTokens, usage.OutputTokens)
+	}
+	if usage.CacheReadTokens == nil || *usage.CacheReadTokens != 200 {
+		t.Errorf("CacheReadTokens = %v, want 200", usage.CacheReadTokens)
+	}
+	if usage.CacheWriteTokens == nil || *usage.CacheWriteTokens != 50 {
+		t.Errorf("CacheWriteTokens = %v, want 50", usage.CacheWriteTokens)
+	}
+
+	event, err := parseStreamEventFromData([]byte(`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4","content":[],"usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":200}}}`))
+	if err != nil {
+		t.Fatalf("parseStreamEventFromData() error = %v", err)
+	}
+	start, ok := event.(types.MessageStartEvent)
+	if !ok {
+		t.Fatalf("expected MessageStartEvent, got %T", event)
+	}
+	if start.Message.Usage.CacheReadTokens == nil || *start.Message.Usage.CacheReadTokens != 200 {
+		t.Errorf("stream CacheReadTokens = %v, want 200", start.Message.Usage.CacheReadTokens)
+	}
+}
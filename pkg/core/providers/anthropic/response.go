@@ -17,7 +17,26 @@ type anthropicResponse struct {
 	Content      []json.RawMessage `json:"content"`
 	StopReason   string            `json:"stop_reason"`
 	StopSequence *string           `json:"stop_sequence,omitempty"`
-	Usage        types.Usage       `json:"usage"`
+	Usage        anthropicUsage    `json:"usage"`
+}
+
+// anthropicUsage matches Anthropic's usage format, which names the prompt
+// cache counters differently from types.Usage.
+type anthropicUsage struct {
+	InputTokens              int  `json:"input_tokens"`
+	OutputTokens             int  `json:"output_tokens"`
+	CacheReadInputTokens     *int `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens *int `json:"cache_creation_input_tokens,omitempty"`
+}
+
+// toUsage converts Anthropic usage into a Vango AI usage.
+func (u anthropicUsage) toUsage() types.Usage {
+	return types.Usage{
+		InputTokens:      u.InputTokens,
+		OutputTokens:     u.OutputTokens,
+		CacheReadTokens:  u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheCreationInputTokens,
+	}
 }
 
 // parseResponse parses an Anthropic response into a Vango AI response.
@@ -41,7 +60,7 @@ func parseResponse(body []byte) (*types.MessageResponse, error) {
 		Content:      content,
 		StopReason:   types.StopReason(anthResp.StopReason),
 		StopSequence: anthResp.StopSequence,
-		Usage:        anthResp.Usage,
+		Usage:        anthResp.Usage.toUsage(),
 	}, nil
 }
 
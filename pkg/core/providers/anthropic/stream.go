@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"bufio"
+	"encoding/json"
 	"io"
 	"strings"
 
@@ -105,11 +106,28 @@ func (s *eventStream) Close() error {
 
 // parseStreamEvent parses a stream event from its type and JSON data.
 func parseStreamEvent(eventType string, data []byte) (types.StreamEvent, error) {
-	return types.UnmarshalStreamEvent(data)
+	return parseStreamEventFromData(data)
 }
 
 // parseStreamEventFromData parses a stream event from JSON data,
 // determining the event type from the "type" field in the data.
 func parseStreamEventFromData(data []byte) (types.StreamEvent, error) {
-	return types.UnmarshalStreamEvent(data)
+	event, err := types.UnmarshalStreamEvent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prompt cache counters only arrive on message_start
+	if start, ok := event.(types.MessageStartEvent); ok {
+		var raw struct {
+			Message struct {
+				Usage anthropicUsage `json:"usage"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(data, &raw); err == nil {
+			start.Message.Usage = raw.Message.Usage.toUsage()
+			event = start
+		}
+	}
+	return event, nil
 }
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// Metadata keys set on responses served through a model route.
+const (
+	// MetadataRoute is the logical model name that was requested (e.g. "fast").
+	MetadataRoute = "route"
+	// MetadataRoutedModel is the "provider/model" target that served the request.
+	MetadataRoutedModel = "routed_model"
+	// MetadataFallbackAttempts is the number of targets that failed before the serving one.
+	MetadataFallbackAttempts = "fallback_attempts"
+)
+
+// SetModelRoute registers a logical model name that resolves to an ordered list
+// of "provider/model" targets. Requests for name try each target in turn and
+// fail over to the next one on retryable errors or provider outages.
+// A route with a single target behaves as a plain alias.
+func (e *Engine) SetModelRoute(name string, targets ...string) error {
+	if name == "" {
+		return NewInvalidRequestError("model route name must not be empty")
+	}
+	if len(targets) == 0 {
+		return NewInvalidRequestError(fmt.Sprintf("model route %q has no targets", name))
+	}
+	for _, target := range targets {
+		if _, _, err := ParseModelString(target); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.routes == nil {
+		e.routes = make(map[string][]string)
+	}
+	e.routes[name] = append([]string(nil), targets...)
+	return nil
+}
+
+// SetModelAlias registers alias as another name for a single "provider/model" target.
+func (e *Engine) SetModelAlias(alias, target string) error {
+	return e.SetModelRoute(alias, target)
+}
+
+// RemoveModelRoute removes a previously registered route or alias.
+func (e *Engine) RemoveModelRoute(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.routes, name)
+}
+
+// ModelRoutes returns a copy of the registered routes.
+func (e *Engine) ModelRoutes() map[string][]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	routes := make(map[string][]string, len(e.routes))
+	for name, targets := range e.routes {
+		routes[name] = append([]string(nil), targets...)
+	}
+	return routes
+}
+
+// ModelRouteNames returns the registered route names in sorted order.
+func (e *Engine) ModelRouteNames() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	names := make([]string, 0, len(e.routes))
+	for name := range e.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveModel returns the ordered "provider/model" targets for a model string.
+// Route names resolve to their targets; anything else must be a valid
+// "provider/model" string and resolves to itself.
+func (e *Engine) ResolveModel(model string) ([]string, error) {
+	if targets, ok := e.route(model); ok {
+		return targets, nil
+	}
+	if _, _, err := ParseModelString(model); err != nil {
+		return nil, err
+	}
+	return []string{model}, nil
+}
+
+// route looks up a registered route.
+func (e *Engine) route(name string) ([]string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	targets, ok := e.routes[name]
+	return targets, ok
+}
+
+// createMessageRouted tries each target of a route until one succeeds.
+func (e *Engine) createMessageRouted(ctx context.Context, req *types.MessageRequest, route string, targets []string) (*types.MessageResponse, error) {
+	var lastErr error
+	for i, target := range targets {
+		resp, err := e.createMessage(ctx, req, target)
+		if err == nil {
+			resp.Metadata = routeMetadata(resp.Metadata, route, target, i)
+			return resp, nil
+		}
+		lastErr = err
+		if !shouldFailover(ctx, err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// streamMessageRouted opens a stream on each target of a route until one
+// produces a healthy first event. Once an event has been handed to the
+// caller, the stream is committed to that target.
+func (e *Engine) streamMessageRouted(ctx context.Context, req *types.MessageRequest, route string, targets []string) (EventStream, error) {
+	var lastErr error
+	for i, target := range targets {
+		last := i == len(targets)-1
+
+		stream, err := e.streamMessage(ctx, req, target)
+		if err != nil {
+			lastErr = err
+			if last || !shouldFailover(ctx, err) {
+				return nil, err
+			}
+			continue
+		}
+
+		// Peek at the first event so overloads reported at stream start can fail over
+		event, err := stream.Next()
+		if !last {
+			failErr := err
+			if failErr == nil {
+				if errEvent, ok := event.(types.ErrorEvent); ok {
+					failErr = &Error{Type: ErrorType(errEvent.Error.Type), Message: errEvent.Error.Message}
+				}
+			}
+			if failErr != nil && failErr != io.EOF && shouldFailover(ctx, failErr) {
+				stream.Close()
+				lastErr = failErr
+				continue
+			}
+		}
+
+		return &routedEventStream{
+			EventStream: stream,
+			first:       event,
+			firstErr:    err,
+			pending:     true,
+			route:       route,
+			target:      target,
+			attempts:    i,
+		}, nil
+	}
+	return nil, lastErr
+}
+
+// shouldFailover reports whether a failed target should be skipped in favour of the next one.
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if IsRetryableError(err) {
+		return true
+	}
+
+	// Unregistered providers and transport failures count as outages, and a
+	// target lacking a required capability may be covered by the next one
+	var coreErr *Error
+	if errors.As(err, &coreErr) && (coreErr.Type == ErrProvider || coreErr.Code == ErrCodeUnsupportedFeature) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// routeMetadata records which target served a routed request.
+func routeMetadata(metadata map[string]any, route, target string, attempts int) map[string]any {
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata[MetadataRoute] = route
+	metadata[MetadataRoutedModel] = target
+	metadata[MetadataFallbackAttempts] = attempts
+	return metadata
+}
+
+// routedEventStream replays the peeked first event and tags message_start
+// with the serving target.
+type routedEventStream struct {
+	EventStream
+	first    types.StreamEvent
+	firstErr error
+	pending  bool
+	route    string
+	target   string
+	attempts int
+}
+
+// Next returns the next event from the underlying stream.
+func (s *routedEventStream) Next() (types.StreamEvent, error) {
+	var event types.StreamEvent
+	var err error
+	if s.pending {
+		s.pending = false
+		event, err = s.first, s.firstErr
+		s.first, s.firstErr = nil, nil
+	} else {
+		event, err = s.EventStream.Next()
+	}
+
+	if start, ok := event.(types.MessageStartEvent); ok {
+		start.Message.Metadata = routeMetadata(start.Message.Metadata, s.route, s.target, s.attempts)
+		event = start
+	}
+	return event, err
+}
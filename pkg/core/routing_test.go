@@ -0,0 +1,227 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// routeTestProvider returns a fixed error or a canned response naming itself.
+type routeTestProvider struct {
+	name   string
+	err    error
+	events []types.StreamEvent
+	calls  int
+	models []string
+}
+
+func (p *routeTestProvider) Name() string { return p.name }
+
+func (p *routeTestProvider) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	p.calls++
+	p.models = append(p.models, req.Model)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &types.MessageResponse{ID: "msg_" + p.name, Model: req.Model}, nil
+}
+
+func (p *routeTestProvider) StreamMessage(ctx context.Context, req *types.MessageRequest) (EventStream, error) {
+	p.calls++
+	p.models = append(p.models, req.Model)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &sliceEventStream{events: p.events}, nil
+}
+
+func (p *routeTestProvider) Capabilities() ProviderCapabilities { return ProviderCapabilities{} }
+
+type sliceEventStream struct {
+	events []types.StreamEvent
+	closed bool
+}
+
+func (s *sliceEventStream) Next() (types.StreamEvent, error) {
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func (s *sliceEventStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestEngine_SetModelRoute_Validation(t *testing.T) {
+	e := NewEngine(nil)
+
+	if err := e.SetModelRoute("", "anthropic/claude"); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := e.SetModelRoute("fast"); err == nil {
+		t.Error("expected error for route without targets")
+	}
+	if err := e.SetModelRoute("fast", "not-a-model"); err == nil {
+		t.Error("expected error for malformed target")
+	}
+	if err := e.SetModelRoute("fast", "groq/llama", "openai/gpt-4o-mini"); err != nil {
+		t.Fatalf("SetModelRoute() error = %v", err)
+	}
+
+	targets, err := e.ResolveModel("fast")
+	if err != nil || len(targets) != 2 || targets[0] != "groq/llama" {
+		t.Errorf("ResolveModel(fast) = %v, %v", targets, err)
+	}
+	targets, err = e.ResolveModel("anthropic/claude")
+	if err != nil || len(targets) != 1 || targets[0] != "anthropic/claude" {
+		t.Errorf("ResolveModel(anthropic/claude) = %v, %v", targets, err)
+	}
+	if _, err := e.ResolveModel("unknown"); err == nil {
+		t.Error("expected error for unknown name")
+	}
+
+	e.RemoveModelRoute("fast")
+	if len(e.ModelRoutes()) != 0 {
+		t.Errorf("ModelRoutes() = %v, want empty", e.ModelRoutes())
+	}
+}
+
+func TestEngine_CreateMessage_Alias(t *testing.T) {
+	e := NewEngine(nil)
+	p := &routeTestProvider{name: "anthropic"}
+	e.RegisterProvider(p)
+	e.SetModelAlias("smart", "anthropic/claude-sonnet-4")
+
+	resp, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "smart"})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if p.models[0] != "claude-sonnet-4" {
+		t.Errorf("provider got model %q", p.models[0])
+	}
+	if resp.Metadata[MetadataRoute] != "smart" || resp.Metadata[MetadataRoutedModel] != "anthropic/claude-sonnet-4" {
+		t.Errorf("Metadata = %v", resp.Metadata)
+	}
+}
+
+func TestEngine_CreateMessage_Failover(t *testing.T) {
+	e := NewEngine(nil)
+	anthropic := &routeTestProvider{name: "anthropic", err: NewOverloadedError("overloaded")}
+	groq := &routeTestProvider{name: "groq"}
+	e.RegisterProvider(anthropic)
+	e.RegisterProvider(groq)
+	// "missing" is not registered and counts as an outage
+	e.SetModelRoute("fast", "anthropic/claude-haiku", "missing/model", "groq/llama-3.3-70b")
+
+	resp, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "fast"})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.ID != "msg_groq" {
+		t.Errorf("served by %q, want groq", resp.ID)
+	}
+	if resp.Metadata[MetadataRoutedModel] != "groq/llama-3.3-70b" {
+		t.Errorf("routed_model = %v", resp.Metadata[MetadataRoutedModel])
+	}
+	if resp.Metadata[MetadataFallbackAttempts] != 2 {
+		t.Errorf("fallback_attempts = %v, want 2", resp.Metadata[MetadataFallbackAttempts])
+	}
+}
+
+func TestEngine_CreateMessage_NoFailoverOnInvalidRequest(t *testing.T) {
+	e := NewEngine(nil)
+	anthropic := &routeTestProvider{name: "anthropic", err: NewInvalidRequestError("bad")}
+	groq := &routeTestProvider{name: "groq"}
+	e.RegisterProvider(anthropic)
+	e.RegisterProvider(groq)
+	e.SetModelRoute("fast", "anthropic/claude-haiku", "groq/llama-3.3-70b")
+
+	_, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "fast"})
+	var coreErr *Error
+	if !errors.As(err, &coreErr) || coreErr.Type != ErrInvalidRequest {
+		t.Fatalf("expected invalid request error, got %v", err)
+	}
+	if groq.calls != 0 {
+		t.Errorf("groq calls = %d, want 0", groq.calls)
+	}
+}
+
+func TestEngine_CreateMessage_AllTargetsFail(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&routeTestProvider{name: "anthropic", err: NewAPIError("first")})
+	e.RegisterProvider(&routeTestProvider{name: "groq", err: NewOverloadedError("last")})
+	e.SetModelRoute("fast", "anthropic/claude-haiku", "groq/llama-3.3-70b")
+
+	_, err := e.CreateMessage(context.Background(), &types.MessageRequest{Model: "fast"})
+	var coreErr *Error
+	if !errors.As(err, &coreErr) || coreErr.Message != "last" {
+		t.Fatalf("expected last target's error, got %v", err)
+	}
+}
+
+func TestEngine_StreamMessage_FailoverOnErrorEvent(t *testing.T) {
+	e := NewEngine(nil)
+	anthropic := &routeTestProvider{name: "anthropic", events: []types.StreamEvent{
+		types.ErrorEvent{Type: "error", Error: types.Error{Type: string(ErrOverloaded), Message: "overloaded"}},
+	}}
+	groq := &routeTestProvider{name: "groq", events: []types.StreamEvent{
+		types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{ID: "msg_groq"}},
+		types.MessageStopEvent{Type: "message_stop"},
+	}}
+	e.RegisterProvider(anthropic)
+	e.RegisterProvider(groq)
+	e.SetModelRoute("fast", "anthropic/claude-haiku", "groq/llama-3.3-70b")
+
+	stream, err := e.StreamMessage(context.Background(), &types.MessageRequest{Model: "fast"})
+	if err != nil {
+		t.Fatalf("StreamMessage() error = %v", err)
+	}
+	defer stream.Close()
+
+	event, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	start, ok := event.(types.MessageStartEvent)
+	if !ok {
+		t.Fatalf("first event = %T, want MessageStartEvent", event)
+	}
+	if start.Message.ID != "msg_groq" || start.Message.Metadata[MetadataRoutedModel] != "groq/llama-3.3-70b" {
+		t.Errorf("message_start = %+v", start.Message)
+	}
+
+	if event, _ := stream.Next(); event == nil || event.EventType() != "message_stop" {
+		t.Errorf("second event = %v, want message_stop", event)
+	}
+	if _, err := stream.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestEngine_StreamMessage_LastTargetErrorDelivered(t *testing.T) {
+	e := NewEngine(nil)
+	e.RegisterProvider(&routeTestProvider{name: "anthropic", err: NewOverloadedError("down")})
+	e.RegisterProvider(&routeTestProvider{name: "groq", events: []types.StreamEvent{
+		types.ErrorEvent{Type: "error", Error: types.Error{Type: string(ErrOverloaded), Message: "also down"}},
+	}})
+	e.SetModelRoute("fast", "anthropic/claude-haiku", "groq/llama-3.3-70b")
+
+	stream, err := e.StreamMessage(context.Background(), &types.MessageRequest{Model: "fast"})
+	if err != nil {
+		t.Fatalf("StreamMessage() error = %v", err)
+	}
+	event, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if _, ok := event.(types.ErrorEvent); !ok {
+		t.Errorf("expected the last target's error event, got %T", event)
+	}
+}
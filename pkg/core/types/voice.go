@@ -7,21 +7,62 @@ type VoiceConfig struct {
 }
 
 // VoiceInputConfig configures speech-to-text.
-// Currently only Cartesia (ink-whisper) is supported.
+// Model selects the provider as "provider/model", e.g. "deepgram/nova-3",
+// "openai/whisper-1" or "whisper-cpp/local". A model without a provider,
+// such as "ink-whisper", uses the default provider (Cartesia when configured).
 type VoiceInputConfig struct {
-	Model    string `json:"model,omitempty"`    // Model: "ink-whisper" (default)
+	Model    string `json:"model,omitempty"`    // Model: "ink-whisper" (default) or "provider/model"
 	Language string `json:"language,omitempty"` // ISO language code (default: "en")
 }
 
 // VoiceOutputConfig configures text-to-speech.
-// Currently only Cartesia (sonic-3) is supported.
+// Voice selects the provider as "provider/voice", e.g.
+// "elevenlabs/21m00Tcm4TlvDq8ikWAM", "openai/alloy" or
+// "piper/en_US-lessac-medium". A voice without a provider, such as a
+// Cartesia voice ID, uses the default provider (Cartesia when configured).
 type VoiceOutputConfig struct {
-	Voice      string  `json:"voice"`                  // Cartesia voice ID (required)
+	Voice      string  `json:"voice"`                  // Voice ID or "provider/voice" (required)
 	Speed      float64 `json:"speed,omitempty"`        // Speed: 0.6-1.5 (default: 1.0)
 	Volume     float64 `json:"volume,omitempty"`       // Volume: 0.5-2.0 (default: 1.0)
 	Emotion    string  `json:"emotion,omitempty"`      // Emotion (neutral, happy, sad, angry, etc.)
-	Format     string  `json:"format,omitempty"`       // Output format: wav, mp3, pcm (default: wav)
+	Format     string  `json:"format,omitempty"`       // Output format: wav, mp3, pcm, mulaw, alaw (default: wav)
 	SampleRate int     `json:"sample_rate,omitempty"`  // Sample rate in Hz (default: 24000)
+
+	// Normalization rewrites text before it is spoken, e.g. stripping
+	// markdown and reading "$5" as "five dollars". On by default.
+	Normalization *TextNormalization `json:"normalization,omitempty"`
+
+	// Chunking controls how streamed text is split up for TTS.
+	Chunking *TextChunking `json:"chunking,omitempty"`
+
+	// Fillers are spoken while the agent waits on a slow model or tool,
+	// so that the line does not go silent. Off unless configured.
+	Fillers *FillerConfig `json:"fillers,omitempty"`
+}
+
+// TextNormalization configures how text is rewritten for speech.
+type TextNormalization struct {
+	Disabled   bool              `json:"disabled,omitempty"`   // Send text to TTS as written
+	Locale     string            `json:"locale,omitempty"`     // Language of the text (default: the input language, else "en")
+	Dictionary map[string]string `json:"dictionary,omitempty"` // Pronunciations by word, e.g. {"SQL": "sequel"}
+}
+
+// TextChunking configures how streamed text is split into chunks for TTS.
+type TextChunking struct {
+	Strategy   string `json:"strategy,omitempty"`    // sentence, clause, words, time or adaptive
+	Words      int    `json:"words,omitempty"`       // Chunk size in words (see the strategy)
+	MaxWords   int    `json:"max_words,omitempty"`   // Largest adaptive chunk (default: 30)
+	IntervalMs int    `json:"interval_ms,omitempty"` // Time strategy interval (default: 300)
+}
+
+// FillerConfig configures speech played while the agent waits. Fillers
+// stop as soon as the response audio starts and are not added to the
+// conversation history.
+type FillerConfig struct {
+	DelayMs   int               `json:"delay_ms,omitempty"`   // Silence before a filler plays (default: 1500)
+	Phrases   []string          `json:"phrases,omitempty"`    // Spoken in turn, e.g. "Let me check that."
+	Tools     map[string]string `json:"tools,omitempty"`      // Message by tool name, spoken while it runs
+	HoldAudio []byte            `json:"hold_audio,omitempty"` // Audio in the output format, looped until the response
 }
 
 // Voice format constants
@@ -29,6 +70,10 @@ const (
 	VoiceFormatMP3 = "mp3"
 	VoiceFormatWAV = "wav"
 	VoiceFormatPCM = "pcm"
+
+	// G.711 telephony formats, synthesized as PCM and encoded locally
+	VoiceFormatMulaw = "mulaw"
+	VoiceFormatAlaw  = "alaw"
 )
 
 // Supported emotions for Cartesia sonic-3
@@ -0,0 +1,83 @@
+package voice
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// sttInputFormat is the format WAV and raw audio is converted to before
+// transcription: 16 kHz mono 16-bit PCM, which every STT backend accepts.
+var sttInputFormat = audio.PCM16(16000, 1)
+
+// rawMediaFormat returns the audio format of a headerless media type such
+// as "audio/pcmu" or "audio/L16;rate=16000". ok is false for containers.
+func rawMediaFormat(mediaType string) (f audio.Format, ok bool) {
+	base, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		base = strings.ToLower(strings.TrimSpace(mediaType))
+	}
+
+	switch base {
+	case "audio/basic", "audio/pcmu", "audio/mulaw", "audio/x-mulaw":
+		f = audio.Format{Encoding: audio.EncodingMulaw, SampleRate: 8000, Channels: 1}
+	case "audio/pcma", "audio/alaw", "audio/x-alaw":
+		f = audio.Format{Encoding: audio.EncodingAlaw, SampleRate: 8000, Channels: 1}
+	case "audio/l16", "audio/pcm":
+		f = audio.PCM16(16000, 1)
+	default:
+		return audio.Format{}, false
+	}
+
+	if rate, err := strconv.Atoi(params["rate"]); err == nil && rate > 0 {
+		f.SampleRate = rate
+	}
+	if channels, err := strconv.Atoi(params["channels"]); err == nil && channels > 0 {
+		f.Channels = channels
+	}
+	return f, true
+}
+
+// normalizeInputAudio converts WAV files and raw audio to a 16-bit mono WAV
+// at sttInputFormat. Compressed containers (mp3, webm, ...) and WAV files
+// that cannot be parsed are returned unchanged for the STT provider to handle.
+func normalizeInputAudio(data []byte, mediaType string) ([]byte, string) {
+	from, ok := rawMediaFormat(mediaType)
+	samples := data
+	if !ok {
+		if !audio.IsWAV(data) {
+			return data, getFormatFromMediaType(mediaType)
+		}
+		w, err := audio.ParseWAV(data)
+		if err != nil {
+			return data, "wav"
+		}
+		from, samples = w.Format, w.Data
+	}
+
+	pcm, err := audio.Convert(samples, from, sttInputFormat)
+	if err != nil {
+		return data, getFormatFromMediaType(mediaType)
+	}
+	wav, err := audio.EncodeWAV(pcm, sttInputFormat)
+	if err != nil {
+		return data, getFormatFromMediaType(mediaType)
+	}
+	return wav, "wav"
+}
+
+// g711Encoding returns the G.711 encoding for a voice output format.
+// Providers synthesize these formats as PCM, which is encoded locally.
+func g711Encoding(format string) (audio.Encoding, bool) {
+	switch format {
+	case types.VoiceFormatMulaw:
+		return audio.EncodingMulaw, true
+	case types.VoiceFormatAlaw:
+		return audio.EncodingAlaw, true
+	default:
+		return "", false
+	}
+}
@@ -2,6 +2,8 @@ package voice
 
 import (
 	"strings"
+
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
 )
 
 // SentenceBuffer accumulates text and extracts complete sentences.
@@ -25,7 +27,7 @@ func (b *SentenceBuffer) Add(text string) []string {
 	// Find sentence boundaries
 	lastEnd := 0
 	for i := 0; i < len(content); i++ {
-		if isSentenceEnd(content, i) {
+		if chunk.IsSentenceEnd(content, i) {
 			sentence := strings.TrimSpace(content[lastEnd : i+1])
 			if sentence != "" {
 				sentences = append(sentences, sentence)
@@ -54,64 +56,3 @@ func (b *SentenceBuffer) Flush() string {
 func (b *SentenceBuffer) Pending() string {
 	return b.buffer.String()
 }
-
-// isSentenceEnd checks if position i is a sentence boundary.
-func isSentenceEnd(s string, i int) bool {
-	if i >= len(s) {
-		return false
-	}
-
-	c := s[i]
-	if c != '.' && c != '!' && c != '?' {
-		return false
-	}
-
-	// Check it's not an abbreviation (Dr., Mr., etc.)
-	if c == '.' && isAbbreviation(s, i) {
-		return false
-	}
-
-	// Check there's whitespace or end of string after
-	if i+1 < len(s) && s[i+1] != ' ' && s[i+1] != '\n' && s[i+1] != '\r' && s[i+1] != '\t' {
-		return false
-	}
-
-	return true
-}
-
-// isAbbreviation checks if the period at position i is likely an abbreviation.
-func isAbbreviation(s string, i int) bool {
-	if i < 1 {
-		return false
-	}
-
-	// Common abbreviations
-	commonAbbreviations := []string{
-		"Dr.", "Mr.", "Mrs.", "Ms.", "Jr.", "Sr.",
-		"Prof.", "Rev.", "Gen.", "Col.", "Lt.", "Sgt.",
-		"Inc.", "Ltd.", "Corp.", "Co.", "vs.", "etc.",
-		"i.e.", "e.g.", "a.m.", "p.m.", "U.S.", "U.K.",
-	}
-
-	// Get the word ending at i (including the period)
-	start := i
-	for start > 0 && s[start-1] != ' ' && s[start-1] != '\n' {
-		start--
-	}
-	word := s[start : i+1]
-
-	for _, abbr := range commonAbbreviations {
-		if strings.EqualFold(word, abbr) {
-			return true
-		}
-	}
-
-	// Check if it's a single uppercase letter followed by period (initials)
-	if i >= 1 && s[i-1] >= 'A' && s[i-1] <= 'Z' {
-		if i < 2 || s[i-2] == ' ' || s[i-2] == '\n' {
-			return true
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,281 @@
+// Package chunk splits streamed LLM text into chunks for TTS.
+//
+// Small chunks start speech sooner; larger ones give the TTS engine more
+// context and sound more natural. Which trade-off is right depends on the
+// voice, the language and the use, so the strategy is configurable.
+package chunk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Chunker splits streamed text into chunks for TTS. Add takes the next
+// piece of text and returns the text that is ready to synthesize, or ""
+// to wait for more. Flush returns all buffered text, at the end of a
+// response or before a pause, after which the Chunker starts afresh.
+type Chunker interface {
+	Add(text string) string
+	Flush() string
+}
+
+// Strategy names a built-in chunking strategy.
+type Strategy string
+
+const (
+	// Sentence sends whole sentences.
+	Sentence Strategy = "sentence"
+	// Clause sends at sentence and clause punctuation: , ; :
+	Clause Strategy = "clause"
+	// Words sends a fixed number of words at a time.
+	Words Strategy = "words"
+	// Time sends whatever whole words have arrived once Interval passes.
+	// The chunker has no timer of its own: Interval is checked as text
+	// arrives, so when the text stops, what is buffered waits for the next
+	// Add or for Flush. Callers flush after a quiet period to send it.
+	Time Strategy = "time"
+	// Adaptive sends a small first chunk for fast time-to-first-audio,
+	// then doubles the chunk size up to whole sentences.
+	Adaptive Strategy = "adaptive"
+)
+
+// Defaults for Config fields left at zero.
+const (
+	DefaultWords         = 5                      // Words strategy
+	DefaultAdaptiveWords = 3                      // Adaptive first chunk
+	DefaultMaxWords      = 30                     // Adaptive largest chunk
+	DefaultInterval      = 300 * time.Millisecond // Time strategy
+)
+
+// Config configures a built-in Chunker.
+type Config struct {
+	// Strategy is the chunking strategy. Default: Sentence.
+	Strategy Strategy
+
+	// Words is the chunk size in words. For Sentence and Clause it is the
+	// most words sent without punctuation (0 means no limit); for Words it
+	// is the chunk size; for Adaptive it is the size of the first chunk.
+	Words int
+
+	// MaxWords is the size at which Adaptive chunks stop growing.
+	MaxWords int
+
+	// Interval is how long Time collects text before sending it, checked
+	// when text is added.
+	Interval time.Duration
+}
+
+// Validate checks the config.
+func (c Config) Validate() error {
+	switch c.Strategy {
+	case "", Sentence, Clause, Words, Time, Adaptive:
+	default:
+		return fmt.Errorf("unknown chunking strategy %q", c.Strategy)
+	}
+	if c.Words < 0 || c.MaxWords < 0 || c.Interval < 0 {
+		return fmt.Errorf("chunking sizes must not be negative")
+	}
+	return nil
+}
+
+// New creates a Chunker using a built-in strategy.
+func New(cfg Config) (Chunker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = Sentence
+	}
+	switch {
+	case cfg.Strategy == Words && cfg.Words == 0:
+		cfg.Words = DefaultWords
+	case cfg.Strategy == Adaptive && cfg.Words == 0:
+		cfg.Words = DefaultAdaptiveWords
+	}
+	if cfg.MaxWords == 0 {
+		cfg.MaxWords = DefaultMaxWords
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &chunker{cfg: cfg, now: time.Now}, nil
+}
+
+// chunker implements the built-in strategies.
+type chunker struct {
+	cfg   Config
+	now   func() time.Time
+	text  string
+	sent  int       // Chunks sent since the last flush
+	since time.Time // When the buffered text started, for Time
+}
+
+func (c *chunker) Add(text string) string {
+	if c.text == "" {
+		c.since = c.now()
+	}
+	c.text += text
+
+	cut := c.cut()
+	if cut == 0 {
+		return ""
+	}
+	chunk := strings.TrimSpace(c.text[:cut])
+	c.text = strings.TrimLeft(c.text[cut:], " \t\r\n")
+	c.since = c.now()
+	if chunk == "" {
+		return ""
+	}
+	c.sent++
+	return chunk
+}
+
+func (c *chunker) Flush() string {
+	chunk := strings.TrimSpace(c.text)
+	c.text, c.sent = "", 0
+	return chunk
+}
+
+// Len returns the length of the buffered text.
+func (c *chunker) Len() int {
+	return len(c.text)
+}
+
+// cut returns where to end the next chunk, or 0 to wait.
+func (c *chunker) cut() int {
+	switch c.cfg.Strategy {
+	case Words:
+		return wordCut(c.text, c.cfg.Words)
+	case Time:
+		if c.now().Sub(c.since) < c.cfg.Interval {
+			return 0
+		}
+		return max(lastBoundary(c.text, true), wordCut(c.text, 1))
+	case Adaptive:
+		size := min(c.cfg.Words<<min(c.sent, 16), c.cfg.MaxWords)
+		if cut := lastBoundary(c.text, size < c.cfg.MaxWords); cut > 0 {
+			return cut
+		}
+		return wordCut(c.text, size)
+	}
+	if cut := lastBoundary(c.text, c.cfg.Strategy == Clause); cut > 0 {
+		return cut
+	}
+	if c.cfg.Words > 0 {
+		return wordCut(c.text, c.cfg.Words)
+	}
+	return 0
+}
+
+// lastBoundary returns the end of the last sentence in text, or of the last
+// clause when clause is set, or 0 if there is none.
+func lastBoundary(text string, clause bool) int {
+	last := 0
+	for i, r := range text {
+		end := i + utf8.RuneLen(r)
+		switch {
+		case strings.ContainsRune("。！？", r), clause && strings.ContainsRune("、，；：", r):
+			// Full-width punctuation is not followed by a space
+			last = end
+		case r == '.' || r == '!' || r == '?':
+			if IsSentenceEnd(text, i) {
+				last = end
+			}
+		case clause && strings.ContainsRune(",;:", r):
+			if end == len(text) || isSpace(text[end]) {
+				last = end
+			}
+		}
+	}
+	return last
+}
+
+// wordCut returns the end of the last whole word if text holds at least n
+// whole words, or 0. A word is whole once whitespace follows it.
+func wordCut(text string, n int) int {
+	words, end := 0, 0
+	inWord := false
+	for i := 0; i < len(text); i++ {
+		if isSpace(text[i]) {
+			if inWord {
+				words, end = words+1, i
+			}
+			inWord = false
+		} else {
+			inWord = true
+		}
+	}
+	if words < n {
+		return 0
+	}
+	return end
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t'
+}
+
+// IsSentenceEnd reports whether the '.', '!' or '?' at s[i] ends a
+// sentence: it is followed by whitespace or the end of s, and is not part
+// of an abbreviation such as "Dr." or an initial.
+func IsSentenceEnd(s string, i int) bool {
+	if i >= len(s) {
+		return false
+	}
+
+	c := s[i]
+	if c != '.' && c != '!' && c != '?' {
+		return false
+	}
+
+	// Check it's not an abbreviation (Dr., Mr., etc.)
+	if c == '.' && isAbbreviation(s, i) {
+		return false
+	}
+
+	// Check there's whitespace or end of string after
+	if i+1 < len(s) && !isSpace(s[i+1]) {
+		return false
+	}
+
+	return true
+}
+
+// commonAbbreviations end with a period that does not end a sentence.
+var commonAbbreviations = []string{
+	"Dr.", "Mr.", "Mrs.", "Ms.", "Jr.", "Sr.",
+	"Prof.", "Rev.", "Gen.", "Col.", "Lt.", "Sgt.",
+	"Inc.", "Ltd.", "Corp.", "Co.", "vs.", "etc.",
+	"i.e.", "e.g.", "a.m.", "p.m.", "U.S.", "U.K.",
+}
+
+// isAbbreviation checks if the period at position i is likely an abbreviation.
+func isAbbreviation(s string, i int) bool {
+	if i < 1 {
+		return false
+	}
+
+	// Get the word ending at i (including the period)
+	start := i
+	for start > 0 && s[start-1] != ' ' && s[start-1] != '\n' {
+		start--
+	}
+	word := s[start : i+1]
+
+	for _, abbr := range commonAbbreviations {
+		if strings.EqualFold(word, abbr) {
+			return true
+		}
+	}
+
+	// Check if it's a single uppercase letter followed by period (initials)
+	if i >= 1 && s[i-1] >= 'A' && s[i-1] <= 'Z' {
+		if i < 2 || s[i-2] == ' ' || s[i-2] == '\n' {
+			return true
+		}
+	}
+
+	return false
+}
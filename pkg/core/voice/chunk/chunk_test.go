@@ -0,0 +1,169 @@
+package chunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feed adds deltas one at a time and returns the chunks sent, with the
+// flushed remainder last.
+func feed(c Chunker, deltas ...string) []string {
+	var chunks []string
+	for _, d := range deltas {
+		if chunk := c.Add(d); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+	if rest := c.Flush(); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
+
+// words splits text into word deltas the way LLMs stream them.
+func words(text string) []string {
+	fields := strings.Fields(text)
+	for i := 1; i < len(fields); i++ {
+		fields[i] = " " + fields[i]
+	}
+	return fields
+}
+
+func mustNew(t *testing.T, cfg Config) Chunker {
+	t.Helper()
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New(%+v) error = %v", cfg, err)
+	}
+	return c
+}
+
+func TestChunker_Strategies(t *testing.T) {
+	text := "Sure, Dr. Smith can see you. We have openings on Monday, Tuesday and Friday this week; which day works best for you?"
+	tests := []struct {
+		cfg  Config
+		want []string
+	}{
+		{Config{Strategy: Sentence}, []string{
+			"Sure, Dr. Smith can see you.",
+			"We have openings on Monday, Tuesday and Friday this week; which day works best for you?",
+		}},
+		{Config{Strategy: Clause}, []string{
+			"Sure,", "Dr. Smith can see you.", "We have openings on Monday,",
+			"Tuesday and Friday this week;", "which day works best for you?",
+		}},
+		{Config{Strategy: Words, Words: 6}, []string{
+			"Sure, Dr. Smith can see you.", "We have openings on Monday, Tuesday",
+			"and Friday this week; which day", "works best for you?",
+		}},
+		{Config{Strategy: Adaptive, Words: 2, MaxWords: 8}, []string{
+			// Small chunks end at any clause, full-size ones at sentences
+			"Sure,", "Dr. Smith can see you.", "We have openings on Monday, Tuesday and Friday",
+			"this week; which day works best for you?",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.cfg.Strategy), func(t *testing.T) {
+			got := feed(mustNew(t, tt.cfg), words(text)...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunks =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunker_SentenceWordLimit(t *testing.T) {
+	c := mustNew(t, Config{Strategy: Sentence, Words: 5})
+	got := feed(c, words("I think that we should probably go to the store and buy some groceries today")...)
+	want := []string{"I think that we should", "probably go to the store", "and buy some groceries today"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+}
+
+func TestChunker_KeepsWordsWhole(t *testing.T) {
+	c := mustNew(t, Config{Strategy: Words, Words: 2})
+	got := feed(c, "The", " bird", " chirp", "ing", " loud", "ly")
+	want := []string{"The bird", "chirping loudly"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+}
+
+func TestChunker_FullWidthPunctuation(t *testing.T) {
+	c := mustNew(t, Config{Strategy: Clause})
+	got := feed(c, "好的，", "我明白了。", "还有")
+	want := []string{"好的，", "我明白了。", "还有"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+}
+
+func TestChunker_Time(t *testing.T) {
+	c := mustNew(t, Config{Strategy: Time, Interval: 200 * time.Millisecond})
+	now := time.Now()
+	c.(*chunker).now = func() time.Time { return now }
+
+	if got := c.Add("Let me"); got != "" {
+		t.Errorf("Add() = %q before the interval", got)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := c.Add(" check"); got != "" {
+		t.Errorf("Add() = %q before the interval", got)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := c.Add(" that for"); got != "Let me check that" {
+		t.Errorf("Add() = %q, want the whole words so far", got)
+	}
+	now = now.Add(50 * time.Millisecond)
+	if got := c.Add(" you"); got != "" {
+		t.Errorf("Add() = %q, want the interval to restart", got)
+	}
+	if got := c.Flush(); got != "for you" {
+		t.Errorf("Flush() = %q", got)
+	}
+}
+
+func TestChunker_FlushRestartsAdaptive(t *testing.T) {
+	c := mustNew(t, Config{Strategy: Adaptive, Words: 2})
+	first := feed(c, words("One two three four five six")...)
+	second := feed(c, words("One two three four five six")...)
+	if !reflect.DeepEqual(first, second) || first[0] != "One two" {
+		t.Errorf("chunks after flush = %q, want %q again", second, first)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if _, err := New(Config{Strategy: "paragraph"}); err == nil || !strings.Contains(err.Error(), `"paragraph"`) {
+		t.Errorf("New() error = %v, want unknown strategy", err)
+	}
+	if _, err := New(Config{Strategy: Words, Words: -1}); err == nil {
+		t.Error("New() with negative words should fail")
+	}
+	if _, err := New(Config{}); err != nil {
+		t.Errorf("New() with zero config error = %v", err)
+	}
+}
+
+func TestIsSentenceEnd(t *testing.T) {
+	tests := []struct {
+		s    string
+		i    int
+		want bool
+	}{
+		{"Hello. World", 5, true},
+		{"Hello.", 5, true},
+		{"Ask Dr. Who", 6, false},
+		{"J. Doe", 1, false},
+		{"3.14", 1, false},
+		{"Really?!", 6, false},
+		{"Really?!", 7, true},
+	}
+	for _, tt := range tests {
+		if got := IsSentenceEnd(tt.s, tt.i); got != tt.want {
+			t.Errorf("IsSentenceEnd(%q, %d) = %v, want %v", tt.s, tt.i, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package voice
+
+import (
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+)
+
+// ChunkConfig converts chunking settings to a chunk.Config. Settings left
+// unset, or nil settings, take the strategy of def.
+func ChunkConfig(c *types.TextChunking, def chunk.Config) chunk.Config {
+	if c == nil {
+		return def
+	}
+	cfg := chunk.Config{
+		Strategy: chunk.Strategy(c.Strategy),
+		Words:    c.Words,
+		MaxWords: c.MaxWords,
+		Interval: time.Duration(c.IntervalMs) * time.Millisecond,
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = def.Strategy
+	}
+	return cfg
+}
+
+// outputChunking returns the chunking settings of a voice config, if any.
+func outputChunking(cfg *types.VoiceConfig) *types.TextChunking {
+	if cfg == nil || cfg.Output == nil {
+		return nil
+	}
+	return cfg.Output.Chunking
+}
@@ -0,0 +1,483 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+// Metadata keys set on responses whose audio went through the pipeline.
+const (
+	// MetadataSTTProvider is the STT provider that transcribed the input audio.
+	MetadataSTTProvider = "stt_provider"
+	// MetadataTTSProvider is the TTS provider that synthesized the output audio.
+	MetadataTTSProvider = "tts_provider"
+)
+
+// unhealthyCooldown is how long a provider that failed is tried only after
+// the healthy ones.
+const unhealthyCooldown = 30 * time.Second
+
+// ProviderStatus is the health of one provider in a pipeline.
+type ProviderStatus struct {
+	Kind     string `json:"kind"` // "stt" or "tts"
+	Name     string `json:"name"`
+	Healthy  bool   `json:"healthy"`
+	Failures int    `json:"failures"` // Consecutive failures
+}
+
+// healthTracker records provider failures. A provider that failed is
+// unhealthy for unhealthyCooldown, or until it next succeeds.
+type healthTracker struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	failures  map[string]int
+	downUntil map[string]time.Time
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		now:       time.Now,
+		failures:  make(map[string]int),
+		downUntil: make(map[string]time.Time),
+	}
+}
+
+func (h *healthTracker) healthy(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.now().Before(h.downUntil[name])
+}
+
+func (h *healthTracker) success(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.failures, name)
+	delete(h.downUntil, name)
+}
+
+func (h *healthTracker) failure(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures[name]++
+	h.downUntil[name] = h.now().Add(unhealthyCooldown)
+}
+
+func (h *healthTracker) status(kind, name string) ProviderStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return ProviderStatus{
+		Kind:     kind,
+		Name:     name,
+		Healthy:  !h.now().Before(h.downUntil[name]),
+		Failures: h.failures[name],
+	}
+}
+
+// candidate is a provider to try, with the model or voice to ask it for.
+type candidate[P interface{ Name() string }] struct {
+	provider P
+	name     string
+	arg      string
+}
+
+// order returns the providers to try for a model or voice. A value named
+// "provider/value" goes to that provider first; any other value goes to the
+// first provider. The rest are asked for their default model or voice, since
+// model and voice names are specific to a provider. Healthy providers are
+// tried before those that failed recently.
+func order[P interface{ Name() string }](providers []P, health *healthTracker, value string) ([]candidate[P], error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+	first := 0
+	arg := value
+	if name, rest, ok := strings.Cut(value, "/"); ok {
+		first = -1
+		for i, p := range providers {
+			if p.Name() == name {
+				first, arg = i, rest
+				break
+			}
+		}
+		if first < 0 {
+			return nil, fmt.Errorf("unknown provider %q in %q", name, value)
+		}
+	}
+
+	all := make([]candidate[P], 0, len(providers))
+	all = append(all, candidate[P]{provider: providers[first], name: providers[first].Name(), arg: arg})
+	for i, p := range providers {
+		if i != first {
+			all = append(all, candidate[P]{provider: p, name: p.Name()})
+		}
+	}
+
+	healthy := make([]candidate[P], 0, len(all))
+	var down []candidate[P]
+	for _, c := range all {
+		if health.healthy(c.name) {
+			healthy = append(healthy, c)
+		} else {
+			down = append(down, c)
+		}
+	}
+	return append(healthy, down...), nil
+}
+
+// failoverError wraps the last error once every provider has failed.
+func failoverError(kind string, tried int, err error) error {
+	if tried <= 1 {
+		return err
+	}
+	return fmt.Errorf("all %d %s providers failed: %w", tried, kind, err)
+}
+
+// sttFailover is an STT provider that tries a list of providers in turn.
+type sttFailover struct {
+	providers []stt.Provider
+	health    *healthTracker
+}
+
+var _ stt.Provider = (*sttFailover)(nil)
+
+// Name returns the provider identifier.
+func (f *sttFailover) Name() string {
+	return "failover"
+}
+
+// Transcribe transcribes with the first provider that succeeds.
+func (f *sttFailover) Transcribe(ctx context.Context, audio io.Reader, opts stt.TranscribeOptions) (*stt.Transcript, error) {
+	cands, err := order(f.providers, f.health, opts.Model)
+	if err != nil {
+		return nil, err
+	}
+	// Buffer the audio so that it can be sent again
+	data, err := io.ReadAll(audio)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+
+	var lastErr error
+	for i, c := range cands {
+		opts.Model = c.arg
+		t, err := c.provider.Transcribe(ctx, bytes.NewReader(data), opts)
+		if err == nil {
+			f.health.success(c.name)
+			if t.Provider == "" {
+				t.Provider = c.name
+			}
+			return t, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.health.failure(c.name)
+		lastErr = failoverError("stt", i+1, err)
+	}
+	return nil, lastErr
+}
+
+// TranscribeStream transcribes streaming audio with the first provider that
+// accepts the stream.
+func (f *sttFailover) TranscribeStream(ctx context.Context, audio io.Reader, opts stt.TranscribeOptions) (<-chan stt.TranscriptDelta, error) {
+	cands, err := order(f.providers, f.health, opts.Model)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for i, c := range cands {
+		opts.Model = c.arg
+		deltas, err := c.provider.TranscribeStream(ctx, audio, opts)
+		if err == nil {
+			f.health.success(c.name)
+			return deltas, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.health.failure(c.name)
+		lastErr = failoverError("stt", i+1, err)
+	}
+	return nil, lastErr
+}
+
+// NewStreamingSTT opens a streaming session with the first provider that
+// accepts it.
+func (f *sttFailover) NewStreamingSTT(ctx context.Context, opts stt.TranscribeOptions) (*stt.StreamingSTT, error) {
+	cands, err := order(f.providers, f.health, opts.Model)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for i, c := range cands {
+		opts.Model = c.arg
+		s, err := c.provider.NewStreamingSTT(ctx, opts)
+		if err == nil {
+			f.health.success(c.name)
+			return s, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.health.failure(c.name)
+		lastErr = failoverError("stt", i+1, err)
+	}
+	return nil, lastErr
+}
+
+// ttsFailover is a TTS provider that tries a list of providers in turn.
+type ttsFailover struct {
+	providers []tts.Provider
+	health    *healthTracker
+}
+
+var _ tts.Provider = (*ttsFailover)(nil)
+
+// Name returns the provider identifier.
+func (f *ttsFailover) Name() string {
+	return "failover"
+}
+
+// Synthesize synthesizes with the first provider that succeeds.
+func (f *ttsFailover) Synthesize(ctx context.Context, text string, opts tts.SynthesizeOptions) (*tts.Synthesis, error) {
+	cands, err := order(f.providers, f.health, opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for i, c := range cands {
+		opts.Voice = c.arg
+		synth, err := c.provider.Synthesize(ctx, text, opts)
+		if err == nil {
+			f.health.success(c.name)
+			if synth.Provider == "" {
+				synth.Provider = c.name
+			}
+			return synth, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.health.failure(c.name)
+		lastErr = failoverError("tts", i+1, err)
+	}
+	return nil, lastErr
+}
+
+// SynthesizeStream opens a synthesis stream with the first provider that
+// accepts it.
+func (f *ttsFailover) SynthesizeStream(ctx context.Context, text string, opts tts.SynthesizeOptions) (*tts.SynthesisStream, error) {
+	cands, err := order(f.providers, f.health, opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for i, c := range cands {
+		opts.Voice = c.arg
+		stream, err := c.provider.SynthesizeStream(ctx, text, opts)
+		if err == nil {
+			f.health.success(c.name)
+			return stream, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.health.failure(c.name)
+		lastErr = failoverError("tts", i+1, err)
+	}
+	return nil, lastErr
+}
+
+// NewStreamingContext opens a streaming context that fails over to the next
+// provider if the current one fails before producing any audio. Text sent
+// before the first audio chunk is replayed to the next provider. Once audio
+// has been delivered the context is committed to its provider.
+func (f *ttsFailover) NewStreamingContext(ctx context.Context, opts tts.StreamingContextOptions) (*tts.StreamingContext, error) {
+	cands, err := order(f.providers, f.health, opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &failoverContext{
+		f:     f,
+		ctx:   ctx,
+		opts:  opts,
+		cands: cands,
+		out:   tts.NewStreamingContext(),
+	}
+	fc.mu.Lock()
+	err = fc.openNext()
+	fc.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	fc.out.SendFunc = fc.send
+	fc.out.CloseFunc = fc.close
+	go fc.forward()
+	return fc.out, nil
+}
+
+// sentText is a chunk of text sent to a streaming context.
+type sentText struct {
+	text    string
+	isFinal bool
+}
+
+// failoverContext drives a streaming context on behalf of a sequence of
+// provider contexts.
+type failoverContext struct {
+	f     *ttsFailover
+	ctx   context.Context
+	opts  tts.StreamingContextOptions
+	cands []candidate[tts.Provider]
+	out   *tts.StreamingContext
+
+	mu        sync.Mutex
+	cur       *tts.StreamingContext
+	curName   string
+	next      int        // Index of the next candidate to try
+	tried     int        // Candidates tried so far
+	sent      []sentText // Text to replay, until the first audio
+	committed bool       // Whether audio has been delivered
+	sendErr   error      // Send failure of the current context
+	closed    bool
+}
+
+// openNext opens a context with the next candidate that accepts one and
+// replays the text sent so far. fc.mu must be held.
+func (fc *failoverContext) openNext() error {
+	var lastErr error
+	for fc.next < len(fc.cands) {
+		c := fc.cands[fc.next]
+		fc.next++
+		fc.tried++
+
+		opts := fc.opts
+		opts.Voice = c.arg
+		sc, err := c.provider.NewStreamingContext(fc.ctx, opts)
+		if err == nil {
+			for _, t := range fc.sent {
+				if err = sc.SendText(t.text, t.isFinal); err != nil {
+					sc.Close()
+					break
+				}
+			}
+		}
+		if err == nil {
+			fc.cur, fc.curName, fc.sendErr = sc, c.name, nil
+			return nil
+		}
+		if fc.ctx.Err() != nil {
+			return err
+		}
+		fc.f.health.failure(c.name)
+		lastErr = failoverError("tts", fc.tried, err)
+	}
+	return lastErr
+}
+
+func (fc *failoverContext) send(text string, isFinal bool) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.committed {
+		return fc.cur.SendText(text, isFinal)
+	}
+	fc.sent = append(fc.sent, sentText{text: text, isFinal: isFinal})
+	if err := fc.cur.SendText(text, isFinal); err != nil {
+		// Let forward fail over; the text is replayed to the next provider
+		fc.sendErr = err
+		fc.cur.Close()
+	}
+	return nil
+}
+
+func (fc *failoverContext) close() error {
+	fc.mu.Lock()
+	fc.closed = true
+	cur := fc.cur
+	fc.mu.Unlock()
+	return cur.Close()
+}
+
+// forward copies audio and word timings from the current context to the
+// output, failing over while no audio has been delivered.
+func (fc *failoverContext) forward() {
+	defer fc.out.FinishAudio()
+	for {
+		fc.mu.Lock()
+		cur, name := fc.cur, fc.curName
+		fc.mu.Unlock()
+
+		got, ok := fc.copyAudio(cur, name)
+		if !ok {
+			cur.Close()
+			return
+		}
+
+		fc.mu.Lock()
+		err := cur.Err()
+		if err == nil {
+			err = fc.sendErr
+		}
+		if err == nil || got || fc.closed || fc.ctx.Err() != nil {
+			if err == nil && !got {
+				fc.f.health.success(name)
+			}
+			fc.mu.Unlock()
+			if err != nil {
+				fc.out.SetError(err)
+			}
+			return
+		}
+
+		fc.f.health.failure(name)
+		cur.Close()
+		if fc.next >= len(fc.cands) {
+			fc.mu.Unlock()
+			fc.out.SetError(failoverError("tts", fc.tried, err))
+			return
+		}
+		if openErr := fc.openNext(); openErr != nil {
+			fc.mu.Unlock()
+			fc.out.SetError(openErr)
+			return
+		}
+		fc.mu.Unlock()
+	}
+}
+
+// copyAudio forwards one context's output until it ends. It reports whether
+// any audio was delivered, and false for ok if the output was closed.
+func (fc *failoverContext) copyAudio(cur *tts.StreamingContext, name string) (got, ok bool) {
+	var words int
+	pushWords := func() {
+		if all := cur.WordTimestamps(); len(all) > words {
+			fc.out.PushWordTimestamps(all[words:]...)
+			words = len(all)
+		}
+	}
+	for chunk := range cur.Audio() {
+		if !got {
+			got = true
+			fc.mu.Lock()
+			fc.committed, fc.sent = true, nil
+			fc.mu.Unlock()
+			fc.f.health.success(name)
+		}
+		pushWords()
+		if !fc.out.PushAudio(chunk) {
+			return got, false
+		}
+	}
+	pushWords()
+	return got, true
+}
@@ -0,0 +1,271 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+var errOutage = errors.New("503 service unavailable")
+
+type fakeSTT struct {
+	stt.Provider
+	name   string
+	fail   bool
+	calls  int
+	models []string
+}
+
+func (f *fakeSTT) Name() string { return f.name }
+
+func (f *fakeSTT) Transcribe(ctx context.Context, r io.Reader, opts stt.TranscribeOptions) (*stt.Transcript, error) {
+	f.calls++
+	f.models = append(f.models, opts.Model)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if f.fail {
+		return nil, errOutage
+	}
+	return &stt.Transcript{Text: f.name + ": " + string(data)}, nil
+}
+
+type fakeTTS struct {
+	tts.Provider
+	name   string
+	fail   bool // Synthesize and NewStreamingContext fail
+	broken bool // Streaming contexts fail before any audio
+	calls  int
+	voices []string
+
+	mu   sync.Mutex
+	text []string // Text received by streaming contexts
+}
+
+func (f *fakeTTS) Name() string { return f.name }
+
+func (f *fakeTTS) Synthesize(ctx context.Context, text string, opts tts.SynthesizeOptions) (*tts.Synthesis, error) {
+	f.calls++
+	f.voices = append(f.voices, opts.Voice)
+	if f.fail {
+		return nil, errOutage
+	}
+	return &tts.Synthesis{Audio: []byte(text), Format: opts.Format}, nil
+}
+
+func (f *fakeTTS) NewStreamingContext(ctx context.Context, opts tts.StreamingContextOptions) (*tts.StreamingContext, error) {
+	f.calls++
+	if f.fail {
+		return nil, errOutage
+	}
+	sc := tts.NewStreamingContext()
+	sc.SendFunc = func(text string, isFinal bool) error {
+		f.mu.Lock()
+		f.text = append(f.text, text)
+		f.mu.Unlock()
+		if isFinal {
+			go func() {
+				defer sc.FinishAudio()
+				if f.broken {
+					sc.SetError(errOutage)
+					return
+				}
+				f.mu.Lock()
+				all := strings.Join(f.text, "")
+				f.mu.Unlock()
+				sc.PushWordTimestamps(tts.WordTimestamp{Word: all, EndMs: 100})
+				sc.PushAudio([]byte(f.name + ": " + all))
+			}()
+		}
+		return nil
+	}
+	return sc, nil
+}
+
+func TestFailover_TranscribeFallsBack(t *testing.T) {
+	primary := &fakeSTT{name: "cartesia", fail: true}
+	backup := &fakeSTT{name: "deepgram"}
+	p := NewPipelineWithFailover([]stt.Provider{primary, backup}, nil)
+
+	_, trans, err := p.TranscribeInput(context.Background(), audioMessage([]byte("audio"), "audio/mpeg"),
+		&types.VoiceConfig{Input: &types.VoiceInputConfig{Model: "ink-whisper"}})
+	if err != nil {
+		t.Fatalf("TranscribeInput() error = %v", err)
+	}
+	if trans.Text != "deepgram: audio" {
+		t.Errorf("Text = %q, want the backup's transcript of the same audio", trans.Text)
+	}
+	if trans.Provider != "deepgram" {
+		t.Errorf("Provider = %q, want deepgram", trans.Provider)
+	}
+	// Model names belong to the primary; the backup uses its default
+	if primary.models[0] != "ink-whisper" || backup.models[0] != "" {
+		t.Errorf("models = %q, %q", primary.models, backup.models)
+	}
+}
+
+func TestFailover_UnhealthyProviderTriedLast(t *testing.T) {
+	primary := &fakeTTS{name: "cartesia", fail: true}
+	backup := &fakeTTS{name: "openai"}
+	p := NewPipelineWithFailover(nil, []tts.Provider{primary, backup})
+	now := time.Now()
+	p.ttsHealth.now = func() time.Time { return now }
+	cfg := &types.VoiceConfig{Output: &types.VoiceOutputConfig{Format: "pcm"}}
+
+	for range 2 {
+		synth, err := p.Synthesize(context.Background(), "hi", cfg)
+		if err != nil {
+			t.Fatalf("Synthesize() error = %v", err)
+		}
+		if synth.Provider != "openai" {
+			t.Errorf("Provider = %q, want openai", synth.Provider)
+		}
+	}
+	if primary.calls != 1 {
+		t.Errorf("failed provider called %d times, want 1", primary.calls)
+	}
+
+	status := p.ProviderStatus()
+	want := []ProviderStatus{
+		{Kind: "tts", Name: "cartesia", Healthy: false, Failures: 1},
+		{Kind: "tts", Name: "openai", Healthy: true},
+	}
+	if len(status) != len(want) || status[0] != want[0] || status[1] != want[1] {
+		t.Errorf("ProviderStatus() = %+v, want %+v", status, want)
+	}
+
+	// After the cooldown the primary is preferred again
+	primary.fail = false
+	now = now.Add(unhealthyCooldown)
+	synth, err := p.Synthesize(context.Background(), "hi", cfg)
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if synth.Provider != "cartesia" {
+		t.Errorf("Provider = %q, want cartesia", synth.Provider)
+	}
+	if s := p.ProviderStatus()[0]; !s.Healthy || s.Failures != 0 {
+		t.Errorf("status after recovery = %+v", s)
+	}
+}
+
+func TestFailover_NamedProviderPreferred(t *testing.T) {
+	first := &fakeTTS{name: "cartesia"}
+	named := &fakeTTS{name: "elevenlabs", fail: true}
+	p := NewPipelineWithFailover(nil, []tts.Provider{first, named})
+
+	synth, err := p.Synthesize(context.Background(), "hi",
+		&types.VoiceConfig{Output: &types.VoiceOutputConfig{Voice: "elevenlabs/rachel", Format: "pcm"}})
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if synth.Provider != "cartesia" {
+		t.Errorf("Provider = %q, want cartesia", synth.Provider)
+	}
+	if named.voices[0] != "rachel" || first.voices[0] != "" {
+		t.Errorf("voices = %q, %q", named.voices, first.voices)
+	}
+
+	_, err = p.Synthesize(context.Background(), "hi",
+		&types.VoiceConfig{Output: &types.VoiceOutputConfig{Voice: "piper/amy"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown provider "piper"`) {
+		t.Errorf("Synthesize() error = %v, want unknown provider", err)
+	}
+}
+
+func TestFailover_AllProvidersFail(t *testing.T) {
+	p := NewPipelineWithFailover(nil, []tts.Provider{
+		&fakeTTS{name: "cartesia", fail: true},
+		&fakeTTS{name: "openai", fail: true},
+	})
+	_, err := p.Synthesize(context.Background(), "hi", &types.VoiceConfig{Output: &types.VoiceOutputConfig{}})
+	if !errors.Is(err, errOutage) {
+		t.Errorf("Synthesize() error = %v, want the last provider's error", err)
+	}
+}
+
+func TestFailover_StreamingContextFailsOverBeforeAudio(t *testing.T) {
+	primary := &fakeTTS{name: "cartesia", broken: true}
+	backup := &fakeTTS{name: "elevenlabs"}
+	p := NewPipelineWithFailover(nil, []tts.Provider{primary, backup})
+
+	sc, err := p.NewStreamingTTSContext(context.Background(), &types.VoiceConfig{Output: &types.VoiceOutputConfig{}})
+	if err != nil {
+		t.Fatalf("NewStreamingTTSContext() error = %v", err)
+	}
+	defer sc.Close()
+	if err := sc.SendText("Hello ", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.SendText("world.", true); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for chunk := range sc.Audio() {
+		got = append(got, string(chunk))
+	}
+	if len(got) != 1 || got[0] != "elevenlabs: Hello world." {
+		t.Errorf("audio = %q, want the backup's audio for the replayed text", got)
+	}
+	if err := sc.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if words := sc.WordTimestamps(); len(words) != 1 || words[0].Word != "Hello world." {
+		t.Errorf("WordTimestamps() = %+v", words)
+	}
+	if s := p.ProviderStatus()[0]; s.Healthy {
+		t.Errorf("status = %+v, want cartesia unhealthy", s)
+	}
+}
+
+func TestFailover_StreamingContextStaysWithWorkingProvider(t *testing.T) {
+	primary := &fakeTTS{name: "cartesia"}
+	backup := &fakeTTS{name: "elevenlabs"}
+	p := NewPipelineWithFailover(nil, []tts.Provider{primary, backup})
+
+	sc, err := p.NewStreamingTTSContext(context.Background(), &types.VoiceConfig{Output: &types.VoiceOutputConfig{}})
+	if err != nil {
+		t.Fatalf("NewStreamingTTSContext() error = %v", err)
+	}
+	defer sc.Close()
+	sc.SendText("Hi.", true)
+	var got []string
+	for chunk := range sc.Audio() {
+		got = append(got, string(chunk))
+	}
+	if len(got) != 1 || got[0] != "cartesia: Hi." {
+		t.Errorf("audio = %q", got)
+	}
+	if backup.calls != 0 {
+		t.Errorf("backup called %d times, want 0", backup.calls)
+	}
+}
+
+func TestFailover_StreamingContextAllFail(t *testing.T) {
+	p := NewPipelineWithFailover(nil, []tts.Provider{
+		&fakeTTS{name: "cartesia", broken: true},
+		&fakeTTS{name: "openai", broken: true},
+	})
+
+	sc, err := p.NewStreamingTTSContext(context.Background(), &types.VoiceConfig{Output: &types.VoiceOutputConfig{}})
+	if err != nil {
+		t.Fatalf("NewStreamingTTSContext() error = %v", err)
+	}
+	defer sc.Close()
+	sc.SendText("Hi.", true)
+	for range sc.Audio() {
+	}
+	if err := sc.Err(); !errors.Is(err, errOutage) {
+		t.Errorf("Err() = %v, want the last provider's error", err)
+	}
+}
@@ -0,0 +1,417 @@
+// Package filler keeps the line from going quiet while a voice agent waits
+// on a slow model or tool.
+//
+// Callers hearing nothing for a few seconds tend to assume the call has
+// dropped. Once the line has been silent for a while, a Player speaks a
+// short filler such as "Let me check that", or a message for the tool being
+// run, and can then loop hold audio. Fillers stop the moment the agent's own
+// audio arrives. They are not part of the response, so callers keep them
+// out of the conversation history.
+package filler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultDelay is how long the line is silent before a filler plays when
+// Config.Delay is zero.
+const DefaultDelay = 1500 * time.Millisecond
+
+// chunkDuration is how much audio is sent at a time when pacing it. The
+// client holds about this much filler audio, which is discarded when the
+// agent speaks.
+const chunkDuration = 100 * time.Millisecond
+
+// Config configures a Player.
+type Config struct {
+	// Delay is how long the line must be silent before a filler plays.
+	// Default: DefaultDelay.
+	Delay time.Duration
+
+	// Phrases are spoken in turn, one per wait, e.g. "Let me check that."
+	Phrases []string
+
+	// Tools maps tool names to a message spoken in place of a phrase
+	// while that tool runs, e.g. "Looking up your order now."
+	Tools map[string]string
+
+	// HoldAudio is looped after the filler until the agent speaks. It is
+	// raw audio in the output format. Without Output.BytesPerSecond it
+	// cannot be paced, and is sent once.
+	HoldAudio []byte
+}
+
+// Validate checks the config.
+func (c Config) Validate() error {
+	if c.Delay < 0 {
+		return fmt.Errorf("filler delay must not be negative")
+	}
+	return nil
+}
+
+// Enabled reports whether the config has anything to play.
+func (c Config) Enabled() bool {
+	return len(c.Phrases) > 0 || len(c.Tools) > 0 || len(c.HoldAudio) > 0
+}
+
+// Kind is the kind of a filler.
+type Kind string
+
+const (
+	// Phrase is one of Config.Phrases.
+	Phrase Kind = "phrase"
+	// Tool is the message for a tool in Config.Tools.
+	Tool Kind = "tool"
+	// Hold is Config.HoldAudio.
+	Hold Kind = "hold"
+)
+
+// Filler describes a filler as it starts to play.
+type Filler struct {
+	Kind Kind
+	Text string // The words spoken; empty for hold audio
+	Tool string // The tool waited on, for Tool fillers
+}
+
+// Synthesizer turns filler text into audio in the output format.
+type Synthesizer func(ctx context.Context, text string) ([]byte, error)
+
+// Output receives the fillers of a Player.
+type Output struct {
+	// BytesPerSecond is the data rate of the audio, used to send it in
+	// real time so that little is buffered when the agent speaks. If zero,
+	// each filler is sent whole.
+	BytesPerSecond int
+
+	// Started, if set, is called as each filler starts.
+	Started func(Filler)
+
+	// Audio is called with filler audio.
+	Audio func(data []byte)
+}
+
+// Player plays fillers while an agent waits. Its methods may be called
+// from any goroutine, and do nothing on a nil Player.
+type Player struct {
+	ctx   context.Context
+	cfg   Config
+	synth Synthesizer
+	out   Output
+	now   func() time.Time
+
+	mu     sync.Mutex
+	cache  map[string][]byte
+	wait   *wait
+	phrase int       // Index of the next phrase
+	end    time.Time // When the audio sent so far finishes playing
+	sent   bool      // Filler audio was sent since the agent last spoke
+	cut    int       // Incremented to cut short the filler playing
+}
+
+// wait is one stretch of waiting, which ends with Done or Stop.
+type wait struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	tool    string // Tool whose message plays next, if any
+	spoken  bool   // A phrase has been spoken
+	held    bool   // Hold audio has been sent
+	running bool   // A goroutine is playing fillers
+	playing bool   // A filler is playing
+	done    bool   // The wait ends once the filler playing is over
+}
+
+// New creates a Player. synth may be nil when only hold audio is used.
+// Fillers stop when ctx is cancelled.
+func New(ctx context.Context, cfg Config, synth Synthesizer, out Output) *Player {
+	if cfg.Delay == 0 {
+		cfg.Delay = DefaultDelay
+	}
+	return &Player{
+		ctx:   ctx,
+		cfg:   cfg,
+		synth: synth,
+		out:   out,
+		now:   time.Now,
+		cache: make(map[string][]byte),
+	}
+}
+
+// Prepare synthesizes the phrases and tool messages ahead of time so that
+// fillers play without delay. Fillers not yet prepared are synthesized when
+// first needed, and ones that fail to synthesize are skipped.
+func (p *Player) Prepare() {
+	if p == nil {
+		return
+	}
+	texts := slices.Clone(p.cfg.Phrases)
+	for _, text := range p.cfg.Tools {
+		texts = append(texts, text)
+	}
+	for _, text := range texts {
+		p.audio(p.ctx, text)
+	}
+}
+
+// Wait tells the Player that the agent is waiting, on the model or on the
+// named tools. Once the line has been silent for Config.Delay, it plays the
+// message of the first tool that has one, or else the next phrase, and then
+// loops the hold audio. If a wait is already under way it carries on, and
+// a tool message is played next.
+func (p *Player) Wait(tools ...string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.wait
+	if w == nil {
+		ctx, cancel := context.WithCancel(p.ctx)
+		w = &wait{ctx: ctx, cancel: cancel}
+		p.wait = w
+		if now := p.now(); p.end.Before(now) {
+			p.end = now
+		}
+	}
+	for _, tool := range tools {
+		if p.cfg.Tools[tool] != "" {
+			w.tool = tool
+			break
+		}
+	}
+	if !w.running {
+		w.running = true
+		go p.run(w)
+	}
+}
+
+// Speak tells the Player that the agent is sending d of its own audio. Any
+// filler playing stops at once, and the next plays only once the agent's
+// audio is over and the line has been silent for Config.Delay again. Speak
+// reports whether filler audio was sent since the agent last spoke, which
+// the client may still be playing and should discard.
+func (p *Player) Speak(d time.Duration) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cut++
+	if now := p.now(); p.end.Before(now) {
+		p.end = now
+	}
+	p.end = p.end.Add(d)
+	sent := p.sent
+	p.sent = false
+	return sent
+}
+
+// Done tells the Player that the agent has stopped waiting, such as when
+// response text starts to arrive. No more fillers start, but one already
+// playing carries on until the agent's audio cuts it short.
+func (p *Player) Done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w := p.wait; w != nil {
+		w.done = true
+		if !w.playing {
+			w.cancel()
+		}
+		p.wait = nil
+	}
+}
+
+// Stop stops fillers at once, such as when the user interrupts or the
+// response is over.
+func (p *Player) Stop() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.wait != nil {
+		p.wait.cancel()
+		p.wait = nil
+	}
+	p.cut++
+	p.end = time.Time{}
+	p.sent = false
+}
+
+// run plays fillers for a wait until it ends or there is nothing to play.
+func (p *Player) run(w *wait) {
+	delay := p.cfg.Delay
+	holding := false
+	for {
+		if !p.sleep(w, delay) {
+			return
+		}
+		f, ok := p.next(w)
+		if !ok {
+			return
+		}
+
+		data := p.cfg.HoldAudio
+		if f.Kind != Hold {
+			holding = false
+			data, ok = p.audio(w.ctx, f.Text)
+		}
+		cut := ok && !p.play(w, f, data, !holding)
+		if !p.played(w) {
+			return
+		}
+		holding = f.Kind == Hold && !cut
+
+		// Hold audio follows on without a gap; anything else only after a
+		// silence
+		delay = p.cfg.Delay
+		if len(p.cfg.HoldAudio) > 0 && !cut {
+			delay = -chunkDuration
+		}
+	}
+}
+
+// next picks the next filler of a wait and marks it as playing. It returns
+// false, and marks the wait as no longer running, if there is none.
+func (p *Player) next(w *wait) (Filler, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var f Filler
+	switch {
+	case w.ctx.Err() != nil:
+		return f, false
+	case w.tool != "":
+		f = Filler{Kind: Tool, Text: p.cfg.Tools[w.tool], Tool: w.tool}
+		w.tool = ""
+	case !w.spoken && len(p.cfg.Phrases) > 0:
+		f = Filler{Kind: Phrase, Text: p.cfg.Phrases[p.phrase%len(p.cfg.Phrases)]}
+		p.phrase++
+		w.spoken = true
+	case len(p.cfg.HoldAudio) > 0 && (!w.held || p.out.BytesPerSecond > 0):
+		f = Filler{Kind: Hold}
+		w.held = true
+	default:
+		w.running = false
+		return f, false
+	}
+	w.playing = true
+	return f, true
+}
+
+// played marks the wait's filler as over. It returns false if the wait
+// ended meanwhile.
+func (p *Player) played(w *wait) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w.playing = false
+	if w.done {
+		w.cancel()
+	}
+	return w.ctx.Err() == nil
+}
+
+// play sends the audio of a filler, paced in real time when the data rate
+// is known. It returns false if the filler was cut short by the agent's
+// audio or the end of the wait. Hold audio gives way as soon as a tool
+// message is due.
+func (p *Player) play(w *wait, f Filler, data []byte, started bool) bool {
+	step := len(data)
+	if p.out.BytesPerSecond > 0 {
+		// Keep to whole 16-bit samples
+		step = max(int(int64(p.out.BytesPerSecond)*int64(chunkDuration)/int64(time.Second))&^1, 2)
+	}
+
+	p.mu.Lock()
+	cut := p.cut
+	p.mu.Unlock()
+
+	for len(data) > 0 {
+		n := min(step, len(data))
+
+		p.mu.Lock()
+		if w.ctx.Err() != nil || p.cut != cut {
+			p.mu.Unlock()
+			return false
+		}
+		if f.Kind == Hold && w.tool != "" {
+			p.mu.Unlock()
+			return true
+		}
+		if started && p.out.Started != nil {
+			p.out.Started(f)
+		}
+		started = false
+		p.out.Audio(data[:n])
+		p.sent = true
+		if p.out.BytesPerSecond > 0 {
+			if now := p.now(); p.end.Before(now) {
+				p.end = now
+			}
+			p.end = p.end.Add(time.Duration(int64(n) * int64(time.Second) / int64(p.out.BytesPerSecond)))
+		}
+		p.mu.Unlock()
+
+		data = data[n:]
+		// Stay one chunk ahead of playback
+		if len(data) > 0 && !p.sleep(w, -chunkDuration) {
+			return false
+		}
+	}
+	return true
+}
+
+// sleep waits until delay after the audio sent so far finishes playing.
+// It returns false if the wait ended.
+func (p *Player) sleep(w *wait, delay time.Duration) bool {
+	for {
+		p.mu.Lock()
+		d := p.end.Add(delay).Sub(p.now())
+		p.mu.Unlock()
+		if d <= 0 {
+			return w.ctx.Err() == nil
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-w.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+			// The agent may have spoken in the meantime, so check again
+		}
+	}
+}
+
+// audio returns the audio for text, synthesizing it if need be.
+func (p *Player) audio(ctx context.Context, text string) ([]byte, bool) {
+	p.mu.Lock()
+	data, ok := p.cache[text]
+	p.mu.Unlock()
+	if ok {
+		return data, true
+	}
+	if p.synth == nil {
+		return nil, false
+	}
+
+	data, err := p.synth(ctx, text)
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+	p.mu.Lock()
+	p.cache[text] = data
+	p.mu.Unlock()
+	return data, true
+}
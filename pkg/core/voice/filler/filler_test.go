@@ -0,0 +1,189 @@
+package filler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the output of a Player.
+type recorder struct {
+	mu      sync.Mutex
+	fillers []Filler
+	audio   []string
+	started time.Time
+	firstAt time.Duration // When the first audio arrived
+}
+
+func (r *recorder) output(bytesPerSecond int) Output {
+	r.started = time.Now()
+	return Output{
+		BytesPerSecond: bytesPerSecond,
+		Started: func(f Filler) {
+			r.mu.Lock()
+			r.fillers = append(r.fillers, f)
+			r.mu.Unlock()
+		},
+		Audio: func(data []byte) {
+			r.mu.Lock()
+			if len(r.audio) == 0 {
+				r.firstAt = time.Since(r.started)
+			}
+			r.audio = append(r.audio, string(data))
+			r.mu.Unlock()
+		},
+	}
+}
+
+func (r *recorder) played() ([]Filler, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Filler(nil), r.fillers...), strings.Join(r.audio, "")
+}
+
+// echo "synthesizes" text as its own bytes.
+func echo(ctx context.Context, text string) ([]byte, error) {
+	return []byte(text), nil
+}
+
+func newPlayer(t *testing.T, cfg Config, rec *recorder, bytesPerSecond int) *Player {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return New(ctx, cfg, echo, rec.output(bytesPerSecond))
+}
+
+func TestPlayer_PhraseAfterDelay(t *testing.T) {
+	rec := &recorder{}
+	p := newPlayer(t, Config{Delay: 30 * time.Millisecond, Phrases: []string{"one.", "two."}}, rec, 0)
+
+	p.Wait()
+	time.Sleep(80 * time.Millisecond)
+	p.Done()
+	if !p.Speak(0) {
+		t.Error("Speak() = false, want true after a filler")
+	}
+	p.Wait()
+	time.Sleep(80 * time.Millisecond)
+	p.Stop()
+
+	fillers, audio := rec.played()
+	if audio != "one.two." {
+		t.Errorf("audio = %q, want the phrases in turn", audio)
+	}
+	if len(fillers) != 2 || fillers[0].Kind != Phrase || fillers[1].Text != "two." {
+		t.Errorf("fillers = %+v", fillers)
+	}
+	if rec.firstAt < 30*time.Millisecond {
+		t.Errorf("first filler after %v, want at least the delay", rec.firstAt)
+	}
+}
+
+func TestPlayer_NoFillerAfterDone(t *testing.T) {
+	rec := &recorder{}
+	p := newPlayer(t, Config{Delay: 40 * time.Millisecond, Phrases: []string{"hmm."}}, rec, 0)
+
+	p.Wait()
+	time.Sleep(10 * time.Millisecond)
+	p.Done()
+	if p.Speak(20 * time.Millisecond) {
+		t.Error("Speak() = true, want false before any filler")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if _, audio := rec.played(); audio != "" {
+		t.Errorf("audio = %q, want none", audio)
+	}
+}
+
+func TestPlayer_DelayCountsFromAgentAudio(t *testing.T) {
+	rec := &recorder{}
+	p := newPlayer(t, Config{Delay: 20 * time.Millisecond, Tools: map[string]string{"search": "Searching."}}, rec, 0)
+
+	// The agent's last 60ms of speech arrives as the tool starts
+	p.Wait("lookup", "search")
+	p.Speak(60 * time.Millisecond)
+	time.Sleep(120 * time.Millisecond)
+	p.Stop()
+
+	fillers, audio := rec.played()
+	if audio != "Searching." || len(fillers) != 1 || fillers[0].Kind != Tool || fillers[0].Tool != "search" {
+		t.Fatalf("fillers = %+v, audio = %q", fillers, audio)
+	}
+	if rec.firstAt < 80*time.Millisecond {
+		t.Errorf("tool message after %v, want after the agent's audio and the delay", rec.firstAt)
+	}
+}
+
+func TestPlayer_SpeakCutsFillerShort(t *testing.T) {
+	rec := &recorder{}
+	// 100 bytes a second, so the 40 byte phrase takes 400ms in 10 byte chunks
+	p := newPlayer(t, Config{Delay: time.Millisecond, Phrases: []string{strings.Repeat("x", 40)}}, rec, 100)
+
+	p.Wait()
+	time.Sleep(150 * time.Millisecond)
+	if !p.Speak(0) {
+		t.Error("Speak() = false, want true while a filler plays")
+	}
+	_, cut := rec.played()
+	time.Sleep(200 * time.Millisecond)
+
+	_, audio := rec.played()
+	if audio != cut {
+		t.Errorf("audio sent after Speak: %q then %q", cut, audio)
+	}
+	if len(audio) == 0 || len(audio) >= 40 {
+		t.Errorf("sent %d bytes, want the phrase cut short", len(audio))
+	}
+}
+
+func TestPlayer_HoldAudioYieldsToTool(t *testing.T) {
+	rec := &recorder{}
+	p := newPlayer(t, Config{
+		Delay:     time.Millisecond,
+		Phrases:   []string{"ok"},
+		Tools:     map[string]string{"book": "Booking"},
+		HoldAudio: []byte("~~"),
+	}, rec, 40)
+
+	p.Wait()
+	time.Sleep(300 * time.Millisecond)
+	p.Wait("book")
+	time.Sleep(300 * time.Millisecond)
+	p.Stop()
+
+	fillers, audio := rec.played()
+	if len(fillers) < 3 || fillers[0].Kind != Phrase || fillers[1].Kind != Hold || fillers[2].Kind != Tool {
+		t.Fatalf("fillers = %+v", fillers)
+	}
+	if !strings.HasPrefix(audio, "ok~~~~") || !strings.Contains(audio, "Booking") {
+		t.Errorf("audio = %q, want the phrase, looped hold audio and then the tool message", audio)
+	}
+}
+
+func TestPlayer_Nil(t *testing.T) {
+	var p *Player
+	p.Prepare()
+	p.Wait("tool")
+	if p.Speak(time.Second) {
+		t.Error("Speak() on a nil Player = true")
+	}
+	p.Done()
+	p.Stop()
+}
+
+func TestPlayer_DoneLetsFillerFinish(t *testing.T) {
+	rec := &recorder{}
+	p := newPlayer(t, Config{Delay: time.Millisecond, Phrases: []string{"abcdefgh"}}, rec, 40)
+
+	p.Wait()
+	time.Sleep(50 * time.Millisecond)
+	p.Done()
+	time.Sleep(250 * time.Millisecond)
+
+	if _, audio := rec.played(); audio != "abcdefgh" {
+		t.Errorf("audio = %q, want the whole phrase", audio)
+	}
+}
@@ -0,0 +1,22 @@
+package voice
+
+import (
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/filler"
+)
+
+// FillerConfig converts filler settings to a filler.Config. Nil settings
+// give a config with nothing to play.
+func FillerConfig(c *types.FillerConfig) filler.Config {
+	if c == nil {
+		return filler.Config{}
+	}
+	return filler.Config{
+		Delay:     time.Duration(c.DelayMs) * time.Millisecond,
+		Phrases:   c.Phrases,
+		Tools:     c.Tools,
+		HoldAudio: c.HoldAudio,
+	}
+}
@@ -0,0 +1,106 @@
+// Package registry holds voice providers by name. It is the shared core of
+// the STT and TTS registries.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Named is a provider with a name.
+type Named interface {
+	Name() string
+}
+
+// Registry holds providers by name and resolves "provider/value" names to
+// them. A name without a provider goes to the default provider.
+type Registry[P Named] struct {
+	prefix string // Error prefix, "stt" or "tts"
+	kind   string // What names select, "model" or "voice"
+
+	mu          sync.RWMutex
+	providers   map[string]P
+	defaultName string
+}
+
+// New creates a registry of the given providers. The first one is the
+// default. Errors start with prefix and call names kind.
+func New[P Named](prefix, kind string, providers ...P) *Registry[P] {
+	r := &Registry[P]{prefix: prefix, kind: kind, providers: make(map[string]P)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider under its name, replacing any provider with the
+// same name. The first provider registered becomes the default.
+func (r *Registry[P]) Register(p P) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+	if r.defaultName == "" {
+		r.defaultName = p.Name()
+	}
+}
+
+// SetDefault sets the provider used for names without a provider.
+func (r *Registry[P]) SetDefault(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("%s: unknown provider %q", r.prefix, name)
+	}
+	r.defaultName = name
+	return nil
+}
+
+// Get returns the provider registered under name.
+func (r *Registry[P]) Get(name string) (P, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Providers returns the names of the registered providers, sorted.
+func (r *Registry[P]) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of registered providers.
+func (r *Registry[P]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.providers)
+}
+
+// Resolve returns the provider for a name such as "provider/value" and the
+// value to pass to it.
+func (r *Registry[P]) Resolve(name string) (P, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var none P
+	if provider, rest, ok := strings.Cut(name, "/"); ok {
+		if p, ok := r.providers[provider]; ok {
+			return p, rest, nil
+		}
+		return none, "", fmt.Errorf("%s: unknown provider %q in %s %q", r.prefix, provider, r.kind, name)
+	}
+
+	p, ok := r.providers[r.defaultName]
+	if !ok {
+		return none, "", fmt.Errorf("%s: no provider for %s %q", r.prefix, r.kind, name)
+	}
+	return p, name, nil
+}
@@ -0,0 +1,324 @@
+package normalize
+
+import "strings"
+
+// locale holds the language-specific rules for reading numbers, money,
+// dates, times and units.
+type locale struct {
+	decimal, group byte // Number separators
+
+	cardinal func(n int64) string
+	ordinal  func(n int64) string
+	dative   func(n int64) string // Ordinal after a dative word, if it differs
+	year     func(n int64) string
+	fraction func(num, den int64) string
+	date     func(l *locale, year, month int, day string) string // day is spoken; year is 0 when absent
+	time     func(l *locale, hour, minute int, suffix string) string
+
+	// one is the word for a count of one before a noun
+	one func(feminine bool) string
+
+	words      map[string]string // Connecting words: "point", "minus", "to", ...
+	months     [12]string
+	currencies map[string]currency // By symbol and ISO code
+	units      map[string]unit
+	scales     map[string]unit   // Scale words after an amount: "million", "bn", ...
+	abbrevs    map[string]string // Abbreviations, lowercase with their periods
+	spoken     map[string]bool   // All-caps words read as words rather than spelled out
+	prefixWord map[byte]string   // Symbols read before a number: "#" -> "number"
+
+	yearWords   map[string]bool // Lowercase words after which a number is a year: "in", "since"
+	dativeWords map[string]bool // Lowercase words after which an ordinal is dative: "am"
+}
+
+// currency is how to read an amount of money.
+type currency struct {
+	one, many           string // Major unit
+	minorOne, minorMany string // Minor unit, or empty when amounts are whole
+	feminine            bool
+}
+
+// unit is how to read a unit of measurement after a number.
+type unit struct {
+	one, many string
+	feminine  bool
+	attached  bool // Only recognised when written directly after the number
+	abbrev    bool // Written with a period, which is dropped
+}
+
+// lookupLocale returns the rules for a locale such as "en", "en-US" or
+// "de_DE", or nil if the language is not supported.
+func lookupLocale(tag string) *locale {
+	lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	switch lang {
+	case "", "en":
+		return english
+	case "de":
+		return german
+	}
+	return nil
+}
+
+// month returns the month number (1-12) for a month name, or 0.
+func (l *locale) month(word string) int {
+	for i, m := range l.months {
+		if strings.EqualFold(word, m) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// count reads a whole number before a noun.
+func (l *locale) count(n int64, feminine bool) string {
+	if n == 1 {
+		return l.one(feminine)
+	}
+	return l.cardinal(n)
+}
+
+var english = &locale{
+	decimal:  '.',
+	group:    ',',
+	cardinal: enCardinal,
+	ordinal:  enOrdinal,
+	year:     enYear,
+	fraction: enFraction,
+	date: func(l *locale, year, month int, day string) string {
+		s := l.months[month-1] + " " + day
+		if year > 0 {
+			s += ", " + l.year(int64(year))
+		}
+		return s
+	},
+	time: func(l *locale, hour, minute int, suffix string) string {
+		var s string
+		switch {
+		case minute == 0 && suffix != "":
+			s = l.cardinal(int64(hour))
+		case minute == 0 && hour > 0 && hour <= 12:
+			s = l.cardinal(int64(hour)) + " o'clock"
+		case minute == 0:
+			s = l.cardinal(int64(hour)) + " hundred"
+		case minute < 10:
+			s = l.cardinal(int64(hour)) + " oh " + l.cardinal(int64(minute))
+		default:
+			s = l.cardinal(int64(hour)) + " " + l.cardinal(int64(minute))
+		}
+		if suffix != "" {
+			s += " " + suffix
+		}
+		return s
+	},
+	one: func(bool) string { return "one" },
+	words: map[string]string{
+		"point": "point", "minus": "minus", "to": "to", "and": "and",
+		"dot": "dot", "at": "at", "percent": "percent", "plus": "plus", "equals": "equals",
+	},
+	months: [12]string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	},
+	currencies: map[string]currency{
+		"$":   {one: "dollar", many: "dollars", minorOne: "cent", minorMany: "cents"},
+		"USD": {one: "dollar", many: "dollars", minorOne: "cent", minorMany: "cents"},
+		"€":   {one: "euro", many: "euros", minorOne: "cent", minorMany: "cents"},
+		"EUR": {one: "euro", many: "euros", minorOne: "cent", minorMany: "cents"},
+		"£":   {one: "pound", many: "pounds", minorOne: "penny", minorMany: "pence"},
+		"GBP": {one: "pound", many: "pounds", minorOne: "penny", minorMany: "pence"},
+		"¥":   {one: "yen", many: "yen"},
+		"JPY": {one: "yen", many: "yen"},
+	},
+	units: map[string]unit{
+		"km":   {one: "kilometer", many: "kilometers"},
+		"m":    {one: "meter", many: "meters", attached: true},
+		"cm":   {one: "centimeter", many: "centimeters"},
+		"mm":   {one: "millimeter", many: "millimeters"},
+		"mi":   {one: "mile", many: "miles"},
+		"ft":   {one: "foot", many: "feet"},
+		"kg":   {one: "kilogram", many: "kilograms"},
+		"g":    {one: "gram", many: "grams", attached: true},
+		"mg":   {one: "milligram", many: "milligrams"},
+		"lb":   {one: "pound", many: "pounds"},
+		"lbs":  {one: "pound", many: "pounds"},
+		"oz":   {one: "ounce", many: "ounces"},
+		"ml":   {one: "milliliter", many: "milliliters"},
+		"l":    {one: "liter", many: "liters", attached: true},
+		"mph":  {one: "mile per hour", many: "miles per hour"},
+		"km/h": {one: "kilometer per hour", many: "kilometers per hour"},
+		"kph":  {one: "kilometer per hour", many: "kilometers per hour"},
+		"°C":   {one: "degree Celsius", many: "degrees Celsius"},
+		"°F":   {one: "degree Fahrenheit", many: "degrees Fahrenheit"},
+		"°":    {one: "degree", many: "degrees"},
+		"KB":   {one: "kilobyte", many: "kilobytes"},
+		"MB":   {one: "megabyte", many: "megabytes"},
+		"GB":   {one: "gigabyte", many: "gigabytes"},
+		"TB":   {one: "terabyte", many: "terabytes"},
+		"Hz":   {one: "hertz", many: "hertz"},
+		"kHz":  {one: "kilohertz", many: "kilohertz"},
+		"MHz":  {one: "megahertz", many: "megahertz"},
+		"GHz":  {one: "gigahertz", many: "gigahertz"},
+		"ms":   {one: "millisecond", many: "milliseconds"},
+		"sec":  {one: "second", many: "seconds"},
+		"min":  {one: "minute", many: "minutes"},
+		"h":    {one: "hour", many: "hours", attached: true},
+		"hr":   {one: "hour", many: "hours"},
+		"hrs":  {one: "hour", many: "hours"},
+		"W":    {one: "watt", many: "watts", attached: true},
+		"kW":   {one: "kilowatt", many: "kilowatts"},
+		"kWh":  {one: "kilowatt hour", many: "kilowatt hours"},
+		"V":    {one: "volt", many: "volts", attached: true},
+		"%":    {one: "percent", many: "percent"},
+		"x":    {one: "time", many: "times", attached: true},
+		"×":    {one: "time", many: "times", attached: true},
+	},
+	scales: map[string]unit{
+		"k": {one: "thousand", many: "thousand", attached: true}, "K": {one: "thousand", many: "thousand", attached: true},
+		"thousand": {one: "thousand", many: "thousand"},
+		"M":        {one: "million", many: "million", attached: true}, "mn": {one: "million", many: "million"},
+		"million": {one: "million", many: "million"},
+		"B":       {one: "billion", many: "billion", attached: true}, "bn": {one: "billion", many: "billion"},
+		"billion":  {one: "billion", many: "billion"},
+		"T":        {one: "trillion", many: "trillion", attached: true},
+		"trillion": {one: "trillion", many: "trillion"},
+	},
+	abbrevs: map[string]string{
+		"e.g.": "for example", "i.e.": "that is", "etc.": "et cetera", "vs.": "versus",
+		"approx.": "approximately",
+	},
+	spoken: map[string]bool{
+		"OK": true, "NO": true, "NOT": true, "YES": true, "AND": true, "OR": true,
+		"THE": true, "ALL": true, "ANY": true, "BIG": true, "NEW": true, "NOW": true,
+		"OFF": true, "ONE": true, "TWO": true, "TOP": true, "WOW": true, "HEY": true,
+		"YOU": true, "ARE": true, "CAN": true, "DO": true, "GO": true, "SO": true,
+		"UP": true, "ON": true, "IN": true, "AT": true, "IS": true, "IT": true,
+		"BE": true, "WE": true, "ME": true, "MY": true, "BY": true, "TO": true,
+		"OF": true, "IF": true, "AS": true, "AN": true, "HI": true, "FOR": true,
+		"BUT": true, "GET": true, "HOW": true, "WHY": true, "OUT": true, "OUR": true,
+		"GIF": true, "PIN": true, "RAM": true, "ROM": true, "ZIP": true,
+	},
+	prefixWord: map[byte]string{'#': "number"},
+	yearWords: map[string]bool{
+		"in": true, "since": true, "until": true, "till": true, "during": true, "circa": true, "year": true,
+	},
+}
+
+var german = &locale{
+	decimal:  ',',
+	group:    '.',
+	cardinal: deCardinal,
+	ordinal:  deOrdinal,
+	dative:   deDative,
+	year:     deYear,
+	fraction: deFraction,
+	date: func(l *locale, year, month int, day string) string {
+		s := day + " " + l.months[month-1]
+		if year > 0 {
+			s += " " + l.year(int64(year))
+		}
+		return s
+	},
+	time: func(l *locale, hour, minute int, suffix string) string {
+		h := l.cardinal(int64(hour))
+		if hour == 1 {
+			h = "ein"
+		}
+		s := h + " Uhr"
+		if minute > 0 {
+			s += " " + l.cardinal(int64(minute))
+		}
+		if suffix != "" {
+			s += " " + suffix
+		}
+		return s
+	},
+	one: func(feminine bool) string {
+		if feminine {
+			return "eine"
+		}
+		return "ein"
+	},
+	words: map[string]string{
+		"point": "Komma", "minus": "minus", "to": "bis", "and": "und",
+		"dot": "Punkt", "at": "at", "percent": "Prozent", "plus": "plus", "equals": "gleich",
+	},
+	months: [12]string{
+		"Januar", "Februar", "März", "April", "Mai", "Juni",
+		"Juli", "August", "September", "Oktober", "November", "Dezember",
+	},
+	currencies: map[string]currency{
+		"$":   {one: "Dollar", many: "Dollar", minorOne: "Cent", minorMany: "Cent"},
+		"USD": {one: "Dollar", many: "Dollar", minorOne: "Cent", minorMany: "Cent"},
+		"€":   {one: "Euro", many: "Euro", minorOne: "Cent", minorMany: "Cent"},
+		"EUR": {one: "Euro", many: "Euro", minorOne: "Cent", minorMany: "Cent"},
+		"£":   {one: "Pfund", many: "Pfund", minorOne: "Penny", minorMany: "Pence"},
+		"GBP": {one: "Pfund", many: "Pfund", minorOne: "Penny", minorMany: "Pence"},
+		"¥":   {one: "Yen", many: "Yen"},
+		"JPY": {one: "Yen", many: "Yen"},
+		"CHF": {one: "Franken", many: "Franken", minorOne: "Rappen", minorMany: "Rappen"},
+	},
+	units: map[string]unit{
+		"km":   {one: "Kilometer", many: "Kilometer"},
+		"m":    {one: "Meter", many: "Meter", attached: true},
+		"cm":   {one: "Zentimeter", many: "Zentimeter"},
+		"mm":   {one: "Millimeter", many: "Millimeter"},
+		"kg":   {one: "Kilogramm", many: "Kilogramm"},
+		"g":    {one: "Gramm", many: "Gramm", attached: true},
+		"mg":   {one: "Milligramm", many: "Milligramm"},
+		"ml":   {one: "Milliliter", many: "Milliliter"},
+		"l":    {one: "Liter", many: "Liter", attached: true},
+		"km/h": {one: "Kilometer pro Stunde", many: "Kilometer pro Stunde"},
+		"mph":  {one: "Meile pro Stunde", many: "Meilen pro Stunde", feminine: true},
+		"°C":   {one: "Grad Celsius", many: "Grad Celsius"},
+		"°F":   {one: "Grad Fahrenheit", many: "Grad Fahrenheit"},
+		"°":    {one: "Grad", many: "Grad"},
+		"KB":   {one: "Kilobyte", many: "Kilobyte"},
+		"MB":   {one: "Megabyte", many: "Megabyte"},
+		"GB":   {one: "Gigabyte", many: "Gigabyte"},
+		"TB":   {one: "Terabyte", many: "Terabyte"},
+		"Hz":   {one: "Hertz", many: "Hertz"},
+		"kHz":  {one: "Kilohertz", many: "Kilohertz"},
+		"MHz":  {one: "Megahertz", many: "Megahertz"},
+		"GHz":  {one: "Gigahertz", many: "Gigahertz"},
+		"ms":   {one: "Millisekunde", many: "Millisekunden", feminine: true},
+		"Sek":  {one: "Sekunde", many: "Sekunden", feminine: true, abbrev: true},
+		"Min":  {one: "Minute", many: "Minuten", feminine: true, abbrev: true},
+		"Std":  {one: "Stunde", many: "Stunden", feminine: true, abbrev: true},
+		"h":    {one: "Stunde", many: "Stunden", feminine: true, attached: true},
+		"W":    {one: "Watt", many: "Watt", attached: true},
+		"kW":   {one: "Kilowatt", many: "Kilowatt"},
+		"kWh":  {one: "Kilowattstunde", many: "Kilowattstunden", feminine: true},
+		"V":    {one: "Volt", many: "Volt", attached: true},
+		"%":    {one: "Prozent", many: "Prozent"},
+		"x":    {one: "Mal", many: "mal", attached: true},
+		"×":    {one: "Mal", many: "mal", attached: true},
+	},
+	scales: map[string]unit{
+		"Tsd":        {one: "tausend", many: "tausend", abbrev: true},
+		"Mio":        {one: "Million", many: "Millionen", feminine: true, abbrev: true},
+		"Million":    {one: "Million", many: "Millionen", feminine: true},
+		"Millionen":  {one: "Million", many: "Millionen", feminine: true},
+		"Mrd":        {one: "Milliarde", many: "Milliarden", feminine: true, abbrev: true},
+		"Milliarde":  {one: "Milliarde", many: "Milliarden", feminine: true},
+		"Milliarden": {one: "Milliarde", many: "Milliarden", feminine: true},
+	},
+	abbrevs: map[string]string{
+		"z.b.": "zum Beispiel", "d.h.": "das heißt", "usw.": "und so weiter",
+		"bzw.": "beziehungsweise", "ca.": "circa", "u.a.": "unter anderem",
+		"ggf.": "gegebenenfalls", "evtl.": "eventuell",
+	},
+	spoken: map[string]bool{
+		"OK": true, "JA": true, "NEIN": true, "UND": true, "ODER": true, "NICHT": true,
+		"DER": true, "DIE": true, "DAS": true, "EIN": true, "ES": true, "IST": true,
+		"SO": true, "WIE": true, "WAS": true, "WER": true, "WO": true, "AN": true,
+		"IN": true, "AM": true, "IM": true, "ZU": true, "MIT": true,
+	},
+	prefixWord: map[byte]string{'#': "Nummer"},
+	yearWords: map[string]bool{
+		"seit": true, "bis": true, "anno": true, "jahr": true, "jahre": true, "jahres": true,
+	},
+	dativeWords: map[string]bool{
+		"am": true, "im": true, "vom": true, "zum": true, "beim": true, "dem": true,
+	},
+}
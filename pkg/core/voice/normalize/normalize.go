@@ -0,0 +1,298 @@
+// Package normalize rewrites LLM text so that it reads naturally when
+// spoken. It strips markdown, reads out numbers, money, dates, times, units
+// and URLs, spells out acronyms and applies a pronunciation dictionary.
+//
+// Text is normalized as it streams: a Normalizer holds back only what might
+// still change, such as a word or number cut off between deltas, or the
+// start of a line that could be a code fence.
+package normalize
+
+import (
+	"strings"
+)
+
+// Normalizer rewrites streamed text for speech. Write takes the next piece
+// of text and returns the text that is ready to speak, which may be empty.
+// Flush returns whatever is left at the end of the text, after which the
+// Normalizer starts afresh.
+type Normalizer interface {
+	Write(text string) string
+	Flush() string
+}
+
+// Config configures the default normalizer.
+type Config struct {
+	// Locale is the language of the text, e.g. "en" (the default) or
+	// "de-DE". Numbers, money, dates, times and units are read out in
+	// English and German; other languages keep them as written.
+	Locale string
+
+	// Dictionary maps words to how they should be spoken, e.g.
+	// {"SQL": "sequel", "nginx": "engine x"}. Words match exactly, or else
+	// ignoring case, and the dictionary takes precedence over other rules.
+	Dictionary map[string]string
+}
+
+// New creates a normalizer for one response.
+func New(cfg Config) Normalizer {
+	n := &textNormalizer{
+		loc:       lookupLocale(cfg.Locale),
+		dict:      cfg.Dictionary,
+		lineStart: true,
+	}
+	if len(cfg.Dictionary) > 0 {
+		n.dictFold = make(map[string]string, len(cfg.Dictionary))
+		for word, spoken := range cfg.Dictionary {
+			n.dictFold[strings.ToLower(word)] = spoken
+		}
+	}
+	return n
+}
+
+// Text normalizes a complete text.
+func Text(text string, cfg Config) string {
+	n := New(cfg)
+	return n.Write(text) + n.Flush()
+}
+
+// textNormalizer is the default Normalizer.
+type textNormalizer struct {
+	loc      *locale // nil when numbers are kept as written
+	dict     map[string]string
+	dictFold map[string]string // dict by lowercase word
+
+	pending   string // Text not yet normalized
+	lineStart bool   // pending starts a line
+	inFence   bool   // Inside a fenced code block
+	prev      prevWord
+}
+
+// prevWord is what the last word was, for reading dates.
+type prevWord int
+
+const (
+	prevOther  prevWord = iota
+	prevMonth           // A month name
+	prevDay             // A day after a month name
+	prevYear            // A word that comes before a year, such as "in"
+	prevDative          // A German word after which ordinals are dative, such as "am"
+)
+
+func (n *textNormalizer) Write(text string) string {
+	n.pending += text
+	return n.process(false)
+}
+
+func (n *textNormalizer) Flush() string {
+	out := n.process(true)
+	n.lineStart, n.inFence, n.prev = true, false, prevOther
+	return out
+}
+
+// process normalizes as much of the pending text as is safe. At the end of
+// the text everything is.
+func (n *textNormalizer) process(final bool) string {
+	var out strings.Builder
+	for n.pending != "" {
+		if n.lineStart {
+			s, ok := n.startLine(final)
+			if !ok {
+				break
+			}
+			out.WriteString(s)
+			continue
+		}
+
+		if nl := strings.IndexByte(n.pending, '\n'); nl >= 0 {
+			out.WriteString(n.inline(n.pending[:nl]))
+			out.WriteByte('\n')
+			n.pending = n.pending[nl+1:]
+			n.lineStart = true
+			continue
+		}
+		if final {
+			out.WriteString(n.inline(n.pending))
+			n.pending = ""
+			break
+		}
+		cut := n.safeCut()
+		if cut > 0 {
+			out.WriteString(n.inline(n.pending[:cut]))
+			n.pending = n.pending[cut:]
+		}
+		break
+	}
+	return out.String()
+}
+
+// safeCut returns how much of the pending text, which has no newline, can
+// be normalized now. The cut is made after a whole word; a trailing number
+// is held back as well, since the next word may be its unit or currency.
+func (n *textNormalizer) safeCut() int {
+	ws := strings.LastIndexAny(n.pending, " \t")
+	if ws < 0 {
+		return 0
+	}
+	end := len(strings.TrimRight(n.pending[:ws], " \t"))
+	start := strings.LastIndexAny(n.pending[:end], " \t") + 1
+	if strings.ContainsAny(n.pending[start:end], "0123456789") {
+		end = len(strings.TrimRight(n.pending[:start], " \t"))
+	}
+	return end
+}
+
+// startLine handles markdown block syntax at the start of a line: code
+// fences, headings, quotes, list markers, rules and tables. It returns
+// false if more text is needed to decide.
+func (n *textNormalizer) startLine(final bool) (string, bool) {
+	line, rest, complete := n.pending, "", final
+	nl := strings.IndexByte(n.pending, '\n')
+	if nl >= 0 {
+		line, rest, complete = n.pending[:nl], n.pending[nl+1:], true
+	}
+	after := n.pending[len(line):] // The newline and what follows, if any
+	trimmed := strings.TrimLeft(line, " \t")
+	first := trimmed
+	if i := strings.IndexAny(trimmed, " \t"); i >= 0 {
+		first = trimmed[:i]
+	} else if !complete {
+		return "", false // The first word may be incomplete
+	}
+
+	// Constructs that take the whole line
+	if n.inFence || isFence(first) || isRule(first) || strings.HasPrefix(first, "|") {
+		if !complete {
+			return "", false
+		}
+		switch {
+		case n.inFence:
+			// Code is not read out
+			n.inFence = !isFence(first)
+			n.pending = rest
+			return "", true
+		case isFence(first):
+			n.inFence = true
+			n.pending = rest
+			return "", true
+		case isRule(trimmed), isTableDivider(trimmed):
+			n.pending = rest
+			return "", true
+		case strings.HasPrefix(first, "|"):
+			n.pending = tableRow(trimmed) + after
+			n.lineStart = false
+			return "", true
+		}
+	}
+
+	if trimmed == "" {
+		// A blank line
+		n.pending = rest
+		if nl < 0 {
+			return "", true
+		}
+		return "\n", true
+	}
+
+	switch {
+	case strings.Trim(first, "#") == "" && len(first) <= 6:
+		trimmed = trimmed[len(first):]
+	case strings.HasPrefix(first, ">"):
+		trimmed = strings.TrimLeft(trimmed, "> \t")
+	case first == "-" || first == "*" || first == "+" || first == "•", isOrderedMarker(first) && first != trimmed:
+		trimmed = trimmed[len(first):]
+	}
+	n.pending = strings.TrimLeft(trimmed, " \t") + after
+	n.lineStart = false
+	return "", true
+}
+
+// isOrderedMarker reports whether a word is the marker of an ordered list
+// item, such as "1." or "2)".
+func isOrderedMarker(word string) bool {
+	n := len(word) - 1
+	return n > 0 && n <= 3 && (word[n] == '.' || word[n] == ')') && isDigits(word[:n])
+}
+
+// isFence reports whether a word opens or closes a fenced code block.
+func isFence(word string) bool {
+	return strings.HasPrefix(word, "```") || strings.HasPrefix(word, "~~~")
+}
+
+// isRule reports whether text is a horizontal rule, or may start one.
+func isRule(text string) bool {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, " ", ""), "\t", "")
+	if len(text) < 3 {
+		return false
+	}
+	return strings.Trim(text, string(text[0])) == "" && strings.ContainsAny(text[:1], "-*_")
+}
+
+// isTableDivider reports whether a table row is the divider under the header.
+func isTableDivider(row string) bool {
+	return strings.Trim(row, "|-: \t") == ""
+}
+
+// tableRow reads a table row as a list of its cells.
+func tableRow(row string) string {
+	var cells []string
+	for _, cell := range strings.Split(strings.Trim(row, "| \t"), "|") {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return strings.Join(cells, ", ")
+}
+
+// inline normalizes text within a line, made of whole words.
+func (n *textNormalizer) inline(text string) string {
+	words := splitWords(text)
+	var out strings.Builder
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if w.text == "" {
+			out.WriteString(w.space)
+			continue
+		}
+		var next string
+		if i+1 < len(words) {
+			next = words[i+1].text
+		}
+		spoken, usedNext := n.word(w.text, next)
+		if usedNext {
+			i++
+		}
+		if spoken == "" {
+			continue
+		}
+		if strings.Trim(spoken, trailPunct) != "" {
+			// Punctuation left over from a dropped word belongs to the
+			// word before it
+			out.WriteString(w.space)
+		}
+		out.WriteString(spoken)
+	}
+	return out.String()
+}
+
+// spacedWord is a word with the whitespace before it. The last entry of a
+// text holds any trailing whitespace, with an empty word.
+type spacedWord struct {
+	space string
+	text  string
+}
+
+func splitWords(text string) []spacedWord {
+	var words []spacedWord
+	for text != "" {
+		i := len(text) - len(strings.TrimLeft(text, " \t\r"))
+		space := text[:i]
+		text = text[i:]
+		j := strings.IndexAny(text, " \t\r")
+		if j < 0 {
+			j = len(text)
+		}
+		words = append(words, spacedWord{space: space, text: text[:j]})
+		text = text[j:]
+	}
+	return words
+}
@@ -0,0 +1,202 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText_English(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"emphasis", "This is **very** _important_ and `code`.", "This is very important and code."},
+		{"link", "See [the docs](https://example.com/docs) or ![a chart](chart.png).", "See the docs or a chart."},
+		{"url", "Go to https://www.example.com/pricing?plan=pro now.", "Go to example dot com now."},
+		{"email", "Mail support@vango.dev today.", "Mail support at vango dot dev today."},
+		{"emoji", "Done 🎉! Great 👍🏽 work.", "Done! Great work."},
+		{"currency", "It costs $1,234.56 or $1.", "It costs one thousand two hundred thirty-four dollars and fifty-six cents or one dollar."},
+		{"cents", "Only $0.05 each.", "Only five cents each."},
+		{"currency after amount", "About 10 EUR or 5€.", "About ten euros or five euros."},
+		{"currency with scale", "Raised $1.5M, then $2 billion.", "Raised one point five million dollars, then two billion dollars."},
+		{"percent", "Up 12.5% today.", "Up twelve point five percent today."},
+		{"numbers", "Call 911, not 007, and subtract -3.", "Call nine hundred eleven, not zero zero seven, and subtract minus three."},
+		{"range", "Wait 5-10 minutes.", "Wait five to ten minutes."},
+		{"year range", "From 1990-2000.", "From one thousand nine hundred ninety to two thousand."},
+		{"phone number", "Call 555-1234 or 1-800-555-0199.", "Call five five five, one two three four or one, eight zero zero, five five five, zero one nine nine."},
+		{"year", "In 1999 and since 2024, not in 1500 ms.", "In nineteen ninety-nine and since twenty twenty-four, not in one thousand five hundred milliseconds."},
+		{"fraction", "Add 1/2 cup and 3/4 tsp, 24/7.", "Add one half cup and three quarters tsp, 24/7."},
+		{"fraction ordinal", "About 2/3 or 1/5.", "About two thirds or one fifth."},
+		{"multiplier", "It is 3.5x faster, 10× cheaper.", "It is three point five times faster, ten times cheaper."},
+		{"number sign", "Issue #42 is fixed.", "Issue number forty-two is fixed."},
+		{"ordinal", "The 1st and 22nd place.", "The first and twenty-second place."},
+		{"date", "Due March 15, 2024.", "Due March fifteenth, twenty twenty-four."},
+		{"numeric date", "Due 2024-03-15 or 3/1/1999.", "Due March fifteenth, twenty twenty-four or March first, nineteen ninety-nine."},
+		{"time", "Meet at 3:30pm, 9 a.m., or 14:05.", "Meet at three thirty PM, nine AM, or fourteen oh five."},
+		{"units", "It is 5 km away, 20°C, 100ms and 1 kg.", "It is five kilometers away, twenty degrees Celsius, one hundred milliseconds and one kilogram."},
+		{"scale", "About 10k users.", "About ten thousand users."},
+		{"acronyms", "The API returns JSON over HTTPS to NASA.", "The A P I returns JSON over H T T P S to NASA."},
+		{"acronym plural", "Two APIs.", "Two A P I's."},
+		{"abbreviation", "Fruit, e.g. apples, etc.", "Fruit, for example apples, et cetera"},
+		{"symbols", "Salt & pepper", "Salt and pepper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.in, Config{}); got != tt.want {
+				t.Errorf("Text(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText_Markdown(t *testing.T) {
+	in := "# Summary\n" +
+		"> Note: read this.\n" +
+		"- first item\n" +
+		"* second **item**\n" +
+		"\n" +
+		"```go\n" +
+		"fmt.Println(\"**\")\n" +
+		"```\n" +
+		"---\n" +
+		"| Plan | Price |\n" +
+		"|------|------:|\n" +
+		"| Pro | $20 |\n" +
+		"1. First item\n" +
+		"2) Second item\n" +
+		"3."
+	want := "Summary\n" +
+		"Note: read this.\n" +
+		"first item\n" +
+		"second item\n" +
+		"\n" +
+		"Plan, Price\n" +
+		"Pro, twenty dollars\n" +
+		"First item\n" +
+		"Second item\n" +
+		"three."
+	if got := Text(in, Config{}); got != want {
+		t.Errorf("Text()\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestText_German(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Es kostet 1.234,56 €.", "Es kostet eintausendzweihundertvierunddreißig Euro und sechsundfünfzig Cent."},
+		{"Am 15. März 2024 um 14:30 Uhr.", "Am fünfzehnten März zweitausendvierundzwanzig um vierzehn Uhr dreißig."},
+		{"Termin am 01.02.1999", "Termin am ersten Februar neunzehnhundertneunundneunzig"},
+		{"Bis zum 3. März, ab 1. Mai", "Bis zum dritten März, ab erster Mai"},
+		{"Seit 1999 gibt es 1/2 Liter und 3/4 Stunde", "Seit neunzehnhundertneunundneunzig gibt es ein halb Liter und drei Viertel Stunde"},
+		{"Ruf 0800-123456 an, 2x täglich", "Ruf null acht null null, eins zwei drei vier fünf sechs an, zwei mal täglich"},
+		{"Noch 1 km bzw. 5 Min.", "Noch ein Kilometer beziehungsweise fünf Minuten"},
+		{"Rund 3,5 Mio. Euro", "Rund drei Komma fünf Millionen Euro"},
+		{"Die USA und 21 Leute", "Die U S A und einundzwanzig Leute"},
+	}
+	for _, tt := range tests {
+		if got := Text(tt.in, Config{Locale: "de-DE"}); got != tt.want {
+			t.Errorf("Text(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestText_UnsupportedLocaleKeepsNumbers(t *testing.T) {
+	got := Text("**Prix:** 5 € pour l'API", Config{Locale: "fr"})
+	if want := "Prix: 5 € pour l'API"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestText_Dictionary(t *testing.T) {
+	cfg := Config{Dictionary: map[string]string{"SQL": "sequel", "nginx": "engine x", "Vango": "van go"}}
+	got := Text("Put SQL behind NGINX, says Vango.", cfg)
+	if want := "Put sequel behind engine x, says van go."; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizer_StreamingMatchesText(t *testing.T) {
+	texts := []string{
+		"**Total:** $1,234.56 due on March 15, 2024 at 3:30 pm.\n\nThanks!",
+		"Steps:\n```bash\nrm -rf **/*\n```\n- Run it 5 km 🚀 away\n| a | b |\n|---|---|\n| 1 | 2 |\n",
+		"Read https://example.com/a_b and the API docs, e.g. section #3.",
+	}
+	for _, text := range texts {
+		want := Text(text, Config{})
+		// Split the text at every size to cut tokens in every place
+		for size := 1; size <= 7; size++ {
+			n := New(Config{})
+			var got strings.Builder
+			for i := 0; i < len(text); i += size {
+				end := min(i+size, len(text))
+				got.WriteString(n.Write(text[i:end]))
+			}
+			got.WriteString(n.Flush())
+			if got.String() != want {
+				t.Errorf("size %d:\n got %q\nwant %q", size, got.String(), want)
+			}
+		}
+	}
+}
+
+func TestNormalizer_EmitsCompleteWords(t *testing.T) {
+	n := New(Config{})
+	steps := []struct {
+		delta string
+		want  string
+	}{
+		{"**Hello", ""},
+		{"** there, it", "Hello there,"},
+		{" costs $5", " it costs"},
+		{" million", ""},
+		{" today.", " five million dollars"},
+	}
+	for _, s := range steps {
+		if got := n.Write(s.delta); got != s.want {
+			t.Errorf("Write(%q) = %q, want %q", s.delta, got, s.want)
+		}
+	}
+	if got := n.Flush(); got != " today." {
+		t.Errorf("Flush() = %q, want %q", got, " today.")
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		fn   func(int64) string
+		n    int64
+		want string
+	}{
+		{enCardinal, 0, "zero"},
+		{enCardinal, 1_000_001, "one million one"},
+		{enCardinal, 999_999, "nine hundred ninety-nine thousand nine hundred ninety-nine"},
+		{enOrdinal, 12, "twelfth"},
+		{enOrdinal, 40, "fortieth"},
+		{enOrdinal, 103, "one hundred third"},
+		{enYear, 1905, "nineteen oh five"},
+		{enYear, 2000, "two thousand"},
+		{enYear, 1800, "eighteen hundred"},
+		{func(n int64) string { return enFraction(1, n) }, 8, "one eighth"},
+		{func(n int64) string { return enFraction(n, 2) }, 1, "one half"},
+		{func(n int64) string { return deFraction(1, n) }, 3, "ein Drittel"},
+		{deCardinal, 1, "eins"},
+		{deCardinal, 101, "einhunderteins"},
+		{deCardinal, 21_000, "einundzwanzigtausend"},
+		{deCardinal, 2_000_500, "zwei Millionen fünfhundert"},
+		{deCardinal, 1_000_000_000, "eine Milliarde"},
+		{deOrdinal, 3, "dritter"},
+		{deOrdinal, 19, "neunzehnter"},
+		{deOrdinal, 20, "zwanzigster"},
+		{deDative, 3, "dritten"},
+		{deDative, 1, "ersten"},
+		{deYear, 1984, "neunzehnhundertvierundachtzig"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.n); got != tt.want {
+			t.Errorf("%d: got %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package normalize
+
+import "strings"
+
+var (
+	enOnes = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	enTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	enScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion"}
+
+	enOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+// enCardinal spells out a non-negative number in English.
+func enCardinal(n int64) string {
+	if n < 20 {
+		return enOnes[n]
+	}
+	var parts []string
+	for scale := 0; n > 0; scale++ {
+		if group := n % 1000; group > 0 {
+			words := enBelow1000(group)
+			if enScales[scale] != "" {
+				words += " " + enScales[scale]
+			}
+			parts = append([]string{words}, parts...)
+		}
+		n /= 1000
+	}
+	return strings.Join(parts, " ")
+}
+
+func enBelow1000(n int64) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, enOnes[n/100]+" hundred")
+		n %= 100
+	}
+	if n > 0 {
+		if n < 20 {
+			parts = append(parts, enOnes[n])
+		} else if n%10 == 0 {
+			parts = append(parts, enTens[n/10])
+		} else {
+			parts = append(parts, enTens[n/10]+"-"+enOnes[n%10])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// enOrdinal spells out a non-negative ordinal number in English.
+func enOrdinal(n int64) string {
+	words := enCardinal(n)
+	i := strings.LastIndexAny(words, " -") + 1
+	last := words[i:]
+	switch {
+	case enOrdinals[last] != "":
+		last = enOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:i] + last
+}
+
+// enYear reads a year the way it is spoken: "nineteen ninety-nine",
+// "two thousand five", "twenty twenty-four".
+func enYear(n int64) string {
+	if n < 1100 || n > 2999 || (n >= 2000 && n < 2010) {
+		return enCardinal(n)
+	}
+	hi, lo := n/100, n%100
+	switch {
+	case lo == 0:
+		return enCardinal(hi) + " hundred"
+	case lo < 10:
+		return enCardinal(hi) + " oh " + enOnes[lo]
+	default:
+		return enCardinal(hi) + " " + enCardinal(lo)
+	}
+}
+
+// enFraction reads a proper fraction in English: "one half", "three quarters".
+func enFraction(num, den int64) string {
+	name := enOrdinal(den)
+	switch den {
+	case 2:
+		name = "half"
+	case 4:
+		name = "quarter"
+	}
+	if num != 1 {
+		if den == 2 {
+			name = "halves"
+		} else {
+			name += "s"
+		}
+	}
+	return enCardinal(num) + " " + name
+}
+
+var (
+	deOnes = []string{
+		"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun",
+		"zehn", "elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn",
+		"siebzehn", "achtzehn", "neunzehn",
+	}
+	deTens = []string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig", "sechzig", "siebzig", "achtzig", "neunzig"}
+
+	// Scales from a million up are separate, inflected words
+	deScales = [][2]string{
+		{"", ""}, {"", ""}, {"Million", "Millionen"}, {"Milliarde", "Milliarden"},
+		{"Billion", "Billionen"}, {"Billiarde", "Billiarden"},
+	}
+)
+
+// deCardinal spells out a non-negative number in German. Numbers below a
+// million are written as one word.
+func deCardinal(n int64) string {
+	if n == 0 {
+		return deOnes[0]
+	}
+	var parts []string
+	for scale := 0; n > 0; scale++ {
+		group := n % 1000
+		n /= 1000
+		if group == 0 {
+			continue
+		}
+		switch scale {
+		case 0:
+			parts = append(parts, deBelow1000(group, true))
+		case 1:
+			// Thousands join the rest of the number
+			word := deBelow1000(group, false) + "tausend"
+			if len(parts) > 0 && !strings.Contains(parts[0], " ") {
+				parts[0] = word + parts[0]
+			} else {
+				parts = append([]string{word}, parts...)
+			}
+		default:
+			word := deScales[scale][1]
+			count := deBelow1000(group, false)
+			if group == 1 {
+				word, count = deScales[scale][0], "eine"
+			}
+			parts = append([]string{count + " " + word}, parts...)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// deBelow1000 spells out 1-999. final reports whether the number ends the
+// word, where one is "eins" rather than "ein".
+func deBelow1000(n int64, final bool) string {
+	var b strings.Builder
+	if n >= 100 {
+		b.WriteString(deOne(n/100, false) + "hundert")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		b.WriteString(deOne(n, final))
+	case n%10 == 0:
+		b.WriteString(deTens[n/10])
+	default:
+		b.WriteString(deOne(n%10, false) + "und" + deTens[n/10])
+	}
+	return b.String()
+}
+
+func deOne(n int64, final bool) string {
+	if n == 1 && !final {
+		return "ein"
+	}
+	return deOnes[n]
+}
+
+// deOrdinal spells out an ordinal number in German, in the nominative
+// masculine form used for dates: "erster", "dritter", "zwanzigster".
+func deOrdinal(n int64) string {
+	switch n {
+	case 1:
+		return "erster"
+	case 3:
+		return "dritter"
+	case 7:
+		return "siebter"
+	case 8:
+		return "achter"
+	}
+	words := deCardinal(n)
+	if n%100 < 20 && n%100 != 0 {
+		return strings.TrimSuffix(words, "s") + "ter"
+	}
+	return words + "ster"
+}
+
+// deDative spells out an ordinal number in German in the dative form used
+// after "am" or "vom": "ersten", "dritten", "zwanzigsten".
+func deDative(n int64) string {
+	return strings.TrimSuffix(deOrdinal(n), "r") + "n"
+}
+
+// deFraction reads a proper fraction in German: "ein halb", "drei Viertel".
+func deFraction(num, den int64) string {
+	name := "halb"
+	if den > 2 {
+		name = strings.TrimSuffix(deOrdinal(den), "er") + "el"
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return deOne(num, false) + " " + name
+}
+
+// deYear reads a year the way it is spoken: "neunzehnhundertneunundneunzig",
+// "zweitausendvierundzwanzig".
+func deYear(n int64) string {
+	if n < 1100 || n > 1999 {
+		return deCardinal(n)
+	}
+	year := deCardinal(n/100) + "hundert"
+	if n%100 > 0 {
+		year += deBelow1000(n%100, true)
+	}
+	return year
+}
@@ -0,0 +1,713 @@
+package normalize
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	markupChars = "*_~`[]<>"        // Markdown and autolink syntax, dropped
+	leadPunct   = "\"'({¿¡“‘«"      // Kept before a word
+	trailPunct  = ".,;:!?\"')}…”’»" // Kept after a word
+	currencies  = "$€£¥"            // Symbols written before or after an amount
+	dashes      = "-–"              // Range separators
+)
+
+// ampm are the spellings of AM and PM, longest first. The last period of
+// "a.m." is usually split off as punctuation.
+var ampm = []string{"a.m.", "p.m.", "a.m", "p.m", "am", "pm"}
+
+// word normalizes one word. next is the following word, if any, which is
+// read together with it for units and currencies; usedNext reports whether
+// it was.
+func (n *textNormalizer) word(tok, next string) (spoken string, usedNext bool) {
+	tok = stripEmoji(stripLink(tok))
+	lead, core, trail := split(tok)
+	defer func() { n.prev = n.nextPrev(core, trail) }()
+	if core == "" {
+		return lead + trail, false
+	}
+
+	if s, ok := n.lookup(core); ok {
+		return lead + s + trail, false
+	}
+	if s, ok := speakURL(core, n.loc); ok {
+		return lead + s + trail, false
+	}
+	if n.loc == nil {
+		return lead + core + trail, false
+	}
+	l := n.loc
+
+	if s, rest, ok := l.abbrev(core + trail); ok {
+		return lead + s + rest, false
+	}
+
+	// The next word is only read with this one if nothing separates them
+	nlead, ncore, ntrail := split(stripEmoji(stripLink(next)))
+	if nlead != "" {
+		ncore, ntrail = "", ""
+	}
+	if s, used, ok := l.money(core, trail, ncore); ok {
+		if used {
+			return lead + s + ntrail, true
+		}
+		return lead + s + trail, false
+	}
+	if s, used, ok := l.clock(core, trail, ncore); ok {
+		if used {
+			if len(ntrail) > 1 && strings.Contains(ncore, ".") {
+				ntrail = ntrail[1:] // The period of "p.m.,"
+			}
+			return lead + s + ntrail, true
+		}
+		return lead + s + trail, false
+	}
+	if s, ok := n.dateWord(core); ok {
+		return lead + s + trail, false
+	}
+	if s, ok := n.dayOrYear(core, trail, ncore); ok {
+		return lead + s, false
+	}
+	if s, ok := l.ordinalWord(core); ok {
+		return lead + s + trail, false
+	}
+	if s, u, ok := l.measure(core, ncore); ok {
+		if u != nil {
+			if u.abbrev {
+				ntrail = strings.TrimPrefix(ntrail, ".")
+			}
+			return lead + s + ntrail, true
+		}
+		return lead + s + trail, false
+	}
+	if s, ok := l.digitGroups(core); ok {
+		return lead + s + trail, false
+	}
+	if s, ok := l.numeric(core); ok {
+		return lead + s + trail, false
+	}
+	if s, ok := l.symbol(core); ok {
+		return lead + s + trail, false
+	}
+	if s, ok := l.acronym(core); ok {
+		return lead + s + trail, false
+	}
+	return lead + core + trail, false
+}
+
+// nextPrev returns the date state after a word.
+func (n *textNormalizer) nextPrev(core, trail string) prevWord {
+	l := n.loc
+	if l == nil {
+		return prevOther
+	}
+	if l.month(core) > 0 {
+		return prevMonth
+	}
+	if n.prev == prevMonth && isDigits(core) && len(core) <= 2 {
+		return prevDay
+	}
+	if trail == "" {
+		lower := strings.ToLower(core)
+		switch {
+		case l.dativeWords[lower]:
+			return prevDative
+		case l.yearWords[lower]:
+			return prevYear
+		}
+	}
+	return prevOther
+}
+
+// ordinal reads an ordinal number, inflected for the word before it.
+func (n *textNormalizer) ordinal(v int64) string {
+	if n.prev == prevDative && n.loc.dative != nil {
+		return n.loc.dative(v)
+	}
+	return n.loc.ordinal(v)
+}
+
+// lookup finds a word in the pronunciation dictionary.
+func (n *textNormalizer) lookup(word string) (string, bool) {
+	if s, ok := n.dict[word]; ok {
+		return s, true
+	}
+	s, ok := n.dictFold[strings.ToLower(word)]
+	return s, ok
+}
+
+// split separates a word into leading punctuation, the word itself and
+// trailing punctuation. Markdown emphasis and code markers are dropped.
+func split(tok string) (lead, core, trail string) {
+	var lb strings.Builder
+	for tok != "" {
+		r, size := utf8.DecodeRuneInString(tok)
+		if strings.ContainsRune(leadPunct, r) {
+			lb.WriteRune(r)
+		} else if !strings.ContainsRune(markupChars, r) {
+			break
+		}
+		tok = tok[size:]
+	}
+	var trailRunes []rune
+	for tok != "" {
+		r, size := utf8.DecodeLastRuneInString(tok)
+		if strings.ContainsRune(trailPunct, r) {
+			trailRunes = append([]rune{r}, trailRunes...)
+		} else if !strings.ContainsRune(markupChars, r) {
+			break
+		}
+		tok = tok[:len(tok)-size]
+	}
+	return lb.String(), tok, string(trailRunes)
+}
+
+// stripLink reduces a markdown link or image to its text.
+func stripLink(tok string) string {
+	tok = strings.Replace(tok, "![", "[", 1)
+	if i := strings.Index(tok, "]("); i >= 0 {
+		rest := tok[i+2:]
+		if j := strings.IndexByte(rest, ')'); j >= 0 {
+			return tok[:i] + rest[j+1:]
+		}
+		return tok[:i]
+	}
+	return tok
+}
+
+// stripEmoji removes emoji, which TTS engines read out by name or not at all.
+func stripEmoji(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // Pictographs, emoticons, flags
+		r >= 0x2600 && r <= 0x27BF,            // Symbols and dingbats
+		r >= 0x2B00 && r <= 0x2BFF,            // Stars and arrows
+		r >= 0x2300 && r <= 0x23FF,            // Clocks and media controls
+		r == 0xFE0F, r == 0x200D, r == 0x20E3: // Variation selector, joiner, keycap
+		return true
+	}
+	return false
+}
+
+// speakURL reads a URL or email address as its host name.
+func speakURL(word string, l *locale) (string, bool) {
+	dot, at := "dot", "at"
+	if l != nil {
+		dot, at = l.words["dot"], l.words["at"]
+	}
+	spell := func(host string) string {
+		return strings.ReplaceAll(strings.Trim(host, "."), ".", " "+dot+" ")
+	}
+
+	lower := strings.ToLower(word)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		if strings.HasPrefix(lower, prefix) {
+			host := word[len(prefix):]
+			if i := strings.IndexAny(host, "/?#:"); i >= 0 {
+				host = host[:i]
+			}
+			host = strings.TrimPrefix(host, "www.")
+			if host == "" {
+				return "", false
+			}
+			return spell(host), true
+		}
+	}
+
+	if user, host, ok := strings.Cut(word, "@"); ok && user != "" && strings.Contains(host, ".") &&
+		!strings.ContainsAny(host, "@/") && !strings.HasSuffix(host, ".") {
+		return spell(user) + " " + at + " " + spell(host), true
+	}
+	return "", false
+}
+
+// abbrev expands an abbreviation. text is the word with its trailing
+// punctuation; the punctuation after the abbreviation is returned as rest.
+func (l *locale) abbrev(text string) (string, string, bool) {
+	lower := strings.ToLower(text)
+	for abbr, s := range l.abbrevs {
+		if strings.HasPrefix(lower, abbr) && strings.Trim(text[len(abbr):], trailPunct) == "" {
+			return s, text[len(abbr):], true
+		}
+	}
+	return "", "", false
+}
+
+// number is a decimal number as written.
+type number struct {
+	neg   bool
+	whole string // Digits before the decimal separator
+	frac  string // Digits after it
+}
+
+// parseNumber parses a number written with the locale's separators.
+func (l *locale) parseNumber(s string) (number, bool) {
+	var num number
+	switch {
+	case strings.HasPrefix(s, "-"):
+		num.neg, s = true, s[1:]
+	case strings.HasPrefix(s, "−"):
+		num.neg, s = true, s[len("−"):]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, string(l.decimal))
+	if hasFrac && !isDigits(frac) {
+		return num, false
+	}
+	groups := strings.Split(whole, string(l.group))
+	for i, g := range groups {
+		if !isDigits(g) || (i > 0 && len(g) != 3) || (i == 0 && len(groups) > 1 && len(g) > 3) {
+			return num, false
+		}
+	}
+	num.whole, num.frac = strings.Join(groups, ""), frac
+	return num, true
+}
+
+// value returns the whole part of a number.
+func (num number) value() (int64, bool) {
+	v, err := strconv.ParseInt(num.whole, 10, 64)
+	return v, err == nil && len(num.whole) <= 15
+}
+
+// isOne reports whether a number is exactly one, which takes a singular noun.
+func (num number) isOne() bool {
+	return !num.neg && strings.TrimLeft(num.whole, "0") == "1" && strings.Trim(num.frac, "0") == ""
+}
+
+// read reads out a number.
+func (l *locale) read(num number) string {
+	var s string
+	v, ok := num.value()
+	if !ok || (len(num.whole) > 1 && num.whole[0] == '0') {
+		s = l.digits(num.whole)
+	} else {
+		s = l.cardinal(v)
+	}
+	if num.frac != "" {
+		s += " " + l.words["point"] + " " + l.digits(num.frac)
+	}
+	if num.neg {
+		s = l.words["minus"] + " " + s
+	}
+	return s
+}
+
+// digits reads digits one at a time.
+func (l *locale) digits(s string) string {
+	words := make([]string, 0, len(s))
+	for _, c := range s {
+		words = append(words, l.cardinal(int64(c-'0')))
+	}
+	return strings.Join(words, " ")
+}
+
+// money reads an amount of money: "$5", "$1,234.56", "$1.5M", "5 €",
+// "10 EUR" or "$2 billion".
+func (l *locale) money(core, trail, next string) (string, bool, bool) {
+	var cur currency
+	var amount string
+	var ok bool
+	r, size := utf8.DecodeRuneInString(core)
+	if strings.ContainsRune(currencies, r) {
+		cur, ok = l.currencies[string(r)]
+		amount = core[size:]
+	} else if r, size := utf8.DecodeLastRuneInString(core); strings.ContainsRune(currencies, r) {
+		cur, ok = l.currencies[string(r)]
+		amount = core[:len(core)-size]
+	}
+
+	// A scale written after the amount, as in "$1.5M" or "$2 billion"
+	var scale *unit
+	usedNext := false
+	if ok {
+		for key, u := range l.scales {
+			if strings.HasSuffix(amount, key) && len(amount) > len(key) {
+				if _, isNum := l.parseNumber(amount[:len(amount)-len(key)]); isNum {
+					u := u
+					scale, amount = &u, amount[:len(amount)-len(key)]
+					break
+				}
+			}
+		}
+		if u, isScale := l.scales[next]; scale == nil && isScale && !u.attached && trail == "" {
+			scale, usedNext = &u, true
+		}
+	} else if c, isCur := l.currencies[next]; isCur && trail == "" {
+		// An amount followed by its currency, as in "5 €" or "10 EUR"
+		cur, amount, ok, usedNext = c, core, true, true
+	}
+	if !ok {
+		return "", false, false
+	}
+	num, isNum := l.parseNumber(amount)
+	if !isNum || num.whole == "" {
+		return "", false, false
+	}
+
+	if scale != nil {
+		return l.read(num) + " " + scale.many + " " + cur.many, usedNext, true
+	}
+	if cur.minorMany == "" || len(num.frac) > 2 {
+		name := cur.many
+		if num.isOne() {
+			name = cur.one
+		}
+		return l.read(num) + " " + name, usedNext, true
+	}
+
+	major, valid := num.value()
+	if !valid {
+		return "", false, false
+	}
+	minor := int64(0)
+	if num.frac != "" {
+		minor, _ = strconv.ParseInt((num.frac + "0")[:2], 10, 64)
+	}
+	var parts []string
+	if major > 0 || minor == 0 {
+		name := cur.many
+		if major == 1 {
+			name = cur.one
+		}
+		parts = append(parts, l.count(major, cur.feminine)+" "+name)
+	}
+	if minor > 0 {
+		name := cur.minorMany
+		if minor == 1 {
+			name = cur.minorOne
+		}
+		parts = append(parts, l.count(minor, false)+" "+name)
+	}
+	s := strings.Join(parts, " "+l.words["and"]+" ")
+	if num.neg {
+		s = l.words["minus"] + " " + s
+	}
+	return s, usedNext, true
+}
+
+// clock reads a time of day: "3:30", "14:05", "3:30pm", "9am", "3:30 PM"
+// or "14:30 Uhr".
+func (l *locale) clock(core, trail, next string) (string, bool, bool) {
+	lower := strings.ToLower(core)
+	suffix := ""
+	for _, s := range ampm {
+		if l == english && strings.HasSuffix(lower, s) && len(lower) > len(s) {
+			suffix, lower = s, lower[:len(lower)-len(s)]
+			break
+		}
+	}
+
+	usedNext := false
+	if trail == "" {
+		if suffix == "" && l == english && slices.Contains(ampm, strings.ToLower(next)) {
+			suffix, usedNext = strings.ToLower(next), true
+		} else if l == german && next == "Uhr" {
+			usedNext = true
+		}
+	}
+
+	hourText, minuteText, hasColon := strings.Cut(lower, ":")
+	if !isDigits(hourText) || len(hourText) > 2 {
+		return "", false, false
+	}
+	if !hasColon {
+		minuteText = "00"
+		if suffix == "" && !usedNext {
+			return "", false, false // A plain number
+		}
+	}
+	if len(minuteText) != 2 || !isDigits(minuteText) {
+		return "", false, false
+	}
+	hour, _ := strconv.Atoi(hourText)
+	minute, _ := strconv.Atoi(minuteText)
+	if hour > 24 || minute > 59 || (suffix != "" && (hour == 0 || hour > 12)) {
+		return "", false, false
+	}
+	if suffix != "" {
+		suffix = strings.ToUpper(strings.ReplaceAll(suffix, ".", ""))
+	}
+	return l.time(l, hour, minute, suffix), usedNext, true
+}
+
+// dateWord reads a numeric date: "2024-03-15" in any locale, "3/15/2024"
+// in English and "15.03.2024" in German.
+func (n *textNormalizer) dateWord(core string) (string, bool) {
+	l := n.loc
+	var parts []string
+	var y, m, d int
+	switch {
+	case strings.Count(core, "-") == 2:
+		parts = strings.Split(core, "-")
+		y, m, d = 0, 1, 2
+	case l == english && strings.Count(core, "/") == 2:
+		parts = strings.Split(core, "/")
+		y, m, d = 2, 0, 1
+	case l == german && strings.Count(core, ".") == 2:
+		parts = strings.Split(core, ".")
+		y, m, d = 2, 1, 0
+	default:
+		return "", false
+	}
+	if len(parts[y]) != 4 || len(parts[m]) > 2 || len(parts[d]) > 2 {
+		return "", false
+	}
+	var values [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || !isDigits(p) {
+			return "", false
+		}
+		values[i] = v
+	}
+	if values[m] < 1 || values[m] > 12 || values[d] < 1 || values[d] > 31 {
+		return "", false
+	}
+	return l.date(l, values[y], values[m], n.ordinal(int64(values[d]))), true
+}
+
+// dayOrYear reads a number after a month name as a day or a year, as in
+// "March 15, 2024" or "März 2024", a year after a word such as "in" or
+// "seit", and a German day before a month name, as in "15. März". The
+// result includes the trailing punctuation to keep.
+func (n *textNormalizer) dayOrYear(core, trail, next string) (string, bool) {
+	l := n.loc
+	if !isDigits(core) {
+		return "", false
+	}
+	v, _ := strconv.Atoi(core)
+	switch {
+	case n.prev == prevMonth && l == english && len(core) <= 2 && v >= 1 && v <= 31:
+		return l.ordinal(int64(v)) + trail, true
+	case (n.prev == prevMonth || n.prev == prevDay) && len(core) == 4:
+		return l.year(int64(v)) + trail, true
+	case n.prev == prevYear && len(core) == 4 && v >= 1100 && v <= 2099 && !l.isUnit(next):
+		return l.year(int64(v)) + trail, true
+	case l == german && strings.HasPrefix(trail, ".") && l.month(next) > 0 && v >= 1 && v <= 31:
+		return n.ordinal(int64(v)) + trail[1:], true
+	}
+	return "", false
+}
+
+// isUnit reports whether a word is a unit or scale read with the number
+// before it.
+func (l *locale) isUnit(word string) bool {
+	u, isUnit := l.units[word]
+	if !isUnit {
+		u, isUnit = l.scales[word]
+	}
+	return isUnit && !u.attached
+}
+
+// ordinalWord reads an English ordinal such as "1st" or "22nd".
+func (l *locale) ordinalWord(core string) (string, bool) {
+	if l != english || len(core) < 3 {
+		return "", false
+	}
+	digits, suffix := core[:len(core)-2], strings.ToLower(core[len(core)-2:])
+	if !isDigits(digits) || len(digits) > 9 {
+		return "", false
+	}
+	v, _ := strconv.ParseInt(digits, 10, 64)
+	want := "th"
+	if v%100 < 11 || v%100 > 13 {
+		switch v % 10 {
+		case 1:
+			want = "st"
+		case 2:
+			want = "nd"
+		case 3:
+			want = "rd"
+		}
+	}
+	if suffix != want {
+		return "", false
+	}
+	return l.ordinal(v), true
+}
+
+// measure reads a number with a unit or scale, written together ("5km",
+// "20°C", "50%", "10k") or as the next word ("5 km", "3 Mio."). It returns
+// the unit when it was the next word.
+func (l *locale) measure(core, next string) (string, *unit, bool) {
+	if num, ok := l.parseNumber(core); ok {
+		if u, isUnit := l.units[next]; isUnit && !u.attached {
+			return l.quantity(num, u), &u, true
+		}
+		if u, isScale := l.scales[next]; isScale && !u.attached {
+			return l.read(num) + " " + u.many, &u, true
+		}
+		return "", nil, false
+	}
+
+	// Try the longest unit written directly after the number
+	best := ""
+	var bestUnit unit
+	for _, table := range []map[string]unit{l.units, l.scales} {
+		for key, u := range table {
+			if len(key) > len(best) && len(core) > len(key) && strings.HasSuffix(core, key) {
+				if _, ok := l.parseNumber(core[:len(core)-len(key)]); ok {
+					best, bestUnit = key, u
+				}
+			}
+		}
+	}
+	if best == "" {
+		return "", nil, false
+	}
+	num, _ := l.parseNumber(core[:len(core)-len(best)])
+	if _, isScale := l.scales[best]; isScale {
+		return l.read(num) + " " + bestUnit.many, nil, true
+	}
+	return l.quantity(num, bestUnit), nil, true
+}
+
+// quantity reads a number of units.
+func (l *locale) quantity(num number, u unit) string {
+	if num.isOne() {
+		return l.one(u.feminine) + " " + u.one
+	}
+	return l.read(num) + " " + u.many
+}
+
+// digitGroups reads digits in groups, as in phone numbers such as
+// "555-1234" or "800-555-0199". Groups are split by hyphens; numbers
+// written like this are not ranges when there are more than two groups, a
+// group starts with zero, or a group of three digits is followed by more.
+func (l *locale) digitGroups(core string) (string, bool) {
+	groups := strings.Split(core, "-")
+	if len(groups) < 2 {
+		return "", false
+	}
+	phone := len(groups) > 2 || (len(groups[0]) == 3 && len(groups[1]) > 3)
+	for _, g := range groups {
+		if !isDigits(g) {
+			return "", false
+		}
+		phone = phone || (len(g) > 1 && g[0] == '0')
+	}
+	if !phone {
+		return "", false
+	}
+	spoken := make([]string, len(groups))
+	for i, g := range groups {
+		spoken[i] = l.digits(g)
+	}
+	return strings.Join(spoken, ", "), true
+}
+
+// numeric reads a plain number, a fraction or a range of numbers.
+func (l *locale) numeric(core string) (string, bool) {
+	if num, ok := l.parseNumber(core); ok {
+		return l.read(num), true
+	}
+	// A proper fraction such as "1/2" or "3/4"
+	if a, b, ok := strings.Cut(core, "/"); ok && isDigits(a) && isDigits(b) && len(a) <= 2 && len(b) <= 2 {
+		num, _ := strconv.ParseInt(a, 10, 64)
+		den, _ := strconv.ParseInt(b, 10, 64)
+		if num >= 1 && num < den && den <= 10 {
+			return l.fraction(num, den), true
+		}
+	}
+	if prefix, ok := l.prefixWord[core[0]]; ok && len(core) > 1 {
+		if num, ok := l.parseNumber(core[1:]); ok {
+			return prefix + " " + l.read(num), true
+		}
+	}
+	// A range such as "5-10" or "10–20%"
+	if i := strings.IndexAny(core[1:], dashes); i >= 0 {
+		i++
+		_, size := utf8.DecodeRuneInString(core[i:])
+		from, ok := l.parseNumber(core[:i])
+		if !ok {
+			return "", false
+		}
+		to, _, ok := l.measure(core[i+size:], "")
+		if !ok {
+			var num number
+			if num, ok = l.parseNumber(core[i+size:]); !ok {
+				return "", false
+			}
+			to = l.read(num)
+		}
+		return l.read(from) + " " + l.words["to"] + " " + to, true
+	}
+	return "", false
+}
+
+// symbol reads a symbol that stands alone as a word.
+func (l *locale) symbol(core string) (string, bool) {
+	switch core {
+	case "&":
+		return l.words["and"], true
+	case "+":
+		return l.words["plus"], true
+	case "=":
+		return l.words["equals"], true
+	}
+	return "", false
+}
+
+// acronym spells out an acronym such as "API" or "HTML" letter by letter.
+// Short all-caps words and acronyms that are read as words, such as "NASA"
+// or "JSON", are kept.
+func (l *locale) acronym(core string) (string, bool) {
+	word, plural := core, ""
+	if strings.HasSuffix(word, "s") {
+		word, plural = word[:len(word)-1], "s"
+	}
+	if len(word) < 2 || len(word) > 5 || l.spoken[word] {
+		return "", false
+	}
+	vowels := false
+	for _, c := range word {
+		if c < 'A' || c > 'Z' {
+			return "", false
+		}
+		vowels = vowels || strings.ContainsRune("AEIOU", c)
+	}
+	if len(word) >= 4 && vowels {
+		return "", false
+	}
+	if plural != "" {
+		plural = "'s"
+	}
+	return strings.Join(strings.Split(word, ""), " ") + plural, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
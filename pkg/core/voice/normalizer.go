@@ -0,0 +1,26 @@
+package voice
+
+import (
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
+)
+
+// NewNormalizer creates the text normalizer for one response, or returns
+// nil when there is no voice output or normalization is disabled. The
+// locale defaults to the input language.
+func NewNormalizer(cfg *types.VoiceConfig) normalize.Normalizer {
+	if cfg == nil || cfg.Output == nil {
+		return nil
+	}
+	var ncfg normalize.Config
+	if n := cfg.Output.Normalization; n != nil {
+		if n.Disabled {
+			return nil
+		}
+		ncfg = normalize.Config{Locale: n.Locale, Dictionary: n.Dictionary}
+	}
+	if ncfg.Locale == "" && cfg.Input != nil {
+		ncfg.Locale = cfg.Input.Language
+	}
+	return normalize.New(ncfg)
+}
@@ -5,18 +5,37 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 
+	"github.com/vango-go/vai/pkg/core/audio"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 	"github.com/vango-go/vai/pkg/core/voice/stt"
 	"github.com/vango-go/vai/pkg/core/voice/tts"
 )
 
+// Errors returned when a pipeline has no provider for a direction, such as
+// when only STT or only TTS provider keys are set.
+var (
+	ErrNoSTTProvider = errors.New("no STT provider configured")
+	ErrNoTTSProvider = errors.New("no TTS provider configured")
+)
+
 // Pipeline handles STT and TTS for message flows.
 type Pipeline struct {
 	sttProvider stt.Provider
 	ttsProvider tts.Provider
+
+	// Failover lists and their health, set by NewPipelineWithFailover
+	sttProviders []stt.Provider
+	ttsProviders []tts.Provider
+	sttHealth    *healthTracker
+	ttsHealth    *healthTracker
 }
 
 // NewPipeline creates a new voice pipeline with Cartesia providers.
@@ -35,25 +54,96 @@ func NewPipelineWithProviders(sttProvider stt.Provider, ttsProvider tts.Provider
 	}
 }
 
-// STTProvider returns the current STT provider.
-func (p *Pipeline) STTProvider() stt.Provider {
-	return p.sttProvider
+// NewPipelineWithFailover creates a voice pipeline that tries the given
+// providers in order. A provider that fails is skipped in favour of the next
+// one and tried last for a short while, so an outage at one provider does
+// not fail requests. Streaming TTS contexts fail over as long as no audio
+// has been produced. Models and voices may be named "provider/value" to
+// prefer a provider; the other providers then use their defaults.
+func NewPipelineWithFailover(sttProviders []stt.Provider, ttsProviders []tts.Provider) *Pipeline {
+	p := &Pipeline{
+		sttProviders: append([]stt.Provider(nil), sttProviders...),
+		ttsProviders: append([]tts.Provider(nil), ttsProviders...),
+		sttHealth:    newHealthTracker(),
+		ttsHealth:    newHealthTracker(),
+	}
+	if len(p.sttProviders) > 0 {
+		p.sttProvider = &sttFailover{providers: p.sttProviders, health: p.sttHealth}
+	}
+	if len(p.ttsProviders) > 0 {
+		p.ttsProvider = &ttsFailover{providers: p.ttsProviders, health: p.ttsHealth}
+	}
+	return p
+}
+
+// ProviderKeys returns the key lookup for stt.ProvidersFromKeys and
+// tts.ProvidersFromKeys. A provider's key comes from keys, else from the
+// environment (<PROVIDER>_API_KEY). For the local whisper-cpp and piper
+// servers it is the server URL, from WHISPER_CPP_URL or PIPER_URL.
+func ProviderKeys(keys map[string]string) func(provider string) string {
+	return func(provider string) string {
+		if key := keys[provider]; key != "" {
+			return key
+		}
+		switch provider {
+		case "whisper-cpp":
+			return os.Getenv("WHISPER_CPP_URL")
+		case "piper":
+			return os.Getenv("PIPER_URL")
+		}
+		return os.Getenv(strings.ToUpper(provider) + "_API_KEY")
+	}
+}
+
+// ProviderStatus returns the health of the providers of a pipeline created
+// with NewPipelineWithFailover, STT providers first, each in order.
+func (p *Pipeline) ProviderStatus() []ProviderStatus {
+	var status []ProviderStatus
+	for _, sp := range p.sttProviders {
+		status = append(status, p.sttHealth.status("stt", sp.Name()))
+	}
+	for _, tp := range p.ttsProviders {
+		status = append(status, p.ttsHealth.status("tts", tp.Name()))
+	}
+	return status
+}
+
+// STTProvider returns the current STT provider, or ErrNoSTTProvider.
+func (p *Pipeline) STTProvider() (stt.Provider, error) {
+	if p.sttProvider == nil {
+		return nil, ErrNoSTTProvider
+	}
+	return p.sttProvider, nil
 }
 
-// TTSProvider returns the current TTS provider.
-func (p *Pipeline) TTSProvider() tts.Provider {
-	return p.ttsProvider
+// TTSProvider returns the current TTS provider, or ErrNoTTSProvider.
+func (p *Pipeline) TTSProvider() (tts.Provider, error) {
+	if p.ttsProvider == nil {
+		return nil, ErrNoTTSProvider
+	}
+	return p.ttsProvider, nil
 }
 
 // ProcessInputAudio transcribes audio content blocks in messages.
 // It returns the processed messages with audio blocks replaced by text,
-// and the concatenated transcript of all audio blocks.
+// and the transcript of the last audio block.
 func (p *Pipeline) ProcessInputAudio(ctx context.Context, messages []types.Message, cfg *types.VoiceConfig) ([]types.Message, string, error) {
+	result, trans, err := p.TranscribeInput(ctx, messages, cfg)
+	if err != nil || trans == nil {
+		return result, "", err
+	}
+	return result, trans.Text, nil
+}
+
+// TranscribeInput is like ProcessInputAudio, but returns the full transcript
+// of the last audio block, including the provider that produced it. The
+// transcript is nil when there was no audio to transcribe.
+func (p *Pipeline) TranscribeInput(ctx context.Context, messages []types.Message, cfg *types.VoiceConfig) ([]types.Message, *stt.Transcript, error) {
 	if cfg == nil || cfg.Input == nil {
-		return messages, "", nil // No voice input config
+		return messages, nil, nil // No voice input config
 	}
 
-	var transcript string
+	var transcript *stt.Transcript
 	result := make([]types.Message, 0, len(messages))
 
 	for _, msg := range messages {
@@ -67,24 +157,31 @@ func (p *Pipeline) ProcessInputAudio(ctx context.Context, messages []types.Messa
 				continue
 			}
 
+			if p.sttProvider == nil {
+				return nil, nil, ErrNoSTTProvider
+			}
+
 			// Decode audio
 			audioData, err := base64.StdEncoding.DecodeString(audioBlock.Source.Data)
 			if err != nil {
-				return nil, "", fmt.Errorf("decode audio: %w", err)
+				return nil, nil, fmt.Errorf("decode audio: %w", err)
 			}
 
+			// Convert float, G.711 and high sample rate audio to 16-bit PCM
+			audioData, format := normalizeInputAudio(audioData, audioBlock.Source.MediaType)
+
 			// Transcribe
 			trans, err := p.sttProvider.Transcribe(ctx, bytes.NewReader(audioData), stt.TranscribeOptions{
 				Model:      cfg.Input.Model,
 				Language:   cfg.Input.Language,
-				Format:     getFormatFromMediaType(audioBlock.Source.MediaType),
+				Format:     format,
 				Timestamps: true,
 			})
 			if err != nil {
-				return nil, "", fmt.Errorf("transcribe: %w", err)
+				return nil, nil, fmt.Errorf("transcribe: %w", err)
 			}
 
-			transcript = trans.Text
+			transcript = trans
 
 			// Replace audio with text
 			newBlocks = append(newBlocks, types.TextBlock{
@@ -104,44 +201,92 @@ func (p *Pipeline) ProcessInputAudio(ctx context.Context, messages []types.Messa
 
 // SynthesizeResponse converts text response to audio.
 func (p *Pipeline) SynthesizeResponse(ctx context.Context, text string, cfg *types.VoiceConfig) ([]byte, error) {
+	synth, err := p.Synthesize(ctx, text, cfg)
+	if err != nil || synth == nil {
+		return nil, err
+	}
+	return synth.Audio, nil
+}
+
+// Synthesize is like SynthesizeResponse, but returns the full synthesis,
+// including the provider that produced it. It returns nil when there is no
+// voice output config or no text.
+func (p *Pipeline) Synthesize(ctx context.Context, text string, cfg *types.VoiceConfig) (*tts.Synthesis, error) {
 	if cfg == nil || cfg.Output == nil {
 		return nil, nil
 	}
 
+	if n := NewNormalizer(cfg); n != nil {
+		text = strings.TrimSpace(n.Write(text) + n.Flush())
+	}
 	if text == "" {
 		return nil, nil
 	}
 
-	// Use configured sample rate or default to 44100 Hz
-	sampleRate := cfg.Output.SampleRate
+	synth, err := p.synthesize(ctx, text, cfg.Output)
+	if err != nil {
+		return nil, fmt.Errorf("synthesize: %w", err)
+	}
+
+	return synth, nil
+}
+
+// synthesize runs TTS for one piece of text. G.711 output formats are
+// requested from the provider as PCM and encoded locally.
+func (p *Pipeline) synthesize(ctx context.Context, text string, out *types.VoiceOutputConfig) (*tts.Synthesis, error) {
+	if p.ttsProvider == nil {
+		return nil, ErrNoTTSProvider
+	}
+	encoding, isG711 := g711Encoding(out.Format)
+
+	// Use configured sample rate or default to 44100 Hz (8000 Hz for telephony)
+	sampleRate := out.SampleRate
 	if sampleRate == 0 {
 		sampleRate = 44100
+		if isG711 {
+			sampleRate = 8000
+		}
+	}
+
+	format := out.Format
+	if isG711 {
+		format = types.VoiceFormatPCM
 	}
 
 	synth, err := p.ttsProvider.Synthesize(ctx, text, tts.SynthesizeOptions{
-		Voice:      cfg.Output.Voice,
-		Speed:      cfg.Output.Speed,
-		Volume:     cfg.Output.Volume,
-		Emotion:    cfg.Output.Emotion,
-		Format:     cfg.Output.Format,
+		Voice:      out.Voice,
+		Speed:      out.Speed,
+		Volume:     out.Volume,
+		Emotion:    out.Emotion,
+		Format:     format,
 		SampleRate: sampleRate,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("synthesize: %w", err)
+	if err != nil || !isG711 {
+		return synth, err
 	}
 
-	return synth.Audio, nil
+	encoded, err := audio.Convert(synth.Audio, audio.PCM16(sampleRate, 1),
+		audio.Format{Encoding: encoding, SampleRate: sampleRate, Channels: 1})
+	if err != nil {
+		return nil, err
+	}
+	return &tts.Synthesis{Audio: encoded, Format: out.Format, Duration: synth.Duration, Provider: synth.Provider}, nil
 }
 
-// StreamingSynthesizer handles streaming TTS for chunked text.
+// StreamingSynthesizer handles streaming TTS for chunked text. Chunks are
+// synthesized one at a time, so their audio arrives in order.
 type StreamingSynthesizer struct {
 	pipeline *Pipeline
 	cfg      *types.VoiceConfig
-	buffer   *SentenceBuffer
+	norm     normalize.Normalizer // nil when text is spoken as written
+	chunker  chunk.Chunker
 	chunks   chan AudioChunk
-	done     chan struct{}
-	wg       sync.WaitGroup
+	wake     chan struct{} // Signals the worker that text is queued
 	ctx      context.Context
+
+	mu      sync.Mutex
+	pending []string // Text chunks waiting for synthesis
+	closed  bool
 }
 
 // AudioChunk represents a chunk of synthesized audio.
@@ -150,61 +295,100 @@ type AudioChunk struct {
 	Format string
 }
 
+// DefaultSynthesizerChunking synthesizes one sentence at a time.
+var DefaultSynthesizerChunking = chunk.Config{Strategy: chunk.Sentence}
+
 // NewStreamingSynthesizer creates a synthesizer for streaming text.
-func (p *Pipeline) NewStreamingSynthesizer(ctx context.Context, cfg *types.VoiceConfig) *StreamingSynthesizer {
-	return &StreamingSynthesizer{
+// Text is chunked as set by cfg.Output.Chunking, else by
+// DefaultSynthesizerChunking. It returns an error for invalid settings.
+func (p *Pipeline) NewStreamingSynthesizer(ctx context.Context, cfg *types.VoiceConfig) (*StreamingSynthesizer, error) {
+	chunker, err := chunk.New(ChunkConfig(outputChunking(cfg), DefaultSynthesizerChunking))
+	if err != nil {
+		return nil, fmt.Errorf("chunking: %w", err)
+	}
+	s := &StreamingSynthesizer{
 		pipeline: p,
 		cfg:      cfg,
-		buffer:   NewSentenceBuffer(),
+		norm:     NewNormalizer(cfg),
+		chunker:  chunker,
 		chunks:   make(chan AudioChunk, 10),
-		done:     make(chan struct{}),
+		wake:     make(chan struct{}, 1),
 		ctx:      ctx,
 	}
+	go s.run()
+	return s, nil
 }
 
 // AddText adds text to the synthesizer.
-// Complete sentences are immediately synthesized.
+// Complete chunks are queued for synthesis.
 func (s *StreamingSynthesizer) AddText(text string) {
-	sentences := s.buffer.Add(text)
+	if s.norm != nil {
+		text = s.norm.Write(text)
+	}
+	s.synthesizeChunk(s.chunker.Add(text))
+}
 
-	// Use configured sample rate or default to 44100 Hz
-	sampleRate := s.cfg.Output.SampleRate
-	if sampleRate == 0 {
-		sampleRate = 44100
+// synthesizeChunk queues a chunk of text for synthesis.
+func (s *StreamingSynthesizer) synthesizeChunk(text string) {
+	if text == "" {
+		return
 	}
+	s.mu.Lock()
+	s.pending = append(s.pending, text)
+	s.mu.Unlock()
+	s.signal()
+}
 
-	for _, sentence := range sentences {
-		s.wg.Add(1)
-		go func(sent string) {
-			defer s.wg.Done()
+// signal wakes the worker.
+func (s *StreamingSynthesizer) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
 
-			synth, err := s.pipeline.ttsProvider.Synthesize(s.ctx, sent, tts.SynthesizeOptions{
-				Voice:      s.cfg.Output.Voice,
-				Speed:      s.cfg.Output.Speed,
-				Volume:     s.cfg.Output.Volume,
-				Emotion:    s.cfg.Output.Emotion,
-				Format:     s.cfg.Output.Format,
-				SampleRate: sampleRate,
-			})
-			if err != nil {
+// run synthesizes the queued chunks in order until Close, or until the
+// context is done, then closes the chunks channel.
+func (s *StreamingSynthesizer) run() {
+	defer close(s.chunks)
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
 				return
 			}
-
 			select {
-			case s.chunks <- AudioChunk{Data: synth.Audio, Format: synth.Format}:
-			case <-s.done:
+			case <-s.wake:
 			case <-s.ctx.Done():
+				return
 			}
-		}(sentence)
+			continue
+		}
+		text := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		synth, err := s.pipeline.synthesize(s.ctx, text, s.cfg.Output)
+		if err != nil {
+			continue
+		}
+
+		select {
+		case s.chunks <- AudioChunk{Data: synth.Audio, Format: synth.Format}:
+		case <-s.ctx.Done():
+			return
+		}
 	}
 }
 
 // Flush synthesizes any remaining text.
 func (s *StreamingSynthesizer) Flush() {
-	remaining := s.buffer.Flush()
-	if remaining != "" {
-		s.AddText(remaining + ".")
+	if s.norm != nil {
+		s.synthesizeChunk(s.chunker.Add(s.norm.Flush()))
 	}
+	s.synthesizeChunk(s.chunker.Flush())
 }
 
 // Chunks returns the channel of audio chunks.
@@ -212,11 +396,13 @@ func (s *StreamingSynthesizer) Chunks() <-chan AudioChunk {
 	return s.chunks
 }
 
-// Close waits for all synthesis to complete and closes the channel.
+// Close ends the input. The chunks channel is closed once the queued
+// chunks have been synthesized and read, or when the context is done.
 func (s *StreamingSynthesizer) Close() {
-	close(s.done)
-	s.wg.Wait()
-	close(s.chunks)
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
 }
 
 func getFormatFromMediaType(mediaType string) string {
@@ -244,13 +430,17 @@ func (p *Pipeline) NewStreamingTTSContext(ctx context.Context, cfg *types.VoiceC
 	if cfg == nil || cfg.Output == nil {
 		return nil, fmt.Errorf("voice output config required")
 	}
+	if p.ttsProvider == nil {
+		return nil, ErrNoTTSProvider
+	}
 
 	opts := tts.StreamingContextOptions{
-		Voice:   cfg.Output.Voice,
-		Speed:   cfg.Output.Speed,
-		Volume:  cfg.Output.Volume,
-		Emotion: cfg.Output.Emotion,
-		Format:  cfg.Output.Format,
+		Voice:      cfg.Output.Voice,
+		Speed:      cfg.Output.Speed,
+		Volume:     cfg.Output.Volume,
+		Emotion:    cfg.Output.Emotion,
+		Format:     cfg.Output.Format,
+		SampleRate: cfg.Output.SampleRate,
 	}
 
 	return p.ttsProvider.NewStreamingContext(ctx, opts)
@@ -0,0 +1,282 @@
+package voice
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+type captureSTT struct {
+	stt.Provider
+	audio []byte
+	opts  stt.TranscribeOptions
+}
+
+func (c *captureSTT) Transcribe(ctx context.Context, r io.Reader, opts stt.TranscribeOptions) (*stt.Transcript, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	c.audio, c.opts = data, opts
+	return &stt.Transcript{Text: "hello"}, nil
+}
+
+type pcmTTS struct {
+	tts.Provider
+	opts tts.SynthesizeOptions
+}
+
+func (p *pcmTTS) Synthesize(ctx context.Context, text string, opts tts.SynthesizeOptions) (*tts.Synthesis, error) {
+	p.opts = opts
+	samples := make([]int16, opts.SampleRate/10)
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(opts.SampleRate)))
+	}
+	return &tts.Synthesis{Audio: audio.EncodePCM16(samples), Format: opts.Format}, nil
+}
+
+func audioMessage(data []byte, mediaType string) []types.Message {
+	return []types.Message{{
+		Role: "user",
+		Content: []types.ContentBlock{types.AudioBlock{
+			Type: "audio",
+			Source: types.AudioSource{
+				Type:      "base64",
+				MediaType: mediaType,
+				Data:      base64.StdEncoding.EncodeToString(data),
+			},
+		}},
+	}}
+}
+
+func TestPipeline_ProcessInputAudio_NormalizesFloatWAV(t *testing.T) {
+	// 100ms of 48 kHz stereo float audio, as recorded by browsers
+	src := audio.Format{Encoding: audio.EncodingFloat32, SampleRate: 48000, Channels: 2}
+	wav, err := audio.EncodeWAV(make([]byte, src.BytesForDurationMs(100)), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sttProvider := &captureSTT{}
+	p := NewPipelineWithProviders(sttProvider, nil)
+	_, transcript, err := p.ProcessInputAudio(context.Background(), audioMessage(wav, "audio/wav"), &types.VoiceConfig{Input: &types.VoiceInputConfig{}})
+	if err != nil {
+		t.Fatalf("ProcessInputAudio() error = %v", err)
+	}
+	if transcript != "hello" {
+		t.Errorf("transcript = %q, want hello", transcript)
+	}
+
+	got, err := audio.ParseWAV(sttProvider.audio)
+	if err != nil {
+		t.Fatalf("STT input is not a WAV file: %v", err)
+	}
+	if got.Format != sttInputFormat {
+		t.Errorf("STT input format = %v, want %v", got.Format, sttInputFormat)
+	}
+	if want := sttInputFormat.BytesForDurationMs(100); len(got.Data) != want {
+		t.Errorf("STT input has %d bytes, want %d", len(got.Data), want)
+	}
+	if sttProvider.opts.Format != "wav" {
+		t.Errorf("Format = %q, want wav", sttProvider.opts.Format)
+	}
+}
+
+func TestPipeline_ProcessInputAudio_Mulaw(t *testing.T) {
+	ulaw := make([]byte, 800) // 100ms at 8 kHz
+	for i := range ulaw {
+		ulaw[i] = 0xFF
+	}
+
+	sttProvider := &captureSTT{}
+	p := NewPipelineWithProviders(sttProvider, nil)
+	if _, _, err := p.ProcessInputAudio(context.Background(), audioMessage(ulaw, "audio/pcmu;rate=8000"), &types.VoiceConfig{Input: &types.VoiceInputConfig{}}); err != nil {
+		t.Fatalf("ProcessInputAudio() error = %v", err)
+	}
+
+	got, err := audio.ParseWAV(sttProvider.audio)
+	if err != nil {
+		t.Fatalf("STT input is not a WAV file: %v", err)
+	}
+	if got.Format != sttInputFormat {
+		t.Errorf("STT input format = %v, want %v", got.Format, sttInputFormat)
+	}
+}
+
+func TestPipeline_ProcessInputAudio_PassesThroughCompressed(t *testing.T) {
+	mp3 := []byte("ID3 not really an mp3")
+
+	sttProvider := &captureSTT{}
+	p := NewPipelineWithProviders(sttProvider, nil)
+	if _, _, err := p.ProcessInputAudio(context.Background(), audioMessage(mp3, "audio/mpeg"), &types.VoiceConfig{Input: &types.VoiceInputConfig{}}); err != nil {
+		t.Fatalf("ProcessInputAudio() error = %v", err)
+	}
+	if string(sttProvider.audio) != string(mp3) {
+		t.Error("compressed audio was modified")
+	}
+	if sttProvider.opts.Format != "mp3" {
+		t.Errorf("Format = %q, want mp3", sttProvider.opts.Format)
+	}
+}
+
+func TestPipeline_SynthesizeResponse_Mulaw(t *testing.T) {
+	ttsProvider := &pcmTTS{}
+	p := NewPipelineWithProviders(nil, ttsProvider)
+
+	out, err := p.SynthesizeResponse(context.Background(), "Hello.", &types.VoiceConfig{
+		Output: &types.VoiceOutputConfig{Voice: "v", Format: types.VoiceFormatMulaw},
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeResponse() error = %v", err)
+	}
+
+	if ttsProvider.opts.Format != types.VoiceFormatPCM || ttsProvider.opts.SampleRate != 8000 {
+		t.Errorf("provider asked for %s at %d Hz, want pcm at 8000 Hz", ttsProvider.opts.Format, ttsProvider.opts.SampleRate)
+	}
+	// One byte per sample
+	if len(out) != 800 {
+		t.Errorf("got %d bytes of μ-law, want 800", len(out))
+	}
+	if s := audio.MulawDecode(out[3]); s == 0 {
+		t.Error("μ-law output decodes to silence")
+	}
+}
+
+func TestPipeline_Synthesize_NormalizesText(t *testing.T) {
+	p := NewPipelineWithFailover(nil, []tts.Provider{&fakeTTS{name: "cartesia"}})
+	cfg := &types.VoiceConfig{Output: &types.VoiceOutputConfig{Format: "pcm"}}
+
+	synth, err := p.Synthesize(context.Background(), "**Total:** $5 for the API.", cfg)
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if got, want := string(synth.Audio), "Total: five dollars for the A P I."; got != want {
+		t.Errorf("synthesized %q, want %q", got, want)
+	}
+
+	// Nothing left to say once code is dropped
+	synth, err = p.Synthesize(context.Background(), "```\nx := 1\n```", cfg)
+	if err != nil || synth != nil {
+		t.Errorf("Synthesize(code) = %v, %v, want nil", synth, err)
+	}
+
+	cfg.Output.Normalization = &types.TextNormalization{Disabled: true}
+	synth, err = p.Synthesize(context.Background(), "**$5**", cfg)
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if got := string(synth.Audio); got != "**$5**" {
+		t.Errorf("synthesized %q with normalization disabled", got)
+	}
+}
+
+func TestNewNormalizer_LocaleFromInput(t *testing.T) {
+	n := NewNormalizer(&types.VoiceConfig{
+		Input:  &types.VoiceInputConfig{Language: "de"},
+		Output: &types.VoiceOutputConfig{Normalization: &types.TextNormalization{Dictionary: map[string]string{"vai": "wei"}}},
+	})
+	if got := n.Write("vai kostet 3 € ") + n.Flush(); got != "wei kostet drei Euro " {
+		t.Errorf("normalized %q", got)
+	}
+	if NewNormalizer(&types.VoiceConfig{Input: &types.VoiceInputConfig{}}) != nil {
+		t.Error("NewNormalizer() without output config should be nil")
+	}
+}
+
+func TestPipeline_MissingProvider(t *testing.T) {
+	ctx := context.Background()
+	cfg := &types.VoiceConfig{
+		Input:  &types.VoiceInputConfig{},
+		Output: &types.VoiceOutputConfig{Format: "pcm"},
+	}
+
+	sttOnly := NewPipelineWithFailover([]stt.Provider{&captureSTT{}}, nil)
+	if _, err := sttOnly.TTSProvider(); !errors.Is(err, ErrNoTTSProvider) {
+		t.Errorf("TTSProvider() error = %v, want ErrNoTTSProvider", err)
+	}
+	if _, err := sttOnly.Synthesize(ctx, "Hello.", cfg); !errors.Is(err, ErrNoTTSProvider) {
+		t.Errorf("Synthesize() error = %v, want ErrNoTTSProvider", err)
+	}
+	if _, err := sttOnly.NewStreamingTTSContext(ctx, cfg); !errors.Is(err, ErrNoTTSProvider) {
+		t.Errorf("NewStreamingTTSContext() error = %v, want ErrNoTTSProvider", err)
+	}
+
+	ttsOnly := NewPipelineWithFailover(nil, []tts.Provider{&fakeTTS{name: "cartesia"}})
+	if _, err := ttsOnly.STTProvider(); !errors.Is(err, ErrNoSTTProvider) {
+		t.Errorf("STTProvider() error = %v, want ErrNoSTTProvider", err)
+	}
+	if _, _, err := ttsOnly.TranscribeInput(ctx, audioMessage([]byte{0, 0}, "audio/pcm"), cfg); !errors.Is(err, ErrNoSTTProvider) {
+		t.Errorf("TranscribeInput() error = %v, want ErrNoSTTProvider", err)
+	}
+	// Text needs no STT provider
+	text := []types.Message{{Role: "user", Content: "Hi"}}
+	if _, _, err := ttsOnly.TranscribeInput(ctx, text, cfg); err != nil {
+		t.Errorf("TranscribeInput(text) error = %v", err)
+	}
+}
+
+// slowFirstTTS takes longer over the first sentence than over the others.
+type slowFirstTTS struct{ fakeTTS }
+
+func (f *slowFirstTTS) Synthesize(ctx context.Context, text string, opts tts.SynthesizeOptions) (*tts.Synthesis, error) {
+	if strings.HasPrefix(text, "First") {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return &tts.Synthesis{Audio: []byte(text), Format: opts.Format}, nil
+}
+
+func TestStreamingSynthesizer_InOrder(t *testing.T) {
+	p := NewPipelineWithFailover(nil, []tts.Provider{&slowFirstTTS{fakeTTS{name: "cartesia"}}})
+	cfg := &types.VoiceConfig{Output: &types.VoiceOutputConfig{Format: "pcm"}}
+	s, err := p.NewStreamingSynthesizer(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"First sentence. ", "Second one. ", "Third one."} {
+		s.AddText(text)
+	}
+	s.Flush()
+	s.Close()
+	var got []string
+	for c := range s.Chunks() {
+		got = append(got, string(c.Data))
+	}
+	if want := []string{"First sentence.", "Second one.", "Third one."}; !slices.Equal(got, want) {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+
+	cfg.Output.Chunking = &types.TextChunking{Strategy: "paragraph"}
+	if _, err := p.NewStreamingSynthesizer(context.Background(), cfg); err == nil {
+		t.Error("NewStreamingSynthesizer() with an unknown strategy: want an error")
+	}
+}
+
+func TestProviderKeys(t *testing.T) {
+	t.Setenv("DEEPGRAM_API_KEY", "dg-env")
+	t.Setenv("OPENAI_API_KEY", "oa-env")
+	t.Setenv("PIPER_URL", "http://localhost:5000")
+	key := ProviderKeys(map[string]string{"openai": "oa-config", "cartesia": ""})
+	for provider, want := range map[string]string{
+		"openai":   "oa-config",
+		"deepgram": "dg-env",
+		"piper":    "http://localhost:5000",
+		"cartesia": os.Getenv("CARTESIA_API_KEY"),
+	} {
+		if got := key(provider); got != want {
+			t.Errorf("key(%q) = %q, want %q", provider, got, want)
+		}
+	}
+}
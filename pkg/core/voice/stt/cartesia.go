@@ -3,18 +3,12 @@ package stt
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
-	"sync"
-	"sync/atomic"
-	"time"
-
-	"github.com/gorilla/websocket"
 )
 
 const (
@@ -185,44 +179,7 @@ func (c *CartesiaProvider) convertResponse(resp cartesiaTranscriptionResponse) *
 // This is a one-shot streaming method where audio comes from an io.Reader.
 // For live audio input, use NewStreamingSTT instead.
 func (c *CartesiaProvider) TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
-	stream, err := c.NewStreamingSTT(ctx, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	// Read audio and send in chunks
-	go func() {
-		defer stream.Close()
-		buf := make([]byte, 4096) // ~85ms at 24kHz 16-bit mono
-		for {
-			n, err := audio.Read(buf)
-			if n > 0 {
-				if sendErr := stream.SendAudio(buf[:n]); sendErr != nil {
-					return
-				}
-			}
-			if err == io.EOF {
-				stream.Finalize()
-				return
-			}
-			if err != nil {
-				return
-			}
-		}
-	}()
-
-	return stream.Transcripts(), nil
-}
-
-// StreamingSTT represents a real-time streaming transcription session.
-type StreamingSTT struct {
-	conn        *websocket.Conn
-	transcripts chan TranscriptDelta
-	done        chan struct{}
-	closed      atomic.Bool
-	writeMu     sync.Mutex
-	ctx         context.Context
-	cancel      context.CancelFunc
+	return transcribeStream(ctx, c, audio, opts)
 }
 
 // NewStreamingSTT creates a new streaming STT session via WebSocket.
@@ -280,94 +237,47 @@ func (c *CartesiaProvider) NewStreamingSTT(ctx context.Context, opts TranscribeO
 	headers.Set("X-API-Key", c.apiKey)
 	headers.Set("Cartesia-Version", cartesiaVersion)
 
-	// Connect WebSocket
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, resp, err := dialer.DialContext(ctx, u.String(), headers)
+	conn, err := dialWebSocket(ctx, nil, u.String(), headers)
 	if err != nil {
-		// Try to get more details from the response
-		if resp != nil {
-			defer resp.Body.Close()
-			body, _ := io.ReadAll(resp.Body)
-			if len(body) > 0 {
-				return nil, fmt.Errorf("websocket connect (status %d): %s", resp.StatusCode, string(body))
-			}
-			return nil, fmt.Errorf("websocket connect: status %d: %w", resp.StatusCode, err)
-		}
-		return nil, fmt.Errorf("websocket connect: %w", err)
-	}
-
-	ctx, cancel := context.WithCancel(ctx)
-	s := &StreamingSTT{
-		conn:        conn,
-		transcripts: make(chan TranscriptDelta, 100),
-		done:        make(chan struct{}),
-		ctx:         ctx,
-		cancel:      cancel,
+		return nil, err
 	}
 
-	// Start read loop
-	go s.readLoop()
-
-	return s, nil
+	return newWebSocketStream(ctx, conn, wsProtocol{
+		finalize: []byte("finalize"),
+		close:    []byte("done"),
+		parse:    parseCartesiaMessage,
+	}), nil
 }
 
-func (s *StreamingSTT) readLoop() {
-	defer func() {
-		close(s.transcripts)
-		close(s.done)
-	}()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		default:
-		}
+// parseCartesiaMessage handles one message from the Cartesia STT WebSocket.
+func parseCartesiaMessage(data []byte) (TranscriptDelta, bool, error) {
+	var msg cartesiaSTTResponse
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return TranscriptDelta{}, false, nil
+	}
 
-		_, data, err := s.conn.ReadMessage()
-		if err != nil {
-			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-				// Could log error here
-			}
-			return
+	switch msg.Type {
+	case "transcript":
+		delta := TranscriptDelta{
+			Text:     msg.Text,
+			IsFinal:  msg.IsFinal,
+			Language: msg.Language,
 		}
-
-		var msg cartesiaSTTResponse
-		if err := json.Unmarshal(data, &msg); err != nil {
-			continue
+		if msg.Duration > 0 {
+			delta.Timestamp = msg.Duration
 		}
+		return delta, true, nil
 
-		switch msg.Type {
-		case "transcript":
-			delta := TranscriptDelta{
-				Text:    msg.Text,
-				IsFinal: msg.IsFinal,
-			}
-			if msg.Duration > 0 {
-				delta.Timestamp = msg.Duration
-			}
-			select {
-			case s.transcripts <- delta:
-			case <-s.done:
-				return
-			}
-
-		case "flush_done":
-			// Acknowledgment of finalize command
-			continue
-
-		case "done":
-			// Session closing
-			return
+	case "done":
+		// Session closing
+		return TranscriptDelta{}, false, io.EOF
 
-		case "error":
-			// Error occurred - could emit error through channel
-			return
-		}
+	case "error":
+		return TranscriptDelta{}, false, fmt.Errorf("cartesia error: %s", msg.Error)
 	}
+
+	// "flush_done" acknowledges a finalize command
+	return TranscriptDelta{}, false, nil
 }
 
 type cartesiaSTTResponse struct {
@@ -385,64 +295,6 @@ type cartesiaSTTResponse struct {
 	} `json:"words"`
 }
 
-// SendAudio sends audio data to the streaming STT session.
-// Audio should be in the format specified during session creation (default: pcm_s16le at 16kHz).
-func (s *StreamingSTT) SendAudio(data []byte) error {
-	if s.closed.Load() {
-		return fmt.Errorf("session closed")
-	}
-	s.writeMu.Lock()
-	defer s.writeMu.Unlock()
-	return s.conn.WriteMessage(websocket.BinaryMessage, data)
-}
-
-// SendAudioBase64 sends base64-encoded audio data.
-func (s *StreamingSTT) SendAudioBase64(data string) error {
-	decoded, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		return fmt.Errorf("decode base64: %w", err)
-	}
-	return s.SendAudio(decoded)
-}
-
-// Finalize flushes any remaining audio and signals end of input.
-// Use this when the user stops speaking but you want to keep the session open.
-func (s *StreamingSTT) Finalize() error {
-	if s.closed.Load() {
-		return fmt.Errorf("session closed")
-	}
-	s.writeMu.Lock()
-	defer s.writeMu.Unlock()
-	return s.conn.WriteMessage(websocket.TextMessage, []byte("finalize"))
-}
-
-// Transcripts returns the channel of transcript deltas.
-func (s *StreamingSTT) Transcripts() <-chan TranscriptDelta {
-	return s.transcripts
-}
-
-// Done returns a channel that's closed when the session ends.
-func (s *StreamingSTT) Done() <-chan struct{} {
-	return s.done
-}
-
-// Close closes the streaming STT session.
-func (s *StreamingSTT) Close() error {
-	if s.closed.Swap(true) {
-		return nil
-	}
-	s.cancel()
-
-	s.writeMu.Lock()
-	// Send "done" command to gracefully close
-	s.conn.WriteMessage(websocket.TextMessage, []byte("done"))
-	s.conn.WriteMessage(websocket.CloseMessage,
-		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-	s.writeMu.Unlock()
-
-	return s.conn.Close()
-}
-
 // getExtension returns the file extension for the given audio format.
 func getExtension(format string) string {
 	switch format {
@@ -0,0 +1,247 @@
+package stt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	deepgramBaseURL = "https://api.deepgram.com"
+	deepgramModel   = "nova-3"
+)
+
+// DeepgramProvider implements the STT Provider interface using Deepgram's
+// pre-recorded and live transcription APIs.
+type DeepgramProvider struct {
+	apiKey string
+	opts   options
+}
+
+// NewDeepgram creates a new Deepgram STT provider.
+func NewDeepgram(apiKey string, opts ...Option) *DeepgramProvider {
+	return &DeepgramProvider{
+		apiKey: apiKey,
+		opts:   newOptions(deepgramBaseURL, opts),
+	}
+}
+
+// Name returns the provider identifier.
+func (d *DeepgramProvider) Name() string {
+	return "deepgram"
+}
+
+// Transcribe converts audio to text using Deepgram's pre-recorded API.
+func (d *DeepgramProvider) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	q := d.query(opts)
+	q.Set("smart_format", "true")
+	if opts.Language == "" {
+		q.Set("detect_language", "true")
+	}
+	contentType := "audio/" + getExtension(opts.Format)
+	if encoding := deepgramEncoding(opts.Format); encoding != "" {
+		// Raw audio needs its format spelled out
+		q.Set("encoding", encoding)
+		q.Set("sample_rate", strconv.Itoa(defaultSampleRate(opts.SampleRate)))
+		q.Set("channels", "1")
+		contentType = "application/octet-stream"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.opts.baseURL+"/v1/listen?"+q.Encode(), audio)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+d.apiKey)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := d.opts.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("deepgram request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("deepgram error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var dgResp deepgramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dgResp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	t := &Transcript{
+		Language: opts.Language,
+		Duration: dgResp.Metadata.Duration,
+	}
+	if len(dgResp.Results.Channels) == 0 {
+		return t, nil
+	}
+	channel := dgResp.Results.Channels[0]
+	if channel.DetectedLanguage != "" {
+		t.Language = channel.DetectedLanguage
+	}
+	if len(channel.Alternatives) == 0 {
+		return t, nil
+	}
+	alt := channel.Alternatives[0]
+	t.Text = alt.Transcript
+	if opts.Timestamps {
+		for _, w := range alt.Words {
+			word := w.PunctuatedWord
+			if word == "" {
+				word = w.Word
+			}
+			t.Words = append(t.Words, Word{Word: word, Start: w.Start, End: w.End})
+		}
+	}
+	return t, nil
+}
+
+type deepgramResponse struct {
+	Metadata struct {
+		Duration float64 `json:"duration"`
+	} `json:"metadata"`
+	Results struct {
+		Channels []struct {
+			DetectedLanguage string                `json:"detected_language"`
+			Alternatives     []deepgramAlternative `json:"alternatives"`
+		} `json:"channels"`
+	} `json:"results"`
+}
+
+type deepgramAlternative struct {
+	Transcript string   `json:"transcript"`
+	Languages  []string `json:"languages"` // Spoken languages, with language=multi
+	Words      []struct {
+		Word           string  `json:"word"`
+		PunctuatedWord string  `json:"punctuated_word"`
+		Start          float64 `json:"start"`
+		End            float64 `json:"end"`
+	} `json:"words"`
+}
+
+// TranscribeStream transcribes streaming audio via Deepgram's live API.
+// For live audio input, use NewStreamingSTT instead.
+func (d *DeepgramProvider) TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
+	return transcribeStream(ctx, d, audio, opts)
+}
+
+// NewStreamingSTT creates a new streaming STT session via Deepgram's live
+// WebSocket API. Only final results are emitted, so each delta is new text.
+// Deltas carry the spoken language when Language is "multi", as live
+// sessions cannot otherwise detect it.
+func (d *DeepgramProvider) NewStreamingSTT(ctx context.Context, opts TranscribeOptions) (*StreamingSTT, error) {
+	encoding := deepgramEncoding(opts.Format)
+	if encoding == "" {
+		encoding = "linear16"
+	}
+
+	q := d.query(opts)
+	q.Set("encoding", encoding)
+	q.Set("sample_rate", strconv.Itoa(defaultSampleRate(opts.SampleRate)))
+	q.Set("channels", "1")
+	q.Set("punctuate", "true")
+	q.Set("smart_format", "true")
+	q.Set("interim_results", "false")
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Token "+d.apiKey)
+
+	conn, err := dialWebSocket(ctx, d.opts.dialer, websocketURL(d.opts.baseURL)+"/v1/listen?"+q.Encode(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var spoken bool
+	return newWebSocketStream(ctx, conn, wsProtocol{
+		finalize: []byte(`{"type":"Finalize"}`),
+		close:    []byte(`{"type":"CloseStream"}`),
+		parse: func(data []byte) (TranscriptDelta, bool, error) {
+			var msg deepgramStreamMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return TranscriptDelta{}, false, nil
+			}
+			if msg.Type != "Results" || !msg.IsFinal || len(msg.Channel.Alternatives) == 0 {
+				return TranscriptDelta{}, false, nil
+			}
+			alt := msg.Channel.Alternatives[0]
+			text := alt.Transcript
+			if text == "" {
+				return TranscriptDelta{}, false, nil
+			}
+			if spoken {
+				text = " " + text
+			}
+			spoken = true
+			delta := TranscriptDelta{Text: text, IsFinal: true, Timestamp: msg.Start + msg.Duration}
+			if len(alt.Languages) > 0 {
+				delta.Language = alt.Languages[0]
+			}
+			return delta, true, nil
+		},
+	}), nil
+}
+
+type deepgramStreamMessage struct {
+	Type     string  `json:"type"` // "Results", "Metadata", "SpeechStarted", "UtteranceEnd"
+	IsFinal  bool    `json:"is_final"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Channel  struct {
+		Alternatives []deepgramAlternative `json:"alternatives"`
+	} `json:"channel"`
+}
+
+// query returns the query parameters shared by batch and streaming requests.
+func (d *DeepgramProvider) query(opts TranscribeOptions) url.Values {
+	q := url.Values{}
+	model := opts.Model
+	if model == "" {
+		model = deepgramModel
+	}
+	q.Set("model", model)
+	if opts.Language != "" {
+		q.Set("language", opts.Language)
+	}
+	return q
+}
+
+// deepgramEncoding returns Deepgram's name for a raw audio format, or ""
+// for containers such as WAV that Deepgram detects itself.
+func deepgramEncoding(format string) string {
+	switch format {
+	case "pcm_s16le", "pcm", "linear16":
+		return "linear16"
+	case "pcm_mulaw", "mulaw":
+		return "mulaw"
+	case "pcm_alaw", "alaw":
+		return "alaw"
+	default:
+		return ""
+	}
+}
+
+// websocketURL turns an http(s) base URL into a ws(s) one.
+func websocketURL(baseURL string) string {
+	if rest, ok := strings.CutPrefix(baseURL, "https://"); ok {
+		return "wss://" + rest
+	}
+	if rest, ok := strings.CutPrefix(baseURL, "http://"); ok {
+		return "ws://" + rest
+	}
+	return baseURL
+}
+
+// defaultSampleRate returns the sample rate, defaulting to 16kHz.
+func defaultSampleRate(sampleRate int) int {
+	if sampleRate == 0 {
+		return 16000
+	}
+	return sampleRate
+}
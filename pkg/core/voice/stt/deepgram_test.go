@@ -0,0 +1,169 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestDeepgram_Transcribe(t *testing.T) {
+	var gotQuery, gotAuth, gotType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/listen" {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{
+			"metadata": {"duration": 1.5},
+			"results": {"channels": [{
+				"detected_language": "en",
+				"alternatives": [{
+					"transcript": "Hello, world.",
+					"words": [
+						{"word": "hello", "punctuated_word": "Hello,", "start": 0.1, "end": 0.4},
+						{"word": "world", "punctuated_word": "world.", "start": 0.5, "end": 0.9}
+					]
+				}]
+			}]}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewDeepgram("dg-key", WithBaseURL(srv.URL))
+	audio := tone(100)
+	tr, err := p.Transcribe(context.Background(), bytes.NewReader(audio), TranscribeOptions{
+		Model:      "nova-2",
+		Format:     "pcm_s16le",
+		SampleRate: 16000,
+		Timestamps: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAuth != "Token dg-key" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotType != "application/octet-stream" {
+		t.Errorf("Content-Type = %q", gotType)
+	}
+	for _, want := range []string{"model=nova-2", "encoding=linear16", "sample_rate=16000", "smart_format=true", "detect_language=true"} {
+		if !strings.Contains(gotQuery, want) {
+			t.Errorf("query %q missing %q", gotQuery, want)
+		}
+	}
+	if !bytes.Equal(gotBody, audio) {
+		t.Errorf("body = %d bytes, want %d", len(gotBody), len(audio))
+	}
+	if tr.Text != "Hello, world." || tr.Language != "en" || tr.Duration != 1.5 {
+		t.Errorf("transcript = %+v", tr)
+	}
+	if len(tr.Words) != 2 || tr.Words[0].Word != "Hello," || tr.Words[1].End != 0.9 {
+		t.Errorf("words = %+v", tr.Words)
+	}
+}
+
+func TestDeepgram_TranscribeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"err_msg":"Invalid credentials."}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	p := NewDeepgram("bad", WithBaseURL(srv.URL))
+	_, err := p.Transcribe(context.Background(), bytes.NewReader(nil), TranscribeOptions{Format: "wav"})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("err = %v, want 401", err)
+	}
+}
+
+func TestDeepgram_StreamingSTT(t *testing.T) {
+	var gotQuery string
+	received := make(chan int, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var n int
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				n += len(data)
+				continue
+			}
+			var control struct{ Type string }
+			json.Unmarshal(data, &control)
+			switch control.Type {
+			case "Finalize":
+				received <- n
+				for _, msg := range []string{
+					`{"type":"Metadata"}`,
+					`{"type":"Results","is_final":false,"channel":{"alternatives":[{"transcript":"hel"}]}}`,
+					`{"type":"Results","is_final":true,"start":0,"duration":0.6,"channel":{"alternatives":[{"transcript":"Hello there."}]}}`,
+					`{"type":"Results","is_final":true,"start":0.6,"duration":0.2,"channel":{"alternatives":[{"transcript":""}]}}`,
+					`{"type":"Results","is_final":true,"start":0.5,"duration":0.25,"channel":{"alternatives":[{"transcript":"How are you?","languages":["en"]}]}}`,
+				} {
+					conn.WriteMessage(websocket.TextMessage, []byte(msg))
+				}
+			case "CloseStream":
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	p := NewDeepgram("dg-key", WithBaseURL(srv.URL))
+	s, err := p.NewStreamingSTT(context.Background(), TranscribeOptions{Format: "pcm_s16le", SampleRate: 24000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	sendChunked(t, s, tone(200))
+	if err := s.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-received; n != len(tone(200)) {
+		t.Errorf("server received %d bytes, want %d", n, len(tone(200)))
+	}
+
+	deltas := collect(t, s, 2)
+	if deltas[0].Text != "Hello there." || deltas[1].Text != " How are you?" {
+		t.Fatalf("deltas = %q, %q", deltas[0].Text, deltas[1].Text)
+	}
+	if deltas[1].Timestamp != 0.75 {
+		t.Errorf("timestamp = %v, want 0.75", deltas[1].Timestamp)
+	}
+	if deltas[0].Language != "" || deltas[1].Language != "en" {
+		t.Errorf("languages = %q, %q, want the one Deepgram reports", deltas[0].Language, deltas[1].Language)
+	}
+	for _, want := range []string{"model=nova-3", "encoding=linear16", "sample_rate=24000", "interim_results=false"} {
+		if !strings.Contains(gotQuery, want) {
+			t.Errorf("query %q missing %q", gotQuery, want)
+		}
+	}
+
+	s.Close()
+	<-s.Done()
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	openAIBaseURL = "https://api.openai.com/v1"
+	openAIModel   = "whisper-1"
+)
+
+// OpenAIProvider implements the STT Provider interface using OpenAI's
+// audio transcription API (whisper-1, gpt-4o-transcribe and
+// gpt-4o-mini-transcribe).
+//
+// The API transcribes whole files, so streaming sessions cut the audio into
+// segments at pauses in speech and transcribe each segment as it ends.
+type OpenAIProvider struct {
+	apiKey string
+	opts   options
+}
+
+// NewOpenAI creates a new OpenAI STT provider.
+func NewOpenAI(apiKey string, opts ...Option) *OpenAIProvider {
+	return &OpenAIProvider{
+		apiKey: apiKey,
+		opts:   newOptions(openAIBaseURL, opts),
+	}
+}
+
+// Name returns the provider identifier.
+func (o *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Transcribe converts audio to text using OpenAI's transcription API.
+func (o *OpenAIProvider) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	file, ext, err := uploadFile(audio, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = openAIModel
+	}
+	fields := [][2]string{{"model", model}}
+	if opts.Language != "" {
+		fields = append(fields, [2]string{"language", opts.Language})
+	}
+	// Only whisper-1 reports language, duration and word timings
+	if model == "whisper-1" {
+		fields = append(fields, [2]string{"response_format", "verbose_json"})
+		if opts.Timestamps {
+			fields = append(fields, [2]string{"timestamp_granularities[]", "word"})
+		}
+	} else {
+		fields = append(fields, [2]string{"response_format", "json"})
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+o.apiKey)
+
+	var resp struct {
+		Text     string           `json:"text"`
+		Language string           `json:"language"`
+		Duration float64          `json:"duration"`
+		Words    []transcriptWord `json:"words"`
+	}
+	if err := postTranscription(ctx, o.opts.httpClient, o.opts.baseURL+"/audio/transcriptions", header, file, ext, fields, &resp); err != nil {
+		return nil, fmt.Errorf("openai transcription: %w", err)
+	}
+
+	t := &Transcript{
+		Text:     resp.Text,
+		Language: whisperLanguage(resp.Language),
+		Duration: resp.Duration,
+		Words:    convertWords(resp.Words),
+	}
+	if t.Language == "" {
+		t.Language = opts.Language
+	}
+	return t, nil
+}
+
+// TranscribeStream transcribes streaming audio segment by segment.
+func (o *OpenAIProvider) TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
+	return transcribeStream(ctx, o, audio, opts)
+}
+
+// NewStreamingSTT creates a streaming session that transcribes each
+// segment of speech once the speaker pauses.
+func (o *OpenAIProvider) NewStreamingSTT(ctx context.Context, opts TranscribeOptions) (*StreamingSTT, error) {
+	return newSegmentedStream(ctx, o, opts)
+}
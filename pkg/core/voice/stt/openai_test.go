@@ -0,0 +1,171 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// transcriptionRequest is a multipart upload received by a stand-in server.
+type transcriptionRequest struct {
+	header http.Header
+	fields map[string][]string
+	file   []byte
+	name   string
+}
+
+// transcriptionServer answers multipart uploads on path with the JSON
+// returned by respond.
+func transcriptionServer(t *testing.T, path string, respond func(n int) any) (*httptest.Server, func() []transcriptionRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []transcriptionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, fh, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(f)
+
+		mu.Lock()
+		reqs = append(reqs, transcriptionRequest{header: r.Header, fields: r.MultipartForm.Value, file: data, name: fh.Filename})
+		n := len(reqs)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(respond(n))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []transcriptionRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]transcriptionRequest(nil), reqs...)
+	}
+}
+
+func TestOpenAI_Transcribe(t *testing.T) {
+	srv, requests := transcriptionServer(t, "/audio/transcriptions", func(int) any {
+		return map[string]any{
+			"text":     "Hello world.",
+			"language": "english",
+			"duration": 0.1,
+			"words": []map[string]any{
+				{"word": "Hello", "start": 0.0, "end": 0.04},
+				{"word": "world", "start": 0.05, "end": 0.1},
+			},
+		}
+	})
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	tr, err := p.Transcribe(context.Background(), bytes.NewReader(tone(100)), TranscribeOptions{
+		Format:     "pcm_s16le",
+		SampleRate: 16000,
+		Language:   "en",
+		Timestamps: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := requests()[0]
+	if got := req.header.Get("Authorization"); got != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", got)
+	}
+	want := map[string]string{"model": "whisper-1", "language": "en", "response_format": "verbose_json", "timestamp_granularities[]": "word"}
+	for k, v := range want {
+		if got := req.fields[k]; len(got) != 1 || got[0] != v {
+			t.Errorf("field %s = %v, want %q", k, got, v)
+		}
+	}
+	// Raw PCM is uploaded as WAV
+	wav, err := audio.ParseWAV(req.file)
+	if err != nil {
+		t.Fatalf("upload is not WAV: %v", err)
+	}
+	if req.name != "audio.wav" || !bytes.Equal(wav.Data, tone(100)) {
+		t.Errorf("upload %s holds %d bytes of audio", req.name, len(wav.Data))
+	}
+
+	if tr.Text != "Hello world." || tr.Language != "en" || len(tr.Words) != 2 || tr.Words[1].Word != "world" {
+		t.Errorf("transcript = %+v", tr)
+	}
+}
+
+func TestOpenAI_TranscribeGPT4o(t *testing.T) {
+	srv, requests := transcriptionServer(t, "/audio/transcriptions", func(int) any {
+		return map[string]any{"text": "Hi."}
+	})
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	tr, err := p.Transcribe(context.Background(), strings.NewReader("ID3..."), TranscribeOptions{
+		Model:      "gpt-4o-transcribe",
+		Format:     "mp3",
+		Language:   "en",
+		Timestamps: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := requests()[0]
+	if req.name != "audio.mp3" || string(req.file) != "ID3..." {
+		t.Errorf("upload %s = %q", req.name, req.file)
+	}
+	if got := req.fields["response_format"]; len(got) != 1 || got[0] != "json" {
+		t.Errorf("response_format = %v, want json", got)
+	}
+	if _, ok := req.fields["timestamp_granularities[]"]; ok {
+		t.Error("timestamp_granularities sent for gpt-4o-transcribe")
+	}
+	if tr.Text != "Hi." || tr.Language != "en" {
+		t.Errorf("transcript = %+v", tr)
+	}
+}
+
+func TestOpenAI_StreamingSTT(t *testing.T) {
+	srv, requests := transcriptionServer(t, "/audio/transcriptions", func(n int) any {
+		return map[string]any{"text": []string{"", "First.", "Second."}[n]}
+	})
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	s, err := p.NewStreamingSTT(context.Background(), TranscribeOptions{Model: "gpt-4o-mini-transcribe", Format: "pcm_s16le", SampleRate: 16000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	sendChunked(t, s, tone(500))
+	sendChunked(t, s, silence(600))
+	sendChunked(t, s, tone(300))
+	s.Finalize()
+
+	deltas := collect(t, s, 2)
+	if deltas[0].Text != "First." || deltas[1].Text != " Second." {
+		t.Fatalf("deltas = %q, %q", deltas[0].Text, deltas[1].Text)
+	}
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requests, want 2", len(reqs))
+	}
+	if got := reqs[0].fields["model"]; len(got) != 1 || got[0] != "gpt-4o-mini-transcribe" {
+		t.Errorf("model = %v", got)
+	}
+	if reqs[0].name != "audio.wav" {
+		t.Errorf("segment uploaded as %s", reqs[0].name)
+	}
+}
@@ -0,0 +1,45 @@
+package stt
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// Option configures an STT provider.
+type Option func(*options)
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+}
+
+func newOptions(baseURL string, opts []Option) options {
+	o := options{baseURL: baseURL, httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithBaseURL sets the API base URL, e.g. for a proxy or a self-hosted server.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for batch requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithDialer sets the WebSocket dialer used for streaming sessions.
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+	}
+}
@@ -38,6 +38,7 @@ type Transcript struct {
 	Language string  // Detected or specified language
 	Duration float64 // Audio duration in seconds
 	Words    []Word  // Word-level details (if timestamps requested)
+	Provider string  // Provider that produced the transcript (set by Registry and failover)
 }
 
 // Word represents a single transcribed word with timing.
@@ -52,4 +53,5 @@ type TranscriptDelta struct {
 	Text      string  // Partial transcript
 	IsFinal   bool    // True if this is a final segment
 	Timestamp float64 // Timestamp in seconds
+	Language  string  // Detected language, if the provider reports it
 }
@@ -0,0 +1,98 @@
+package stt
+
+import (
+	"context"
+	"io"
+
+	"github.com/vango-go/vai/pkg/core/voice/internal/registry"
+)
+
+// Registry routes transcription to providers by model name. Models are
+// named "provider/model", e.g. "deepgram/nova-3", "openai/whisper-1" or
+// "whisper-cpp/local"; a name without a provider, such as "ink-whisper",
+// goes to the default provider.
+//
+// A Registry is itself a Provider, so it can be used wherever one
+// provider is expected.
+type Registry struct {
+	*registry.Registry[Provider]
+}
+
+var _ Provider = (*Registry)(nil)
+
+// NewRegistry creates a registry of the given providers. The first one is
+// the default.
+func NewRegistry(providers ...Provider) *Registry {
+	return &Registry{registry.New[Provider]("stt", "model", providers...)}
+}
+
+// Name returns the provider identifier.
+func (r *Registry) Name() string {
+	return "registry"
+}
+
+// Transcribe converts audio to text with the provider for opts.Model.
+func (r *Registry) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	p, opts, err := r.route(opts)
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.Transcribe(ctx, audio, opts)
+	if err == nil && t.Provider == "" {
+		t.Provider = p.Name()
+	}
+	return t, err
+}
+
+// TranscribeStream transcribes streaming audio with the provider for opts.Model.
+func (r *Registry) TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
+	p, opts, err := r.route(opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.TranscribeStream(ctx, audio, opts)
+}
+
+// NewStreamingSTT creates a streaming session with the provider for opts.Model.
+func (r *Registry) NewStreamingSTT(ctx context.Context, opts TranscribeOptions) (*StreamingSTT, error) {
+	p, opts, err := r.route(opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.NewStreamingSTT(ctx, opts)
+}
+
+func (r *Registry) route(opts TranscribeOptions) (Provider, TranscribeOptions, error) {
+	p, model, err := r.Resolve(opts.Model)
+	if err != nil {
+		return nil, opts, err
+	}
+	opts.Model = model
+	return p, opts, nil
+}
+
+// ProvidersFromKeys returns every provider that has a key, in order of
+// preference. key returns the API key for "cartesia", "deepgram" and
+// "openai", and the server URL for "whisper-cpp".
+func ProvidersFromKeys(key func(provider string) string) []Provider {
+	var providers []Provider
+	if k := key("cartesia"); k != "" {
+		providers = append(providers, NewCartesia(k))
+	}
+	if k := key("deepgram"); k != "" {
+		providers = append(providers, NewDeepgram(k))
+	}
+	if k := key("openai"); k != "" {
+		providers = append(providers, NewOpenAI(k))
+	}
+	if k := key("whisper-cpp"); k != "" {
+		providers = append(providers, NewWhisperCpp(WithBaseURL(k)))
+	}
+	return providers
+}
+
+// NewRegistryFromKeys creates a registry of every provider that has a key.
+// Cartesia is the default when available.
+func NewRegistryFromKeys(key func(provider string) string) *Registry {
+	return NewRegistry(ProvidersFromKeys(key)...)
+}
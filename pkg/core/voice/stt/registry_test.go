@@ -0,0 +1,93 @@
+package stt
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// namedProvider records the options it is called with.
+type namedProvider struct {
+	Provider
+	name string
+	opts TranscribeOptions
+}
+
+func (p *namedProvider) Name() string { return p.name }
+
+func (p *namedProvider) Transcribe(ctx context.Context, r io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	p.opts = opts
+	return &Transcript{Text: p.name}, nil
+}
+
+func TestRegistry_RoutesByModel(t *testing.T) {
+	cartesia := &namedProvider{name: "cartesia"}
+	deepgram := &namedProvider{name: "deepgram"}
+	whisper := &namedProvider{name: "whisper-cpp"}
+	r := NewRegistry(cartesia, deepgram, whisper)
+
+	tests := []struct {
+		model     string
+		provider  *namedProvider
+		wantModel string
+	}{
+		{"deepgram/nova-3", deepgram, "nova-3"},
+		{"whisper-cpp/local", whisper, "local"},
+		{"ink-whisper", cartesia, "ink-whisper"},
+		{"", cartesia, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			tr, err := r.Transcribe(context.Background(), strings.NewReader(""), TranscribeOptions{Model: tt.model, Language: "en"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tr.Text != tt.provider.name {
+				t.Errorf("routed to %s, want %s", tr.Text, tt.provider.name)
+			}
+			if tt.provider.opts.Model != tt.wantModel || tt.provider.opts.Language != "en" {
+				t.Errorf("opts = %+v, want model %q", tt.provider.opts, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestRegistry_UnknownProvider(t *testing.T) {
+	r := NewRegistry(&namedProvider{name: "cartesia"})
+	_, err := r.Transcribe(context.Background(), strings.NewReader(""), TranscribeOptions{Model: "openai/whisper-1"})
+	if err == nil || !strings.Contains(err.Error(), `"openai"`) {
+		t.Fatalf("err = %v, want unknown provider", err)
+	}
+
+	if _, _, err := NewRegistry().Resolve("ink-whisper"); err == nil {
+		t.Fatal("empty registry resolved a model")
+	}
+}
+
+func TestRegistry_SetDefault(t *testing.T) {
+	r := NewRegistry(&namedProvider{name: "cartesia"}, &namedProvider{name: "deepgram"})
+	if err := r.SetDefault("deepgram"); err != nil {
+		t.Fatal(err)
+	}
+	p, model, err := r.Resolve("nova-3")
+	if err != nil || p.Name() != "deepgram" || model != "nova-3" {
+		t.Fatalf("Resolve = %v, %q, %v", p, model, err)
+	}
+	if err := r.SetDefault("openai"); err == nil {
+		t.Fatal("SetDefault accepted an unregistered provider")
+	}
+}
+
+func TestNewRegistryFromKeys(t *testing.T) {
+	keys := map[string]string{"deepgram": "dg", "whisper-cpp": "http://localhost:9000"}
+	r := NewRegistryFromKeys(func(provider string) string { return keys[provider] })
+
+	if got := strings.Join(r.Providers(), ","); got != "deepgram,whisper-cpp" {
+		t.Fatalf("providers = %s", got)
+	}
+	p, _, err := r.Resolve("nova-3")
+	if err != nil || p.Name() != "deepgram" {
+		t.Fatalf("default = %v, %v", p, err)
+	}
+}
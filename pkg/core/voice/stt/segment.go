@@ -0,0 +1,174 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// Segmentation settings for providers that only transcribe whole files.
+const (
+	segmentSpeechRMS = 0.01  // Chunk RMS (0-1) above which audio counts as speech
+	segmentSilenceMs = 400   // Trailing silence that ends a segment
+	segmentMaxMs     = 15000 // Longest segment before it is cut regardless
+	segmentPrerollMs = 200   // Audio kept from before speech starts
+)
+
+// segment is a stretch of PCM16 audio containing speech.
+type segment struct {
+	data  []byte
+	endMs int // Stream position of the end of the segment
+}
+
+// newSegmentedStream creates a streaming session on top of a provider's
+// batch Transcribe. Incoming audio is cut into segments at pauses in speech
+// and each segment is transcribed as a WAV file, producing one final delta.
+// Finalize cuts whatever speech is buffered. Segments without speech are
+// dropped, so silence never reaches the provider.
+func newSegmentedStream(ctx context.Context, p Provider, opts TranscribeOptions) (*StreamingSTT, error) {
+	format := audio.PCM16(defaultSampleRate(opts.SampleRate), 1)
+	var conv *audio.Converter
+	if opts.Format != "" {
+		enc, err := audio.ParseEncoding(opts.Format)
+		if err != nil {
+			return nil, err
+		}
+		if enc != audio.EncodingPCM16 {
+			from := audio.Format{Encoding: enc, SampleRate: format.SampleRate, Channels: 1}
+			if conv, err = audio.NewConverter(from, format); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := NewStreamingSTT()
+	segments := make(chan segment, 16)
+
+	var (
+		mu        sync.Mutex
+		closed    bool
+		buf       []byte // PCM16 audio of the current segment
+		speech    bool   // Whether buf contains speech
+		silenceMs int    // Silence since the last speech in buf
+		posMs     int    // Stream position of the end of buf
+	)
+	cut := func() {
+		if speech {
+			segments <- segment{data: buf, endMs: posMs}
+		}
+		buf, speech, silenceMs = nil, false, 0
+	}
+
+	s.SendFunc = func(data []byte) error {
+		if conv != nil {
+			var err error
+			if data, err = conv.Convert(data); err != nil {
+				return err
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return ErrSessionClosed
+		}
+
+		durationMs := format.DurationMs(len(data))
+		buf = append(buf, data...)
+		posMs += durationMs
+		switch {
+		case chunkRMS(data) >= segmentSpeechRMS:
+			speech, silenceMs = true, 0
+		case speech:
+			silenceMs += durationMs
+		default:
+			// Keep only a little audio from before the speech
+			if keep := format.BytesForDurationMs(segmentPrerollMs); len(buf) > keep {
+				buf = append([]byte(nil), buf[len(buf)-keep:]...)
+			}
+		}
+		if speech && (silenceMs >= segmentSilenceMs || format.DurationMs(len(buf)) >= segmentMaxMs) {
+			cut()
+		}
+		return nil
+	}
+	s.FinalizeFunc = func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			cut()
+		}
+		return nil
+	}
+	// end stops accepting audio and lets the worker finish queued segments
+	end := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			cut()
+			closed = true
+			close(segments)
+		}
+		return nil
+	}
+	s.endInput = end
+	s.CloseFunc = func() error {
+		cancel()
+		return end()
+	}
+
+	go func() {
+		defer s.FinishTranscripts()
+		segOpts := opts
+		segOpts.Format = "wav"
+		var spoken bool
+		for seg := range segments {
+			if ctx.Err() != nil {
+				continue
+			}
+			wav, err := audio.EncodeWAV(seg.data, format)
+			if err != nil {
+				s.SetError(err)
+				continue
+			}
+			t, err := p.Transcribe(ctx, bytes.NewReader(wav), segOpts)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.SetError(err)
+				}
+				continue
+			}
+			text := strings.TrimSpace(t.Text)
+			if text == "" {
+				continue
+			}
+			if spoken {
+				text = " " + text
+			}
+			spoken = true
+			s.PushTranscript(TranscriptDelta{Text: text, IsFinal: true, Timestamp: float64(seg.endMs) / 1000, Language: t.Language})
+		}
+	}()
+
+	closeOnCancel(ctx, s)
+	return s, nil
+}
+
+// chunkRMS returns the RMS level of PCM16 audio, from 0 to 1.
+func chunkRMS(data []byte) float64 {
+	samples := audio.DecodePCM16(data)
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range samples {
+		f := float64(v) / 32768
+		sum += f * f
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
@@ -0,0 +1,166 @@
+package stt
+
+import (
+	"context"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// tone returns ms of a 440 Hz PCM16 tone at 16kHz.
+func tone(ms int) []byte {
+	samples := make([]int16, 16*ms)
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/16000))
+	}
+	return audio.EncodePCM16(samples)
+}
+
+// silence returns ms of PCM16 silence at 16kHz.
+func silence(ms int) []byte {
+	return make([]byte, 32*ms)
+}
+
+// sendChunked sends audio in 20ms chunks, like a live microphone.
+func sendChunked(t *testing.T, s *StreamingSTT, data []byte) {
+	t.Helper()
+	for len(data) > 0 {
+		n := min(640, len(data))
+		if err := s.SendAudio(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+		data = data[n:]
+	}
+}
+
+func collect(t *testing.T, s *StreamingSTT, n int) []TranscriptDelta {
+	t.Helper()
+	var deltas []TranscriptDelta
+	for len(deltas) < n {
+		select {
+		case d, ok := <-s.Transcripts():
+			if !ok {
+				t.Fatalf("transcripts closed after %d deltas: %v", len(deltas), s.Err())
+			}
+			deltas = append(deltas, d)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d deltas", len(deltas))
+		}
+	}
+	return deltas
+}
+
+// segmentProvider transcribes each segment as "segment N" and records the
+// audio it was given.
+type segmentProvider struct {
+	Provider
+	mu    sync.Mutex
+	files [][]byte
+	opts  []TranscribeOptions
+}
+
+func (p *segmentProvider) Transcribe(ctx context.Context, r io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files = append(p.files, data)
+	p.opts = append(p.opts, opts)
+	return &Transcript{Text: " segment " + strings.Repeat("I", len(p.files)) + " "}, nil
+}
+
+func TestSegmentedStream_CutsAtPauses(t *testing.T) {
+	p := &segmentProvider{}
+	s, err := newSegmentedStream(context.Background(), p, TranscribeOptions{Format: "pcm_s16le", SampleRate: 16000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	sendChunked(t, s, silence(1000))
+	sendChunked(t, s, tone(600))
+	sendChunked(t, s, silence(500))
+	sendChunked(t, s, tone(300))
+	if err := s.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	deltas := collect(t, s, 2)
+	if deltas[0].Text != "segment I" || deltas[1].Text != " segment II" {
+		t.Fatalf("deltas = %q, %q", deltas[0].Text, deltas[1].Text)
+	}
+	if !deltas[0].IsFinal || deltas[0].Timestamp != 2.0 {
+		t.Errorf("first delta = %+v, want final at 2.0s", deltas[0])
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	wav, err := audio.ParseWAV(p.files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pre-roll, speech and the silence that ended the segment
+	if got := wav.Format.DurationMs(len(wav.Data)); got != 1200 {
+		t.Errorf("first segment = %dms, want 1200ms", got)
+	}
+	if p.opts[0].Format != "wav" {
+		t.Errorf("format = %q, want wav", p.opts[0].Format)
+	}
+}
+
+func TestSegmentedStream_SkipsSilence(t *testing.T) {
+	p := &segmentProvider{}
+	s, err := newSegmentedStream(context.Background(), p, TranscribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendChunked(t, s, silence(2000))
+	s.Finalize()
+	s.Close()
+
+	select {
+	case <-s.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("session did not end")
+	}
+	if len(p.files) != 0 {
+		t.Fatalf("transcribed %d segments of silence", len(p.files))
+	}
+}
+
+func TestSegmentedStream_ConvertsMulaw(t *testing.T) {
+	p := &segmentProvider{}
+	s, err := newSegmentedStream(context.Background(), p, TranscribeOptions{Format: "pcm_mulaw", SampleRate: 8000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	pcm := audio.PCM16(8000, 1)
+	ulaw := audio.Format{Encoding: audio.EncodingMulaw, SampleRate: 8000, Channels: 1}
+	speech, err := audio.Convert(tone(500), pcm, ulaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SendAudio(speech)
+	s.Finalize()
+	collect(t, s, 1)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	wav, err := audio.ParseWAV(p.files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wav.Format != pcm {
+		t.Errorf("segment format = %v, want %v", wav.Format, pcm)
+	}
+}
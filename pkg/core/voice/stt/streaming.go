@@ -0,0 +1,290 @@
+package stt
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrSessionClosed is returned when sending to a closed streaming session.
+var ErrSessionClosed = errors.New("session closed")
+
+// StreamingSTT represents a real-time streaming transcription session.
+// Audio is sent incrementally via SendAudio, and transcripts are received
+// via Transcripts. Each delta carries new text only, so the deltas of a
+// session concatenate to the full transcript.
+type StreamingSTT struct {
+	transcripts chan TranscriptDelta
+	done        chan struct{}
+	stop        chan struct{}
+	closed      atomic.Bool
+	closeOnce   sync.Once
+	finishOnce  sync.Once
+	err         error
+	errMu       sync.Mutex
+
+	// For implementations to use
+	SendFunc     func(data []byte) error
+	FinalizeFunc func() error
+	CloseFunc    func() error
+
+	// endInput signals that no more audio will be sent, letting the session
+	// deliver its remaining transcripts and end on its own.
+	endInput func() error
+}
+
+// NewStreamingSTT creates a new streaming session for an implementation to
+// drive.
+func NewStreamingSTT() *StreamingSTT {
+	return &StreamingSTT{
+		transcripts: make(chan TranscriptDelta, 100),
+		done:        make(chan struct{}),
+		stop:        make(chan struct{}),
+	}
+}
+
+// SendAudio sends audio data to the streaming STT session.
+// Audio should be in the format specified during session creation (default: pcm_s16le at 16kHz).
+func (s *StreamingSTT) SendAudio(data []byte) error {
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+	if s.SendFunc != nil {
+		return s.SendFunc(data)
+	}
+	return nil
+}
+
+// SendAudioBase64 sends base64-encoded audio data.
+func (s *StreamingSTT) SendAudioBase64(data string) error {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("decode base64: %w", err)
+	}
+	return s.SendAudio(decoded)
+}
+
+// Finalize flushes any remaining audio and signals end of input.
+// Use this when the user stops speaking but you want to keep the session open.
+func (s *StreamingSTT) Finalize() error {
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+	if s.FinalizeFunc != nil {
+		return s.FinalizeFunc()
+	}
+	return nil
+}
+
+// Transcripts returns the channel of transcript deltas.
+func (s *StreamingSTT) Transcripts() <-chan TranscriptDelta {
+	return s.transcripts
+}
+
+// Done returns a channel that's closed when the session ends.
+func (s *StreamingSTT) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that ended the session, if any.
+func (s *StreamingSTT) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// Close closes the streaming STT session.
+func (s *StreamingSTT) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		close(s.stop)
+		if s.CloseFunc != nil {
+			err = s.CloseFunc()
+		}
+	})
+	return err
+}
+
+// Internal methods for implementations
+
+// PushTranscript sends a transcript delta. Returns false if closed.
+func (s *StreamingSTT) PushTranscript(delta TranscriptDelta) bool {
+	select {
+	case s.transcripts <- delta:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// SetError sets the session error.
+func (s *StreamingSTT) SetError(err error) {
+	s.errMu.Lock()
+	s.err = err
+	s.errMu.Unlock()
+}
+
+// FinishTranscripts closes the transcript channel and ends the session.
+// It must be called once no more transcripts will be pushed.
+func (s *StreamingSTT) FinishTranscripts() {
+	s.finishOnce.Do(func() {
+		close(s.transcripts)
+		close(s.done)
+	})
+}
+
+// streamDrainTimeout is how long transcribeStream waits for the final
+// transcripts after the end of the audio.
+const streamDrainTimeout = 3 * time.Second
+
+// transcribeStream implements Provider.TranscribeStream on top of
+// NewStreamingSTT, sending audio from the reader in chunks.
+func transcribeStream(ctx context.Context, p Provider, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
+	stream, err := p.NewStreamingSTT(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read audio and send in chunks
+	go func() {
+		buf := make([]byte, 4096) // ~85ms at 24kHz 16-bit mono
+		for {
+			n, err := audio.Read(buf)
+			if n > 0 {
+				if sendErr := stream.SendAudio(buf[:n]); sendErr != nil {
+					stream.Close()
+					return
+				}
+			}
+			if err == io.EOF {
+				// Give the provider time to transcribe the last of the audio
+				if stream.endInput != nil {
+					stream.endInput()
+				} else {
+					stream.Finalize()
+				}
+				select {
+				case <-stream.Done():
+				case <-ctx.Done():
+				case <-time.After(streamDrainTimeout):
+				}
+				stream.Close()
+				return
+			}
+			if err != nil {
+				stream.Close()
+				return
+			}
+		}
+	}()
+
+	return stream.Transcripts(), nil
+}
+
+// closeOnCancel closes the session when ctx is cancelled.
+func closeOnCancel(ctx context.Context, s *StreamingSTT) {
+	stop := context.AfterFunc(ctx, func() { s.Close() })
+	go func() {
+		<-s.stop
+		stop()
+	}()
+}
+
+// wsProtocol describes a streaming STT WebSocket API that takes audio as
+// binary messages and answers with JSON text messages.
+type wsProtocol struct {
+	finalize []byte // Text message that flushes buffered audio
+	close    []byte // Text message that ends the stream
+
+	// parse handles one message. It returns io.EOF when the server ends
+	// the stream, and any other error when the stream failed.
+	parse func(data []byte) (delta TranscriptDelta, ok bool, err error)
+}
+
+// dialWebSocket connects to a streaming STT endpoint.
+func dialWebSocket(ctx context.Context, dialer *websocket.Dialer, url string, headers http.Header) (*websocket.Conn, error) {
+	if dialer == nil {
+		dialer = &websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	}
+	conn, resp, err := dialer.DialContext(ctx, url, headers)
+	if err != nil {
+		// Try to get more details from the response
+		if resp != nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if len(body) > 0 {
+				return nil, fmt.Errorf("websocket connect (status %d): %s", resp.StatusCode, string(body))
+			}
+			return nil, fmt.Errorf("websocket connect: status %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("websocket connect: %w", err)
+	}
+	return conn, nil
+}
+
+// newWebSocketStream runs a streaming session over a connected WebSocket.
+func newWebSocketStream(ctx context.Context, conn *websocket.Conn, proto wsProtocol) *StreamingSTT {
+	s := NewStreamingSTT()
+
+	var writeMu sync.Mutex
+	write := func(msgType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(msgType, data)
+	}
+
+	s.SendFunc = func(data []byte) error {
+		return write(websocket.BinaryMessage, data)
+	}
+	s.FinalizeFunc = func() error {
+		return write(websocket.TextMessage, proto.finalize)
+	}
+	s.endInput = func() error {
+		return write(websocket.TextMessage, proto.close)
+	}
+	s.CloseFunc = func() error {
+		// Ask the server to end the stream gracefully
+		writeMu.Lock()
+		conn.WriteMessage(websocket.TextMessage, proto.close)
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		writeMu.Unlock()
+		return conn.Close()
+	}
+
+	go func() {
+		defer s.FinishTranscripts()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if !s.closed.Load() && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					s.SetError(err)
+				}
+				return
+			}
+
+			delta, ok, err := proto.parse(data)
+			if err != nil {
+				if err != io.EOF {
+					s.SetError(err)
+				}
+				return
+			}
+			if ok && !s.PushTranscript(delta) {
+				return
+			}
+		}
+	}()
+
+	closeOnCancel(ctx, s)
+	return s
+}
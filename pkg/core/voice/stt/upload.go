@@ -0,0 +1,146 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// uploadFile reads audio for a file-based transcription API. Raw audio is
+// wrapped in a WAV header, since those APIs take files.
+func uploadFile(r io.Reader, opts TranscribeOptions) ([]byte, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("read audio: %w", err)
+	}
+	enc, err := audio.ParseEncoding(opts.Format)
+	if err != nil {
+		// A container format such as WAV or MP3
+		return data, getExtension(opts.Format), nil
+	}
+	format := audio.Format{Encoding: enc, SampleRate: defaultSampleRate(opts.SampleRate), Channels: 1}
+	if enc != audio.EncodingPCM16 {
+		// WAV files hold 16-bit PCM
+		if data, err = audio.Convert(data, format, audio.PCM16(format.SampleRate, 1)); err != nil {
+			return nil, "", err
+		}
+		format = audio.PCM16(format.SampleRate, 1)
+	}
+	wav, err := audio.EncodeWAV(data, format)
+	if err != nil {
+		return nil, "", err
+	}
+	return wav, "wav", nil
+}
+
+// postTranscription uploads an audio file with form fields and decodes the
+// JSON response into v.
+func postTranscription(ctx context.Context, client *http.Client, url string, header http.Header, file []byte, ext string, fields [][2]string, v any) error {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fw, err := mw.CreateFormFile("file", "audio."+ext)
+	if err != nil {
+		return fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := fw.Write(file); err != nil {
+		return fmt.Errorf("write audio data: %w", err)
+	}
+	for _, field := range fields {
+		if err := mw.WriteField(field[0], field[1]); err != nil {
+			return fmt.Errorf("write %s field: %w", field[0], err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &buf)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// transcriptWord is the word timing returned by Whisper-style APIs.
+type transcriptWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func convertWords(words []transcriptWord) []Word {
+	if len(words) == 0 {
+		return nil
+	}
+	out := make([]Word, len(words))
+	for i, w := range words {
+		out[i] = Word{Word: w.Word, Start: w.Start, End: w.End}
+	}
+	return out
+}
+
+// whisperLanguage returns the language code for a language Whisper names in
+// its verbose output, such as "english". Codes and unknown names are
+// returned as they are.
+func whisperLanguage(name string) string {
+	if code, ok := whisperLanguages[strings.ToLower(name)]; ok {
+		return code
+	}
+	return name
+}
+
+// whisperLanguages are the codes of the languages Whisper detects, by the
+// name it reports.
+var whisperLanguages = map[string]string{
+	"afrikaans": "af", "albanian": "sq", "amharic": "am", "arabic": "ar",
+	"armenian": "hy", "assamese": "as", "azerbaijani": "az", "bashkir": "ba",
+	"basque": "eu", "belarusian": "be", "bengali": "bn", "bosnian": "bs",
+	"breton": "br", "bulgarian": "bg", "cantonese": "yue", "catalan": "ca",
+	"chinese": "zh", "croatian": "hr", "czech": "cs", "danish": "da",
+	"dutch": "nl", "english": "en", "estonian": "et", "faroese": "fo",
+	"finnish": "fi", "french": "fr", "galician": "gl", "georgian": "ka",
+	"german": "de", "greek": "el", "gujarati": "gu", "haitian creole": "ht",
+	"hausa": "ha", "hawaiian": "haw", "hebrew": "he", "hindi": "hi",
+	"hungarian": "hu", "icelandic": "is", "indonesian": "id", "italian": "it",
+	"japanese": "ja", "javanese": "jv", "kannada": "kn", "kazakh": "kk",
+	"khmer": "km", "korean": "ko", "lao": "lo", "latin": "la",
+	"latvian": "lv", "lingala": "ln", "lithuanian": "lt", "luxembourgish": "lb",
+	"macedonian": "mk", "malagasy": "mg", "malay": "ms", "malayalam": "ml",
+	"maltese": "mt", "maori": "mi", "marathi": "mr", "mongolian": "mn",
+	"myanmar": "my", "nepali": "ne", "norwegian": "no", "nynorsk": "nn",
+	"occitan": "oc", "pashto": "ps", "persian": "fa", "polish": "pl",
+	"portuguese": "pt", "punjabi": "pa", "romanian": "ro", "russian": "ru",
+	"sanskrit": "sa", "serbian": "sr", "shona": "sn", "sindhi": "sd",
+	"sinhala": "si", "slovak": "sk", "slovenian": "sl", "somali": "so",
+	"spanish": "es", "sundanese": "su", "swahili": "sw", "swedish": "sv",
+	"tagalog": "tl", "tajik": "tg", "tamil": "ta", "tatar": "tt",
+	"telugu": "te", "thai": "th", "tibetan": "bo", "turkish": "tr",
+	"turkmen": "tk", "ukrainian": "uk", "urdu": "ur", "uzbek": "uz",
+	"vietnamese": "vi", "welsh": "cy", "yiddish": "yi", "yoruba": "yo",
+}
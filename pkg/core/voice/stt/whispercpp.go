@@ -0,0 +1,85 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const whisperCppBaseURL = "http://127.0.0.1:8080"
+
+// WhisperCppProvider implements the STT Provider interface using a local
+// whisper.cpp server (examples/server in the whisper.cpp repository). The
+// server transcribes with the model it was started with, so the model name,
+// e.g. "local" in "whisper-cpp/local", is ignored.
+//
+// Like OpenAI, the server transcribes whole files, so streaming sessions
+// transcribe each segment of speech once the speaker pauses.
+type WhisperCppProvider struct {
+	opts options
+}
+
+// NewWhisperCpp creates a new whisper.cpp STT provider. The server is
+// expected at http://127.0.0.1:8080 unless WithBaseURL is given.
+func NewWhisperCpp(opts ...Option) *WhisperCppProvider {
+	return &WhisperCppProvider{opts: newOptions(whisperCppBaseURL, opts)}
+}
+
+// Name returns the provider identifier.
+func (w *WhisperCppProvider) Name() string {
+	return "whisper-cpp"
+}
+
+// Transcribe converts audio to text using the whisper.cpp server.
+func (w *WhisperCppProvider) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (*Transcript, error) {
+	file, ext, err := uploadFile(audio, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only verbose output reports the language and word timings
+	fields := [][2]string{{"temperature", "0"}, {"response_format", "json"}}
+	if opts.Timestamps || opts.Language == "" {
+		fields[1][1] = "verbose_json"
+	}
+	if opts.Language != "" {
+		fields = append(fields, [2]string{"language", opts.Language})
+	}
+
+	var resp struct {
+		Text     string  `json:"text"`
+		Language string  `json:"language"`
+		Duration float64 `json:"duration"`
+		Segments []struct {
+			Words []transcriptWord `json:"words"`
+		} `json:"segments"`
+	}
+	if err := postTranscription(ctx, w.opts.httpClient, w.opts.baseURL+"/inference", nil, file, ext, fields, &resp); err != nil {
+		return nil, fmt.Errorf("whisper.cpp transcription: %w", err)
+	}
+
+	t := &Transcript{
+		Text:     strings.TrimSpace(resp.Text),
+		Language: whisperLanguage(resp.Language),
+		Duration: resp.Duration,
+	}
+	if t.Language == "" {
+		t.Language = opts.Language
+	}
+	for _, seg := range resp.Segments {
+		t.Words = append(t.Words, convertWords(seg.Words)...)
+	}
+	return t, nil
+}
+
+// TranscribeStream transcribes streaming audio segment by segment.
+func (w *WhisperCppProvider) TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptDelta, error) {
+	return transcribeStream(ctx, w, audio, opts)
+}
+
+// NewStreamingSTT creates a streaming session that transcribes each
+// segment of speech once the speaker pauses.
+func (w *WhisperCppProvider) NewStreamingSTT(ctx context.Context, opts TranscribeOptions) (*StreamingSTT, error) {
+	return newSegmentedStream(ctx, w, opts)
+}
@@ -0,0 +1,83 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWhisperCpp_Transcribe(t *testing.T) {
+	srv, requests := transcriptionServer(t, "/inference", func(int) any {
+		return map[string]any{
+			"text":     " And so my fellow Americans.\n",
+			"language": "en",
+			"duration": 0.1,
+			"segments": []map[string]any{{
+				"words": []map[string]any{
+					{"word": " And", "start": 0.0, "end": 0.02},
+					{"word": " so", "start": 0.03, "end": 0.05},
+				},
+			}},
+		}
+	})
+
+	p := NewWhisperCpp(WithBaseURL(srv.URL))
+	tr, err := p.Transcribe(context.Background(), bytes.NewReader(tone(100)), TranscribeOptions{
+		Model:      "local",
+		Format:     "pcm_s16le",
+		Language:   "en",
+		Timestamps: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := requests()[0]
+	if got := req.header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none", got)
+	}
+	if _, ok := req.fields["model"]; ok {
+		t.Error("model sent to whisper.cpp server")
+	}
+	if got := req.fields["response_format"]; len(got) != 1 || got[0] != "verbose_json" {
+		t.Errorf("response_format = %v", got)
+	}
+	if tr.Text != "And so my fellow Americans." {
+		t.Errorf("text = %q", tr.Text)
+	}
+	if len(tr.Words) != 2 || tr.Words[1].Word != " so" {
+		t.Errorf("words = %+v", tr.Words)
+	}
+}
+
+func TestWhisperCpp_TranscribeStream(t *testing.T) {
+	srv, requests := transcriptionServer(t, "/inference", func(n int) any {
+		return map[string]any{"text": " Testing one two.\n", "language": "english"}
+	})
+
+	p := NewWhisperCpp(WithBaseURL(srv.URL))
+	input := io.MultiReader(bytes.NewReader(silence(300)), bytes.NewReader(tone(400)))
+	deltas, err := p.TranscribeStream(context.Background(), input, TranscribeOptions{Format: "pcm_s16le"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text strings.Builder
+	var language string
+	for d := range deltas {
+		text.WriteString(d.Text)
+		language = d.Language
+	}
+	if text.String() != "Testing one two." || language != "en" {
+		t.Errorf("text = %q in %q, want English", text.String(), language)
+	}
+	if n := len(requests()); n != 1 {
+		t.Fatalf("%d requests, want 1", n)
+	}
+	// Without a language, the verbose output reports the detected one
+	if got := requests()[0].fields["response_format"]; len(got) != 1 || got[0] != "verbose_json" {
+		t.Errorf("response_format = %v", got)
+	}
+}
@@ -323,11 +323,33 @@ type cartesiaWSRequest struct {
 }
 
 type cartesiaWSResponse struct {
-	Type       string `json:"type"` // "chunk", "done", "error"
-	Data       string `json:"data,omitempty"`
-	Done       bool   `json:"done,omitempty"`
-	Error      string `json:"error,omitempty"`
-	StatusCode int    `json:"status_code,omitempty"`
+	Type           string                  `json:"type"` // "chunk", "timestamps", "done", "error"
+	Data           string                  `json:"data,omitempty"`
+	Done           bool                    `json:"done,omitempty"`
+	Error          string                  `json:"error,omitempty"`
+	StatusCode     int                     `json:"status_code,omitempty"`
+	WordTimestamps *cartesiaWordTimestamps `json:"word_timestamps,omitempty"`
+}
+
+// cartesiaWordTimestamps holds word timings in seconds.
+type cartesiaWordTimestamps struct {
+	Words []string  `json:"words"`
+	Start []float64 `json:"start"`
+	End   []float64 `json:"end"`
+}
+
+// toWordTimestamps converts the parallel arrays to word timestamps.
+func (t *cartesiaWordTimestamps) toWordTimestamps() []WordTimestamp {
+	n := min(len(t.Words), len(t.Start), len(t.End))
+	words := make([]WordTimestamp, n)
+	for i := range n {
+		words[i] = WordTimestamp{
+			Word:    t.Words[i],
+			StartMs: int(t.Start[i] * 1000),
+			EndMs:   int(t.End[i] * 1000),
+		}
+	}
+	return words
 }
 
 var contextCounter atomic.Uint64
@@ -408,6 +430,7 @@ func (c *CartesiaProvider) NewStreamingContext(ctx context.Context, opts Streami
 		OutputFormat:     outputFormat,
 		ContextID:        contextID,
 		MaxBufferDelayMs: maxBufferDelay,
+		AddTimestamps:    opts.WordTimestamps,
 	}
 
 	if opts.Speed != 0 || opts.Volume != 0 || opts.Emotion != "" {
@@ -496,6 +519,11 @@ func (c *CartesiaProvider) NewStreamingContext(ctx context.Context, opts Streami
 					return
 				}
 
+			case "timestamps":
+				if msg.WordTimestamps != nil {
+					sc.PushWordTimestamps(msg.WordTimestamps.toWordTimestamps()...)
+				}
+
 			case "done":
 				return
 
@@ -523,6 +551,7 @@ type cartesiaStreamingRequest struct {
 	Continue         bool                      `json:"continue"`
 	Flush            bool                      `json:"flush,omitempty"`
 	MaxBufferDelayMs int                       `json:"max_buffer_delay_ms,omitempty"`
+	AddTimestamps    bool                      `json:"add_timestamps,omitempty"`
 	GenerationConfig *cartesiaGenerationConfig `json:"generation_config,omitempty"`
 	Language         *string                   `json:"language,omitempty"`
 }
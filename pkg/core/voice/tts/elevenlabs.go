@@ -0,0 +1,354 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	elevenLabsBaseURL = "https://api.elevenlabs.io"
+	elevenLabsModel   = "eleven_flash_v2_5"
+	elevenLabsVoiceID = "21m00Tcm4TlvDq8ikWAM" // Rachel
+)
+
+// ElevenLabsProvider implements the TTS Provider interface using
+// ElevenLabs. Streaming contexts use the WebSocket input streaming API, so
+// text is spoken as it arrives.
+type ElevenLabsProvider struct {
+	apiKey string
+	opts   options
+}
+
+// NewElevenLabs creates a new ElevenLabs TTS provider.
+func NewElevenLabs(apiKey string, opts ...Option) *ElevenLabsProvider {
+	return &ElevenLabsProvider{
+		apiKey: apiKey,
+		opts:   newOptions(elevenLabsBaseURL, elevenLabsModel, opts),
+	}
+}
+
+// Name returns the provider identifier.
+func (e *ElevenLabsProvider) Name() string {
+	return "elevenlabs"
+}
+
+// Synthesize converts text to audio using ElevenLabs' TTS API.
+func (e *ElevenLabsProvider) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) (*Synthesis, error) {
+	resp, sourceRate, err := e.post(ctx, "", text, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+	format := getFormat(opts.Format)
+	if format != "mp3" {
+		if data, err = encodePCM(data, sourceRate, format, opts.SampleRate); err != nil {
+			return nil, err
+		}
+	}
+	return &Synthesis{Audio: data, Format: format}, nil
+}
+
+// SynthesizeStream converts text to streaming audio using ElevenLabs'
+// streaming endpoint.
+func (e *ElevenLabsProvider) SynthesizeStream(ctx context.Context, text string, opts SynthesizeOptions) (*SynthesisStream, error) {
+	var out *pcmOutput
+	resp, sourceRate, err := e.post(ctx, "/stream", text, opts)
+	if err != nil {
+		return nil, err
+	}
+	if getFormat(opts.Format) != "mp3" {
+		if out, err = newPCMOutput(sourceRate, opts.Format, opts.SampleRate); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	return streamResponse(ctx, resp, out), nil
+}
+
+// post sends a synthesis request to the endpoint for the voice and returns
+// the response and, for PCM output, its sample rate.
+func (e *ElevenLabsProvider) post(ctx context.Context, suffix, text string, opts SynthesizeOptions) (*http.Response, int, error) {
+	outputFormat, sourceRate := elevenLabsOutputFormat(opts.Format, opts.SampleRate)
+	reqBody := elevenLabsRequest{
+		Text:          text,
+		ModelID:       e.opts.model,
+		VoiceSettings: elevenLabsSettings(opts.Speed),
+	}
+	if opts.Language != "" {
+		reqBody.LanguageCode = opts.Language
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal request: %w", err)
+	}
+
+	u := e.opts.baseURL + "/v1/text-to-speech/" + url.PathEscape(elevenLabsVoice(opts.Voice)) + suffix + "?output_format=" + outputFormat
+	req, err := http.NewRequestWithContext(ctx, "POST", u, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("xi-api-key", e.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doRequest(e.opts.httpClient, req, "elevenlabs")
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, sourceRate, nil
+}
+
+type elevenLabsRequest struct {
+	Text          string                   `json:"text"`
+	ModelID       string                   `json:"model_id,omitempty"`
+	LanguageCode  string                   `json:"language_code,omitempty"`
+	VoiceSettings *elevenLabsVoiceSettings `json:"voice_settings,omitempty"`
+}
+
+type elevenLabsVoiceSettings struct {
+	Speed float64 `json:"speed,omitempty"`
+}
+
+func elevenLabsSettings(speed float64) *elevenLabsVoiceSettings {
+	if speed == 0 {
+		return nil
+	}
+	// ElevenLabs accepts speeds from 0.7 to 1.2
+	return &elevenLabsVoiceSettings{Speed: max(0.7, min(1.2, speed))}
+}
+
+// elevenLabsVoice returns the voice ID, defaulting to Rachel.
+func elevenLabsVoice(voice string) string {
+	if voice == "" {
+		return elevenLabsVoiceID
+	}
+	return voice
+}
+
+// elevenLabsOutputFormat returns the output_format for a requested format
+// and, for PCM, the sample rate the audio will have. Rates ElevenLabs does
+// not offer are produced at 24kHz and resampled.
+func elevenLabsOutputFormat(format string, sampleRate int) (string, int) {
+	if getFormat(format) == "mp3" {
+		return "mp3_44100_128", 0
+	}
+	switch rate := defaultSampleRate(sampleRate); rate {
+	case 8000, 16000, 22050, 24000, 44100, 48000:
+		return "pcm_" + strconv.Itoa(rate), rate
+	default:
+		return "pcm_24000", 24000
+	}
+}
+
+// NewStreamingContext creates a streaming context using ElevenLabs'
+// WebSocket input streaming API. Text chunks are spoken as they arrive.
+func (e *ElevenLabsProvider) NewStreamingContext(ctx context.Context, opts StreamingContextOptions) (*StreamingContext, error) {
+	outputFormat, sourceRate := elevenLabsOutputFormat(opts.Format, opts.SampleRate)
+	var out *pcmOutput
+	if sourceRate != 0 {
+		var err error
+		if out, err = newPCMOutput(sourceRate, opts.Format, opts.SampleRate); err != nil {
+			return nil, err
+		}
+	}
+
+	q := url.Values{}
+	q.Set("model_id", e.opts.model)
+	q.Set("output_format", outputFormat)
+	if opts.Language != "" {
+		q.Set("language_code", opts.Language)
+	}
+	u := websocketURL(e.opts.baseURL) + "/v1/text-to-speech/" + url.PathEscape(elevenLabsVoice(opts.Voice)) + "/stream-input?" + q.Encode()
+
+	headers := http.Header{}
+	headers.Set("xi-api-key", e.apiKey)
+	dialer := e.opts.dialer
+	if dialer == nil {
+		dialer = &websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	}
+	conn, resp, err := dialer.DialContext(ctx, u, headers)
+	if err != nil {
+		// A rejected key or voice is explained in the response body
+		if resp != nil {
+			defer resp.Body.Close()
+			if body, _ := io.ReadAll(resp.Body); len(body) > 0 {
+				return nil, fmt.Errorf("websocket connect (status %d): %s", resp.StatusCode, body)
+			}
+			return nil, fmt.Errorf("websocket connect: status %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("websocket connect: %w", err)
+	}
+
+	// The first message opens the stream and carries the voice settings
+	start := elevenLabsStreamMessage{Text: " ", VoiceSettings: elevenLabsSettings(opts.Speed)}
+	if err := conn.WriteJSON(start); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	sc := NewStreamingContext()
+	var writeMu sync.Mutex
+
+	sc.SendFunc = func(text string, isFinal bool) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		if text != "" {
+			// Text must end with a space so words are not split. The text
+			// comes chunked, so flush it: ElevenLabs would otherwise hold
+			// short chunks until its chunk_length_schedule is reached.
+			if err := conn.WriteJSON(elevenLabsStreamMessage{Text: text + " ", Flush: true}); err != nil {
+				return err
+			}
+		}
+		if isFinal {
+			// Empty text ends the stream once all audio is sent
+			return conn.WriteJSON(elevenLabsStreamMessage{})
+		}
+		return nil
+	}
+	sc.CloseFunc = func() error {
+		return conn.Close()
+	}
+
+	go func() {
+		defer sc.FinishAudio()
+		defer conn.Close()
+
+		var pcmBytes int // PCM received so far, to place word timings
+		for {
+			select {
+			case <-ctx.Done():
+				sc.SetError(ctx.Err())
+				return
+			case <-sc.Done():
+				return
+			default:
+			}
+
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if !sc.closed.Load() && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					sc.SetError(err)
+				}
+				return
+			}
+
+			var msg elevenLabsStreamResponse
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			if msg.Error != "" || msg.Message != "" {
+				sc.SetError(fmt.Errorf("elevenlabs error: %s", firstNonEmpty(msg.Message, msg.Error)))
+				return
+			}
+
+			if msg.Audio != "" {
+				audioData, err := base64.StdEncoding.DecodeString(msg.Audio)
+				if err != nil {
+					sc.SetError(fmt.Errorf("decode audio: %w", err))
+					return
+				}
+				if opts.WordTimestamps && sourceRate != 0 && msg.Alignment != nil {
+					offsetMs := pcmBytes * 1000 / (2 * sourceRate)
+					sc.PushWordTimestamps(msg.Alignment.words(offsetMs)...)
+				}
+				pcmBytes += len(audioData)
+				if out != nil {
+					if audioData, err = out.write(audioData); err != nil {
+						sc.SetError(err)
+						return
+					}
+				}
+				if len(audioData) > 0 && !sc.PushAudio(audioData) {
+					return
+				}
+			}
+
+			if msg.IsFinal {
+				return
+			}
+		}
+	}()
+
+	return sc, nil
+}
+
+type elevenLabsStreamMessage struct {
+	Text          string                   `json:"text"`
+	Flush         bool                     `json:"flush,omitempty"`
+	VoiceSettings *elevenLabsVoiceSettings `json:"voice_settings,omitempty"`
+}
+
+type elevenLabsStreamResponse struct {
+	Audio   string `json:"audio"`
+	IsFinal bool   `json:"isFinal"`
+	// Alignment times the text as sent. ElevenLabs also sends
+	// normalizedAlignment, for the text as it normalized it, whose words
+	// do not match the text the caller tracks.
+	Alignment *elevenLabsAlignment `json:"alignment"`
+	Message   string               `json:"message"`
+	Error     string               `json:"error"`
+}
+
+// elevenLabsAlignment holds character timings relative to one audio chunk.
+type elevenLabsAlignment struct {
+	Chars            []string `json:"chars"`
+	CharStartTimesMs []int    `json:"charStartTimesMs"`
+	CharDurationsMs  []int    `json:"charDurationsMs"`
+}
+
+// words groups the characters into words, offset by the start of the chunk.
+func (a *elevenLabsAlignment) words(offsetMs int) []WordTimestamp {
+	n := min(len(a.Chars), len(a.CharStartTimesMs), len(a.CharDurationsMs))
+	var words []WordTimestamp
+	var word []rune
+	var start, end int
+	for i := range n {
+		r := []rune(a.Chars[i])
+		if len(r) == 0 {
+			continue
+		}
+		if unicode.IsSpace(r[0]) {
+			if len(word) > 0 {
+				words = append(words, WordTimestamp{Word: string(word), StartMs: offsetMs + start, EndMs: offsetMs + end})
+				word = word[:0]
+			}
+			continue
+		}
+		if len(word) == 0 {
+			start = a.CharStartTimesMs[i]
+		}
+		word = append(word, r...)
+		end = a.CharStartTimesMs[i] + a.CharDurationsMs[i]
+	}
+	if len(word) > 0 {
+		words = append(words, WordTimestamp{Word: string(word), StartMs: offsetMs + start, EndMs: offsetMs + end})
+	}
+	return words
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,242 @@
+package tts
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// pcm returns n bytes of PCM16 audio.
+func pcm(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+// readAll drains a streaming context's audio.
+func readAll(t *testing.T, sc *StreamingContext) []byte {
+	t.Helper()
+	var out []byte
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case chunk, ok := <-sc.Audio():
+			if !ok {
+				return out
+			}
+			out = append(out, chunk...)
+		case <-timeout:
+			t.Fatal("timed out waiting for audio")
+		}
+	}
+}
+
+func TestElevenLabs_Synthesize(t *testing.T) {
+	var gotPath, gotQuery, gotKey string
+	var gotBody elevenLabsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		gotKey = r.Header.Get("xi-api-key")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write(pcm(4800))
+	}))
+	defer srv.Close()
+
+	p := NewElevenLabs("xi-key", WithBaseURL(srv.URL), WithModel("eleven_multilingual_v2"))
+	synth, err := p.Synthesize(context.Background(), "Hallo Welt", SynthesizeOptions{
+		Voice:      "voice123",
+		Format:     "wav",
+		SampleRate: 24000,
+		Speed:      2,
+		Language:   "de",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/v1/text-to-speech/voice123" || gotQuery != "output_format=pcm_24000" {
+		t.Errorf("request = %s?%s", gotPath, gotQuery)
+	}
+	if gotKey != "xi-key" {
+		t.Errorf("xi-api-key = %q", gotKey)
+	}
+	if gotBody.Text != "Hallo Welt" || gotBody.ModelID != "eleven_multilingual_v2" || gotBody.LanguageCode != "de" {
+		t.Errorf("body = %+v", gotBody)
+	}
+	if gotBody.VoiceSettings == nil || gotBody.VoiceSettings.Speed != 1.2 {
+		t.Errorf("voice settings = %+v, want speed clamped to 1.2", gotBody.VoiceSettings)
+	}
+
+	wav, err := audio.ParseWAV(synth.Audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if synth.Format != "wav" || wav.Format != audio.PCM16(24000, 1) || len(wav.Data) != 4800 {
+		t.Errorf("synthesis = %s %v with %d bytes", synth.Format, wav.Format, len(wav.Data))
+	}
+}
+
+func TestElevenLabs_SynthesizeResamples(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write(make([]byte, 4800)) // 100ms at 24kHz
+	}))
+	defer srv.Close()
+
+	p := NewElevenLabs("xi-key", WithBaseURL(srv.URL))
+	synth, err := p.Synthesize(context.Background(), "Hi", SynthesizeOptions{Format: "pcm", SampleRate: 12000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != "output_format=pcm_24000" {
+		t.Errorf("query = %s", gotQuery)
+	}
+	if n := len(synth.Audio); n < 2300 || n > 2500 {
+		t.Errorf("got %d bytes, want about 100ms at 12kHz", n)
+	}
+}
+
+func TestElevenLabs_StreamingContext(t *testing.T) {
+	var gotPath, gotQuery string
+	var texts []string
+	var flushed []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var msg elevenLabsStreamMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			texts = append(texts, msg.Text)
+			flushed = append(flushed, msg.Flush)
+			if msg.Text != "" {
+				continue
+			}
+			// End of stream: send audio for "Hi there" in two chunks
+			conn.WriteJSON(map[string]any{
+				"audio": base64.StdEncoding.EncodeToString(pcm(3200)), // 100ms at 16kHz
+				"alignment": map[string]any{
+					"chars":            []string{"H", "i", " "},
+					"charStartTimesMs": []int{0, 40, 80},
+					"charDurationsMs":  []int{40, 40, 20},
+				},
+				// ElevenLabs' own reading of the text, which is not what was sent
+				"normalizedAlignment": map[string]any{
+					"chars":            []string{"H", "e", "y", " "},
+					"charStartTimesMs": []int{0, 30, 60, 80},
+					"charDurationsMs":  []int{30, 30, 20, 20},
+				},
+			})
+			conn.WriteJSON(map[string]any{
+				"audio": base64.StdEncoding.EncodeToString(pcm(3200)),
+				"alignment": map[string]any{
+					"chars":            []string{"t", "h", "e", "r", "e"},
+					"charStartTimesMs": []int{0, 10, 20, 30, 40},
+					"charDurationsMs":  []int{10, 10, 10, 10, 30},
+				},
+			})
+			conn.WriteJSON(map[string]any{"isFinal": true})
+			return
+		}
+	}))
+	defer srv.Close()
+
+	p := NewElevenLabs("xi-key", WithBaseURL(srv.URL))
+	sc, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{
+		Voice:          "voice123",
+		Format:         "pcm",
+		SampleRate:     16000,
+		WordTimestamps: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	if err := sc.SendText("Hi", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.SendText("there", true); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readAll(t, sc)
+	if len(got) != 6400 {
+		t.Errorf("got %d bytes of audio, want 6400", len(got))
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1/text-to-speech/voice123/stream-input" || !strings.Contains(gotQuery, "output_format=pcm_16000") {
+		t.Errorf("request = %s?%s", gotPath, gotQuery)
+	}
+	if want := []string{" ", "Hi ", "there ", ""}; strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+	// Every chunk is spoken right away, not only the last
+	if len(flushed) != 4 || !flushed[1] || !flushed[2] {
+		t.Errorf("flushed = %v, want each chunk flushed", flushed)
+	}
+
+	words := sc.WordTimestamps()
+	want := []WordTimestamp{{Word: "Hi", StartMs: 0, EndMs: 80}, {Word: "there", StartMs: 100, EndMs: 170}}
+	if len(words) != 2 || words[0] != want[0] || words[1] != want[1] {
+		t.Errorf("words = %+v, want %+v", words, want)
+	}
+}
+
+func TestElevenLabs_StreamingContextError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+		conn.WriteJSON(map[string]any{"message": "Invalid API key", "error": "auth_error", "code": 1008})
+	}))
+	defer srv.Close()
+
+	p := NewElevenLabs("bad", WithBaseURL(srv.URL))
+	sc, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Format: "pcm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	readAll(t, sc)
+	if err := sc.Err(); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("Err() = %v", err)
+	}
+}
+
+func TestElevenLabs_StreamingContextRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"detail":{"status":"invalid_voice_id"}}`))
+	}))
+	defer srv.Close()
+
+	p := NewElevenLabs("xi-key", WithBaseURL(srv.URL))
+	_, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Voice: "nope", Format: "pcm"})
+	if err == nil || !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "invalid_voice_id") {
+		t.Fatalf("err = %v, want the status and response body", err)
+	}
+}
@@ -0,0 +1,128 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	openAIBaseURL = "https://api.openai.com/v1"
+	openAIModel   = "gpt-4o-mini-tts"
+	openAIVoice   = "alloy"
+
+	// openAISampleRate is the sample rate of OpenAI's PCM output.
+	openAISampleRate = 24000
+)
+
+// OpenAIProvider implements the TTS Provider interface using OpenAI's
+// speech API. The API takes whole texts, so streaming contexts speak one
+// sentence at a time.
+type OpenAIProvider struct {
+	apiKey string
+	opts   options
+}
+
+// NewOpenAI creates a new OpenAI TTS provider.
+func NewOpenAI(apiKey string, opts ...Option) *OpenAIProvider {
+	return &OpenAIProvider{
+		apiKey: apiKey,
+		opts:   newOptions(openAIBaseURL, openAIModel, opts),
+	}
+}
+
+// Name returns the provider identifier.
+func (o *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Synthesize converts text to audio using OpenAI's speech API.
+func (o *OpenAIProvider) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) (*Synthesis, error) {
+	resp, err := o.post(ctx, text, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+	format := getFormat(opts.Format)
+	if format != "mp3" {
+		if data, err = encodePCM(data, openAISampleRate, format, opts.SampleRate); err != nil {
+			return nil, err
+		}
+	}
+	return &Synthesis{Audio: data, Format: format}, nil
+}
+
+// SynthesizeStream converts text to audio, streaming it as it is generated.
+func (o *OpenAIProvider) SynthesizeStream(ctx context.Context, text string, opts SynthesizeOptions) (*SynthesisStream, error) {
+	var out *pcmOutput
+	resp, err := o.post(ctx, text, opts)
+	if err != nil {
+		return nil, err
+	}
+	if getFormat(opts.Format) != "mp3" {
+		if out, err = newPCMOutput(openAISampleRate, opts.Format, opts.SampleRate); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	return streamResponse(ctx, resp, out), nil
+}
+
+// NewStreamingContext creates a streaming context that speaks each
+// sentence as soon as it is complete.
+func (o *OpenAIProvider) NewStreamingContext(ctx context.Context, opts StreamingContextOptions) (*StreamingContext, error) {
+	return newSentenceContext(ctx, o, opts)
+}
+
+// post sends a speech request. Audio other than MP3 is requested as PCM
+// and converted locally, so any sample rate can be served.
+func (o *OpenAIProvider) post(ctx context.Context, text string, opts SynthesizeOptions) (*http.Response, error) {
+	reqBody := openAISpeechRequest{
+		Model:          o.opts.model,
+		Input:          text,
+		Voice:          opts.Voice,
+		ResponseFormat: "pcm",
+		Speed:          opts.Speed,
+	}
+	if reqBody.Voice == "" {
+		reqBody.Voice = openAIVoice
+	}
+	if getFormat(opts.Format) == "mp3" {
+		reqBody.ResponseFormat = "mp3"
+	}
+	// Only the gpt-4o models take delivery instructions
+	if opts.Emotion != "" && strings.HasPrefix(reqBody.Model, "gpt-4o") {
+		reqBody.Instructions = "Speak in a " + opts.Emotion + " tone."
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", o.opts.baseURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(o.opts.httpClient, req, "openai")
+}
+
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float64 `json:"speed,omitempty"`
+	Instructions   string  `json:"instructions,omitempty"`
+}
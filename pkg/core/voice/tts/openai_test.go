@@ -0,0 +1,145 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// speechServer is an OpenAI speech API stand-in that returns 100ms of
+// 24kHz PCM per request.
+func speechServer(t *testing.T) (*httptest.Server, func() []openAISpeechRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []openAISpeechRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var req openAISpeechRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		reqs = append(reqs, req)
+		mu.Unlock()
+		w.Write(pcm(4800))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []openAISpeechRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]openAISpeechRequest(nil), reqs...)
+	}
+}
+
+func TestOpenAI_Synthesize(t *testing.T) {
+	srv, requests := speechServer(t)
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	synth, err := p.Synthesize(context.Background(), "Hello.", SynthesizeOptions{
+		Voice:      "nova",
+		Emotion:    "happy",
+		Format:     "wav",
+		SampleRate: 24000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := requests()[0]
+	want := openAISpeechRequest{
+		Model:          "gpt-4o-mini-tts",
+		Input:          "Hello.",
+		Voice:          "nova",
+		ResponseFormat: "pcm",
+		Instructions:   "Speak in a happy tone.",
+	}
+	if req != want {
+		t.Errorf("request = %+v, want %+v", req, want)
+	}
+	wav, err := audio.ParseWAV(synth.Audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wav.Data) != 4800 || wav.Format.SampleRate != 24000 {
+		t.Errorf("wav = %v with %d bytes", wav.Format, len(wav.Data))
+	}
+}
+
+func TestOpenAI_SynthesizeStreamResamples(t *testing.T) {
+	srv, _ := speechServer(t)
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL), WithModel("tts-1"))
+	stream, err := p.SynthesizeStream(context.Background(), "Hello.", SynthesizeOptions{Format: "pcm", SampleRate: 8000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for chunk := range stream.Chunks() {
+		n += len(chunk)
+	}
+	stream.Close()
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// 100ms at 8kHz
+	if n < 1500 || n > 1700 {
+		t.Errorf("got %d bytes, want about 1600", n)
+	}
+}
+
+func TestOpenAI_StreamingContext(t *testing.T) {
+	srv, requests := speechServer(t)
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	sc, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Voice: "alloy", Format: "pcm", SampleRate: 24000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	sc.SendText("Hello there. How", false)
+	sc.SendText(" are you? I am", false)
+	sc.SendText(" fine", false)
+	sc.Flush()
+
+	got := readAll(t, sc)
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	reqs := requests()
+	var inputs []string
+	for _, r := range reqs {
+		inputs = append(inputs, r.Input)
+	}
+	want := []string{"Hello there.", "How are you?", "I am fine"}
+	if len(inputs) != len(want) || inputs[0] != want[0] || inputs[1] != want[1] || inputs[2] != want[2] {
+		t.Fatalf("inputs = %q, want %q", inputs, want)
+	}
+	if len(got) != 3*4800 {
+		t.Errorf("got %d bytes of audio, want %d", len(got), 3*4800)
+	}
+}
+
+func TestOpenAI_StreamingContextWAV(t *testing.T) {
+	srv, _ := speechServer(t)
+
+	p := NewOpenAI("sk-test", WithBaseURL(srv.URL))
+	sc, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Format: "wav"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	sc.SendText("One. Two.", true)
+	got := readAll(t, sc)
+	// One header for the whole stream
+	if !audio.IsWAV(got) || len(got) != 44+2*4800 {
+		t.Errorf("got %d bytes, want a 44 byte header and two sentences", len(got))
+	}
+}
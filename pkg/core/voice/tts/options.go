@@ -0,0 +1,53 @@
+package tts
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// Option configures a TTS provider.
+type Option func(*options)
+
+type options struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+}
+
+func newOptions(baseURL, model string, opts []Option) options {
+	o := options{baseURL: baseURL, model: model, httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithBaseURL sets the API base URL, e.g. for a proxy or a self-hosted server.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithModel sets the synthesis model, e.g. "eleven_multilingual_v2" or "tts-1".
+func WithModel(model string) Option {
+	return func(o *options) {
+		o.model = model
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for batch requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithDialer sets the WebSocket dialer used for streaming contexts.
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+	}
+}
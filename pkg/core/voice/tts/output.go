@@ -0,0 +1,156 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// streamingWAVSize is the data size written in the header of a WAV stream
+// whose length is not known up front.
+const streamingWAVSize = math.MaxInt32 &^ 1
+
+// defaultSampleRate returns the sample rate, defaulting to 24kHz.
+func defaultSampleRate(sampleRate int) int {
+	if sampleRate == 0 {
+		return 24000
+	}
+	return sampleRate
+}
+
+// encodePCM converts PCM16 mono audio to the requested format ("wav" or
+// "pcm") and sample rate.
+func encodePCM(data []byte, sourceRate int, format string, sampleRate int) ([]byte, error) {
+	to := audio.PCM16(defaultSampleRate(sampleRate), 1)
+	data, err := audio.Convert(data, audio.PCM16(sourceRate, 1), to)
+	if err != nil {
+		return nil, err
+	}
+	switch getFormat(format) {
+	case "pcm", "raw":
+		return data, nil
+	case "wav":
+		return audio.EncodeWAV(data, to)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// pcmOutput converts a stream of PCM16 mono audio to the requested format
+// and sample rate. WAV output starts with a header for a stream of unknown
+// length.
+type pcmOutput struct {
+	conv   *audio.Converter
+	header []byte // Written before the first chunk
+}
+
+func newPCMOutput(sourceRate int, format string, sampleRate int) (*pcmOutput, error) {
+	to := audio.PCM16(defaultSampleRate(sampleRate), 1)
+	conv, err := audio.NewConverter(audio.PCM16(sourceRate, 1), to)
+	if err != nil {
+		return nil, err
+	}
+	o := &pcmOutput{conv: conv}
+	switch getFormat(format) {
+	case "pcm", "raw":
+	case "wav":
+		if o.header, err = audio.WAVHeader(to, streamingWAVSize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+	return o, nil
+}
+
+// write converts the next chunk. It may return no audio.
+func (o *pcmOutput) write(chunk []byte) ([]byte, error) {
+	data, err := o.conv.Convert(chunk)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	if o.header != nil {
+		data = append(o.header, data...)
+		o.header = nil
+	}
+	return data, nil
+}
+
+// flush returns the audio held back by resampling.
+func (o *pcmOutput) flush() ([]byte, error) {
+	return o.conv.Flush()
+}
+
+// doRequest sends a request and returns the response if it succeeded.
+func doRequest(client *http.Client, req *http.Request, provider string) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request: %w", provider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s error %d: %s", provider, resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+// streamResponse streams an audio response body. Chunks pass through out
+// when it is set, and unchanged otherwise.
+func streamResponse(ctx context.Context, resp *http.Response, out *pcmOutput) *SynthesisStream {
+	stream := NewSynthesisStream()
+	go func() {
+		defer stream.FinishSending()
+		defer resp.Body.Close()
+
+		buf := make([]byte, 4096)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				chunk := append([]byte(nil), buf[:n]...)
+				if out != nil {
+					var convErr error
+					if chunk, convErr = out.write(chunk); convErr != nil {
+						stream.SetError(convErr)
+						return
+					}
+				}
+				if len(chunk) > 0 && !stream.Send(chunk) {
+					return
+				}
+			}
+			if err == io.EOF {
+				if out != nil {
+					if tail, _ := out.flush(); len(tail) > 0 {
+						stream.Send(tail)
+					}
+				}
+				return
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				stream.SetError(err)
+				return
+			}
+		}
+	}()
+	return stream
+}
+
+// websocketURL turns an http(s) base URL into a ws(s) one.
+func websocketURL(baseURL string) string {
+	if rest, ok := strings.CutPrefix(baseURL, "https://"); ok {
+		return "wss://" + rest
+	}
+	if rest, ok := strings.CutPrefix(baseURL, "http://"); ok {
+		return "ws://" + rest
+	}
+	return baseURL
+}
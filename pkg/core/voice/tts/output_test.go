@@ -0,0 +1,57 @@
+package tts
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// oddReader returns its data a few bytes at a time, splitting samples.
+type oddReader struct {
+	data  []byte
+	sizes []int
+}
+
+func (r *oddReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	size := r.sizes[0]
+	r.sizes = append(r.sizes[1:], size)
+	n := copy(p[:min(size, len(p))], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestStreamResponse_OddChunks(t *testing.T) {
+	samples := make([]int16, 500)
+	for i := range samples {
+		samples[i] = int16(i*131 - 30000)
+	}
+	data := audio.EncodePCM16(samples)
+
+	out, err := newPCMOutput(24000, "pcm", 24000)
+	if err != nil {
+		t.Fatalf("newPCMOutput() error = %v", err)
+	}
+	resp := &http.Response{Body: io.NopCloser(&oddReader{data: data, sizes: []int{3, 7, 1, 4}})}
+	stream := streamResponse(context.Background(), resp, out)
+
+	var got []byte
+	for chunk := range stream.Chunks() {
+		if len(chunk)%2 != 0 {
+			t.Fatalf("chunk of %d bytes splits a sample", len(chunk))
+		}
+		got = append(got, chunk...)
+	}
+	stream.Close()
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	if string(got) != string(data) {
+		t.Error("streamed audio differs from the response")
+	}
+}
@@ -0,0 +1,115 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+const piperBaseURL = "http://127.0.0.1:5000"
+
+// PiperProvider implements the TTS Provider interface using a local HTTP
+// TTS server such as Piper's (python -m piper.http_server). It posts
+// {"text", "voice", "length_scale"} as JSON to the base URL and expects a
+// WAV file back, so any server speaking that protocol works.
+//
+// Streaming contexts speak one sentence at a time. MP3 output is not
+// supported.
+type PiperProvider struct {
+	opts options
+}
+
+// NewPiper creates a new Piper TTS provider. The server is expected at
+// http://127.0.0.1:5000 unless WithBaseURL is given.
+func NewPiper(opts ...Option) *PiperProvider {
+	return &PiperProvider{opts: newOptions(piperBaseURL, "", opts)}
+}
+
+// Name returns the provider identifier.
+func (p *PiperProvider) Name() string {
+	return "piper"
+}
+
+// Synthesize converts text to audio using the TTS server.
+func (p *PiperProvider) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) (*Synthesis, error) {
+	format := getFormat(opts.Format)
+	if format == "mp3" {
+		return nil, fmt.Errorf("piper: unsupported output format %q", opts.Format)
+	}
+
+	reqBody := piperRequest{Text: text, Voice: opts.Voice}
+	if opts.Speed > 0 {
+		// Piper stretches phoneme lengths rather than scaling speed
+		reqBody.LengthScale = 1 / opts.Speed
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.opts.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doRequest(p.opts.httpClient, req, "piper")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+
+	wav, err := audio.ParseWAV(data)
+	if err != nil {
+		return nil, fmt.Errorf("piper: %w", err)
+	}
+	// Voices differ in sample rate; normalize to mono PCM16 first
+	pcm, err := audio.Convert(wav.Data, wav.Format, audio.PCM16(wav.Format.SampleRate, 1))
+	if err != nil {
+		return nil, err
+	}
+	out, err := encodePCM(pcm, wav.Format.SampleRate, format, opts.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &Synthesis{
+		Audio:    out,
+		Format:   format,
+		Duration: float64(wav.Format.DurationMs(len(wav.Data))) / 1000,
+	}, nil
+}
+
+type piperRequest struct {
+	Text        string  `json:"text"`
+	Voice       string  `json:"voice,omitempty"`
+	LengthScale float64 `json:"length_scale,omitempty"`
+}
+
+// SynthesizeStream synthesizes the text and returns it as a single chunk.
+func (p *PiperProvider) SynthesizeStream(ctx context.Context, text string, opts SynthesizeOptions) (*SynthesisStream, error) {
+	synth, err := p.Synthesize(ctx, text, opts)
+	if err != nil {
+		return nil, err
+	}
+	stream := NewSynthesisStream()
+	stream.chunks <- synth.Audio
+	stream.FinishSending()
+	return stream, nil
+}
+
+// NewStreamingContext creates a streaming context that speaks each
+// sentence as soon as it is complete.
+func (p *PiperProvider) NewStreamingContext(ctx context.Context, opts StreamingContextOptions) (*StreamingContext, error) {
+	if getFormat(opts.Format) == "mp3" {
+		return nil, fmt.Errorf("piper: unsupported output format %q", opts.Format)
+	}
+	return newSentenceContext(ctx, p, opts)
+}
@@ -0,0 +1,84 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+func TestPiper_Synthesize(t *testing.T) {
+	var got piperRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		// 100ms at 22.05kHz, the rate of most Piper voices
+		wav, _ := audio.EncodeWAV(make([]byte, 4410), audio.PCM16(22050, 1))
+		w.Header().Set("Content-Type", "audio/wav")
+		w.Write(wav)
+	}))
+	defer srv.Close()
+
+	p := NewPiper(WithBaseURL(srv.URL))
+	synth, err := p.Synthesize(context.Background(), "Hallo.", SynthesizeOptions{
+		Voice:      "de_DE-thorsten-medium",
+		Speed:      1.25,
+		Format:     "pcm",
+		SampleRate: 16000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Text != "Hallo." || got.Voice != "de_DE-thorsten-medium" || got.LengthScale != 0.8 {
+		t.Errorf("request = %+v", got)
+	}
+	if synth.Format != "pcm" || synth.Duration != 0.1 {
+		t.Errorf("synthesis = %s, %vs", synth.Format, synth.Duration)
+	}
+	// 100ms at 16kHz
+	if n := len(synth.Audio); n < 3100 || n > 3300 {
+		t.Errorf("got %d bytes, want about 3200", n)
+	}
+}
+
+func TestPiper_RejectsMP3(t *testing.T) {
+	p := NewPiper()
+	if _, err := p.Synthesize(context.Background(), "Hi.", SynthesizeOptions{Format: "mp3"}); err == nil {
+		t.Error("Synthesize accepted mp3")
+	}
+	if _, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Format: "mp3"}); err == nil {
+		t.Error("NewStreamingContext accepted mp3")
+	}
+}
+
+func TestPiper_StreamingContext(t *testing.T) {
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req piperRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		texts = append(texts, req.Text)
+		wav, _ := audio.EncodeWAV(make([]byte, 2400), audio.PCM16(24000, 1))
+		w.Write(wav)
+	}))
+	defer srv.Close()
+
+	p := NewPiper(WithBaseURL(srv.URL))
+	sc, err := p.NewStreamingContext(context.Background(), StreamingContextOptions{Format: "pcm", SampleRate: 24000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	sc.SendText("First sentence! Second", false)
+	sc.SendText(" sentence", true)
+
+	if got := readAll(t, sc); len(got) != 4800 {
+		t.Errorf("got %d bytes, want 4800", len(got))
+	}
+	if len(texts) != 2 || texts[0] != "First sentence!" || texts[1] != "Second sentence" {
+		t.Errorf("texts = %q", texts)
+	}
+}
@@ -33,6 +33,15 @@ type StreamingContextOptions struct {
 	Format           string  // Output format: "wav", "mp3", or "pcm"
 	SampleRate       int     // Sample rate
 	MaxBufferDelayMs int     // Max time to buffer text before generating (0-5000ms, default 500)
+	WordTimestamps   bool    // Request per-word timing, if the provider supports it
+}
+
+// WordTimestamp is the timing of one spoken word, in milliseconds from the
+// start of the context's audio.
+type WordTimestamp struct {
+	Word    string
+	StartMs int
+	EndMs   int
 }
 
 // StreamingContext manages an incremental TTS session.
@@ -45,6 +54,9 @@ type StreamingContext struct {
 	closed    atomic.Bool
 	closeOnce sync.Once
 
+	wordsMu sync.Mutex
+	words   []WordTimestamp
+
 	// For implementations to use
 	SendFunc  func(text string, isFinal bool) error
 	CloseFunc func() error
@@ -87,6 +99,14 @@ func (sc *StreamingContext) Err() error {
 	return sc.err
 }
 
+// WordTimestamps returns the timing of the words synthesized so far. It is
+// empty unless WordTimestamps was requested and the provider supports it.
+func (sc *StreamingContext) WordTimestamps() []WordTimestamp {
+	sc.wordsMu.Lock()
+	defer sc.wordsMu.Unlock()
+	return append([]WordTimestamp(nil), sc.words...)
+}
+
 // Close closes the streaming context.
 func (sc *StreamingContext) Close() error {
 	var err error
@@ -117,6 +137,13 @@ func (sc *StreamingContext) PushAudio(chunk []byte) bool {
 	}
 }
 
+// PushWordTimestamps records the timing of synthesized words.
+func (sc *StreamingContext) PushWordTimestamps(words ...WordTimestamp) {
+	sc.wordsMu.Lock()
+	sc.words = append(sc.words, words...)
+	sc.wordsMu.Unlock()
+}
+
 // SetError sets the context error.
 func (sc *StreamingContext) SetError(err error) {
 	sc.errMu.Lock()
@@ -152,6 +179,7 @@ type Synthesis struct {
 	Audio    []byte  // Audio data
 	Format   string  // Audio format
 	Duration float64 // Duration in seconds (if available)
+	Provider string  // Provider that produced the audio (set by Registry and failover)
 }
 
 // SynthesisStream provides streaming audio output.
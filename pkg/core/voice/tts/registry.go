@@ -0,0 +1,91 @@
+package tts
+
+import (
+	"context"
+
+	"github.com/vango-go/vai/pkg/core/voice/internal/registry"
+)
+
+// Registry routes synthesis to providers by voice. Voices are named
+// "provider/voice", e.g. "elevenlabs/21m00Tcm4TlvDq8ikWAM", "openai/alloy"
+// or "piper/en_US-lessac-medium"; a voice without a provider, such as a
+// Cartesia voice ID, goes to the default provider.
+//
+// A Registry is itself a Provider, so it can be used wherever one
+// provider is expected.
+type Registry struct {
+	*registry.Registry[Provider]
+}
+
+var _ Provider = (*Registry)(nil)
+
+// NewRegistry creates a registry of the given providers. The first one is
+// the default.
+func NewRegistry(providers ...Provider) *Registry {
+	return &Registry{registry.New[Provider]("tts", "voice", providers...)}
+}
+
+// Name returns the provider identifier.
+func (r *Registry) Name() string {
+	return "registry"
+}
+
+// Synthesize converts text to audio with the provider for opts.Voice.
+func (r *Registry) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) (*Synthesis, error) {
+	p, voice, err := r.Resolve(opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	opts.Voice = voice
+	synth, err := p.Synthesize(ctx, text, opts)
+	if err == nil && synth.Provider == "" {
+		synth.Provider = p.Name()
+	}
+	return synth, err
+}
+
+// SynthesizeStream converts text to streaming audio with the provider for opts.Voice.
+func (r *Registry) SynthesizeStream(ctx context.Context, text string, opts SynthesizeOptions) (*SynthesisStream, error) {
+	p, voice, err := r.Resolve(opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	opts.Voice = voice
+	return p.SynthesizeStream(ctx, text, opts)
+}
+
+// NewStreamingContext creates a streaming context with the provider for opts.Voice.
+func (r *Registry) NewStreamingContext(ctx context.Context, opts StreamingContextOptions) (*StreamingContext, error) {
+	p, voice, err := r.Resolve(opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	opts.Voice = voice
+	return p.NewStreamingContext(ctx, opts)
+}
+
+// ProvidersFromKeys returns every provider that has a key, in order of
+// preference. key returns the API key for "cartesia", "elevenlabs" and
+// "openai", and the server URL for "piper".
+func ProvidersFromKeys(key func(provider string) string) []Provider {
+	var providers []Provider
+	if k := key("cartesia"); k != "" {
+		providers = append(providers, NewCartesia(k))
+	}
+	if k := key("elevenlabs"); k != "" {
+		providers = append(providers, NewElevenLabs(k))
+	}
+	if k := key("openai"); k != "" {
+		providers = append(providers, NewOpenAI(k))
+	}
+	if k := key("piper"); k != "" {
+		providers = append(providers, NewPiper(WithBaseURL(k)))
+	}
+	return providers
+}
+
+// NewRegistryFromKeys creates a registry of every provider that has a key.
+// Cartesia is the default when available.
+func NewRegistryFromKeys(key func(provider string) string) *Registry {
+	return NewRegistry(ProvidersFromKeys(key)...)
+}
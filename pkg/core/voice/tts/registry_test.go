@@ -0,0 +1,77 @@
+package tts
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// namedProvider records the options it is called with.
+type namedProvider struct {
+	Provider
+	name string
+	opts SynthesizeOptions
+}
+
+func (p *namedProvider) Name() string { return p.name }
+
+func (p *namedProvider) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) (*Synthesis, error) {
+	p.opts = opts
+	return &Synthesis{Audio: []byte(p.name)}, nil
+}
+
+func TestRegistry_RoutesByVoice(t *testing.T) {
+	cartesia := &namedProvider{name: "cartesia"}
+	eleven := &namedProvider{name: "elevenlabs"}
+	piper := &namedProvider{name: "piper"}
+	r := NewRegistry(cartesia, eleven, piper)
+
+	tests := []struct {
+		voice     string
+		provider  *namedProvider
+		wantVoice string
+	}{
+		{"elevenlabs/21m00Tcm4TlvDq8ikWAM", eleven, "21m00Tcm4TlvDq8ikWAM"},
+		{"piper/en_US-lessac-medium", piper, "en_US-lessac-medium"},
+		{"a0e99841-438c-4a64-b679-ae501e7d6091", cartesia, "a0e99841-438c-4a64-b679-ae501e7d6091"},
+		{"", cartesia, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.voice, func(t *testing.T) {
+			synth, err := r.Synthesize(context.Background(), "Hi.", SynthesizeOptions{Voice: tt.voice, Speed: 1.1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(synth.Audio) != tt.provider.name {
+				t.Errorf("routed to %s, want %s", synth.Audio, tt.provider.name)
+			}
+			if tt.provider.opts.Voice != tt.wantVoice || tt.provider.opts.Speed != 1.1 {
+				t.Errorf("opts = %+v, want voice %q", tt.provider.opts, tt.wantVoice)
+			}
+		})
+	}
+}
+
+func TestRegistry_UnknownProvider(t *testing.T) {
+	r := NewRegistry(&namedProvider{name: "cartesia"})
+	_, err := r.NewStreamingContext(context.Background(), StreamingContextOptions{Voice: "openai/alloy"})
+	if err == nil || !strings.Contains(err.Error(), `"openai"`) {
+		t.Fatalf("err = %v, want unknown provider", err)
+	}
+}
+
+func TestNewRegistryFromKeys(t *testing.T) {
+	keys := map[string]string{"elevenlabs": "xi", "openai": "sk", "piper": "http://localhost:5000"}
+	r := NewRegistryFromKeys(func(provider string) string { return keys[provider] })
+
+	if got := strings.Join(r.Providers(), ","); got != "elevenlabs,openai,piper" {
+		t.Fatalf("providers = %s", got)
+	}
+	if err := r.SetDefault("piper"); err != nil {
+		t.Fatal(err)
+	}
+	p, voice, err := r.Resolve("en_US-amy-low")
+	if err != nil || p.Name() != "piper" || voice != "en_US-amy-low" {
+		t.Fatalf("Resolve = %v, %q, %v", p, voice, err)
+	}
+}
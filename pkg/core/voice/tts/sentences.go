@@ -0,0 +1,160 @@
+package tts
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// newSentenceContext creates a streaming context on top of a provider's
+// SynthesizeStream, for providers without an incremental text API. Text
+// is collected into sentences, which are synthesized one at a time, in
+// order, as soon as they are complete. Word timestamps are not available.
+func newSentenceContext(ctx context.Context, p Provider, opts StreamingContextOptions) (*StreamingContext, error) {
+	// Sentences are synthesized as raw PCM so they join into one stream
+	format := "pcm"
+	var out *pcmOutput
+	if getFormat(opts.Format) == "mp3" {
+		format = "mp3"
+	} else {
+		var err error
+		sampleRate := defaultSampleRate(opts.SampleRate)
+		if out, err = newPCMOutput(sampleRate, opts.Format, sampleRate); err != nil {
+			return nil, err
+		}
+	}
+	synthOpts := SynthesizeOptions{
+		Voice:      opts.Voice,
+		Speed:      opts.Speed,
+		Volume:     opts.Volume,
+		Emotion:    opts.Emotion,
+		Language:   opts.Language,
+		Format:     format,
+		SampleRate: opts.SampleRate,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sc := NewStreamingContext()
+
+	var (
+		mu      sync.Mutex
+		pending string
+		queue   []string
+		ended   bool
+		notify  = make(chan struct{}, 1)
+	)
+	enqueue := func(sentence string) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			queue = append(queue, sentence)
+		}
+	}
+	signal := func() {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+
+	sc.SendFunc = func(text string, isFinal bool) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if ended {
+			return ErrContextClosed
+		}
+		pending += text
+		if n := lastSentenceEnd(pending); n > 0 {
+			enqueue(pending[:n])
+			pending = pending[n:]
+		}
+		if isFinal {
+			enqueue(pending)
+			pending = ""
+			ended = true
+		}
+		signal()
+		return nil
+	}
+	sc.CloseFunc = func() error {
+		cancel()
+		return nil
+	}
+
+	// next waits for the next sentence. It returns false once all text has
+	// been synthesized or the context is cancelled.
+	next := func() (string, bool) {
+		for {
+			mu.Lock()
+			if len(queue) > 0 {
+				sentence := queue[0]
+				queue = queue[1:]
+				mu.Unlock()
+				return sentence, true
+			}
+			done := ended
+			mu.Unlock()
+			if done {
+				return "", false
+			}
+
+			select {
+			case <-notify:
+			case <-ctx.Done():
+				return "", false
+			}
+		}
+	}
+
+	go func() {
+		defer sc.FinishAudio()
+		for {
+			sentence, ok := next()
+			if !ok {
+				break
+			}
+			stream, err := p.SynthesizeStream(ctx, sentence, synthOpts)
+			if err != nil {
+				sc.SetError(err)
+				return
+			}
+			for chunk := range stream.Chunks() {
+				if out != nil {
+					if chunk, err = out.write(chunk); err != nil {
+						break
+					}
+				}
+				if len(chunk) > 0 && !sc.PushAudio(chunk) {
+					stream.Close()
+					return
+				}
+			}
+			stream.Close()
+			if err == nil {
+				err = stream.Err()
+			}
+			if err != nil {
+				sc.SetError(err)
+				return
+			}
+		}
+		if err := ctx.Err(); err != nil && !sc.closed.Load() {
+			sc.SetError(err)
+		}
+	}()
+
+	return sc, nil
+}
+
+// lastSentenceEnd returns the length of the text up to and including the
+// last complete sentence, or 0 if no sentence is complete. A sentence is
+// complete when its closing punctuation is followed by whitespace.
+func lastSentenceEnd(text string) int {
+	for i := len(text) - 2; i >= 0; i-- {
+		switch text[i] {
+		case '.', '!', '?', ';', ':':
+			if next := text[i+1]; next == ' ' || next == '\n' || next == '\t' {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
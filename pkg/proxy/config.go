@@ -5,6 +5,9 @@ import (
 	"log/slog"
 	"os"
 	"time"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
 )
 
 // Config holds all proxy server configuration.
@@ -24,6 +27,10 @@ type Config struct {
 	// Provider keys
 	ProviderKeys map[string]string `json:"provider_keys" yaml:"provider_keys"`
 
+	// Model routes map logical model names (e.g. "fast") to ordered
+	// "provider/model" fallback targets.
+	ModelRoutes map[string][]string `json:"model_routes" yaml:"model_routes"`
+
 	// Rate limiting
 	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
 
@@ -35,6 +42,19 @@ type Config struct {
 	WriteTimeout    time.Duration `json:"write_timeout" yaml:"write_timeout"`
 	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
 
+	// ModelCatalogFile is an optional JSON catalog merged over the built-in model catalog.
+	ModelCatalogFile string `json:"model_catalog_file" yaml:"model_catalog_file"`
+
+	// ModelCatalog replaces the built-in catalog when set.
+	ModelCatalog *catalog.Catalog `json:"-" yaml:"-"`
+
+	// CapabilityMode controls requests using features the provider does not
+	// support: "strict" (default), "degrade" or "off".
+	CapabilityMode core.CapabilityMode `json:"capability_mode" yaml:"capability_mode"`
+
+	// Middleware wraps every request passing through the engine.
+	Middleware []core.Middleware `json:"-" yaml:"-"`
+
 	// Logger
 	Logger *slog.Logger `json:"-" yaml:"-"`
 }
@@ -181,6 +201,44 @@ func WithProviderKey(provider, key string) ConfigOption {
 	}
 }
 
+// WithModelRoute maps a logical model name to ordered "provider/model" fallback targets.
+func WithModelRoute(name string, targets ...string) ConfigOption {
+	return func(c *Config) {
+		if c.ModelRoutes == nil {
+			c.ModelRoutes = make(map[string][]string)
+		}
+		c.ModelRoutes[name] = targets
+	}
+}
+
+// WithModelCatalog replaces the built-in model catalog served on /v1/models.
+func WithModelCatalog(c *catalog.Catalog) ConfigOption {
+	return func(cfg *Config) {
+		cfg.ModelCatalog = c
+	}
+}
+
+// WithModelCatalogFile merges a JSON catalog file over the built-in model catalog.
+func WithModelCatalogFile(path string) ConfigOption {
+	return func(c *Config) {
+		c.ModelCatalogFile = path
+	}
+}
+
+// WithCapabilityMode sets how unsupported features are handled.
+func WithCapabilityMode(mode core.CapabilityMode) ConfigOption {
+	return func(c *Config) {
+		c.CapabilityMode = mode
+	}
+}
+
+// WithMiddleware adds engine middleware.
+func WithMiddleware(middleware ...core.Middleware) ConfigOption {
+	return func(c *Config) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
 // WithLogger sets the logger.
 func WithLogger(logger *slog.Logger) ConfigOption {
 	return func(c *Config) {
@@ -0,0 +1,530 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/audio"
+	"github.com/vango-go/vai/pkg/core/live"
+	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
+)
+
+const (
+	// liveWriteTimeout bounds each WebSocket write to a live client.
+	liveWriteTimeout = 10 * time.Second
+
+	// liveCloseGrace is how long to wait for the client to answer a close frame.
+	liveCloseGrace = time.Second
+)
+
+// Live error codes sent in error events.
+const (
+	liveErrInvalidRequest = "invalid_request"
+	liveErrUnsupported    = "unsupported"
+	liveErrSession        = "session_error"
+	liveErrIdleTimeout    = "idle_timeout"
+)
+
+// errLiveConnClosed is returned by readConfigure when the connection ended.
+var errLiveConnClosed = errors.New("live connection closed")
+
+// errLiveClientTools is returned for tools the client would have to run.
+// The live endpoint does not accept tool_result messages yet, so a session
+// using them could never complete a tool call.
+var errLiveClientTools = errors.New("tools executed by the client are not supported on the live endpoint yet")
+
+// liveSession is the part of live.Session used by the live endpoint.
+type liveSession interface {
+	Start(ctx context.Context) error
+	SendAudio(data []byte) error
+	SendText(text string) error
+	Commit() error
+	Interrupt(transcript string) error
+	AckPlayback(positionMs int)
+	Events() <-chan live.Event
+	Close() error
+}
+
+// LiveSessionConfig is the agent definition sent in session.configure.
+type LiveSessionConfig struct {
+	Model       string          `json:"model"`
+	System      string          `json:"system,omitempty"`
+	Tools       []types.Tool    `json:"tools,omitempty"`
+	Messages    []types.Message `json:"messages,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+
+	// SampleRate is the rate of the 16-bit mono PCM audio exchanged in
+	// binary frames. Default: 24000.
+	SampleRate int `json:"sample_rate,omitempty"`
+
+	// InputFormat describes client audio that is not 16-bit PCM at
+	// SampleRate, such as 48kHz float from a browser.
+	InputFormat audio.Format `json:"input_format,omitempty"`
+
+	Voice *LiveVoiceConfig `json:"voice,omitempty"`
+}
+
+// LiveVoiceConfig configures speech and turn-taking for a live session.
+// Omitted VAD, grace period and interrupt fields keep their defaults.
+type LiveVoiceConfig struct {
+	Input       *types.VoiceInputConfig  `json:"input,omitempty"`
+	Output      *types.VoiceOutputConfig `json:"output,omitempty"`
+	VAD         live.VADConfig           `json:"vad"`
+	GracePeriod live.GracePeriodConfig   `json:"grace_period"`
+	Interrupt   live.InterruptConfig     `json:"interrupt"`
+}
+
+// liveClientMessage is a JSON message from a live client.
+type liveClientMessage struct {
+	Type       string             `json:"type"`
+	Config     *LiveSessionConfig `json:"config,omitempty"`
+	Text       string             `json:"text,omitempty"`
+	Transcript string             `json:"transcript,omitempty"`
+	PositionMs int                `json:"position_ms,omitempty"`
+}
+
+// newLiveClientMessage returns a message whose config is pre-filled with
+// defaults, so that decoding a session.configure only overrides sent fields.
+func newLiveClientMessage() *liveClientMessage {
+	return &liveClientMessage{
+		Config: &LiveSessionConfig{
+			Voice: defaultLiveVoiceConfig(),
+		},
+	}
+}
+
+func defaultLiveVoiceConfig() *LiveVoiceConfig {
+	return &LiveVoiceConfig{
+		VAD:         live.DefaultVADConfig(),
+		GracePeriod: live.DefaultGracePeriodConfig(),
+		Interrupt:   live.DefaultInterruptConfig(),
+	}
+}
+
+// sessionConfig converts the client configuration to a live.SessionConfig.
+func (c *LiveSessionConfig) sessionConfig() (live.SessionConfig, error) {
+	if c.Model == "" {
+		return live.SessionConfig{}, fmt.Errorf("config.model is required")
+	}
+	for i, tool := range c.Tools {
+		switch tool.Type {
+		case types.ToolTypeFunction, types.ToolTypeComputerUse, types.ToolTypeTextEditor:
+			return live.SessionConfig{}, fmt.Errorf("config.tools[%d] (%s): %w", i, tool.Type, errLiveClientTools)
+		}
+	}
+	voice := c.Voice
+	if voice == nil {
+		voice = defaultLiveVoiceConfig()
+	}
+
+	cfg := live.SessionConfig{
+		Model:       c.Model,
+		System:      c.System,
+		Tools:       c.Tools,
+		Messages:    c.Messages,
+		MaxTokens:   c.MaxTokens,
+		Temperature: c.Temperature,
+		SampleRate:  c.SampleRate,
+		Channels:    1,
+		InputFormat: c.InputFormat,
+		VAD:         voice.VAD,
+		GracePeriod: voice.GracePeriod,
+		Interrupt:   voice.Interrupt,
+	}
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 24000
+	}
+	if cfg.MaxTokens == 0 {
+		cfg.MaxTokens = 1024
+	}
+	if voice.Input != nil || voice.Output != nil {
+		cfg.Voice = &types.VoiceConfig{Input: voice.Input, Output: voice.Output}
+	}
+
+	// Accept the mode names used in API_SPEC §11.4
+	switch cfg.Interrupt.Mode {
+	case "manual", "disabled":
+		cfg.Interrupt.Mode = live.InterruptModeNever
+	case live.InterruptModeAuto, live.InterruptModeAlways, live.InterruptModeNever:
+	default:
+		return live.SessionConfig{}, fmt.Errorf("invalid interrupt mode %q", cfg.Interrupt.Mode)
+	}
+	switch cfg.Interrupt.SavePartial {
+	case "discard":
+		cfg.Interrupt.SavePartial = live.PartialSaveNone
+	case "save":
+		cfg.Interrupt.SavePartial = live.PartialSaveFull
+	case live.PartialSaveNone, live.PartialSaveMarked, live.PartialSaveFull:
+	default:
+		return live.SessionConfig{}, fmt.Errorf("invalid save_partial %q", cfg.Interrupt.SavePartial)
+	}
+
+	return cfg, nil
+}
+
+// liveProviders returns the TTS and STT providers of live sessions, or an
+// error when the voice pipeline lacks either.
+func (s *Server) liveProviders() (tts.Provider, stt.Provider, error) {
+	if s.voicePipeline == nil {
+		return nil, nil, errors.New("voice pipeline not configured")
+	}
+	ttsProvider, err := s.voicePipeline.TTSProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+	sttProvider, err := s.voicePipeline.STTProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ttsProvider, sttProvider, nil
+}
+
+// newLiveSession creates a live session backed by the engine and voice
+// pipeline. handleLive has checked that both providers are configured.
+func (s *Server) newLiveSession(cfg live.SessionConfig) liveSession {
+	ttsProvider, sttProvider, _ := s.liveProviders()
+	return live.NewSession(cfg, liveLLMClient{engine: s.engine}, ttsProvider, sttProvider)
+}
+
+// handleLive handles WebSocket /v1/messages/live (API_SPEC §11).
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	keyConfig, ok := s.auth.AuthenticateWebSocket(r)
+	if !ok {
+		s.writeError(w, http.StatusUnauthorized, "authentication_error", "Missing or invalid API key")
+		return
+	}
+	if _, _, err := s.liveProviders(); err != nil {
+		s.writeError(w, http.StatusServiceUnavailable, "api_error", "Live sessions unavailable: "+err.Error())
+		return
+	}
+
+	// Reserve a session slot before upgrading
+	if !s.rateLimiter.CheckLiveSessionLimit(int(s.liveSessions.Add(1) - 1)) {
+		s.liveSessions.Add(-1)
+		s.metrics.RecordRateLimitHit(keyConfig.UserID, "live_sessions")
+		s.writeError(w, http.StatusTooManyRequests, "rate_limit_error", "Too many concurrent live sessions")
+		return
+	}
+	defer s.liveSessions.Add(-1)
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		return
+	}
+
+	lc := &liveConn{
+		server:      s,
+		conn:        conn,
+		userID:      keyConfig.UserID,
+		idleTimeout: s.config.RateLimit.SessionIdleTimeout,
+	}
+	lc.serve(r.Context())
+}
+
+// liveConn serves one live WebSocket connection.
+type liveConn struct {
+	server      *Server
+	conn        *websocket.Conn
+	userID      string
+	idleTimeout time.Duration
+
+	writeMu sync.Mutex
+	closing atomic.Bool
+}
+
+func (c *liveConn) serve(ctx context.Context) {
+	defer c.conn.Close()
+	s := c.server
+
+	// The first message must configure the session
+	cfg, err := c.readConfigure()
+	if err != nil {
+		switch {
+		case errors.Is(err, errLiveConnClosed):
+		case errors.Is(err, errLiveClientTools):
+			c.closeWithError(liveErrUnsupported, err.Error())
+		default:
+			c.closeWithError(liveErrInvalidRequest, err.Error())
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	session := s.liveSessionFactory(cfg)
+	if err := session.Start(ctx); err != nil {
+		session.Close()
+		c.closeWithError(liveErrSession, err.Error())
+		return
+	}
+
+	provider, model, _ := core.ParseModelString(cfg.Model)
+	start := time.Now()
+	s.metrics.RecordLiveSessionStart()
+	s.logger.Info("live session started", "user_id", c.userID, "model", cfg.Model)
+
+	// Close gracefully when the server shuts down
+	go func() {
+		select {
+		case <-s.done:
+			c.close(websocket.CloseGoingAway, "server shutting down")
+		case <-ctx.Done():
+		}
+	}()
+
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		c.forwardEvents(session, provider, model)
+	}()
+
+	status := c.readLoop(session)
+
+	session.Close()
+	<-forwarded
+
+	duration := time.Since(start)
+	s.metrics.RecordLiveSessionEnd(provider, model, status, duration)
+	s.logger.Info("live session ended",
+		"user_id", c.userID,
+		"model", cfg.Model,
+		"status", status,
+		"duration_ms", duration.Milliseconds(),
+	)
+}
+
+// readConfigure reads the session.configure message.
+func (c *liveConn) readConfigure() (live.SessionConfig, error) {
+	c.extendReadDeadline()
+	msgType, data, err := c.conn.ReadMessage()
+	if err != nil {
+		c.readStatus(err)
+		return live.SessionConfig{}, errLiveConnClosed
+	}
+	if msgType != websocket.TextMessage {
+		return live.SessionConfig{}, fmt.Errorf("first message must be session.configure")
+	}
+
+	msg := newLiveClientMessage()
+	if err := json.Unmarshal(data, msg); err != nil {
+		return live.SessionConfig{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if msg.Type != "session.configure" || msg.Config == nil {
+		return live.SessionConfig{}, fmt.Errorf("first message must be session.configure")
+	}
+	return msg.Config.sessionConfig()
+}
+
+// readLoop relays client audio and messages to the session until the
+// connection ends, and returns the session status for metrics.
+func (c *liveConn) readLoop(session liveSession) string {
+	for {
+		c.extendReadDeadline()
+		msgType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return c.readStatus(err)
+		}
+
+		switch msgType {
+		case websocket.BinaryMessage:
+			c.server.metrics.RecordLiveAudio("input", len(data))
+			if err := session.SendAudio(data); err != nil {
+				c.sendError(liveErrSession, err.Error())
+			}
+		case websocket.TextMessage:
+			c.handleMessage(session, data)
+		}
+	}
+}
+
+// readStatus maps the error that ended the read loop to a session status.
+func (c *liveConn) readStatus(err error) string {
+	var netErr net.Error
+	switch {
+	case c.closing.Load():
+		return "success"
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+		return "success"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		c.closeWithError(liveErrIdleTimeout, fmt.Sprintf("No client messages for %s", c.idleTimeout))
+		return "idle_timeout"
+	default:
+		return "error"
+	}
+}
+
+func (c *liveConn) handleMessage(session liveSession, data []byte) {
+	var msg liveClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.sendError(liveErrInvalidRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	var err error
+	switch msg.Type {
+	case "input.text":
+		err = session.SendText(msg.Text)
+	case "input.commit":
+		err = session.Commit()
+	case "input.interrupt":
+		err = session.Interrupt(msg.Transcript)
+	case "output.played":
+		session.AckPlayback(msg.PositionMs)
+	case "session.configure":
+		err = fmt.Errorf("session is already configured")
+	case "session.update", "tool_result":
+		c.sendError(liveErrUnsupported, fmt.Sprintf("%s is not supported yet", msg.Type))
+		return
+	default:
+		err = fmt.Errorf("unknown message type %q", msg.Type)
+	}
+	if err != nil {
+		c.sendError(liveErrInvalidRequest, err.Error())
+	}
+}
+
+// forwardEvents writes session events to the client. Audio is sent as
+// binary frames and everything else as JSON. When the session ends on its
+// own, the connection is closed.
+func (c *liveConn) forwardEvents(session liveSession, provider, model string) {
+	for event := range session.Events() {
+		var err error
+		switch e := event.(type) {
+		case *live.AudioDeltaEvent:
+			c.server.metrics.RecordLiveAudio("output", len(e.Data))
+			err = c.write(websocket.BinaryMessage, e.Data)
+		case *live.TurnLatencyEvent:
+			c.recordTurnLatency(provider, model, e)
+			err = c.writeEvent(event)
+		default:
+			err = c.writeEvent(event)
+		}
+		if err != nil {
+			return
+		}
+	}
+	c.close(websocket.CloseNormalClosure, "session closed")
+}
+
+// recordTurnLatency records the stages of a turn. Stages that did not run
+// in the turn are skipped.
+func (c *liveConn) recordTurnLatency(provider, model string, e *live.TurnLatencyEvent) {
+	stages := []struct {
+		name     string
+		ms       int
+		optional bool
+	}{
+		{"endpointing", e.EndpointingMs, true},
+		{"semantic_check", e.SemanticCheckMs, true},
+		{"grace_period", e.GracePeriodMs, true},
+		{"interrupt_detection", e.InterruptDetectionMs, true},
+		{"llm_first_token", e.LLMFirstTokenMs, false},
+		{"tts_first_audio", e.TTSFirstAudioMs, false},
+		{"mouth_to_ear", e.MouthToEarMs, false},
+	}
+	for _, stage := range stages {
+		if stage.optional && stage.ms == 0 {
+			continue
+		}
+		c.server.metrics.RecordLiveTurnLatency(provider, model, stage.name, time.Duration(stage.ms)*time.Millisecond)
+	}
+}
+
+// writeEvent writes an event as JSON with its type in a "type" field.
+func (c *liveConn) writeEvent(event live.Event) error {
+	data, err := marshalLiveEvent(event)
+	if err != nil {
+		return err
+	}
+	return c.write(websocket.TextMessage, data)
+}
+
+func (c *liveConn) sendError(code, message string) {
+	c.writeEvent(&live.ErrorEvent{Code: code, Message: message})
+}
+
+// closeWithError sends an error event and closes the connection.
+func (c *liveConn) closeWithError(code, message string) {
+	c.sendError(code, message)
+	closeCode := websocket.ClosePolicyViolation
+	if code == liveErrSession {
+		closeCode = websocket.CloseInternalServerErr
+	}
+	c.close(closeCode, code)
+}
+
+// close starts the close handshake. The read loop returns when the client
+// answers or after liveCloseGrace.
+func (c *liveConn) close(code int, reason string) {
+	if c.closing.Swap(true) {
+		return
+	}
+	c.writeMu.Lock()
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(liveWriteTimeout))
+	c.writeMu.Unlock()
+	c.conn.SetReadDeadline(time.Now().Add(liveCloseGrace))
+}
+
+func (c *liveConn) write(msgType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	return c.conn.WriteMessage(msgType, data)
+}
+
+func (c *liveConn) extendReadDeadline() {
+	if c.closing.Load() {
+		return
+	}
+	if c.idleTimeout > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+	} else {
+		c.conn.SetReadDeadline(time.Time{})
+	}
+}
+
+// marshalLiveEvent encodes a live event as a JSON object whose first
+// field is "type", as described in API_SPEC §11.6.
+func marshalLiveEvent(event live.Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := json.Marshal(event.EventType())
+	if err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(`{"type":`), typ...)
+	if len(data) > 2 {
+		out = append(out, ',')
+	}
+	return append(out, data[1:]...), nil
+}
+
+// liveLLMClient adapts the engine to live.LLMClient.
+type liveLLMClient struct {
+	engine *core.Engine
+}
+
+func (c liveLLMClient) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	return c.engine.CreateMessage(ctx, req)
+}
+
+func (c liveLLMClient) StreamMessage(ctx context.Context, req *types.MessageRequest) (live.EventStream, error) {
+	return c.engine.StreamMessage(ctx, req)
+}
@@ -0,0 +1,440 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vango-go/vai/pkg/core/live"
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+)
+
+// fakeLiveSession echoes input back as events.
+type fakeLiveSession struct {
+	config live.SessionConfig
+	events chan live.Event
+
+	mu       sync.Mutex
+	audio    [][]byte
+	playedMs int
+	closed   bool
+}
+
+func (f *fakeLiveSession) Start(ctx context.Context) error {
+	f.events <- &live.SessionCreatedEvent{SessionID: "live_test", SampleRate: f.config.SampleRate, Channels: 1}
+	return nil
+}
+
+func (f *fakeLiveSession) SendAudio(data []byte) error {
+	f.mu.Lock()
+	f.audio = append(f.audio, data)
+	f.mu.Unlock()
+	// Speak back what was heard
+	f.events <- &live.AudioDeltaEvent{Data: data, Format: "pcm_s16le"}
+	return nil
+}
+
+func (f *fakeLiveSession) SendText(text string) error {
+	f.events <- &live.InputCommittedEvent{Transcript: text}
+	return nil
+}
+
+func (f *fakeLiveSession) Commit() error {
+	return nil
+}
+
+func (f *fakeLiveSession) Interrupt(transcript string) error {
+	f.events <- &live.AudioFlushEvent{}
+	return nil
+}
+
+func (f *fakeLiveSession) AckPlayback(positionMs int) {
+	f.mu.Lock()
+	f.playedMs = positionMs
+	f.mu.Unlock()
+	f.events <- &live.AudioCommittedEvent{DurationMs: positionMs}
+}
+
+func (f *fakeLiveSession) Events() <-chan live.Event { return f.events }
+
+func (f *fakeLiveSession) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.closed {
+		f.closed = true
+		close(f.events)
+	}
+	return nil
+}
+
+func (f *fakeLiveSession) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// newLiveTestServer starts a proxy whose live sessions are fakes.
+func newLiveTestServer(t *testing.T, opts ...ConfigOption) (*Server, string, <-chan *fakeLiveSession) {
+	t.Helper()
+	requireTCPListenServer(t)
+
+	server, err := NewServer(append([]ConfigOption{WithAPIKey("test-key", "test", "user1", 100)}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.voicePipeline = voice.NewPipeline("test-key")
+
+	sessions := make(chan *fakeLiveSession, 4)
+	server.liveSessionFactory = func(cfg live.SessionConfig) liveSession {
+		session := &fakeLiveSession{config: cfg, events: make(chan live.Event, 10)}
+		sessions <- session
+		return session
+	}
+
+	ts := httptest.NewServer(server.mux)
+	t.Cleanup(ts.Close)
+	return server, "ws" + strings.TrimPrefix(ts.URL, "http") + "/v1/messages/live", sessions
+}
+
+func dialLive(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	header := http.Header{"Authorization": {"Bearer test-key"}}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readLiveJSON(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msgType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if msgType != websocket.TextMessage {
+		t.Fatalf("expected a text frame, got type %d", msgType)
+	}
+	var msg map[string]any
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return msg
+}
+
+func TestServer_Live(t *testing.T) {
+	server, url, sessions := newLiveTestServer(t)
+	conn := dialLive(t, url)
+
+	conn.WriteJSON(map[string]any{
+		"type": "session.configure",
+		"config": map[string]any{
+			"model":       "anthropic/claude-haiku-4-5-20251001",
+			"system":      "Be brief.",
+			"sample_rate": 16000,
+			"voice": map[string]any{
+				"output":    map[string]any{"voice": "v1"},
+				"interrupt": map[string]any{"mode": "disabled"},
+			},
+		},
+	})
+
+	created := readLiveJSON(t, conn)
+	if created["type"] != "session.created" || created["session_id"] != "live_test" {
+		t.Fatalf("expected session.created, got %v", created)
+	}
+	session := <-sessions
+	if session.config.SampleRate != 16000 || session.config.System != "Be brief." {
+		t.Errorf("unexpected session config: %+v", session.config)
+	}
+	if session.config.Voice == nil || session.config.Voice.Output.Voice != "v1" {
+		t.Errorf("voice output not passed through: %+v", session.config.Voice)
+	}
+	if session.config.Interrupt.Mode != live.InterruptModeNever {
+		t.Errorf("expected interrupt mode never, got %q", session.config.Interrupt.Mode)
+	}
+	if session.config.GracePeriod != live.DefaultGracePeriodConfig() {
+		t.Errorf("expected default grace period, got %+v", session.config.GracePeriod)
+	}
+
+	// Binary audio in, binary audio out
+	pcm := make([]byte, 640)
+	if err := conn.WriteMessage(websocket.BinaryMessage, pcm); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msgType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read audio: %v", err)
+	}
+	if msgType != websocket.BinaryMessage || len(data) != len(pcm) {
+		t.Errorf("expected %d bytes of binary audio, got type %d with %d bytes", len(pcm), msgType, len(data))
+	}
+
+	conn.WriteJSON(map[string]any{"type": "input.text", "text": "hello"})
+	if msg := readLiveJSON(t, conn); msg["type"] != "input.committed" || msg["transcript"] != "hello" {
+		t.Errorf("expected input.committed, got %v", msg)
+	}
+
+	conn.WriteJSON(map[string]any{"type": "output.played", "position_ms": 1200})
+	if msg := readLiveJSON(t, conn); msg["type"] != "audio.committed" {
+		t.Errorf("expected audio.committed, got %v", msg)
+	}
+	session.mu.Lock()
+	if session.playedMs != 1200 {
+		t.Errorf("expected playback acknowledged at 1200ms, got %d", session.playedMs)
+	}
+	session.mu.Unlock()
+
+	conn.WriteJSON(map[string]any{"type": "session.update", "config": map[string]any{"model": "openai/gpt-4o"}})
+	if msg := readLiveJSON(t, conn); msg["type"] != "error" || msg["code"] != liveErrUnsupported {
+		t.Errorf("expected unsupported error, got %v", msg)
+	}
+
+	if got := testutil.ToFloat64(server.metrics.LiveSessionsActive); got != 1 {
+		t.Errorf("expected 1 active session, got %v", got)
+	}
+
+	// Graceful close from the client ends the session
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye"))
+	deadline := time.Now().Add(2 * time.Second)
+	for testutil.ToFloat64(server.metrics.LiveSessionsActive) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !session.isClosed() {
+		t.Error("session was not closed")
+	}
+	if got := testutil.ToFloat64(server.metrics.LiveSessionsTotal.WithLabelValues("success")); got != 1 {
+		t.Errorf("expected 1 successful session, got %v", got)
+	}
+	if got := testutil.ToFloat64(server.metrics.LiveAudioBytesTotal.WithLabelValues("input")); got != 640 {
+		t.Errorf("expected 640 input audio bytes, got %v", got)
+	}
+}
+
+func TestServer_LiveTurnLatency(t *testing.T) {
+	server, url, sessions := newLiveTestServer(t)
+	conn := dialLive(t, url)
+
+	conn.WriteJSON(map[string]any{
+		"type":   "session.configure",
+		"config": map[string]any{"model": "anthropic/claude-haiku-4-5-20251001"},
+	})
+	readLiveJSON(t, conn)
+	session := <-sessions
+
+	session.events <- &live.TurnLatencyEvent{
+		EndpointingMs:   400,
+		LLMFirstTokenMs: 350,
+		TTSFirstAudioMs: 150,
+		MouthToEarMs:    1100,
+	}
+	if msg := readLiveJSON(t, conn); msg["type"] != "turn.latency" || msg["mouth_to_ear_ms"] != 1100.0 {
+		t.Fatalf("expected turn.latency, got %v", msg)
+	}
+
+	// Stages that did not run are not observed
+	if n := testutil.CollectAndCount(server.metrics.LiveTurnLatency); n != 4 {
+		t.Errorf("expected 4 latency stages, got %d", n)
+	}
+	families, err := server.metrics.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum float64
+	for _, family := range families {
+		if family.GetName() != "vango_live_turn_latency_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["provider"] != "anthropic" || labels["model"] != "claude-haiku-4-5-20251001" {
+				t.Errorf("unexpected labels %v", labels)
+			}
+			if labels["stage"] == "mouth_to_ear" {
+				sum = m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	if sum != 1.1 {
+		t.Errorf("expected 1.1s mouth-to-ear, got %v", sum)
+	}
+}
+
+func TestServer_LiveRequiresConfigure(t *testing.T) {
+	_, url, _ := newLiveTestServer(t)
+	conn := dialLive(t, url)
+
+	conn.WriteMessage(websocket.BinaryMessage, make([]byte, 64))
+	msg := readLiveJSON(t, conn)
+	if msg["type"] != "error" || msg["code"] != liveErrInvalidRequest {
+		t.Fatalf("expected invalid_request error, got %v", msg)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("expected policy violation close, got %v", err)
+	}
+}
+
+func TestServer_LiveRejectsClientTools(t *testing.T) {
+	_, url, sessions := newLiveTestServer(t)
+	conn := dialLive(t, url)
+
+	conn.WriteJSON(map[string]any{
+		"type": "session.configure",
+		"config": map[string]any{
+			"model": "anthropic/claude-sonnet-4",
+			"tools": []map[string]any{
+				{"type": "web_search"},
+				{"type": "function", "name": "get_weather"},
+			},
+		},
+	})
+	msg := readLiveJSON(t, conn)
+	if msg["type"] != "error" || msg["code"] != liveErrUnsupported {
+		t.Fatalf("expected unsupported error, got %v", msg)
+	}
+	if message, _ := msg["message"].(string); !strings.Contains(message, "config.tools[1]") {
+		t.Errorf("expected the error to name config.tools[1], got %q", message)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("expected policy violation close, got %v", err)
+	}
+	select {
+	case <-sessions:
+		t.Error("expected no session to be created")
+	default:
+	}
+}
+
+func TestServer_LiveIdleTimeout(t *testing.T) {
+	server, url, sessions := newLiveTestServer(t)
+	server.config.RateLimit.SessionIdleTimeout = 100 * time.Millisecond
+	conn := dialLive(t, url)
+
+	conn.WriteJSON(map[string]any{"type": "session.configure", "config": map[string]any{"model": "fake/model"}})
+	readLiveJSON(t, conn) // session.created
+	session := <-sessions
+
+	msg := readLiveJSON(t, conn)
+	if msg["type"] != "error" || msg["code"] != liveErrIdleTimeout {
+		t.Fatalf("expected idle_timeout error, got %v", msg)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !session.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !session.isClosed() {
+		t.Error("idle session was not closed")
+	}
+}
+
+func TestServer_LiveAuthAndLimits(t *testing.T) {
+	server, url, _ := newLiveTestServer(t)
+
+	// Missing API key
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without an API key, got %v", resp)
+	}
+
+	// API key as a query parameter
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?api_key=test-key", nil)
+	if err != nil {
+		t.Fatalf("dial with api_key query parameter: %v", err)
+	}
+	conn.Close()
+
+	// Session limit
+	server.rateLimiter.config.MaxConcurrentSessions = 0
+	_, resp, err = websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer test-key"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429 over the session limit, got %v", resp)
+	}
+}
+
+func TestServer_LiveRequiresProviders(t *testing.T) {
+	requireTCPListenServer(t)
+	server, err := NewServer(WithAPIKey("test-key", "test", "user1", 100))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.voicePipeline = voice.NewPipelineWithFailover([]stt.Provider{stt.NewCartesia("test-key")}, nil)
+	ts := httptest.NewServer(server.mux)
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/v1/messages/live"
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer test-key"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a TTS provider, got %v", resp)
+	}
+}
+
+func TestServer_LiveShutdown(t *testing.T) {
+	server, url, _ := newLiveTestServer(t)
+	conn := dialLive(t, url)
+
+	conn.WriteJSON(map[string]any{"type": "session.configure", "config": map[string]any{"model": "fake/model"}})
+	readLiveJSON(t, conn) // session.created
+
+	server.Shutdown(context.Background())
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expected going away close on shutdown, got %v", err)
+	}
+}
+
+func TestMarshalLiveEvent(t *testing.T) {
+	data, err := marshalLiveEvent(&live.VADListeningEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"vad.listening"}` {
+		t.Errorf("unexpected JSON for empty event: %s", data)
+	}
+
+	data, err = marshalLiveEvent(&live.VADSilenceEvent{DurationMs: 1500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"vad.silence","duration_ms":1500}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestLiveSessionConfig_Invalid(t *testing.T) {
+	for _, body := range []string{
+		`{"type":"session.configure","config":{}}`,
+		`{"type":"session.configure","config":{"model":"a/b","voice":{"interrupt":{"mode":"sometimes"}}}}`,
+		`{"type":"session.configure","config":{"model":"a/b","voice":{"interrupt":{"save_partial":"maybe"}}}}`,
+	} {
+		msg := newLiveClientMessage()
+		if err := json.Unmarshal([]byte(body), msg); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := msg.Config.sessionConfig(); err == nil {
+			t.Errorf("expected an error for %s", body)
+		}
+	}
+}
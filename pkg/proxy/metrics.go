@@ -28,6 +28,7 @@ type Metrics struct {
 	LiveSessionsTotal   *prometheus.CounterVec
 	LiveSessionDuration *prometheus.HistogramVec
 	LiveAudioBytesTotal *prometheus.CounterVec
+	LiveTurnLatency     *prometheus.HistogramVec
 
 	// Error metrics
 	ErrorsTotal *prometheus.CounterVec
@@ -117,6 +118,16 @@ func NewMetrics(namespace string) *Metrics {
 		[]string{"direction"},
 	)
 
+	liveTurnLatency := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "live_turn_latency_seconds",
+			Help:      "Latency of each stage of a live session turn in seconds",
+			Buckets:   []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10},
+		},
+		[]string{"provider", "model", "stage"},
+	)
+
 	errorsTotal := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
@@ -145,6 +156,7 @@ func NewMetrics(namespace string) *Metrics {
 		liveSessionsTotal,
 		liveSessionDuration,
 		liveAudioBytesTotal,
+		liveTurnLatency,
 		errorsTotal,
 		rateLimitHits,
 	)
@@ -159,6 +171,7 @@ func NewMetrics(namespace string) *Metrics {
 		LiveSessionsTotal:   liveSessionsTotal,
 		LiveSessionDuration: liveSessionDuration,
 		LiveAudioBytesTotal: liveAudioBytesTotal,
+		LiveTurnLatency:     liveTurnLatency,
 		ErrorsTotal:         errorsTotal,
 		RateLimitHits:       rateLimitHits,
 	}
@@ -209,6 +222,12 @@ func (m *Metrics) RecordLiveAudio(direction string, bytes int) {
 	m.LiveAudioBytesTotal.WithLabelValues(direction).Add(float64(bytes))
 }
 
+// RecordLiveTurnLatency records how long one stage of a live session turn
+// took, e.g. "llm_first_token" or "mouth_to_ear".
+func (m *Metrics) RecordLiveTurnLatency(provider, model, stage string, duration time.Duration) {
+	m.LiveTurnLatency.WithLabelValues(provider, model, stage).Observe(duration.Seconds())
+}
+
 // RecordError records an error.
 func (m *Metrics) RecordError(provider, errorType string) {
 	m.ErrorsTotal.WithLabelValues(provider, errorType).Inc()
@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
+)
+
+// providerDisplayNames maps provider IDs to human-readable names.
+var providerDisplayNames = map[string]string{
+	"anthropic":    "Anthropic",
+	"openai":       "OpenAI",
+	"oai-resp":     "OpenAI Responses",
+	"groq":         "Groq",
+	"cerebras":     "Cerebras",
+	"gemini":       "Google Gemini",
+	"gemini-oauth": "Google Gemini (OAuth)",
+}
+
+// ModelsResponse is the body of GET /v1/models.
+type ModelsResponse struct {
+	Providers []ProviderModels `json:"providers"`
+}
+
+// ProviderModels groups the models of one provider.
+type ProviderModels struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Models []ModelInfo `json:"models"`
+}
+
+// ModelInfo describes a single model in the /v1/models response.
+type ModelInfo struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	FullID          string            `json:"full_id"`
+	Description     string            `json:"description,omitempty"`
+	ContextWindow   int               `json:"context_window"`
+	MaxOutputTokens int               `json:"max_output_tokens"`
+	Capabilities    ModelCapabilities `json:"capabilities"`
+	NativeTools     []string          `json:"native_tools"`
+	Pricing         *ModelPricingInfo `json:"pricing,omitempty"`
+}
+
+// ModelCapabilities lists the feature flags of a model.
+type ModelCapabilities struct {
+	Text             bool `json:"text"`
+	Vision           bool `json:"vision"`
+	AudioInput       bool `json:"audio_input"`
+	AudioOutput      bool `json:"audio_output"`
+	Video            bool `json:"video"`
+	Tools            bool `json:"tools"`
+	ToolStreaming    bool `json:"tool_streaming"`
+	Thinking         bool `json:"thinking"`
+	StructuredOutput bool `json:"structured_output"`
+}
+
+// ModelPricingInfo is the per-million-token pricing of a model.
+type ModelPricingInfo struct {
+	InputPerMTok      float64 `json:"input_per_mtok"`
+	OutputPerMTok     float64 `json:"output_per_mtok"`
+	CacheReadPerMTok  float64 `json:"cache_read_per_mtok,omitempty"`
+	CacheWritePerMTok float64 `json:"cache_write_per_mtok,omitempty"`
+	Currency          string  `json:"currency,omitempty"`
+}
+
+// NewModelInfo converts a catalog entry to its API representation.
+func NewModelInfo(m catalog.Model) ModelInfo {
+	info := ModelInfo{
+		ID:              m.Name,
+		Name:            m.DisplayName,
+		FullID:          m.ID,
+		Description:     m.Description,
+		ContextWindow:   m.Context,
+		MaxOutputTokens: m.MaxOutput,
+		Capabilities: ModelCapabilities{
+			Text:             true,
+			Vision:           m.Capabilities.Vision,
+			AudioInput:       m.Capabilities.AudioInput,
+			AudioOutput:      m.Capabilities.AudioOutput,
+			Video:            m.Capabilities.Video,
+			Tools:            m.Capabilities.Tools,
+			ToolStreaming:    m.Capabilities.ToolStreaming,
+			Thinking:         m.Capabilities.Thinking,
+			StructuredOutput: m.Capabilities.StructuredOutput,
+		},
+		NativeTools: m.Capabilities.NativeTools,
+	}
+	if info.NativeTools == nil {
+		info.NativeTools = []string{}
+	}
+	if m.Pricing != nil {
+		info.Pricing = &ModelPricingInfo{
+			InputPerMTok:      m.Pricing.InputPerMillion,
+			OutputPerMTok:     m.Pricing.OutputPerMillion,
+			CacheReadPerMTok:  m.Pricing.CacheReadPerMillion,
+			CacheWritePerMTok: m.Pricing.CacheWritePerMillion,
+			Currency:          m.Pricing.Currency,
+		}
+	}
+	return info
+}
+
+// handleModels handles /v1/models requests.
+// The ?provider= and ?capability= query parameters filter the list.
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var models []catalog.Model
+	if provider := query.Get("provider"); provider != "" {
+		models = s.catalog.ListByProvider(provider)
+	} else {
+		models = s.catalog.List()
+	}
+
+	if capability := query.Get("capability"); capability != "" {
+		models = slices.DeleteFunc(models, func(m catalog.Model) bool {
+			return !hasCapability(m.Capabilities, capability)
+		})
+	}
+
+	resp := ModelsResponse{Providers: []ProviderModels{}}
+	for _, m := range models {
+		if len(resp.Providers) == 0 || resp.Providers[len(resp.Providers)-1].ID != m.Provider {
+			name := providerDisplayNames[m.Provider]
+			if name == "" {
+				name = m.Provider
+			}
+			resp.Providers = append(resp.Providers, ProviderModels{ID: m.Provider, Name: name})
+		}
+		group := &resp.Providers[len(resp.Providers)-1]
+		group.Models = append(group.Models, NewModelInfo(m))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleModel handles /v1/models/{provider}/{model} requests.
+func (s *Server) handleModel(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/models/")
+	model, ok := s.catalog.Get(id)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "not_found_error", fmt.Sprintf("model %q not found", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(NewModelInfo(model))
+}
+
+// hasCapability reports whether caps includes a named feature or native tool.
+func hasCapability(caps core.ProviderCapabilities, name string) bool {
+	switch name {
+	case "text":
+		return true
+	case "vision":
+		return caps.Vision
+	case "audio_input":
+		return caps.AudioInput
+	case "audio_output":
+		return caps.AudioOutput
+	case "video":
+		return caps.Video
+	case "tools":
+		return caps.Tools
+	case "tool_streaming":
+		return caps.ToolStreaming
+	case "thinking":
+		return caps.Thinking
+	case "structured_output":
+		return caps.StructuredOutput
+	default:
+		return slices.Contains(caps.NativeTools, name)
+	}
+}
@@ -3,19 +3,27 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
+	"github.com/vango-go/vai/pkg/core/live"
 	"github.com/vango-go/vai/pkg/core/providers/anthropic"
 	"github.com/vango-go/vai/pkg/core/types"
 	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
+	"github.com/vango-go/vai/pkg/core/voice/tts"
 )
 
 // Server is the Vango AI proxy server.
@@ -26,6 +34,7 @@ type Server struct {
 	// Core components
 	engine        *core.Engine
 	voicePipeline *voice.Pipeline
+	catalog       *catalog.Catalog
 
 	// HTTP server
 	httpServer *http.Server
@@ -44,6 +53,10 @@ type Server struct {
 	// WebSocket upgrader
 	upgrader websocket.Upgrader
 
+	// Live sessions
+	liveSessions       atomic.Int64
+	liveSessionFactory func(live.SessionConfig) liveSession
+
 	// Lifecycle
 	done     chan struct{}
 	shutdown atomic.Bool
@@ -69,6 +82,14 @@ func NewServer(opts ...ConfigOption) (*Server, error) {
 
 	// Initialize core engine
 	engine := core.NewEngine(config.ProviderKeys)
+	engine.Use(config.Middleware...)
+	switch config.CapabilityMode {
+	case "":
+	case core.CapabilityStrict, core.CapabilityDegrade, core.CapabilityOff:
+		engine.SetCapabilityMode(config.CapabilityMode)
+	default:
+		return nil, fmt.Errorf("invalid capability mode %q", config.CapabilityMode)
+	}
 
 	// Register providers
 	if anthropicKey := config.ProviderKeys["anthropic"]; anthropicKey != "" {
@@ -79,14 +100,34 @@ func NewServer(opts ...ConfigOption) (*Server, error) {
 		engine.RegisterProvider(newAnthropicAdapter(provider))
 	}
 
-	// Initialize voice pipeline
-	var voicePipeline *voice.Pipeline
-	cartesiaKey := config.ProviderKeys["cartesia"]
-	if cartesiaKey == "" {
-		cartesiaKey = os.Getenv("CARTESIA_API_KEY")
+	// Register model routes
+	for name, targets := range config.ModelRoutes {
+		if err := engine.SetModelRoute(name, targets...); err != nil {
+			return nil, fmt.Errorf("model route %q: %w", name, err)
+		}
 	}
-	if cartesiaKey != "" {
-		voicePipeline = voice.NewPipeline(cartesiaKey)
+
+	// Load the model catalog
+	modelCatalog := config.ModelCatalog
+	if modelCatalog == nil {
+		modelCatalog = catalog.Default()
+	}
+	if config.ModelCatalogFile != "" {
+		if err := modelCatalog.LoadFile(config.ModelCatalogFile); err != nil {
+			return nil, fmt.Errorf("model catalog: %w", err)
+		}
+	}
+	engine.SetPricing(modelCatalog)
+
+	// Initialize voice pipeline. Requests fail over between the providers
+	// with keys; a provider can be preferred per request as "provider/model"
+	// for STT and "provider/voice" for TTS.
+	var voicePipeline *voice.Pipeline
+	voiceKey := voice.ProviderKeys(config.ProviderKeys)
+	sttProviders := stt.ProvidersFromKeys(voiceKey)
+	ttsProviders := tts.ProvidersFromKeys(voiceKey)
+	if len(sttProviders) > 0 || len(ttsProviders) > 0 {
+		voicePipeline = voice.NewPipelineWithFailover(sttProviders, ttsProviders)
 	}
 
 	s := &Server{
@@ -94,6 +135,7 @@ func NewServer(opts ...ConfigOption) (*Server, error) {
 		logger:        logger,
 		engine:        engine,
 		voicePipeline: voicePipeline,
+		catalog:       modelCatalog,
 		metrics:       metrics,
 		done:          make(chan struct{}),
 		upgrader: websocket.Upgrader{
@@ -106,6 +148,8 @@ func NewServer(opts ...ConfigOption) (*Server, error) {
 		},
 	}
 
+	s.liveSessionFactory = s.newLiveSession
+
 	// Initialize middleware
 	s.auth = NewAuthMiddleware(config.APIKeys, logger, metrics)
 	s.rateLimiter = NewRateLimiter(config.RateLimit, logger, metrics)
@@ -138,6 +182,8 @@ func (s *Server) setupRoutes() {
 			s.handleMessages(w, r)
 		case r.Method == "GET" && r.URL.Path == "/v1/models":
 			s.handleModels(w, r)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/v1/models/"):
+			s.handleModel(w, r)
 		case r.Method == "POST" && r.URL.Path == "/v1/audio":
 			s.handleAudio(w, r)
 		default:
@@ -146,8 +192,10 @@ func (s *Server) setupRoutes() {
 	}))
 	s.mux.Handle("/v1/", apiHandler)
 
-	// WebSocket endpoint for live sessions
-	//s.mux.HandleFunc("GET /v1/messages/live", s.handleLive)
+	// WebSocket endpoint for live sessions. It authenticates itself, since
+	// API keys may be passed as a query parameter, and is not wrapped in the
+	// logging middleware, whose response writer cannot be hijacked.
+	s.mux.Handle("GET /v1/messages/live", s.recovery.Recover(http.HandlerFunc(s.handleLive)))
 }
 
 // withMiddleware wraps a handler with all middleware.
@@ -236,7 +284,8 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 	if s.voicePipeline != nil {
 		health["voice"] = map[string]any{
-			"status": "available",
+			"status":    "available",
+			"providers": s.voicePipeline.ProviderStatus(),
 		}
 	}
 
@@ -255,70 +304,158 @@ func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract provider and model
-	provider, model, err := core.ParseModelString(req.Model)
+	// Extract provider and model; routed requests are attributed to their first target
+	targets, err := s.engine.ResolveModel(req.Model)
 	if err != nil {
 		s.writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
 		return
 	}
+	provider, model, _ := core.ParseModelString(targets[0])
+
+	if req.Stream {
+		s.handleMessagesStream(w, r, &req, provider, model, start)
+		return
+	}
 
 	// Process request
 	resp, err := s.engine.CreateMessage(r.Context(), &req)
 	if err != nil {
 		s.metrics.RecordError(provider, "request_error")
-		s.writeError(w, http.StatusInternalServerError, "api_error", err.Error())
+		s.writeEngineError(w, err)
 		return
 	}
 
-	// Record metrics
+	// Record metrics against the target that actually served the request
+	provider, model = routedTarget(resp.Metadata, provider, model)
 	duration := time.Since(start)
 	s.metrics.RecordRequest(provider, model, "/v1/messages", "success", duration)
 	if resp.Usage.InputTokens > 0 || resp.Usage.OutputTokens > 0 {
 		s.metrics.RecordTokens(provider, model, resp.Usage.InputTokens, resp.Usage.OutputTokens)
 	}
+	if resp.Usage.CostUSD != nil {
+		s.metrics.RecordCost(provider, model, *resp.Usage.CostUSD)
+	}
 
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Request-ID", r.Context().Value(ContextKeyRequestID).(string))
 	w.Header().Set("X-Model", req.Model)
+	if routed, ok := resp.Metadata[core.MetadataRoutedModel].(string); ok {
+		w.Header().Set("X-Routed-Model", routed)
+	}
 	w.Header().Set("X-Input-Tokens", fmt.Sprint(resp.Usage.InputTokens))
 	w.Header().Set("X-Output-Tokens", fmt.Sprint(resp.Usage.OutputTokens))
+	if resp.Usage.CostUSD != nil {
+		w.Header().Set("X-Cost-USD", fmt.Sprintf("%.6f", *resp.Usage.CostUSD))
+	}
 	w.Header().Set("X-Duration-Ms", fmt.Sprint(duration.Milliseconds()))
 
 	json.NewEncoder(w).Encode(resp)
 }
 
-// handleModels handles /v1/models requests.
-func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
-	// Return available models
-	models := map[string]any{
-		"providers": []map[string]any{
-			{
-				"id":   "anthropic",
-				"name": "Anthropic",
-				"models": []map[string]any{
-					{
-						"id":      "claude-sonnet-4",
-						"name":    "Claude Sonnet 4",
-						"full_id": "anthropic/claude-sonnet-4",
-					},
-					{
-						"id":      "claude-opus-4",
-						"name":    "Claude Opus 4",
-						"full_id": "anthropic/claude-opus-4",
-					},
-					{
-						"id":      "claude-haiku-4-5-20251001",
-						"name":    "Claude Haiku 4.5",
-						"full_id": "anthropic/claude-haiku-4-5-20251001",
-					},
-				},
-			},
-		},
+// handleMessagesStream handles /v1/messages requests with stream=true.
+// Events are written as Server-Sent Events in the Anthropic format.
+func (s *Server) handleMessagesStream(w http.ResponseWriter, r *http.Request, req *MessageRequest, provider, model string, start time.Time) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "api_error", "Streaming not supported")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(models)
+	stream, err := s.engine.StreamMessage(r.Context(), req)
+	if err != nil {
+		s.metrics.RecordError(provider, "request_error")
+		s.writeEngineError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Model", req.Model)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var usage types.Usage
+	status := "success"
+	for {
+		event, err := stream.Next()
+		if err != nil {
+			if err != io.EOF {
+				status = "error"
+				s.metrics.RecordError(provider, "stream_error")
+				s.writeSSE(w, types.ErrorEvent{
+					Type:  "error",
+					Error: streamErrorFromError(err),
+				})
+				flusher.Flush()
+			}
+			break
+		}
+		if event == nil {
+			break
+		}
+
+		switch e := event.(type) {
+		case types.MessageStartEvent:
+			usage = usage.Add(e.Message.Usage)
+			provider, model = routedTarget(e.Message.Metadata, provider, model)
+		case types.MessageDeltaEvent:
+			usage.OutputTokens = e.Usage.OutputTokens
+			if e.Usage.CostUSD != nil {
+				usage.CostUSD = e.Usage.CostUSD
+			}
+		}
+
+		if err := s.writeSSE(w, event); err != nil {
+			// Client went away
+			status = "client_closed"
+			break
+		}
+		flusher.Flush()
+	}
+
+	s.metrics.RecordRequest(provider, model, "/v1/messages", status, time.Since(start))
+	if usage.InputTokens > 0 || usage.OutputTokens > 0 {
+		s.metrics.RecordTokens(provider, model, usage.InputTokens, usage.OutputTokens)
+	}
+	if usage.CostUSD != nil {
+		s.metrics.RecordCost(provider, model, *usage.CostUSD)
+	}
+}
+
+// routedTarget returns the provider and model recorded by the engine for a
+// routed request, or the given fallback values.
+func routedTarget(metadata map[string]any, provider, model string) (string, string) {
+	routed, ok := metadata[core.MetadataRoutedModel].(string)
+	if !ok {
+		return provider, model
+	}
+	p, m, err := core.ParseModelString(routed)
+	if err != nil {
+		return provider, model
+	}
+	return p, m
+}
+
+// writeSSE writes a single stream event in SSE format.
+func (s *Server) writeSSE(w http.ResponseWriter, event types.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.EventType(), data)
+	return err
+}
+
+// streamErrorFromError converts an error into a stream error payload.
+func streamErrorFromError(err error) types.Error {
+	var coreErr *core.Error
+	if errors.As(err, &coreErr) {
+		return types.Error{Type: string(coreErr.Type), Message: coreErr.Message}
+	}
+	return types.Error{Type: string(core.ErrAPI), Message: err.Error()}
 }
 
 // handleAudio handles /v1/audio requests.
@@ -355,6 +492,62 @@ func (s *Server) handleSynthesize(w http.ResponseWriter, r *http.Request, req *A
 	s.writeError(w, http.StatusNotImplemented, "api_error", "Synthesis not yet implemented")
 }
 
+// writeEngineError writes an error returned by the engine, preserving its type.
+func (s *Server) writeEngineError(w http.ResponseWriter, err error) {
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) {
+		s.writeError(w, http.StatusInternalServerError, "api_error", err.Error())
+		return
+	}
+
+	if coreErr.RetryAfter != nil {
+		w.Header().Set("Retry-After", strconv.Itoa(*coreErr.RetryAfter))
+	}
+
+	body := map[string]any{
+		"type":    coreErr.Type,
+		"message": coreErr.Message,
+	}
+	if coreErr.Param != "" {
+		body["param"] = coreErr.Param
+	}
+	if coreErr.Code != "" {
+		body["code"] = coreErr.Code
+	}
+	if coreErr.RetryAfter != nil {
+		body["retry_after"] = *coreErr.RetryAfter
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusForErrorType(coreErr.Type))
+	json.NewEncoder(w).Encode(map[string]any{
+		"type":  "error",
+		"error": body,
+	})
+}
+
+// statusForErrorType maps an error type to an HTTP status code.
+func statusForErrorType(t core.ErrorType) int {
+	switch t {
+	case core.ErrInvalidRequest:
+		return http.StatusBadRequest
+	case core.ErrAuthentication:
+		return http.StatusUnauthorized
+	case core.ErrPermission:
+		return http.StatusForbidden
+	case core.ErrNotFound:
+		return http.StatusNotFound
+	case core.ErrRateLimit:
+		return http.StatusTooManyRequests
+	case core.ErrOverloaded:
+		return 529
+	case core.ErrProvider:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // writeError writes an error response.
 func (s *Server) writeError(w http.ResponseWriter, status int, errType, message string) {
 	w.Header().Set("Content-Type", "application/json")
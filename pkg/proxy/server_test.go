@@ -3,12 +3,18 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
+	"github.com/vango-go/vai/pkg/core/types"
 )
 
 func requireTCPListenServer(t testing.TB) {
@@ -385,3 +391,330 @@ func TestServer_Shutdown(t *testing.T) {
 		t.Errorf("unexpected shutdown error: %v", err)
 	}
 }
+
+// fakeProvider is a core.Provider that replays canned responses.
+type fakeProvider struct {
+	name   string
+	resp   *types.MessageResponse
+	events []types.StreamEvent
+	err    error
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.resp, nil
+}
+
+func (p *fakeProvider) StreamMessage(ctx context.Context, req *types.MessageRequest) (core.EventStream, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &fakeEventStream{events: p.events}, nil
+}
+
+func (p *fakeProvider) Capabilities() core.ProviderCapabilities {
+	return core.ProviderCapabilities{Tools: true}
+}
+
+type fakeEventStream struct {
+	events []types.StreamEvent
+	index  int
+}
+
+func (s *fakeEventStream) Next() (types.StreamEvent, error) {
+	if s.index >= len(s.events) {
+		return nil, io.EOF
+	}
+	event := s.events[s.index]
+	s.index++
+	return event, nil
+}
+
+func (s *fakeEventStream) Close() error { return nil }
+
+func TestServer_MessagesStream(t *testing.T) {
+	server, err := NewServer(WithAPIKey("test-key", "test", "user1", 100))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{
+		name: "fake",
+		events: []types.StreamEvent{
+			types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{ID: "msg_1", Usage: types.Usage{InputTokens: 3}}},
+			types.ContentBlockStartEvent{Type: "content_block_start", Index: 0, ContentBlock: types.TextBlock{Type: "text"}},
+			types.ContentBlockDeltaEvent{Type: "content_block_delta", Index: 0, Delta: types.TextDelta{Type: "text_delta", Text: "hi"}},
+			types.MessageStopEvent{Type: "message_stop"},
+		},
+	})
+
+	body := `{"model":"fake/model","stream":true,"messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %s", ct)
+	}
+
+	out := w.Body.String()
+	for _, want := range []string{
+		"event: message_start\n",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"hi\"}}\n\n",
+		"event: message_stop\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected SSE output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestServer_MessagesErrorStatus(t *testing.T) {
+	server, err := NewServer(WithAPIKey("test-key", "test", "user1", 100))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{
+		name: "fake",
+		err:  core.NewRateLimitError("slow down", 7),
+	})
+
+	body := `{"model":"fake/model","messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "7" {
+		t.Errorf("expected Retry-After 7, got %q", w.Header().Get("Retry-After"))
+	}
+
+	var resp map[string]map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["error"]["type"] != "rate_limit_error" {
+		t.Errorf("expected rate_limit_error, got %v", resp["error"]["type"])
+	}
+}
+
+func TestServer_ModelRouteFailover(t *testing.T) {
+	server, err := NewServer(
+		WithAPIKey("test-key", "test", "user1", 100),
+		WithModelRoute("fast", "down/model-a", "up/model-b"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{name: "down", err: core.NewOverloadedError("overloaded")})
+	server.engine.RegisterProvider(&fakeProvider{
+		name: "up",
+		resp: &types.MessageResponse{ID: "msg_up", Type: "message", Role: "assistant"},
+	})
+
+	body := `{"model":"fast","messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Routed-Model"); got != "up/model-b" {
+		t.Errorf("X-Routed-Model = %q, want up/model-b", got)
+	}
+
+	var resp types.MessageResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Metadata[core.MetadataRoute] != "fast" {
+		t.Errorf("metadata = %v", resp.Metadata)
+	}
+}
+
+func TestServer_InvalidModelRoute(t *testing.T) {
+	if _, err := NewServer(WithModelRoute("fast", "not-a-model")); err == nil {
+		t.Error("expected error for invalid model route")
+	}
+}
+
+func TestServer_Middleware(t *testing.T) {
+	var seen string
+	server, err := NewServer(
+		WithAPIKey("test-key", "test", "user1", 100),
+		WithMiddleware(core.MiddlewareFuncs{
+			CreateMessage: func(ctx context.Context, req *types.MessageRequest, next core.CreateMessageFunc) (*types.MessageResponse, error) {
+				seen = req.Model
+				return next(ctx, req)
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{
+		name: "fake",
+		resp: &types.MessageResponse{ID: "msg_1", Type: "message", Role: "assistant"},
+	})
+
+	body := `{"model":"fake/model","messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if seen != "fake/model" {
+		t.Errorf("middleware saw model %q, want fake/model", seen)
+	}
+}
+
+func TestServer_UnsupportedFeature(t *testing.T) {
+	server, err := NewServer(WithAPIKey("test-key", "test", "user1", 100))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{name: "fake"})
+
+	body := `{"model":"fake/model","messages":[{"role":"user","content":"hi"}],"tools":[{"type":"web_search"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["error"]["param"] != "tools[0]" || resp["error"]["code"] != core.ErrCodeUnsupportedFeature {
+		t.Errorf("error = %v", resp["error"])
+	}
+}
+
+func TestServer_InvalidCapabilityMode(t *testing.T) {
+	if _, err := NewServer(WithCapabilityMode("lenient")); err == nil {
+		t.Error("expected error for invalid capability mode")
+	}
+}
+
+func TestServer_Models(t *testing.T) {
+	server, err := NewServer(
+		WithAPIKey("test-key", "test", "user1", 100),
+		WithModelCatalog(catalog.New(
+			catalog.Model{
+				ID:           "anthropic/claude-sonnet-4",
+				DisplayName:  "Claude Sonnet 4",
+				Context:      200000,
+				MaxOutput:    64000,
+				Capabilities: core.ProviderCapabilities{Vision: true, Tools: true},
+				Pricing:      &catalog.ModelPricing{InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+			},
+			catalog.Model{ID: "groq/llama-3.3-70b-versatile", Capabilities: core.ProviderCapabilities{Tools: true}},
+		)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer test-key")
+		w := httptest.NewRecorder()
+		server.mux.ServeHTTP(w, req)
+		return w
+	}
+
+	var list ModelsResponse
+	json.NewDecoder(get("/v1/models").Body).Decode(&list)
+	if len(list.Providers) != 2 || list.Providers[0].ID != "anthropic" || list.Providers[0].Name != "Anthropic" {
+		t.Fatalf("providers = %+v", list.Providers)
+	}
+	sonnet := list.Providers[0].Models[0]
+	if sonnet.FullID != "anthropic/claude-sonnet-4" || sonnet.ContextWindow != 200000 || !sonnet.Capabilities.Text {
+		t.Errorf("model = %+v", sonnet)
+	}
+	if sonnet.Pricing == nil || sonnet.Pricing.CacheWritePerMTok != 3.75 {
+		t.Errorf("pricing = %+v", sonnet.Pricing)
+	}
+
+	json.NewDecoder(get("/v1/models?capability=vision").Body).Decode(&list)
+	if len(list.Providers) != 1 || list.Providers[0].ID != "anthropic" {
+		t.Errorf("capability filter = %+v", list.Providers)
+	}
+
+	json.NewDecoder(get("/v1/models?provider=groq").Body).Decode(&list)
+	if len(list.Providers) != 1 || list.Providers[0].ID != "groq" {
+		t.Errorf("provider filter = %+v", list.Providers)
+	}
+
+	w := get("/v1/models/anthropic/claude-sonnet-4-20250514")
+	var info ModelInfo
+	json.NewDecoder(w.Body).Decode(&info)
+	if w.Code != http.StatusOK || info.FullID != "anthropic/claude-sonnet-4" {
+		t.Errorf("GET model = %d %+v", w.Code, info)
+	}
+
+	if w := get("/v1/models/anthropic/unknown"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown model, got %d", w.Code)
+	}
+}
+
+func TestServer_Cost(t *testing.T) {
+	server, err := NewServer(
+		WithAPIKey("test-key", "test", "user1", 100),
+		WithModelCatalog(catalog.New(catalog.Model{
+			ID:      "fake/model",
+			Pricing: &catalog.ModelPricing{InputPerMillion: 3, OutputPerMillion: 15},
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.engine.RegisterProvider(&fakeProvider{
+		name: "fake",
+		resp: &types.MessageResponse{
+			ID: "msg_1", Type: "message", Role: "assistant",
+			Usage: types.Usage{InputTokens: 1000, OutputTokens: 200},
+		},
+	})
+
+	body := `{"model":"fake/model","messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/messages", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+
+	server.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Cost-USD"); got != "0.006000" {
+		t.Errorf("X-Cost-USD = %q, want 0.006000", got)
+	}
+
+	var resp types.MessageResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Usage.CostUSD == nil || *resp.Usage.CostUSD != 0.006 {
+		t.Errorf("usage.cost_usd = %v, want 0.006", resp.Usage.CostUSD)
+	}
+
+	if got := testutil.ToFloat64(server.metrics.CostUSDTotal.WithLabelValues("fake", "model")); got != 0.006 {
+		t.Errorf("cost metric = %v, want 0.006", got)
+	}
+}
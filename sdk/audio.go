@@ -16,7 +16,7 @@ type AudioService struct {
 // TranscribeRequest configures transcription.
 type TranscribeRequest struct {
 	Audio      []byte // Audio data
-	Model      string // Model to use (default: "ink-whisper")
+	Model      string // Model to use, as "provider/model" (default: "ink-whisper")
 	Language   string // ISO language code (default: "en")
 	Format     string // Audio format hint (wav, mp3, webm, etc.)
 	SampleRate int    // Audio sample rate in Hz
@@ -29,7 +29,8 @@ type Transcript = stt.Transcript
 // Word represents a transcribed word with timing.
 type Word = stt.Word
 
-// Transcribe converts audio to text using Cartesia.
+// Transcribe converts audio to text with the STT provider named by the
+// model, e.g. "deepgram/nova-3" or "openai/whisper-1".
 func (s *AudioService) Transcribe(ctx context.Context, req *TranscribeRequest) (*Transcript, error) {
 	provider := s.client.getSTTProvider()
 
@@ -45,7 +46,7 @@ func (s *AudioService) Transcribe(ctx context.Context, req *TranscribeRequest) (
 // SynthesizeRequest configures synthesis.
 type SynthesizeRequest struct {
 	Text       string  // Text to synthesize (required)
-	Voice      string  // Cartesia voice ID (required)
+	Voice      string  // Voice, as "provider/voice" or a Cartesia voice ID (required)
 	Speed      float64 // Speed multiplier (0.6-1.5, default 1.0)
 	Volume     float64 // Volume multiplier (0.5-2.0, default 1.0)
 	Emotion    string  // Emotion hint (neutral, happy, sad, etc.)
@@ -61,7 +62,8 @@ type SynthesisResult struct {
 	Duration float64 // Duration in seconds
 }
 
-// Synthesize converts text to audio using Cartesia.
+// Synthesize converts text to audio with the TTS provider named by the
+// voice, e.g. "elevenlabs/21m00Tcm4TlvDq8ikWAM" or "openai/alloy".
 func (s *AudioService) Synthesize(ctx context.Context, req *SynthesizeRequest) (*SynthesisResult, error) {
 	provider := s.client.getTTSProvider()
 
@@ -105,7 +107,8 @@ func (s *AudioStream) Close() error {
 	return s.stream.Close()
 }
 
-// StreamSynthesize converts text to streaming audio using Cartesia WebSocket.
+// StreamSynthesize converts text to streaming audio with the TTS provider
+// named by the voice.
 func (s *AudioService) StreamSynthesize(ctx context.Context, req *SynthesizeRequest) (*AudioStream, error) {
 	provider := s.client.getTTSProvider()
 
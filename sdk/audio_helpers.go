@@ -1,6 +1,30 @@
 package vai
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/vango-go/vai/pkg/core/audio"
+)
+
+// AudioFormat describes raw audio: sample encoding, sample rate and channel count.
+type AudioFormat = audio.Format
+
+// AudioEncoding is a raw audio sample encoding.
+type AudioEncoding = audio.Encoding
+
+// Audio encodings for AudioFormat.
+const (
+	AudioEncodingPCM16   = audio.EncodingPCM16
+	AudioEncodingFloat32 = audio.EncodingFloat32
+	AudioEncodingMulaw   = audio.EncodingMulaw
+	AudioEncodingAlaw    = audio.EncodingAlaw
+)
+
+// ConvertAudio converts a complete buffer of raw audio between formats,
+// for example 48 kHz float samples from a browser to 16 kHz 16-bit PCM.
+func ConvertAudio(data []byte, from, to AudioFormat) ([]byte, error) {
+	return audio.Convert(data, from, to)
+}
 
 // PCMToWAV wraps raw PCM audio data with a WAV header.
 //
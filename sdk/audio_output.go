@@ -2,6 +2,8 @@ package vai
 
 import (
 	"sync"
+
+	"github.com/vango-go/vai/pkg/core/audio"
 )
 
 // AudioOutputConfig configures audio output buffering behavior.
@@ -14,6 +16,11 @@ type AudioOutputConfig struct {
 	// ChannelSize is the buffer size for the audio chunks channel.
 	// Default: 20.
 	ChannelSize int
+
+	// Format is the format of emitted chunks. TTS audio (16-bit mono PCM at
+	// the session sample rate) is converted to it, e.g. 8 kHz μ-law for
+	// telephony. Unset fields keep the TTS format.
+	Format AudioFormat
 }
 
 // DefaultAudioOutputConfig returns the default audio output configuration.
@@ -40,8 +47,9 @@ func DefaultAudioOutputConfig() AudioOutputConfig {
 //	    }
 //	}
 type AudioOutput struct {
-	config     AudioOutputConfig
-	sampleRate int
+	config    AudioOutputConfig
+	format    AudioFormat
+	converter *audio.Converter
 
 	chunks chan []byte
 	flush  chan struct{}
@@ -61,12 +69,26 @@ func NewAudioOutput(sampleRate int, config AudioOutputConfig) *AudioOutput {
 		config.ChannelSize = 20
 	}
 
-	return &AudioOutput{
-		config:     config,
-		sampleRate: sampleRate,
-		chunks:     make(chan []byte, config.ChannelSize),
-		flush:      make(chan struct{}, 1),
+	source := audio.PCM16(sampleRate, 1)
+	a := &AudioOutput{
+		config: config,
+		format: config.Format.WithDefaults(source),
+		chunks: make(chan []byte, config.ChannelSize),
+		flush:  make(chan struct{}, 1),
 	}
+	if a.format != source {
+		// An invalid format leaves audio unconverted
+		a.converter, _ = audio.NewConverter(source, a.format)
+		if a.converter == nil {
+			a.format = source
+		}
+	}
+	return a
+}
+
+// Format returns the format of emitted chunks.
+func (a *AudioOutput) Format() AudioFormat {
+	return a.format
 }
 
 // Chunks returns a channel that emits audio chunks ready for playback.
@@ -143,11 +165,17 @@ func (a *AudioOutput) pushAudio(data []byte) {
 		return
 	}
 
+	if a.converter != nil {
+		converted, err := a.converter.Convert(data)
+		if err != nil {
+			return
+		}
+		data = converted
+	}
 	a.buffer = append(a.buffer, data...)
 
 	// Calculate min buffer size in bytes
-	// At 16-bit mono: bytes = sampleRate * 2 * (ms / 1000)
-	minBytes := (a.sampleRate * 2 * a.config.MinBufferMs) / 1000
+	minBytes := a.format.BytesForDurationMs(a.config.MinBufferMs)
 
 	// Check if we've buffered enough to start emitting
 	if !a.bufferReady && len(a.buffer) >= minBytes {
@@ -181,6 +209,9 @@ func (a *AudioOutput) doFlush() {
 	// Clear internal buffer and reset pre-buffering state
 	a.buffer = nil
 	a.bufferReady = false
+	if a.converter != nil {
+		a.converter.Reset()
+	}
 	a.mu.Unlock()
 
 	// Drain any pending chunks in the channel
@@ -0,0 +1,61 @@
+package vai
+
+import (
+	"testing"
+)
+
+func TestAudioOutput_PassesThroughPCM(t *testing.T) {
+	out := NewAudioOutput(24000, AudioOutputConfig{MinBufferMs: 10, ChannelSize: 4})
+	defer out.Close()
+
+	if got := out.Format(); got.Encoding != AudioEncodingPCM16 || got.SampleRate != 24000 || got.Channels != 1 {
+		t.Fatalf("Format() = %v, want 16-bit mono PCM at 24000 Hz", got)
+	}
+
+	data := make([]byte, 480) // 10ms
+	out.pushAudio(data)
+	if got := len(<-out.Chunks()); got != len(data) {
+		t.Errorf("chunk has %d bytes, want %d", got, len(data))
+	}
+}
+
+func TestAudioOutput_ConvertsToMulaw(t *testing.T) {
+	out := NewAudioOutput(24000, AudioOutputConfig{
+		MinBufferMs: 20,
+		ChannelSize: 4,
+		Format:      AudioFormat{Encoding: AudioEncodingMulaw, SampleRate: 8000},
+	})
+	defer out.Close()
+
+	// 10ms is below the 20ms pre-buffer, measured in output bytes
+	out.pushAudio(make([]byte, 480))
+	select {
+	case <-out.Chunks():
+		t.Fatal("chunk emitted before MinBufferMs of audio was buffered")
+	default:
+	}
+
+	out.pushAudio(make([]byte, 480))
+	chunk := <-out.Chunks()
+	if len(chunk) < 155 || len(chunk) > 160 {
+		t.Errorf("chunk has %d bytes of μ-law, want about 160", len(chunk))
+	}
+	for _, b := range chunk {
+		if b != 0xFF {
+			t.Fatalf("silence encoded as %#x, want 0xff", b)
+		}
+	}
+}
+
+func TestConvertAudio(t *testing.T) {
+	from := AudioFormat{Encoding: AudioEncodingFloat32, SampleRate: 48000, Channels: 2}
+	to := AudioFormat{Encoding: AudioEncodingPCM16, SampleRate: 16000, Channels: 1}
+
+	out, err := ConvertAudio(make([]byte, from.BytesForDurationMs(100)), from, to)
+	if err != nil {
+		t.Fatalf("ConvertAudio() error = %v", err)
+	}
+	if want := to.BytesForDurationMs(100); len(out) != want {
+		t.Errorf("got %d bytes, want %d", len(out), want)
+	}
+}
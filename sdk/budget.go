@@ -0,0 +1,160 @@
+package vai
+
+import (
+	"sync"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// Budget is a spend limit shared by several runs. Every Run or RunStream
+// configured with WithBudget adds its usage to the budget after each turn and
+// stops with RunStopBudgetExceeded once the next turn is expected to go over.
+// A Budget is safe for concurrent use.
+//
+// Costs come from Usage.CostUSD, which is only known for models with pricing
+// in the client's model catalog.
+type Budget struct {
+	mu          sync.Mutex
+	maxCostUSD  float64
+	maxTokens   int
+	spentUSD    float64
+	spentTokens int
+}
+
+// NewBudget creates a budget capped at maxCostUSD dollars and maxTokens tokens.
+// A zero limit is not enforced.
+func NewBudget(maxCostUSD float64, maxTokens int) *Budget {
+	return &Budget{maxCostUSD: maxCostUSD, maxTokens: maxTokens}
+}
+
+// Add records usage against the budget.
+func (b *Budget) Add(usage types.Usage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spentUSD += usageCost(usage)
+	b.spentTokens += usageTokens(usage)
+}
+
+// SpentUSD returns the total cost recorded so far.
+func (b *Budget) SpentUSD() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spentUSD
+}
+
+// SpentTokens returns the total tokens recorded so far.
+func (b *Budget) SpentTokens() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spentTokens
+}
+
+// Fraction returns the share of the tightest limit used so far.
+// It can exceed 1 once the budget is overspent.
+func (b *Budget) Fraction() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return spendFraction(b.spentUSD, b.maxCostUSD, b.spentTokens, b.maxTokens)
+}
+
+// Exceeded reports whether any limit has been reached.
+func (b *Budget) Exceeded() bool {
+	return b.wouldExceed(types.Usage{})
+}
+
+// Reset clears the recorded spend.
+func (b *Budget) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spentUSD = 0
+	b.spentTokens = 0
+}
+
+// wouldExceed reports whether spending next on top of the recorded usage
+// would reach a limit.
+func (b *Budget) wouldExceed(next types.Usage) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return limitReached(b.spentUSD+usageCost(next), b.maxCostUSD) ||
+		limitReached(float64(b.spentTokens+usageTokens(next)), float64(b.maxTokens))
+}
+
+// WithMaxCostUSD stops the run before a turn that is expected to take its
+// total cost to usd or more. The cost of the previous turn is used as the
+// estimate for the next one.
+func WithMaxCostUSD(usd float64) RunOption {
+	return func(c *runConfig) { c.maxCostUSD = usd }
+}
+
+// WithBudget draws the run's spend from a budget shared with other runs.
+func WithBudget(b *Budget) RunOption {
+	return func(c *runConfig) { c.budget = b }
+}
+
+// WithBudgetWarning sets the share of the cost limit or budget at which
+// RunStream emits a BudgetWarningEvent. Default is 0.8.
+func WithBudgetWarning(fraction float64) RunOption {
+	return func(c *runConfig) { c.budgetWarning = fraction }
+}
+
+// BudgetWarningEvent is emitted once per RunStream when spend crosses the
+// warning threshold of WithMaxCostUSD or the shared Budget.
+type BudgetWarningEvent struct {
+	Fraction float64 `json:"fraction"` // Share of the tightest limit used
+	CostUSD  float64 `json:"cost_usd"` // Cost of this run so far
+	Budget   *Budget `json:"-"`        // Shared budget, if any
+}
+
+func (e BudgetWarningEvent) runStreamEventType() string { return "budget_warning" }
+
+// overBudget reports whether the next turn, estimated by last, would exceed
+// the run's cost limit or shared budget.
+func (c *runConfig) overBudget(result *RunResult, last types.Usage) bool {
+	if limitReached(usageCost(result.Usage)+usageCost(last), c.maxCostUSD) {
+		return true
+	}
+	return c.budget != nil && c.budget.wouldExceed(last)
+}
+
+// budgetFraction returns the share of the tightest spend limit used by the run.
+func (c *runConfig) budgetFraction(result *RunResult) float64 {
+	fraction := spendFraction(usageCost(result.Usage), c.maxCostUSD, 0, 0)
+	if c.budget != nil {
+		fraction = max(fraction, c.budget.Fraction())
+	}
+	return fraction
+}
+
+// limitReached reports whether spent has reached a non-zero limit.
+func limitReached(spent, limit float64) bool {
+	return limit > 0 && spent >= limit
+}
+
+// spendFraction returns the larger of the cost and token shares used.
+func spendFraction(usd, maxUSD float64, tokens, maxTokens int) float64 {
+	var fraction float64
+	if maxUSD > 0 {
+		fraction = usd / maxUSD
+	}
+	if maxTokens > 0 {
+		fraction = max(fraction, float64(tokens)/float64(maxTokens))
+	}
+	return fraction
+}
+
+// usageCost returns the cost of usage, or zero if it is unknown.
+func usageCost(usage types.Usage) float64 {
+	if usage.CostUSD == nil {
+		return 0
+	}
+	return *usage.CostUSD
+}
+
+// usageTokens returns the tokens in usage. Providers that omit the total
+// report input and output counts only.
+func usageTokens(usage types.Usage) int {
+	if usage.TotalTokens > 0 {
+		return usage.TotalTokens
+	}
+	return usage.InputTokens + usage.OutputTokens
+}
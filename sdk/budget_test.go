@@ -0,0 +1,182 @@
+package vai
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// toolLoopProvider asks for a tool call on every turn, so runs only end on a limit.
+type toolLoopProvider struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *toolLoopProvider) Name() string { return "loop" }
+
+func (p *toolLoopProvider) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	return &types.MessageResponse{
+		ID:         "msg_loop",
+		Role:       "assistant",
+		Content:    []types.ContentBlock{types.ToolUseBlock{Type: "tool_use", ID: "call_1", Name: "lookup", Input: map[string]any{}}},
+		StopReason: types.StopReasonToolUse,
+		Usage:      types.Usage{InputTokens: 1000},
+	}, nil
+}
+
+func (p *toolLoopProvider) StreamMessage(ctx context.Context, req *types.MessageRequest) (core.EventStream, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	delta := types.MessageDeltaEvent{Type: "message_delta"}
+	delta.Delta.StopReason = types.StopReasonToolUse
+	return &mockEventStream{events: []types.StreamEvent{
+		types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{
+			ID: "msg_loop", Role: "assistant", Usage: types.Usage{InputTokens: 1000},
+		}},
+		types.ContentBlockStartEvent{Type: "content_block_start", Index: 0, ContentBlock: types.ToolUseBlock{
+			Type: "tool_use", ID: "call_1", Name: "lookup", Input: map[string]any{},
+		}},
+		types.ContentBlockStopEvent{Type: "content_block_stop", Index: 0},
+		delta,
+		types.MessageStopEvent{Type: "message_stop"},
+	}}, nil
+}
+
+func (p *toolLoopProvider) Capabilities() core.ProviderCapabilities {
+	return core.ProviderCapabilities{Tools: true}
+}
+
+// newBudgetTestClient prices every loop turn at exactly one dollar.
+func newBudgetTestClient(p core.Provider) *Client {
+	return newRetryTestClient(p, WithModelCatalog(catalog.New(catalog.Model{
+		ID:      "loop/model",
+		Pricing: &catalog.ModelPricing{InputPerMillion: 1000},
+	})))
+}
+
+func TestBudget_Accounting(t *testing.T) {
+	cost := 0.25
+	b := NewBudget(2, 1000)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Add(types.Usage{InputTokens: 100, OutputTokens: 50, CostUSD: &cost})
+		}()
+	}
+	wg.Wait()
+
+	if got := b.SpentUSD(); got != 1 {
+		t.Errorf("SpentUSD() = %v, want 1", got)
+	}
+	if got := b.SpentTokens(); got != 600 {
+		t.Errorf("SpentTokens() = %d, want 600", got)
+	}
+	if got := b.Fraction(); got != 0.6 {
+		t.Errorf("Fraction() = %v, want 0.6 (token limit is tighter)", got)
+	}
+	if b.Exceeded() {
+		t.Error("Exceeded() = true, want false")
+	}
+
+	b.Add(types.Usage{TotalTokens: 400})
+	if !b.Exceeded() {
+		t.Error("Exceeded() = false after reaching the token limit")
+	}
+
+	b.Reset()
+	if b.SpentUSD() != 0 || b.SpentTokens() != 0 || b.Exceeded() {
+		t.Error("Reset() did not clear spend")
+	}
+}
+
+func TestRun_MaxCostUSD(t *testing.T) {
+	p := &toolLoopProvider{}
+	client := newBudgetTestClient(p)
+
+	result, err := client.Messages.Run(context.Background(), testRequest("loop/model"), WithMaxCostUSD(2.5))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.StopReason != RunStopBudgetExceeded {
+		t.Errorf("StopReason = %q, want %q", result.StopReason, RunStopBudgetExceeded)
+	}
+	// A third $1 turn would take the run to $3, past the $2.50 limit
+	if result.TurnCount != 2 {
+		t.Errorf("TurnCount = %d, want 2", result.TurnCount)
+	}
+	if result.Usage.CostUSD == nil || *result.Usage.CostUSD != 2 {
+		t.Errorf("Usage.CostUSD = %v, want 2", result.Usage.CostUSD)
+	}
+}
+
+func TestRun_SharedBudget(t *testing.T) {
+	p := &toolLoopProvider{}
+	client := newBudgetTestClient(p)
+	budget := NewBudget(3, 0)
+
+	first, err := client.Messages.Run(context.Background(), testRequest("loop/model"), WithBudget(budget))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	second, err := client.Messages.Run(context.Background(), testRequest("loop/model"), WithBudget(budget))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if first.TurnCount != 2 || second.TurnCount != 1 {
+		t.Errorf("TurnCount = %d, %d; want 2, 1", first.TurnCount, second.TurnCount)
+	}
+	if first.StopReason != RunStopBudgetExceeded || second.StopReason != RunStopBudgetExceeded {
+		t.Errorf("StopReason = %q, %q", first.StopReason, second.StopReason)
+	}
+	if got := budget.SpentUSD(); got != 3 {
+		t.Errorf("SpentUSD() = %v, want 3", got)
+	}
+}
+
+func TestRunStream_BudgetWarning(t *testing.T) {
+	p := &toolLoopProvider{}
+	client := newBudgetTestClient(p)
+
+	stream, err := client.Messages.RunStream(context.Background(), testRequest("loop/model"),
+		WithMaxCostUSD(4), WithBudgetWarning(0.5))
+	if err != nil {
+		t.Fatalf("RunStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var warnings []BudgetWarningEvent
+	var result *RunResult
+	for event := range stream.Events() {
+		switch e := event.(type) {
+		case BudgetWarningEvent:
+			warnings = append(warnings, e)
+		case RunCompleteEvent:
+			result = e.Result
+		}
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("got %d budget warnings, want 1", len(warnings))
+	}
+	if warnings[0].Fraction != 0.5 || warnings[0].CostUSD != 2 {
+		t.Errorf("warning = %+v, want fraction 0.5 at $2", warnings[0])
+	}
+	if result == nil || result.StopReason != RunStopBudgetExceeded {
+		t.Fatalf("result = %+v, want budget_exceeded", result)
+	}
+	if result.TurnCount != 3 {
+		t.Errorf("TurnCount = %d, want 3", result.TurnCount)
+	}
+}
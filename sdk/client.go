@@ -26,11 +26,13 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
 	"github.com/vango-go/vai/pkg/core/providers/anthropic"
 	"github.com/vango-go/vai/pkg/core/providers/cerebras"
 	"github.com/vango-go/vai/pkg/core/providers/gemini_oauth"
@@ -64,9 +66,18 @@ type Client struct {
 	tracer     trace.Tracer
 
 	// Direct mode only
-	core          *core.Engine
-	providerKeys  map[string]string
-	voicePipeline *voice.Pipeline
+	core           *core.Engine
+	providerKeys   map[string]string
+	voicePipeline  *voice.Pipeline
+	modelRoutes    map[string][]string
+	capabilityMode core.CapabilityMode
+
+	// Model catalog, created on first use unless provided
+	catalog     *catalog.Catalog
+	catalogOnce sync.Once
+
+	// Middleware wrapping every LLM call
+	middleware []core.Middleware
 
 	// Retry configuration
 	maxRetries   int
@@ -94,7 +105,13 @@ func NewClient(opts ...ClientOption) *Client {
 	} else {
 		c.mode = modeDirect
 		c.core = core.NewEngine(c.providerKeys)
+		c.core.Use(c.middleware...)
+		if c.capabilityMode != "" {
+			c.core.SetCapabilityMode(c.capabilityMode)
+		}
+		c.core.SetPricing(c.modelCatalog())
 		c.initProviders()
+		c.initModelRoutes()
 		c.initVoicePipeline()
 	}
 
@@ -152,49 +169,65 @@ func (c *Client) initProviders() {
 	}
 }
 
-// initVoicePipeline initializes the voice pipeline if Cartesia API key is available.
-func (c *Client) initVoicePipeline() {
-	cartesiaKey := c.getCartesiaAPIKey()
-	if cartesiaKey != "" {
-		c.voicePipeline = voice.NewPipeline(cartesiaKey)
+// initModelRoutes registers the configured model routes with the engine.
+func (c *Client) initModelRoutes() {
+	for name, targets := range c.modelRoutes {
+		if err := c.core.SetModelRoute(name, targets...); err != nil {
+			c.logger.Warn("ignoring invalid model route", "route", name, "error", err)
+		}
 	}
 }
 
-// getCartesiaAPIKey returns the Cartesia API key from provider keys or environment.
-func (c *Client) getCartesiaAPIKey() string {
-	if key, ok := c.providerKeys["cartesia"]; ok && key != "" {
-		return key
+// initVoicePipeline initializes the voice pipeline if any STT or TTS provider
+// has a key. Requests fail over between the providers with keys; a provider
+// can be preferred per request as "provider/model" for STT and
+// "provider/voice" for TTS.
+func (c *Client) initVoicePipeline() {
+	key := voice.ProviderKeys(c.providerKeys)
+	sttProviders := stt.ProvidersFromKeys(key)
+	ttsProviders := tts.ProvidersFromKeys(key)
+	if len(sttProviders) > 0 || len(ttsProviders) > 0 {
+		c.voicePipeline = voice.NewPipelineWithFailover(sttProviders, ttsProviders)
 	}
-	return os.Getenv("CARTESIA_API_KEY")
 }
 
-// getSTTProvider returns the STT provider (Cartesia).
+// getSTTProvider returns the STT provider, which routes "provider/model"
+// names to Cartesia, Deepgram, OpenAI or whisper.cpp.
 func (c *Client) getSTTProvider() stt.Provider {
 	if c.voicePipeline != nil {
-		return c.voicePipeline.STTProvider()
+		provider, err := c.voicePipeline.STTProvider()
+		if err != nil {
+			return nil
+		}
+		return provider
 	}
-	// Create a standalone provider
-	cartesiaKey := c.getCartesiaAPIKey()
-	if cartesiaKey == "" {
+	// Create a standalone registry
+	registry := stt.NewRegistryFromKeys(voice.ProviderKeys(c.providerKeys))
+	if registry.Len() == 0 {
 		return nil
 	}
-	return stt.NewCartesia(cartesiaKey)
+	return registry
 }
 
-// getTTSProvider returns the TTS provider (Cartesia).
+// getTTSProvider returns the TTS provider, which routes "provider/voice"
+// names to Cartesia, ElevenLabs, OpenAI or Piper.
 func (c *Client) getTTSProvider() tts.Provider {
 	if c.voicePipeline != nil {
-		return c.voicePipeline.TTSProvider()
+		provider, err := c.voicePipeline.TTSProvider()
+		if err != nil {
+			return nil
+		}
+		return provider
 	}
-	// Create a standalone provider
-	cartesiaKey := c.getCartesiaAPIKey()
-	if cartesiaKey == "" {
+	// Create a standalone registry
+	registry := tts.NewRegistryFromKeys(voice.ProviderKeys(c.providerKeys))
+	if registry.Len() == 0 {
 		return nil
 	}
-	return tts.NewCartesia(cartesiaKey)
+	return registry
 }
 
-// VoicePipeline returns the voice pipeline (only available in Direct Mode with Cartesia key).
+// VoicePipeline returns the voice pipeline (only available in Direct Mode with a voice provider key).
 func (c *Client) VoicePipeline() *voice.Pipeline {
 	return c.voicePipeline
 }
@@ -221,7 +254,7 @@ func (c *Client) Engine() *core.Engine {
 // Deprecated: Use client.Messages.RunStream(ctx, req, WithLive(&LiveConfig{})) instead.
 // This method will be removed in a future version.
 //
-// Direct Mode only. Requires CARTESIA_API_KEY for STT/TTS.
+// Direct Mode only. Requires an STT and a TTS provider key, e.g. CARTESIA_API_KEY.
 func (c *Client) Live(ctx context.Context, config LiveConfig) (*LiveSession, error) {
 	if c.mode != modeDirect {
 		return nil, fmt.Errorf("live sessions only supported in direct mode")
@@ -230,13 +263,13 @@ func (c *Client) Live(ctx context.Context, config LiveConfig) (*LiveSession, err
 	// Get STT provider
 	sttProvider := c.getSTTProvider()
 	if sttProvider == nil {
-		return nil, fmt.Errorf("STT provider not available - ensure CARTESIA_API_KEY is set")
+		return nil, fmt.Errorf("STT provider not available - set CARTESIA_API_KEY, DEEPGRAM_API_KEY, OPENAI_API_KEY or WHISPER_CPP_URL")
 	}
 
 	// Get TTS provider
 	ttsProvider := c.getTTSProvider()
 	if ttsProvider == nil {
-		return nil, fmt.Errorf("TTS provider not available - ensure CARTESIA_API_KEY is set")
+		return nil, fmt.Errorf("TTS provider not available - set CARTESIA_API_KEY, ELEVENLABS_API_KEY, OPENAI_API_KEY or PIPER_URL")
 	}
 
 	// Create adapters
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/vango-go/vai/pkg/core"
 	"github.com/vango-go/vai/pkg/core/live"
 	"github.com/vango-go/vai/pkg/core/types"
 	"github.com/vango-go/vai/pkg/core/voice/stt"
@@ -33,6 +34,13 @@ type LiveConfig struct {
 	// Tools are the available tools for the agent.
 	Tools []types.Tool
 
+	// ToolHandlers execute the agent's tool calls by tool name.
+	// Use ToolSet.Handlers() to run the tools of a ToolSet.
+	ToolHandlers map[string]ToolHandler
+
+	// MaxToolRounds limits tool call rounds per response. Default: 5.
+	MaxToolRounds int
+
 	// Messages are any pre-existing conversation history.
 	Messages []types.Message
 
@@ -54,6 +62,11 @@ type LiveConfig struct {
 	// Channels is the number of audio channels. Default: 1 (mono).
 	Channels int
 
+	// InputFormat is the format of raw audio passed to SendAudio when it
+	// differs from 16-bit PCM at SampleRate, e.g. 48kHz float from a browser
+	// or 8kHz μ-law from a phone line. Unset fields default to the session format.
+	InputFormat AudioFormat
+
 	// MaxTokens is the maximum tokens for LLM responses.
 	MaxTokens int
 
@@ -64,6 +77,10 @@ type LiveConfig struct {
 	// If nil, uses DefaultAudioOutputConfig().
 	AudioOutput *AudioOutputConfig
 
+	// Normalizer, if set, creates the normalizer that rewrites each
+	// response for speech. By default it follows Voice.Output.Normalization.
+	Normalizer func() TextNormalizer
+
 	// Debug enables debug event emission.
 	Debug bool
 }
@@ -96,6 +113,54 @@ type LiveVADConfig struct {
 	// Used for STT silence filtering and interrupt detection.
 	// Range: 0.0-1.0. Default: 0.02
 	EnergyThreshold float64
+
+	// TurnCompletePrompts override the semantic turn check prompt by
+	// language code ("es") or tag ("es-MX"). Prompts have %s for the
+	// transcript and ask for YES or NO.
+	TurnCompletePrompts map[string]string
+
+	// Acoustic enables local speech/silence detection on the input audio,
+	// which ends turns without waiting for NoActivityTimeoutMs.
+	// If nil, acoustic detection is disabled.
+	Acoustic *LiveAcousticVADConfig
+}
+
+// LiveAcousticVADConfig configures frame-based acoustic speech detection.
+// Zero values use the defaults.
+type LiveAcousticVADConfig struct {
+	// FrameMs is the analysis frame length. Default: 20
+	FrameMs int
+
+	// MinSpeechMs is how much consecutive speech starts an utterance.
+	// Default: 60
+	MinSpeechMs int
+
+	// HangoverMs is how much consecutive silence ends an utterance.
+	// Default: 400
+	HangoverMs int
+
+	// MaxZeroCrossingRate is the highest zero-crossing rate accepted as speech.
+	// Range: 0.0-1.0. Default: 0.35
+	MaxZeroCrossingRate float64
+
+	// TranscriptSettleMs is how long to wait for late STT results after
+	// the end of speech. Default: 250
+	TranscriptSettleMs int
+}
+
+// coreConfig converts to the core live config. A nil config is disabled.
+func (c *LiveAcousticVADConfig) coreConfig() live.AcousticVADConfig {
+	if c == nil {
+		return live.AcousticVADConfig{}
+	}
+	return live.AcousticVADConfig{
+		Enabled:             true,
+		FrameMs:             c.FrameMs,
+		MinSpeechMs:         c.MinSpeechMs,
+		HangoverMs:          c.HangoverMs,
+		MaxZeroCrossingRate: c.MaxZeroCrossingRate,
+		TranscriptSettleMs:  c.TranscriptSettleMs,
+	}
 }
 
 // LiveGracePeriodConfig configures the post-VAD continuation window.
@@ -131,6 +196,55 @@ type LiveInterruptConfig struct {
 
 	// SavePartial controls partial message handling: "none", "marked", "full"
 	SavePartial string
+
+	// Backchannels add to the built-in backchannels by language code
+	// ("de") or tag ("de-AT"), or "*" for every language. Backchannels
+	// never interrupt the agent.
+	Backchannels map[string][]string
+
+	// InterruptCheckPrompts override the semantic interrupt check prompt
+	// by language code or tag. Prompts have %s for the transcript and ask
+	// for INTERRUPT or BACKCHANNEL.
+	InterruptCheckPrompts map[string]string
+
+	// EchoSuppression removes the agent's own voice from the input when
+	// it leaks from the speaker into the microphone, so that it neither
+	// triggers interrupts nor reaches STT.
+	// If nil, echo suppression is disabled.
+	EchoSuppression *LiveEchoSuppressionConfig
+}
+
+// LiveEchoSuppressionConfig configures echo suppression and double-talk
+// detection against the audio sent to the client. Zero values use the defaults.
+type LiveEchoSuppressionConfig struct {
+	// FrameMs is the analysis frame length. Default: 10
+	FrameMs int
+
+	// MaxDelayMs is the longest time from audio playing to its echo
+	// arriving, including network and client buffering. Default: 500
+	MaxDelayMs int
+
+	// DoubleTalkRatio is how many times louder than the expected echo
+	// input must be to count as the user's speech. Default: 2.0
+	DoubleTalkRatio float64
+
+	// HangoverMs is how long input keeps passing after the user's speech.
+	// Default: 200
+	HangoverMs int
+}
+
+// coreConfig converts to the core live config. A nil config is disabled.
+func (c *LiveEchoSuppressionConfig) coreConfig() live.EchoSuppressionConfig {
+	if c == nil {
+		return live.EchoSuppressionConfig{}
+	}
+	return live.EchoSuppressionConfig{
+		Enabled:         true,
+		FrameMs:         c.FrameMs,
+		MaxDelayMs:      c.MaxDelayMs,
+		DoubleTalkRatio: c.DoubleTalkRatio,
+		HangoverMs:      c.HangoverMs,
+	}
 }
 
 // LiveEvent is the interface for all live session events.
@@ -212,6 +326,27 @@ type LiveResponseDoneEvent struct{}
 func (*LiveResponseDoneEvent) liveEvent()                  {}
 func (e LiveResponseDoneEvent) runStreamEventType() string { return "live_response_done" }
 
+// LiveToolUseEvent is emitted when the agent calls a tool.
+type LiveToolUseEvent struct {
+	ID    string
+	Name  string
+	Input map[string]any
+}
+
+func (*LiveToolUseEvent) liveEvent()                  {}
+func (e LiveToolUseEvent) runStreamEventType() string { return "live_tool_use" }
+
+// LiveToolResultEvent is emitted when a tool call completes.
+type LiveToolResultEvent struct {
+	ID      string
+	Name    string
+	Content string
+	IsError bool
+}
+
+func (*LiveToolResultEvent) liveEvent()                  {}
+func (e LiveToolResultEvent) runStreamEventType() string { return "live_tool_result" }
+
 // LiveAudioDeltaEvent is emitted for TTS audio chunks.
 type LiveAudioDeltaEvent struct {
 	Data   []byte
@@ -237,6 +372,19 @@ type LiveAudioFlushEvent struct{}
 func (*LiveAudioFlushEvent) liveEvent()                  {}
 func (e LiveAudioFlushEvent) runStreamEventType() string { return "live_audio_flush" }
 
+// LiveFillerEvent is emitted when filler audio starts while the agent waits
+// on the model or a tool. The filler audio follows like response audio but
+// is not part of the response, and a LiveAudioFlushEvent discards it when
+// the response audio starts. RunStream voice output emits it as well.
+type LiveFillerEvent struct {
+	Kind string // "phrase", "tool" or "hold"
+	Text string // The words spoken
+	Tool string // The tool being waited on
+}
+
+func (*LiveFillerEvent) liveEvent()                  {}
+func (e LiveFillerEvent) runStreamEventType() string { return "live_filler" }
+
 // LiveGracePeriodStartedEvent is emitted when grace period starts.
 type LiveGracePeriodStartedEvent struct {
 	Transcript string
@@ -297,6 +445,24 @@ type LiveResponseInterruptedEvent struct {
 func (*LiveResponseInterruptedEvent) liveEvent()                  {}
 func (e LiveResponseInterruptedEvent) runStreamEventType() string { return "live_response_interrupted" }
 
+// LiveTurnLatencyEvent is emitted when the first audio of a response is
+// sent, with how long each stage of the turn took. Stages that did not run
+// in the turn are zero.
+type LiveTurnLatencyEvent struct {
+	SpeechEndedAt        time.Time // Zero for text input
+	CommittedAt          time.Time
+	EndpointingMs        int // End of speech to VAD commit
+	SemanticCheckMs      int // Turn-completion check
+	GracePeriodMs        int // First commit to final commit when the user continued
+	InterruptDetectionMs int // Interrupt capture to confirmation
+	LLMFirstTokenMs      int // Response start to first text token
+	TTSFirstAudioMs      int // First text sent to TTS to first audio
+	MouthToEarMs         int // End of speech (or commit, for text input) to first audio
+}
+
+func (*LiveTurnLatencyEvent) liveEvent()                  {}
+func (e LiveTurnLatencyEvent) runStreamEventType() string { return "live_turn_latency" }
+
 // LiveErrorEvent is emitted on errors.
 type LiveErrorEvent struct {
 	Code    string
@@ -332,14 +498,18 @@ func newLiveSession(
 ) *LiveSession {
 	// Convert SDK config to core config
 	coreConfig := live.SessionConfig{
-		Model:      config.Model,
-		System:     config.System,
-		Tools:      config.Tools,
-		Messages:   config.Messages,
-		Voice:      config.Voice,
-		SampleRate: config.SampleRate,
-		Channels:   config.Channels,
-		MaxTokens:  config.MaxTokens,
+		Model:         config.Model,
+		System:        config.System,
+		Tools:         config.Tools,
+		ToolHandlers:  liveToolHandlers(config.ToolHandlers),
+		MaxToolRounds: config.MaxToolRounds,
+		Messages:      config.Messages,
+		Voice:         config.Voice,
+		SampleRate:    config.SampleRate,
+		Channels:      config.Channels,
+		InputFormat:   config.InputFormat,
+		MaxTokens:     config.MaxTokens,
+		Normalizer:    config.Normalizer,
 	}
 
 	if config.Temperature != nil {
@@ -355,6 +525,8 @@ func newLiveSession(
 			SemanticCheck:       config.VAD.SemanticCheck,
 			MinWordsForCheck:    config.VAD.MinWordsForCheck,
 			EnergyThreshold:     config.VAD.EnergyThreshold,
+			TurnCompletePrompts: config.VAD.TurnCompletePrompts,
+			Acoustic:            config.VAD.Acoustic.coreConfig(),
 		}
 	} else {
 		coreConfig.VAD = live.DefaultVADConfig()
@@ -389,12 +561,15 @@ func newLiveSession(
 		}
 
 		coreConfig.Interrupt = live.InterruptConfig{
-			Mode:              mode,
-			EnergyThreshold:   config.Interrupt.EnergyThreshold,
-			CaptureDurationMs: config.Interrupt.CaptureDurationMs,
-			SemanticCheck:     config.Interrupt.SemanticCheck,
-			SemanticModel:     config.Interrupt.SemanticModel,
-			SavePartial:       savePartial,
+			Mode:                  mode,
+			EnergyThreshold:       config.Interrupt.EnergyThreshold,
+			CaptureDurationMs:     config.Interrupt.CaptureDurationMs,
+			SemanticCheck:         config.Interrupt.SemanticCheck,
+			SemanticModel:         config.Interrupt.SemanticModel,
+			SavePartial:           savePartial,
+			Backchannels:          config.Interrupt.Backchannels,
+			InterruptCheckPrompts: config.Interrupt.InterruptCheckPrompts,
+			EchoSuppression:       config.Interrupt.EchoSuppression.coreConfig(),
 		}
 	} else {
 		coreConfig.Interrupt = live.DefaultInterruptConfig()
@@ -459,6 +634,13 @@ func (ls *LiveSession) Interrupt(transcript string) error {
 	return ls.session.Interrupt(transcript)
 }
 
+// AckPlayback reports how much of the current response's audio has been
+// played, in milliseconds from the start of the response. If the user
+// interrupts, only the words played by then are kept in the history.
+func (ls *LiveSession) AckPlayback(positionMs int) {
+	ls.session.AckPlayback(positionMs)
+}
+
 // SendText sends a discrete text message as a complete user turn.
 // This bypasses VAD and grace period - the text is processed as a complete turn.
 // If the session is speaking or processing, it waits for the response to complete.
@@ -599,6 +781,20 @@ func (ls *LiveSession) convertEvent(event live.Event) LiveEvent {
 		}
 	case *live.MessageStopEvent:
 		return &LiveResponseDoneEvent{}
+	case *live.ToolUseEvent:
+		input, _ := e.Input.(map[string]any)
+		return &LiveToolUseEvent{
+			ID:    e.ID,
+			Name:  e.Name,
+			Input: input,
+		}
+	case *live.ToolResultEvent:
+		return &LiveToolResultEvent{
+			ID:      e.ID,
+			Name:    e.Name,
+			Content: e.Content,
+			IsError: e.IsError,
+		}
 	case *live.AudioDeltaEvent:
 		// Push to AudioOutput for buffered playback
 		if ls.audioOutput != nil {
@@ -618,6 +814,12 @@ func (ls *LiveSession) convertEvent(event live.Event) LiveEvent {
 			ls.audioOutput.doFlush()
 		}
 		return &LiveAudioFlushEvent{}
+	case *live.FillerStartedEvent:
+		return &LiveFillerEvent{
+			Kind: e.Kind,
+			Text: e.Text,
+			Tool: e.Tool,
+		}
 	case *live.GracePeriodStartedEvent:
 		return &LiveGracePeriodStartedEvent{
 			Transcript: e.Transcript,
@@ -650,6 +852,18 @@ func (ls *LiveSession) convertEvent(event live.Event) LiveEvent {
 			InterruptTranscript: e.InterruptTranscript,
 			AudioPositionMs:     e.AudioPositionMs,
 		}
+	case *live.TurnLatencyEvent:
+		return &LiveTurnLatencyEvent{
+			SpeechEndedAt:        e.SpeechEndedAt,
+			CommittedAt:          e.CommittedAt,
+			EndpointingMs:        e.EndpointingMs,
+			SemanticCheckMs:      e.SemanticCheckMs,
+			GracePeriodMs:        e.GracePeriodMs,
+			InterruptDetectionMs: e.InterruptDetectionMs,
+			LLMFirstTokenMs:      e.LLMFirstTokenMs,
+			TTSFirstAudioMs:      e.TTSFirstAudioMs,
+			MouthToEarMs:         e.MouthToEarMs,
+		}
 	case *live.ErrorEvent:
 		return &LiveErrorEvent{
 			Code:    e.Code,
@@ -670,17 +884,34 @@ func (ls *LiveSession) convertEvent(event live.Event) LiveEvent {
 	}
 }
 
+// liveToolHandlers merges SDK tool handlers into the core live form.
+// Later maps take precedence.
+func liveToolHandlers(handlers ...map[string]ToolHandler) map[string]live.ToolHandler {
+	var merged map[string]live.ToolHandler
+	for _, m := range handlers {
+		for name, h := range m {
+			if merged == nil {
+				merged = make(map[string]live.ToolHandler)
+			}
+			merged[name] = live.ToolHandler(h)
+		}
+	}
+	return merged
+}
+
 // llmClientAdapter wraps the SDK client to implement live.LLMClient.
 type llmClientAdapter struct {
 	client *Client
 }
 
 func (a *llmClientAdapter) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
-	return a.client.core.CreateMessage(ctx, req)
+	return a.client.createMessageWithRetry(ctx, req)
 }
 
 func (a *llmClientAdapter) StreamMessage(ctx context.Context, req *types.MessageRequest) (live.EventStream, error) {
-	coreStream, err := a.client.core.StreamMessage(ctx, req)
+	coreStream, err := a.client.openStreamWithRetry(ctx, func() (core.EventStream, error) {
+		return a.client.core.StreamMessage(ctx, req)
+	})
 	if err != nil {
 		return nil, err
 	}
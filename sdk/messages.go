@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/vango-go/vai/pkg/core"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/stt"
 )
 
 // MessagesService handles message creation and streaming.
@@ -42,11 +45,18 @@ func (s *MessagesService) Create(ctx context.Context, req *MessageRequest) (*Res
 // createDirect handles direct mode message creation with voice processing.
 func (s *MessagesService) createDirect(ctx context.Context, req *MessageRequest) (*Response, error) {
 	processedReq := req
-	var userTranscript string
+	var userTranscript *stt.Transcript
+
+	// Refuse voice output up front rather than after the LLM request
+	if req.Voice != nil && req.Voice.Output != nil && s.client.voicePipeline != nil {
+		if _, err := s.client.voicePipeline.TTSProvider(); err != nil {
+			return nil, fmt.Errorf("synthesize audio: %w", err)
+		}
+	}
 
 	// Process audio input if voice config is set
 	if req.Voice != nil && req.Voice.Input != nil && s.client.voicePipeline != nil {
-		processedMessages, transcript, err := s.client.voicePipeline.ProcessInputAudio(ctx, req.Messages, req.Voice)
+		processedMessages, transcript, err := s.client.voicePipeline.TranscribeInput(ctx, req.Messages, req.Voice)
 		if err != nil {
 			return nil, fmt.Errorf("process audio input: %w", err)
 		}
@@ -59,29 +69,42 @@ func (s *MessagesService) createDirect(ctx context.Context, req *MessageRequest)
 	}
 
 	// Make the LLM request
-	resp, err := s.client.core.CreateMessage(ctx, processedReq)
+	resp, err := s.client.createMessageWithRetry(ctx, processedReq)
 	if err != nil {
 		return nil, err
 	}
 
-	// Add user transcript to metadata if available
-	if userTranscript != "" {
+	// Add user transcript and the provider that produced it to metadata if available
+	if userTranscript != nil {
 		if resp.Metadata == nil {
 			resp.Metadata = make(map[string]any)
 		}
-		resp.Metadata["user_transcript"] = userTranscript
+		if userTranscript.Text != "" {
+			resp.Metadata["user_transcript"] = userTranscript.Text
+		}
+		if userTranscript.Provider != "" {
+			resp.Metadata[voice.MetadataSTTProvider] = userTranscript.Provider
+		}
 	}
 
 	// Synthesize audio output if voice output is configured
 	if req.Voice != nil && req.Voice.Output != nil && s.client.voicePipeline != nil {
 		textContent := resp.TextContent()
 		if textContent != "" {
-			audioData, err := s.client.voicePipeline.SynthesizeResponse(ctx, textContent, req.Voice)
+			synth, err := s.client.voicePipeline.Synthesize(ctx, textContent, req.Voice)
 			if err != nil {
 				return nil, fmt.Errorf("synthesize audio: %w", err)
 			}
 
-			if audioData != nil && len(audioData) > 0 {
+			if synth != nil && len(synth.Audio) > 0 {
+				audioData := synth.Audio
+				if synth.Provider != "" {
+					if resp.Metadata == nil {
+						resp.Metadata = make(map[string]any)
+					}
+					resp.Metadata[voice.MetadataTTSProvider] = synth.Provider
+				}
+
 				// Determine format
 				format := req.Voice.Output.Format
 				if format == "" {
@@ -108,8 +131,23 @@ func (s *MessagesService) createDirect(ctx context.Context, req *MessageRequest)
 
 // createViaProxy sends a request via the proxy server.
 func (s *MessagesService) createViaProxy(ctx context.Context, req *MessageRequest) (*Response, error) {
-	// TODO: Implement proxy mode HTTP request
-	return nil, fmt.Errorf("proxy mode not yet implemented")
+	// The proxy decides between JSON and SSE based on the stream flag
+	reqCopy := *req
+	reqCopy.Stream = false
+
+	create := core.ChainCreateMessage(s.client.proxyCreateMessage, s.client.middleware...)
+
+	var resp *types.MessageResponse
+	err := s.client.withRetry(ctx, func() error {
+		var err error
+		resp, err = create(ctx, &reqCopy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{MessageResponse: resp}, nil
 }
 
 // Stream sends a streaming message request.
@@ -119,7 +157,9 @@ func (s *MessagesService) Stream(ctx context.Context, req *MessageRequest) (*Str
 	reqCopy.Stream = true
 
 	if s.client.mode == modeDirect {
-		eventStream, err := s.client.core.StreamMessage(ctx, &reqCopy)
+		eventStream, err := s.client.openStreamWithRetry(ctx, func() (core.EventStream, error) {
+			return s.client.core.StreamMessage(ctx, &reqCopy)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -132,8 +172,15 @@ func (s *MessagesService) Stream(ctx context.Context, req *MessageRequest) (*Str
 
 // streamViaProxy establishes a streaming connection via the proxy.
 func (s *MessagesService) streamViaProxy(ctx context.Context, req *MessageRequest) (*Stream, error) {
-	// TODO: Implement proxy mode SSE streaming
-	return nil, fmt.Errorf("proxy mode streaming not yet implemented")
+	open := core.ChainStreamMessage(s.client.proxyStreamMessage, s.client.middleware...)
+
+	eventStream, err := s.client.openStreamWithRetry(ctx, func() (core.EventStream, error) {
+		return open(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newStreamFromEventStream(eventStream), nil
 }
 
 // Run executes a tool execution loop.
@@ -2,8 +2,14 @@ package vai
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
 )
 
 // ModelsService handles model listing and information.
@@ -12,24 +18,10 @@ type ModelsService struct {
 }
 
 // Model represents an available model.
-type Model struct {
-	ID           string                    `json:"id"`           // "anthropic/claude-sonnet-4"
-	Provider     string                    `json:"provider"`     // "anthropic"
-	Name         string                    `json:"name"`         // "claude-sonnet-4"
-	DisplayName  string                    `json:"display_name"` // "Claude Sonnet 4"
-	Description  string                    `json:"description,omitempty"`
-	Context      int                       `json:"context"`    // Max context window
-	MaxOutput    int                       `json:"max_output"` // Max output tokens
-	Capabilities core.ProviderCapabilities `json:"capabilities"`
-	Pricing      *ModelPricing             `json:"pricing,omitempty"`
-}
+type Model = catalog.Model
 
 // ModelPricing contains pricing information for a model.
-type ModelPricing struct {
-	InputPerMillion  float64 `json:"input_per_million"`
-	OutputPerMillion float64 `json:"output_per_million"`
-	Currency         string  `json:"currency"` // "USD"
-}
+type ModelPricing = catalog.ModelPricing
 
 // ListModelsResponse contains the list of available models.
 type ListModelsResponse struct {
@@ -38,22 +30,159 @@ type ListModelsResponse struct {
 
 // List returns all available models.
 func (s *ModelsService) List(ctx context.Context) (*ListModelsResponse, error) {
-	// TODO: Implement model listing
-	return &ListModelsResponse{
-		Models: []Model{},
-	}, nil
+	if s.client.mode == modeProxy {
+		return s.listViaProxy(ctx, "")
+	}
+	return &ListModelsResponse{Models: s.client.modelCatalog().List()}, nil
 }
 
 // Get returns information about a specific model.
+// modelID is a "provider/model" string; dated snapshots resolve to their base model.
 func (s *ModelsService) Get(ctx context.Context, modelID string) (*Model, error) {
-	// TODO: Implement model retrieval
-	return nil, nil
+	if s.client.mode == modeProxy {
+		var info proxyModelInfo
+		if err := s.client.getProxyJSON(ctx, "/v1/models/"+modelID, &info); err != nil {
+			return nil, err
+		}
+		model := info.toModel()
+		return &model, nil
+	}
+
+	model, ok := s.client.modelCatalog().Get(modelID)
+	if !ok {
+		return nil, core.NewNotFoundError(fmt.Sprintf("model %q not found", modelID))
+	}
+	return &model, nil
 }
 
 // ListByProvider returns models from a specific provider.
 func (s *ModelsService) ListByProvider(ctx context.Context, provider string) (*ListModelsResponse, error) {
-	// TODO: Implement filtered model listing
-	return &ListModelsResponse{
-		Models: []Model{},
-	}, nil
+	if s.client.mode == modeProxy {
+		return s.listViaProxy(ctx, provider)
+	}
+	models := s.client.modelCatalog().ListByProvider(provider)
+	if models == nil {
+		models = []Model{}
+	}
+	return &ListModelsResponse{Models: models}, nil
+}
+
+// listViaProxy fetches the model list from the proxy, optionally filtered by provider.
+func (s *ModelsService) listViaProxy(ctx context.Context, provider string) (*ListModelsResponse, error) {
+	path := "/v1/models"
+	if provider != "" {
+		path += "?provider=" + url.QueryEscape(provider)
+	}
+	var resp proxyModelsResponse
+	if err := s.client.getProxyJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+
+	models := []Model{}
+	for _, provider := range resp.Providers {
+		for _, info := range provider.Models {
+			models = append(models, info.toModel())
+		}
+	}
+	return &ListModelsResponse{Models: models}, nil
+}
+
+// proxyModelsResponse matches the proxy's /v1/models response.
+type proxyModelsResponse struct {
+	Providers []struct {
+		ID     string           `json:"id"`
+		Models []proxyModelInfo `json:"models"`
+	} `json:"providers"`
+}
+
+// proxyModelInfo matches a single model entry of the proxy's /v1/models response.
+type proxyModelInfo struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	FullID          string `json:"full_id"`
+	Description     string `json:"description,omitempty"`
+	ContextWindow   int    `json:"context_window"`
+	MaxOutputTokens int    `json:"max_output_tokens"`
+	Capabilities    struct {
+		Vision           bool `json:"vision"`
+		AudioInput       bool `json:"audio_input"`
+		AudioOutput      bool `json:"audio_output"`
+		Video            bool `json:"video"`
+		Tools            bool `json:"tools"`
+		ToolStreaming    bool `json:"tool_streaming"`
+		Thinking         bool `json:"thinking"`
+		StructuredOutput bool `json:"structured_output"`
+	} `json:"capabilities"`
+	NativeTools []string `json:"native_tools"`
+	Pricing     *struct {
+		InputPerMTok      float64 `json:"input_per_mtok"`
+		OutputPerMTok     float64 `json:"output_per_mtok"`
+		CacheReadPerMTok  float64 `json:"cache_read_per_mtok"`
+		CacheWritePerMTok float64 `json:"cache_write_per_mtok"`
+		Currency          string  `json:"currency"`
+	} `json:"pricing,omitempty"`
+}
+
+// toModel converts a proxy model entry to the SDK representation.
+func (info proxyModelInfo) toModel() Model {
+	m := Model{
+		ID:          info.FullID,
+		Name:        info.ID,
+		DisplayName: info.Name,
+		Description: info.Description,
+		Context:     info.ContextWindow,
+		MaxOutput:   info.MaxOutputTokens,
+		Capabilities: core.ProviderCapabilities{
+			Vision:           info.Capabilities.Vision,
+			AudioInput:       info.Capabilities.AudioInput,
+			AudioOutput:      info.Capabilities.AudioOutput,
+			Video:            info.Capabilities.Video,
+			Tools:            info.Capabilities.Tools,
+			ToolStreaming:    info.Capabilities.ToolStreaming,
+			Thinking:         info.Capabilities.Thinking,
+			StructuredOutput: info.Capabilities.StructuredOutput,
+			NativeTools:      info.NativeTools,
+		},
+	}
+	if provider, _, err := core.ParseModelString(info.FullID); err == nil {
+		m.Provider = provider
+	}
+	if info.Pricing != nil {
+		currency := info.Pricing.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+		m.Pricing = &ModelPricing{
+			InputPerMillion:      info.Pricing.InputPerMTok,
+			OutputPerMillion:     info.Pricing.OutputPerMTok,
+			CacheReadPerMillion:  info.Pricing.CacheReadPerMTok,
+			CacheWritePerMillion: info.Pricing.CacheWritePerMTok,
+			Currency:             currency,
+		}
+	}
+	return m
+}
+
+// modelCatalog returns the client's model catalog, creating the default one on first use.
+func (c *Client) modelCatalog() *catalog.Catalog {
+	c.catalogOnce.Do(func() {
+		if c.catalog == nil {
+			c.catalog = catalog.Default()
+		}
+	})
+	return c.catalog
+}
+
+// getProxyJSON performs a GET against the proxy and decodes the JSON body into dest.
+func (c *Client) getProxyJSON(ctx context.Context, path string, dest any) error {
+	resp, err := c.doProxyRequest(ctx, http.MethodGet, path, nil, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decode %s: %w", strings.SplitN(path, "?", 2)[0], err)
+	}
+	return nil
 }
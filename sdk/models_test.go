@@ -0,0 +1,122 @@
+package vai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
+)
+
+func TestModels_Direct(t *testing.T) {
+	client := NewClient()
+
+	list, err := client.Models.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Models) == 0 {
+		t.Fatal("expected built-in models")
+	}
+
+	model, err := client.Models.Get(context.Background(), "anthropic/claude-haiku-4-5-20251001")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if model.ID != "anthropic/claude-haiku-4-5" || model.Pricing == nil {
+		t.Errorf("Get() = %+v", model)
+	}
+
+	_, err = client.Models.Get(context.Background(), "anthropic/nonexistent-model")
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Type != core.ErrNotFound {
+		t.Errorf("expected not_found_error, got %v", err)
+	}
+
+	groq, err := client.Models.ListByProvider(context.Background(), "groq")
+	if err != nil {
+		t.Fatalf("ListByProvider() error = %v", err)
+	}
+	for _, m := range groq.Models {
+		if m.Provider != "groq" {
+			t.Errorf("unexpected provider %q in groq list", m.Provider)
+		}
+	}
+}
+
+func TestModels_CustomCatalog(t *testing.T) {
+	c := catalog.New(catalog.Model{ID: "local/tiny", Context: 2048, MaxOutput: 512})
+	client := NewClient(WithModelCatalog(c))
+
+	list, _ := client.Models.List(context.Background())
+	if len(list.Models) != 1 || list.Models[0].ID != "local/tiny" {
+		t.Errorf("List() = %+v", list.Models)
+	}
+}
+
+func TestModels_Proxy(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	sonnet := `{"id":"claude-sonnet-4","name":"Claude Sonnet 4","full_id":"anthropic/claude-sonnet-4",
+		"context_window":200000,"max_output_tokens":64000,
+		"capabilities":{"text":true,"vision":true,"tools":true},
+		"native_tools":["web_search"],
+		"pricing":{"input_per_mtok":3,"output_per_mtok":15,"cache_read_per_mtok":0.3}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/models/anthropic/claude-sonnet-4":
+			w.Write([]byte(sonnet))
+		case r.URL.Path == "/v1/models" && r.URL.Query().Get("provider") == "groq":
+			w.Write([]byte(`{"providers":[{"id":"groq","name":"Groq","models":[
+				{"id":"llama-3.3-70b-versatile","full_id":"groq/llama-3.3-70b-versatile","context_window":131072}]}]}`))
+		case r.URL.Path == "/v1/models":
+			w.Write([]byte(`{"providers":[
+				{"id":"anthropic","name":"Anthropic","models":[` + sonnet + `]},
+				{"id":"groq","name":"Groq","models":[{"id":"llama-3.3-70b-versatile","full_id":"groq/llama-3.3-70b-versatile"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"error","error":{"type":"not_found_error","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	list, err := client.Models.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Models) != 2 {
+		t.Fatalf("List() returned %d models, want 2", len(list.Models))
+	}
+
+	model, err := client.Models.Get(context.Background(), "anthropic/claude-sonnet-4")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if model.Provider != "anthropic" || model.Name != "claude-sonnet-4" || model.Context != 200000 {
+		t.Errorf("Get() = %+v", model)
+	}
+	if !model.Capabilities.Vision || len(model.Capabilities.NativeTools) != 1 {
+		t.Errorf("capabilities = %+v", model.Capabilities)
+	}
+	if model.Pricing == nil || model.Pricing.CacheReadPerMillion != 0.3 || model.Pricing.Currency != "USD" {
+		t.Errorf("pricing = %+v", model.Pricing)
+	}
+
+	groq, err := client.Models.ListByProvider(context.Background(), "groq")
+	if err != nil || len(groq.Models) != 1 || groq.Models[0].Provider != "groq" {
+		t.Errorf("ListByProvider(groq) = %+v, %v", groq, err)
+	}
+
+	_, err = client.Models.Get(context.Background(), "anthropic/missing")
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Type != core.ErrNotFound {
+		t.Errorf("expected not_found_error, got %v", err)
+	}
+}
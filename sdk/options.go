@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/catalog"
 )
 
 // ClientOption is a function that configures a Client.
@@ -76,3 +79,53 @@ func WithRetryBackoff(d time.Duration) ClientOption {
 		c.retryBackoff = d
 	}
 }
+
+// WithModelRoute maps a logical model name (e.g. "fast") to an ordered list of
+// "provider/model" targets. Requests for name fail over to the next target on
+// retryable errors or provider outages; the serving target is reported in
+// the response metadata.
+// Direct Mode only; in Proxy Mode routes are configured on the proxy.
+func WithModelRoute(name string, targets ...string) ClientOption {
+	return func(c *Client) {
+		if c.modelRoutes == nil {
+			c.modelRoutes = make(map[string][]string)
+		}
+		c.modelRoutes[name] = targets
+	}
+}
+
+// WithModelAlias maps alias to a single "provider/model" target.
+// Direct Mode only.
+func WithModelAlias(alias, target string) ClientOption {
+	return WithModelRoute(alias, target)
+}
+
+// WithMiddleware adds middleware around every LLM call made by the client,
+// including Messages.Create, Messages.Stream, Run, RunStream and live sessions.
+// In Direct Mode it is registered on the engine; in Proxy Mode it wraps the
+// HTTP calls to the proxy.
+func WithMiddleware(middleware ...core.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithCapabilityMode sets how requests using features the provider does not
+// support are handled: rejected (core.CapabilityStrict, the default), degraded
+// with the changes recorded in response metadata (core.CapabilityDegrade), or
+// sent unchecked (core.CapabilityOff).
+// Direct Mode only.
+func WithCapabilityMode(mode core.CapabilityMode) ClientOption {
+	return func(c *Client) {
+		c.capabilityMode = mode
+	}
+}
+
+// WithModelCatalog replaces the built-in model catalog used by Models and
+// cost tracking. Start from catalog.Default() to extend the built-in list.
+// Direct Mode only; in Proxy Mode the proxy's catalog is served.
+func WithModelCatalog(c *catalog.Catalog) ClientOption {
+	return func(client *Client) {
+		client.catalog = c
+	}
+}
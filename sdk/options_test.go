@@ -1,10 +1,14 @@
 package vai
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"testing"
 	"time"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
 )
 
 func TestNewClient_DefaultMode(t *testing.T) {
@@ -81,6 +85,45 @@ func TestWithRetryBackoff(t *testing.T) {
 	}
 }
 
+func TestWithModelRoute(t *testing.T) {
+	client := NewClient(
+		WithModelRoute("fast", "anthropic/claude-haiku-4-5-20251001", "groq/llama-3.3-70b-versatile"),
+		WithModelAlias("smart", "anthropic/claude-sonnet-4"),
+		WithModelRoute("broken", "no-slash"),
+	)
+
+	routes := client.Engine().ModelRoutes()
+	if len(routes["fast"]) != 2 || routes["fast"][1] != "groq/llama-3.3-70b-versatile" {
+		t.Errorf("fast route = %v", routes["fast"])
+	}
+	if len(routes["smart"]) != 1 {
+		t.Errorf("smart alias = %v", routes["smart"])
+	}
+	if _, ok := routes["broken"]; ok {
+		t.Error("invalid route should not be registered")
+	}
+}
+
+func TestModelRoute_Failover(t *testing.T) {
+	primary := &scriptedProvider{name: "primary", failures: 1, err: core.NewOverloadedError("overloaded")}
+	backup := &scriptedProvider{name: "backup"}
+
+	client := NewClient(WithModelRoute("fast", "primary/model-a", "backup/model-b"))
+	client.Engine().RegisterProvider(primary)
+	client.Engine().RegisterProvider(backup)
+
+	resp, err := client.Messages.Create(context.Background(), testRequest("fast"))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if resp.Metadata[core.MetadataRoutedModel] != "backup/model-b" {
+		t.Errorf("routed_model = %v, want backup/model-b", resp.Metadata[core.MetadataRoutedModel])
+	}
+	if backup.calls.Load() != 1 {
+		t.Errorf("backup calls = %d, want 1", backup.calls.Load())
+	}
+}
+
 func TestClient_Services(t *testing.T) {
 	client := NewClient()
 
@@ -105,3 +148,36 @@ func TestClient_Engine(t *testing.T) {
 		t.Error("Proxy mode client should not have engine")
 	}
 }
+
+func TestWithMiddleware_AppliesToCreateAndRun(t *testing.T) {
+	var calls int
+	counter := core.MiddlewareFuncs{
+		CreateMessage: func(ctx context.Context, req *types.MessageRequest, next core.CreateMessageFunc) (*types.MessageResponse, error) {
+			calls++
+			return next(ctx, req)
+		},
+	}
+
+	client := NewClient(WithMiddleware(counter))
+	client.Engine().RegisterProvider(&scriptedProvider{name: "scripted"})
+
+	if _, err := client.Messages.Create(context.Background(), testRequest("scripted/model")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := client.Messages.Run(context.Background(), testRequest("scripted/model")); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("middleware calls = %d, want 2", calls)
+	}
+}
+
+func TestWithCapabilityMode(t *testing.T) {
+	client := NewClient(WithCapabilityMode(core.CapabilityDegrade))
+	if client.Engine().CapabilityMode() != core.CapabilityDegrade {
+		t.Errorf("CapabilityMode() = %q, want degrade", client.Engine().CapabilityMode())
+	}
+	if NewClient().Engine().CapabilityMode() != core.CapabilityStrict {
+		t.Error("expected strict capability mode by default")
+	}
+}
@@ -0,0 +1,294 @@
+package vai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// doProxyRequest sends a JSON request to the proxy server.
+// The caller is responsible for closing the response body.
+func (c *Client) doProxyRequest(ctx context.Context, method, path string, body any, stream bool) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	} else {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+
+	// Check for errors before handing the body to the caller
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, parseProxyError(resp)
+	}
+
+	return resp, nil
+}
+
+// proxyCreateMessage sends a single non-streaming request to the proxy.
+func (c *Client) proxyCreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	httpResp, err := c.doProxyRequest(ctx, http.MethodPost, "/v1/messages", req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return decodeMessageResponse(body)
+}
+
+// proxyStreamMessage opens a single SSE stream on the proxy.
+func (c *Client) proxyStreamMessage(ctx context.Context, req *types.MessageRequest) (core.EventStream, error) {
+	httpResp, err := c.doProxyRequest(ctx, http.MethodPost, "/v1/messages", req, true)
+	if err != nil {
+		return nil, err
+	}
+	return newProxyEventStream(httpResp.Body), nil
+}
+
+// proxyErrorResponse matches the proxy's error envelope.
+type proxyErrorResponse struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type       string `json:"type"`
+		Message    string `json:"message"`
+		Param      string `json:"param,omitempty"`
+		Code       string `json:"code,omitempty"`
+		RetryAfter *int   `json:"retry_after,omitempty"`
+	} `json:"error"`
+}
+
+// parseProxyError converts a proxy error response into a core.Error.
+func parseProxyError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var proxyErr proxyErrorResponse
+	if err := json.Unmarshal(body, &proxyErr); err != nil || proxyErr.Error.Type == "" {
+		// Can't parse error, fall back to the HTTP status
+		return &core.Error{
+			Type:          errorTypeFromStatus(resp.StatusCode),
+			Message:       fmt.Sprintf("proxy returned %s: %s", resp.Status, strings.TrimSpace(string(body))),
+			ProviderError: string(body),
+		}
+	}
+
+	e := &core.Error{
+		Type:      core.ErrorType(proxyErr.Error.Type),
+		Message:   proxyErr.Error.Message,
+		Param:     proxyErr.Error.Param,
+		Code:      proxyErr.Error.Code,
+		RequestID: resp.Header.Get("X-Request-ID"),
+	}
+
+	// Prefer the explicit value in the body, then the Retry-After header
+	if proxyErr.Error.RetryAfter != nil {
+		e.RetryAfter = proxyErr.Error.RetryAfter
+	} else if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			e.RetryAfter = &secs
+		}
+	}
+
+	return e
+}
+
+// errorTypeFromStatus maps an HTTP status code to an error type.
+func errorTypeFromStatus(status int) core.ErrorType {
+	switch status {
+	case http.StatusBadRequest:
+		return core.ErrInvalidRequest
+	case http.StatusUnauthorized:
+		return core.ErrAuthentication
+	case http.StatusForbidden:
+		return core.ErrPermission
+	case http.StatusNotFound:
+		return core.ErrNotFound
+	case http.StatusTooManyRequests:
+		return core.ErrRateLimit
+	case 529, http.StatusServiceUnavailable:
+		return core.ErrOverloaded
+	default:
+		return core.ErrAPI
+	}
+}
+
+// proxyMessageResponse mirrors types.MessageResponse with raw content blocks.
+type proxyMessageResponse struct {
+	ID           string           `json:"id"`
+	Type         string           `json:"type"`
+	Role         string           `json:"role"`
+	Model        string           `json:"model"`
+	Content      json.RawMessage  `json:"content"`
+	StopReason   types.StopReason `json:"stop_reason"`
+	StopSequence *string          `json:"stop_sequence,omitempty"`
+	Usage        types.Usage      `json:"usage"`
+	Metadata     map[string]any   `json:"metadata,omitempty"`
+}
+
+// decodeMessageResponse parses a MessageResponse including polymorphic content blocks.
+func decodeMessageResponse(data []byte) (*types.MessageResponse, error) {
+	var raw proxyMessageResponse
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	resp := &types.MessageResponse{
+		ID:           raw.ID,
+		Type:         raw.Type,
+		Role:         raw.Role,
+		Model:        raw.Model,
+		StopReason:   raw.StopReason,
+		StopSequence: raw.StopSequence,
+		Usage:        raw.Usage,
+		Metadata:     raw.Metadata,
+	}
+
+	if len(raw.Content) > 0 && string(raw.Content) != "null" {
+		content, err := types.UnmarshalContentBlocks(raw.Content)
+		if err != nil {
+			return nil, fmt.Errorf("parse content: %w", err)
+		}
+		resp.Content = content
+	}
+
+	return resp, nil
+}
+
+// proxyEventStream implements core.EventStream over a proxy SSE response.
+type proxyEventStream struct {
+	reader *bufio.Reader
+	closer io.Closer
+	err    error
+}
+
+// newProxyEventStream creates an event stream from an SSE response body.
+func newProxyEventStream(body io.ReadCloser) *proxyEventStream {
+	return &proxyEventStream{
+		reader: bufio.NewReader(body),
+		closer: body,
+	}
+}
+
+// Next returns the next event from the stream.
+// Returns nil, io.EOF when the stream is complete.
+func (s *proxyEventStream) Next() (types.StreamEvent, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var data strings.Builder
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF && data.Len() > 0 {
+				// Dispatch a final event that wasn't followed by a blank line
+				event, derr := s.dispatch(data.String())
+				if derr != nil {
+					return nil, derr
+				}
+				s.err = io.EOF
+				if event != nil {
+					return event, nil
+				}
+			}
+			s.err = err
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		// A blank line terminates the current event
+		if line == "" {
+			if data.Len() == 0 {
+				continue
+			}
+			event, err := s.dispatch(data.String())
+			data.Reset()
+			if err != nil {
+				return nil, err
+			}
+			if event == nil {
+				continue
+			}
+			return event, nil
+		}
+
+		// Comments and the event name are informational; the type lives in the data
+		if strings.HasPrefix(line, ":") || strings.HasPrefix(line, "event:") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "data:") {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+// dispatch parses the accumulated data of a single SSE event.
+// Ping events and unknown event types are skipped by returning nil. An
+// error event ends the stream with its error, as in Direct Mode.
+func (s *proxyEventStream) dispatch(data string) (types.StreamEvent, error) {
+	if data == "[DONE]" {
+		s.err = io.EOF
+		return nil, io.EOF
+	}
+
+	event, err := types.UnmarshalStreamEvent([]byte(data))
+	if err != nil {
+		// Skip unparseable events rather than failing the stream
+		return nil, nil
+	}
+	switch e := event.(type) {
+	case types.PingEvent:
+		return nil, nil
+	case types.ErrorEvent:
+		s.err = &core.Error{Type: core.ErrorType(e.Error.Type), Message: e.Error.Message}
+		return nil, s.err
+	}
+
+	return event, nil
+}
+
+// Close releases resources associated with the stream.
+func (s *proxyEventStream) Close() error {
+	return s.closer.Close()
+}
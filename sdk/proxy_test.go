@@ -0,0 +1,322 @@
+package vai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+func TestProxy_Create(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("expected /v1/messages, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer vango_sk_test" {
+			t.Errorf("expected bearer auth, got %q", r.Header.Get("Authorization"))
+		}
+
+		var req types.MessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "anthropic/claude-sonnet-4" {
+			t.Errorf("expected model to be forwarded, got %q", req.Model)
+		}
+		if req.Stream {
+			t.Error("expected stream=false for Create")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "msg_proxy",
+			"type": "message",
+			"role": "assistant",
+			"model": "anthropic/claude-sonnet-4",
+			"content": [
+				{"type": "text", "text": "Hello via proxy"},
+				{"type": "tool_use", "id": "call_1", "name": "get_weather", "input": {"city": "Paris"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 10, "output_tokens": 5, "total_tokens": 15}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithAPIKey("vango_sk_test"))
+
+	resp, err := client.Messages.Create(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if resp.TextContent() != "Hello via proxy" {
+		t.Errorf("TextContent() = %q", resp.TextContent())
+	}
+	uses := resp.ToolUses()
+	if len(uses) != 1 || uses[0].Input["city"] != "Paris" {
+		t.Errorf("ToolUses() = %+v", uses)
+	}
+	if resp.Usage.TotalTokens != 15 {
+		t.Errorf("Usage.TotalTokens = %d, want 15", resp.Usage.TotalTokens)
+	}
+}
+
+func TestProxy_Stream(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("expected SSE accept header, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_stream","type":"message","role":"assistant","model":"anthropic/claude-sonnet-4","content":[],"usage":{"input_tokens":7,"output_tokens":0}}}
+
+: keep-alive comment
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Streaming "}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"via proxy"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":3}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	stream, err := client.Messages.Stream(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var eventTypes []string
+	for event := range stream.Events() {
+		eventTypes = append(eventTypes, event.EventType())
+	}
+
+	want := []string{"message_start", "content_block_start", "content_block_delta", "content_block_delta", "content_block_stop", "message_delta", "message_stop"}
+	if strings.Join(eventTypes, ",") != strings.Join(want, ",") {
+		t.Errorf("event types = %v, want %v", eventTypes, want)
+	}
+	if got := stream.TextContent(); got != "Streaming via proxy" {
+		t.Errorf("TextContent() = %q", got)
+	}
+	if stream.Response().StopReason != types.StopReasonEndTurn {
+		t.Errorf("StopReason = %q", stream.Response().StopReason)
+	}
+}
+
+func TestProxy_StreamError(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_stream","type":"message","role":"assistant","model":"anthropic/claude-sonnet-4","content":[],"usage":{"input_tokens":7,"output_tokens":0}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Half an"}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"upstream overloaded"}}
+
+`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	stream, err := client.Messages.Stream(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer stream.Close()
+
+	for event := range stream.Events() {
+		if _, ok := event.(types.ErrorEvent); ok {
+			t.Error("error event passed on as a stream event")
+		}
+	}
+	var coreErr *core.Error
+	if !errors.As(stream.Err(), &coreErr) {
+		t.Fatalf("Err() = %v, want *core.Error", stream.Err())
+	}
+	if coreErr.Type != core.ErrOverloaded || coreErr.Message != "upstream overloaded" {
+		t.Errorf("Err() = %+v", coreErr)
+	}
+}
+
+func TestProxy_Error(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	_, err := client.Messages.Create(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) {
+		t.Fatalf("expected *core.Error, got %T (%v)", err, err)
+	}
+	if coreErr.Type != core.ErrRateLimit {
+		t.Errorf("Type = %q, want rate_limit_error", coreErr.Type)
+	}
+	if coreErr.RetryAfter == nil || *coreErr.RetryAfter != 12 {
+		t.Errorf("RetryAfter = %v, want 12", coreErr.RetryAfter)
+	}
+	if !coreErr.IsRetryable() {
+		t.Error("expected rate limit error to be retryable")
+	}
+}
+
+func TestProxy_Error_Unparseable(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: 529,
+		Status:     "529 Overloaded",
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("upstream overloaded")),
+	}
+
+	err := parseProxyError(resp)
+
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) {
+		t.Fatalf("expected *core.Error, got %T", err)
+	}
+	if coreErr.Type != core.ErrOverloaded {
+		t.Errorf("Type = %q, want overloaded_error", coreErr.Type)
+	}
+}
+
+func TestProxy_Run(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","model":"anthropic/claude-sonnet-4",
+				"content":[{"type":"tool_use","id":"call_1","name":"lookup","input":{"q":"x"}}],
+				"stop_reason":"tool_use","usage":{"input_tokens":5,"output_tokens":5,"total_tokens":10}}`))
+			return
+		}
+
+		// Second turn must carry the tool result back
+		var req types.MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		last := req.Messages[len(req.Messages)-1]
+		blocks := last.ContentBlocks()
+		if len(blocks) != 1 || blocks[0].BlockType() != "tool_result" {
+			t.Errorf("expected tool_result in last message, got %+v", blocks)
+		}
+
+		w.Write([]byte(`{"id":"msg_2","type":"message","role":"assistant","model":"anthropic/claude-sonnet-4",
+			"content":[{"type":"text","text":"done"}],
+			"stop_reason":"end_turn","usage":{"input_tokens":5,"output_tokens":5,"total_tokens":10}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	result, err := client.Messages.Run(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Look it up"}},
+	}, WithToolHandler("lookup", func(ctx context.Context, input json.RawMessage) (any, error) {
+		return "found", nil
+	}))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.StopReason != RunStopEndTurn {
+		t.Errorf("StopReason = %q, want end_turn", result.StopReason)
+	}
+	if result.ToolCallCount != 1 {
+		t.Errorf("ToolCallCount = %d, want 1", result.ToolCallCount)
+	}
+	if result.Usage.TotalTokens != 20 {
+		t.Errorf("Usage.TotalTokens = %d, want 20", result.Usage.TotalTokens)
+	}
+}
+
+func TestProxy_Middleware(t *testing.T) {
+	requireTCPListenSDK(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.System != "injected" {
+			t.Errorf("expected middleware to set system prompt, got %v", req.System)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithMiddleware(core.MiddlewareFuncs{
+		CreateMessage: func(ctx context.Context, req *types.MessageRequest, next core.CreateMessageFunc) (*types.MessageResponse, error) {
+			reqCopy := *req
+			reqCopy.System = "injected"
+			return next(ctx, &reqCopy)
+		},
+	}))
+
+	if _, err := client.Messages.Create(context.Background(), &MessageRequest{
+		Model:    "anthropic/claude-sonnet-4",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
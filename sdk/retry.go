@@ -0,0 +1,224 @@
+package vai
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// maxRetryBackoff caps the delay between two attempts.
+const maxRetryBackoff = 30 * time.Second
+
+// withRetry calls fn until it succeeds, returns a non-retryable error,
+// or the configured number of retries is exhausted.
+func (c *Client) withRetry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !c.shouldRetry(attempt, err) {
+			return err
+		}
+		if !c.waitForRetry(ctx, attempt, err) {
+			return err
+		}
+	}
+}
+
+// shouldRetry reports whether another attempt is allowed after err.
+func (c *Client) shouldRetry(attempt int, err error) bool {
+	return attempt < c.maxRetries && core.IsRetryableError(err)
+}
+
+// waitForRetry sleeps before the next attempt.
+// It returns false if the context ends first or the wait would outlive its deadline.
+func (c *Client) waitForRetry(ctx context.Context, attempt int, err error) bool {
+	delay := c.retryDelay(attempt, err)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	c.logger.Debug("retrying request",
+		"attempt", attempt+1,
+		"max_retries", c.maxRetries,
+		"delay", delay,
+		"error", err,
+	)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// retryDelay computes the delay before retry number attempt+1.
+// A server-provided Retry-After wins; otherwise exponential backoff with jitter is used.
+func (c *Client) retryDelay(attempt int, err error) time.Duration {
+	if retryAfter, ok := core.RetryAfterFromError(err); ok {
+		return retryAfter
+	}
+
+	backoff := c.retryBackoff
+	if backoff <= 0 {
+		return 0
+	}
+	for i := 0; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+
+	// Equal jitter: keep half the backoff, randomize the rest
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// createMessageWithRetry sends a non-streaming request through the engine with retries.
+func (c *Client) createMessageWithRetry(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	var resp *types.MessageResponse
+	err := c.withRetry(ctx, func() error {
+		var err error
+		resp, err = c.core.CreateMessage(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// openStreamWithRetry opens a stream and wraps it so that failures occurring
+// before any event reached the caller are retried transparently.
+func (c *Client) openStreamWithRetry(ctx context.Context, open func() (core.EventStream, error)) (core.EventStream, error) {
+	stream, err := open()
+	if c.maxRetries <= 0 {
+		return stream, err
+	}
+
+	rs := &retryingEventStream{
+		client:  c,
+		ctx:     ctx,
+		open:    open,
+		current: stream,
+	}
+	if err != nil {
+		if err := rs.retry(err); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// retryingEventStream re-opens the underlying stream on retryable failures,
+// as long as no event has been handed to the caller yet.
+type retryingEventStream struct {
+	client    *Client
+	ctx       context.Context
+	open      func() (core.EventStream, error)
+	attempt   int
+	delivered bool
+
+	// Close may run on another goroutine while Next retries
+	mu      sync.Mutex
+	current core.EventStream
+	closed  bool
+}
+
+// Next returns the next event, retrying the stream if it fails before the first event.
+func (s *retryingEventStream) Next() (types.StreamEvent, error) {
+	for {
+		s.mu.Lock()
+		current := s.current
+		s.mu.Unlock()
+		if current == nil {
+			return nil, io.EOF
+		}
+		event, err := current.Next()
+		if s.delivered {
+			return event, err
+		}
+
+		// Providers may report overloads as an in-band error event
+		if err == nil {
+			if errEvent, ok := event.(types.ErrorEvent); ok {
+				err = &core.Error{Type: core.ErrorType(errEvent.Error.Type), Message: errEvent.Error.Message}
+			}
+		}
+
+		if err == nil || err == io.EOF {
+			if event != nil {
+				s.delivered = true
+			}
+			return event, err
+		}
+
+		if s.retry(err) != nil {
+			// Deliver the original failure unchanged
+			if event != nil {
+				s.delivered = true
+				return event, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// retry replaces the current stream with a fresh one. It returns the last
+// error if no further attempt should be made, such as once Close has run.
+func (s *retryingEventStream) retry(err error) error {
+	for {
+		if s.isClosed() || !s.client.shouldRetry(s.attempt, err) || !s.client.waitForRetry(s.ctx, s.attempt, err) {
+			return err
+		}
+		s.attempt++
+
+		s.mu.Lock()
+		if s.current != nil {
+			s.current.Close()
+			s.current = nil
+		}
+		s.mu.Unlock()
+
+		stream, openErr := s.open()
+		if openErr != nil {
+			err = openErr
+			continue
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			stream.Close()
+			return err
+		}
+		s.current = stream
+		s.mu.Unlock()
+		return nil
+	}
+}
+
+func (s *retryingEventStream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Close releases the current underlying stream and stops further retries.
+func (s *retryingEventStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.current == nil {
+		return nil
+	}
+	return s.current.Close()
+}
@@ -0,0 +1,320 @@
+package vai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vai/pkg/core"
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+// scriptedProvider is a core.Provider that fails a fixed number of times before succeeding.
+type scriptedProvider struct {
+	name     string
+	failures int
+	err      error
+	events   []types.StreamEvent
+	usage    types.Usage
+	calls    atomic.Int32
+}
+
+func (p *scriptedProvider) Name() string { return p.name }
+
+func (p *scriptedProvider) CreateMessage(ctx context.Context, req *types.MessageRequest) (*types.MessageResponse, error) {
+	if int(p.calls.Add(1)) <= p.failures {
+		return nil, p.err
+	}
+	return &types.MessageResponse{
+		ID:         "msg_ok",
+		Role:       "assistant",
+		Content:    []types.ContentBlock{types.TextBlock{Type: "text", Text: "ok"}},
+		StopReason: types.StopReasonEndTurn,
+		Usage:      p.usage,
+	}, nil
+}
+
+func (p *scriptedProvider) StreamMessage(ctx context.Context, req *types.MessageRequest) (core.EventStream, error) {
+	if int(p.calls.Add(1)) <= p.failures {
+		return nil, p.err
+	}
+	return &mockEventStream{events: p.events}, nil
+}
+
+func (p *scriptedProvider) Capabilities() core.ProviderCapabilities {
+	return core.ProviderCapabilities{}
+}
+
+func newRetryTestClient(p core.Provider, opts ...ClientOption) *Client {
+	client := NewClient(append([]ClientOption{WithRetryBackoff(time.Millisecond)}, opts...)...)
+	client.Engine().RegisterProvider(p)
+	return client
+}
+
+func testRequest(model string) *MessageRequest {
+	return &MessageRequest{
+		Model:    model,
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	}
+}
+
+func TestRetry_Create_RetriesRetryableErrors(t *testing.T) {
+	p := &scriptedProvider{name: "scripted", failures: 2, err: core.NewOverloadedError("overloaded")}
+	client := newRetryTestClient(p, WithRetries(2))
+
+	resp, err := client.Messages.Create(context.Background(), testRequest("scripted/model"))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if resp.TextContent() != "ok" {
+		t.Errorf("TextContent() = %q, want ok", resp.TextContent())
+	}
+	if got := p.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetry_Create_GivesUpAfterMaxRetries(t *testing.T) {
+	p := &scriptedProvider{name: "scripted", failures: 5, err: core.NewOverloadedError("overloaded")}
+	client := newRetryTestClient(p, WithRetries(2))
+
+	_, err := client.Messages.Create(context.Background(), testRequest("scripted/model"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := p.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", got)
+	}
+}
+
+func TestRetry_Create_NonRetryable(t *testing.T) {
+	p := &scriptedProvider{name: "scripted", failures: 1, err: core.NewInvalidRequestError("bad")}
+	client := newRetryTestClient(p, WithRetries(3))
+
+	_, err := client.Messages.Create(context.Background(), testRequest("scripted/model"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := p.calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetry_Create_DisabledByDefault(t *testing.T) {
+	p := &scriptedProvider{name: "scripted", failures: 1, err: core.NewOverloadedError("overloaded")}
+	client := newRetryTestClient(p)
+
+	if _, err := client.Messages.Create(context.Background(), testRequest("scripted/model")); err == nil {
+		t.Fatal("expected error without retries configured")
+	}
+	if got := p.calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetry_RespectsContextDeadline(t *testing.T) {
+	retryAfter := 10
+	p := &scriptedProvider{
+		name:     "scripted",
+		failures: 1,
+		err:      &core.Error{Type: core.ErrRateLimit, Message: "slow down", RetryAfter: &retryAfter},
+	}
+	client := newRetryTestClient(p, WithRetries(3))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Messages.Create(ctx, testRequest("scripted/model"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("retry waited past the context deadline")
+	}
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Type != core.ErrRateLimit {
+		t.Errorf("expected the original rate limit error, got %v", err)
+	}
+}
+
+func TestRetry_Stream_RetriesOpen(t *testing.T) {
+	p := &scriptedProvider{
+		name:     "scripted",
+		failures: 1,
+		err:      core.NewAPIError("boom"),
+		events: []types.StreamEvent{
+			types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{ID: "msg_stream"}},
+			types.MessageStopEvent{Type: "message_stop"},
+		},
+	}
+	client := newRetryTestClient(p, WithRetries(1))
+
+	stream, err := client.Messages.Stream(context.Background(), testRequest("scripted/model"))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer stream.Close()
+
+	count := 0
+	for range stream.Events() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("events = %d, want 2", count)
+	}
+	if got := p.calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+// flakyEventStream fails with err on the first Next call.
+type flakyEventStream struct {
+	err    error
+	events []types.StreamEvent
+	index  int
+	closed atomic.Bool
+}
+
+func (s *flakyEventStream) Next() (types.StreamEvent, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.index >= len(s.events) {
+		return nil, io.EOF
+	}
+	event := s.events[s.index]
+	s.index++
+	return event, nil
+}
+
+func (s *flakyEventStream) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+func TestRetryingEventStream_BeforeFirstEvent(t *testing.T) {
+	client := NewClient(WithRetries(2), WithRetryBackoff(time.Millisecond))
+
+	opens := 0
+	open := func() (core.EventStream, error) {
+		opens++
+		if opens == 1 {
+			// In-band overload before anything was delivered
+			return &flakyEventStream{events: []types.StreamEvent{
+				types.ErrorEvent{Type: "error", Error: types.Error{Type: "overloaded_error", Message: "overloaded"}},
+			}}, nil
+		}
+		if opens == 2 {
+			return &flakyEventStream{err: core.NewAPIError("connection reset")}, nil
+		}
+		return &flakyEventStream{events: []types.StreamEvent{
+			types.MessageStartEvent{Type: "message_start"},
+		}}, nil
+	}
+
+	stream, err := client.openStreamWithRetry(context.Background(), open)
+	if err != nil {
+		t.Fatalf("openStreamWithRetry() error = %v", err)
+	}
+
+	event, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if event.EventType() != "message_start" {
+		t.Errorf("EventType() = %q, want message_start", event.EventType())
+	}
+	if opens != 3 {
+		t.Errorf("opens = %d, want 3", opens)
+	}
+}
+
+func TestRetryingEventStream_NoRetryAfterDelivery(t *testing.T) {
+	client := NewClient(WithRetries(2), WithRetryBackoff(time.Millisecond))
+
+	opens := 0
+	failing := &flakyEventStream{events: []types.StreamEvent{types.MessageStartEvent{Type: "message_start"}}}
+	open := func() (core.EventStream, error) {
+		opens++
+		return failing, nil
+	}
+
+	stream, err := client.openStreamWithRetry(context.Background(), open)
+	if err != nil {
+		t.Fatalf("openStreamWithRetry() error = %v", err)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	// Fail after the caller has already seen an event
+	failing.err = core.NewOverloadedError("overloaded")
+	if _, err := stream.Next(); err == nil {
+		t.Fatal("expected mid-stream error to be surfaced")
+	}
+	if opens != 1 {
+		t.Errorf("opens = %d, want 1", opens)
+	}
+}
+
+func TestRetryingEventStream_CloseDuringRetry(t *testing.T) {
+	client := NewClient(WithRetries(2), WithRetryBackoff(time.Millisecond))
+
+	opening, release := make(chan struct{}), make(chan struct{})
+	fresh := &flakyEventStream{events: []types.StreamEvent{types.MessageStartEvent{Type: "message_start"}}}
+	opens := 0
+	open := func() (core.EventStream, error) {
+		opens++
+		if opens == 1 {
+			return &flakyEventStream{err: core.NewOverloadedError("overloaded")}, nil
+		}
+		close(opening)
+		<-release
+		return fresh, nil
+	}
+
+	stream, err := client.openStreamWithRetry(context.Background(), open)
+	if err != nil {
+		t.Fatalf("openStreamWithRetry() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Next()
+		done <- err
+	}()
+
+	// The caller closes the stream while the retry is opening a new one
+	<-opening
+	stream.Close()
+	close(release)
+	if err := <-done; err == nil {
+		t.Error("Next() after Close succeeded, want the original error")
+	}
+	if !fresh.closed.Load() {
+		t.Error("stream opened after Close was not closed")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := NewClient(WithRetryBackoff(100 * time.Millisecond))
+
+	for attempt := 0; attempt < 3; attempt++ {
+		full := 100 * time.Millisecond << attempt
+		d := client.retryDelay(attempt, core.NewAPIError("boom"))
+		if d < full/2 || d > full {
+			t.Errorf("retryDelay(%d) = %v, want within [%v, %v]", attempt, d, full/2, full)
+		}
+	}
+
+	if d := client.retryDelay(20, core.NewAPIError("boom")); d > maxRetryBackoff {
+		t.Errorf("retryDelay should be capped at %v, got %v", maxRetryBackoff, d)
+	}
+
+	if d := client.retryDelay(0, core.NewRateLimitError("slow down", 3)); d != 3*time.Second {
+		t.Errorf("retryDelay with Retry-After = %v, want 3s", d)
+	}
+}
@@ -3,6 +3,7 @@ package vai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -12,6 +13,10 @@ import (
 
 	"github.com/vango-go/vai/pkg/core/live"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice"
+	"github.com/vango-go/vai/pkg/core/voice/chunk"
+	"github.com/vango-go/vai/pkg/core/voice/filler"
+	"github.com/vango-go/vai/pkg/core/voice/normalize"
 	"github.com/vango-go/vai/pkg/core/voice/tts"
 )
 
@@ -19,14 +24,15 @@ import (
 type RunStopReason string
 
 const (
-	RunStopEndTurn      RunStopReason = "end_turn"       // Model finished naturally
-	RunStopMaxToolCalls RunStopReason = "max_tool_calls" // Hit tool call limit
-	RunStopMaxTurns     RunStopReason = "max_turns"      // Hit turn limit
-	RunStopMaxTokens    RunStopReason = "max_tokens"     // Hit token limit
-	RunStopTimeout      RunStopReason = "timeout"        // Hit timeout
-	RunStopCustom       RunStopReason = "custom"         // Custom stop condition
-	RunStopCancelled    RunStopReason = "cancelled"      // Cancelled by user
-	RunStopError        RunStopReason = "error"          // Error occurred
+	RunStopEndTurn        RunStopReason = "end_turn"        // Model finished naturally
+	RunStopMaxToolCalls   RunStopReason = "max_tool_calls"  // Hit tool call limit
+	RunStopMaxTurns       RunStopReason = "max_turns"       // Hit turn limit
+	RunStopMaxTokens      RunStopReason = "max_tokens"      // Hit token limit
+	RunStopBudgetExceeded RunStopReason = "budget_exceeded" // Hit cost limit or shared budget
+	RunStopTimeout        RunStopReason = "timeout"         // Hit timeout
+	RunStopCustom         RunStopReason = "custom"          // Custom stop condition
+	RunStopCancelled      RunStopReason = "cancelled"       // Cancelled by user
+	RunStopError          RunStopReason = "error"           // Error occurred
 )
 
 // RunResult contains the result of a tool execution loop.
@@ -71,6 +77,9 @@ type runConfig struct {
 	maxToolCalls  int
 	maxTurns      int
 	maxTokens     int
+	maxCostUSD    float64
+	budget        *Budget
+	budgetWarning float64
 	timeout       time.Duration
 	stopWhen      func(*Response) bool
 	toolHandlers  map[string]ToolHandler
@@ -83,6 +92,7 @@ type runConfig struct {
 
 	// Live mode configuration
 	voiceOutput     *LiveVoiceOutput
+	textNormalizer  func() TextNormalizer
 	interruptConfig *LiveInterrupt
 	liveConfig      *LiveConfig // Non-nil enables live mode
 }
@@ -93,6 +103,7 @@ func defaultRunConfig() runConfig {
 		toolHandlers:  make(map[string]ToolHandler),
 		parallelTools: true,
 		toolTimeout:   30 * time.Second,
+		budgetWarning: 0.8,
 	}
 }
 
@@ -215,6 +226,14 @@ type LiveVoiceOutput struct {
 	Speed      float64 `json:"speed,omitempty"`
 	Format     string  `json:"format,omitempty"`
 	SampleRate int     `json:"sample_rate,omitempty"`
+
+	// Chunking controls how response text is split up for TTS, in place
+	// of the request's voice.output.chunking.
+	Chunking *TextChunking `json:"chunking,omitempty"`
+
+	// Fillers are spoken while the agent waits on a slow model or tool, in
+	// place of the request's voice.output.fillers.
+	Fillers *FillerConfig `json:"fillers,omitempty"`
 }
 
 // LiveInterrupt configures barge-in detection for live mode.
@@ -259,6 +278,26 @@ func WithVoiceOutput(cfg LiveVoiceOutput) RunOption {
 	}
 }
 
+// TextNormalizer rewrites streamed text for speech before it reaches TTS.
+type TextNormalizer = normalize.Normalizer
+
+// WithTextNormalizer replaces the normalizer that rewrites responses for
+// speech, which by default follows req.Voice.Output.Normalization. fn is
+// called once per response.
+//
+// Example:
+//
+//	stream, err := client.Messages.RunStream(ctx, req,
+//	    vai.WithTextNormalizer(func() vai.TextNormalizer {
+//	        return normalize.New(normalize.Config{Locale: "de"})
+//	    }),
+//	)
+func WithTextNormalizer(fn func() TextNormalizer) RunOption {
+	return func(c *runConfig) {
+		c.textNormalizer = fn
+	}
+}
+
 // WithInterruptConfig configures interrupt (barge-in) detection.
 //
 // Example:
@@ -303,6 +342,40 @@ func WithLive(cfg *LiveConfig) RunOption {
 	}
 }
 
+// chunkConfig returns how to chunk text for TTS: as set by WithVoiceOutput,
+// else by the request's voice output, else defaultVoiceChunking.
+func (c *runConfig) chunkConfig(voiceCfg *VoiceConfig) chunk.Config {
+	def := defaultVoiceChunking
+	if c.voiceOutput != nil && c.voiceOutput.Chunking != nil {
+		return voice.ChunkConfig(c.voiceOutput.Chunking, def)
+	}
+	if voiceCfg != nil && voiceCfg.Output != nil {
+		return voice.ChunkConfig(voiceCfg.Output.Chunking, def)
+	}
+	return def
+}
+
+// fillerConfig returns the fillers to play while the agent waits: as set by
+// WithVoiceOutput, else by the request's voice output.
+func (c *runConfig) fillerConfig(voiceCfg *VoiceConfig) filler.Config {
+	if c.voiceOutput != nil && c.voiceOutput.Fillers != nil {
+		return voice.FillerConfig(c.voiceOutput.Fillers)
+	}
+	if voiceCfg != nil && voiceCfg.Output != nil {
+		return voice.FillerConfig(voiceCfg.Output.Fillers)
+	}
+	return filler.Config{}
+}
+
+// newNormalizer creates the normalizer for one response, or nil if text is
+// spoken as written.
+func (c *runConfig) newNormalizer(voiceCfg *VoiceConfig) TextNormalizer {
+	if c.textNormalizer != nil {
+		return c.textNormalizer()
+	}
+	return voice.NewNormalizer(voiceCfg)
+}
+
 // --- Run Loop Implementation ---
 
 // runLoop executes the main tool execution loop.
@@ -322,6 +395,9 @@ func (s *MessagesService) runLoop(ctx context.Context, req *MessageRequest, cfg
 		defer cancel()
 	}
 
+	// Usage of the previous turn, used to estimate the cost of the next one
+	var lastUsage types.Usage
+
 	// Main loop
 	for {
 		// Check timeout
@@ -353,6 +429,15 @@ func (s *MessagesService) runLoop(ctx context.Context, req *MessageRequest, cfg
 			return result, nil
 		}
 
+		// Check cost limit and shared budget
+		if cfg.overBudget(result, lastUsage) {
+			result.StopReason = RunStopBudgetExceeded
+			if cfg.onStop != nil {
+				cfg.onStop(result)
+			}
+			return result, nil
+		}
+
 		// Build request for this turn
 		turnReq := &types.MessageRequest{
 			Model:         req.Model,
@@ -398,6 +483,10 @@ func (s *MessagesService) runLoop(ctx context.Context, req *MessageRequest, cfg
 		// Aggregate usage
 		result.Usage = result.Usage.Add(resp.Usage)
 		result.TurnCount++
+		lastUsage = resp.Usage
+		if cfg.budget != nil {
+			cfg.budget.Add(resp.Usage)
+		}
 
 		// Create step record
 		step := RunStep{
@@ -821,29 +910,34 @@ type voiceStreamer struct {
 	ttsCtx     *tts.StreamingContext
 	sendEvents func(RunStreamEvent)
 	format     string
+	normalizer TextNormalizer // Rewrites text for speech, if set
+
+	// Fillers while the agent waits, if configured
+	fillers        *filler.Player
+	bytesPerSecond int // Data rate of PCM output, or 0 if unknown
 
 	// Text batching
-	buffer     strings.Builder
+	chunker    chunk.Chunker
 	bufferMu   sync.Mutex
 	flushTimer *time.Timer
 	done       chan struct{}
 	wg         sync.WaitGroup
 
 	// Config
-	maxChars     int           // Send after this many chars
-	maxDelay     time.Duration // Max time before sending buffered text
-	sentenceEnds string        // Characters that trigger immediate send
+	maxDelay time.Duration // Max time before sending buffered text
 }
 
-func newVoiceStreamer(ttsCtx *tts.StreamingContext, format string, sendEvents func(RunStreamEvent)) *voiceStreamer {
+// defaultVoiceChunking sends sentences, or about 80 characters at a time.
+var defaultVoiceChunking = chunk.Config{Strategy: chunk.Sentence, Words: 15}
+
+func newVoiceStreamer(ttsCtx *tts.StreamingContext, format string, chunker chunk.Chunker, sendEvents func(RunStreamEvent)) *voiceStreamer {
 	vs := &voiceStreamer{
-		ttsCtx:       ttsCtx,
-		sendEvents:   sendEvents,
-		format:       format,
-		done:         make(chan struct{}),
-		maxChars:     80,                     // Send every ~80 chars
-		maxDelay:     150 * time.Millisecond, // Or after 150ms
-		sentenceEnds: ".!?",
+		ttsCtx:     ttsCtx,
+		sendEvents: sendEvents,
+		format:     format,
+		chunker:    chunker,
+		done:       make(chan struct{}),
+		maxDelay:   150 * time.Millisecond, // Send buffered text after 150ms without more
 	}
 
 	// Start audio forwarding goroutine
@@ -853,47 +947,60 @@ func newVoiceStreamer(ttsCtx *tts.StreamingContext, format string, sendEvents fu
 	return vs
 }
 
+// checkFillers checks that fillers can play in a stream's voice output.
+// Filler audio is spliced into the response audio, paced and cut off when
+// the response starts, so the output must be PCM at a known sample rate.
+func checkFillers(cfg filler.Config, out *VoiceOutputConfig) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if out.Format != types.VoiceFormatPCM || out.SampleRate <= 0 {
+		return fmt.Errorf("fillers require pcm output with a sample_rate")
+	}
+	return nil
+}
+
+// startFillers plays fillers while the agent waits. The output was checked
+// by checkFillers.
+func (vs *voiceStreamer) startFillers(ctx context.Context, cfg filler.Config, synth filler.Synthesizer, out *VoiceOutputConfig) {
+	vs.bytesPerSecond = out.SampleRate * 2
+	vs.fillers = filler.New(ctx, cfg, synth, filler.Output{
+		BytesPerSecond: vs.bytesPerSecond,
+		Started: func(f filler.Filler) {
+			vs.sendEvents(LiveFillerEvent{Kind: string(f.Kind), Text: f.Text, Tool: f.Tool})
+		},
+		Audio: func(data []byte) {
+			vs.sendEvents(AudioChunkEvent{Data: data, Format: vs.format})
+		},
+	})
+	go vs.fillers.Prepare()
+}
+
 // AddText adds text to the buffer and may trigger a send.
 func (vs *voiceStreamer) AddText(text string) {
+	// The response is coming; any filler playing ends with its audio
+	vs.fillers.Done()
+
 	vs.bufferMu.Lock()
 	defer vs.bufferMu.Unlock()
 
-	vs.buffer.WriteString(text)
-	content := vs.buffer.String()
-
-	// Check if we should send now
-	shouldSend := false
-
-	// Send if buffer is large enough
-	if len(content) >= vs.maxChars {
-		shouldSend = true
+	if vs.normalizer != nil {
+		text = vs.normalizer.Write(text)
 	}
+	vs.sendLocked(vs.chunker.Add(text))
 
-	// Send on sentence boundaries
-	if len(text) > 0 && strings.ContainsAny(text, vs.sentenceEnds) {
-		shouldSend = true
-	}
-
-	if shouldSend {
-		vs.sendBufferLocked()
-	} else {
-		// Reset/start the flush timer
-		vs.resetTimerLocked()
-	}
+	// Reset/start the flush timer for whatever is still buffered
+	vs.resetTimerLocked()
 }
 
-func (vs *voiceStreamer) sendBufferLocked() {
-	content := strings.TrimSpace(vs.buffer.String())
+func (vs *voiceStreamer) sendLocked(content string) {
 	if content == "" {
 		return
 	}
 
-	vs.buffer.Reset()
-	if vs.flushTimer != nil {
-		vs.flushTimer.Stop()
-		vs.flushTimer = nil
-	}
-
 	// Send to TTS (continue=true, more text coming)
 	vs.ttsCtx.SendText(content, false)
 }
@@ -905,15 +1012,17 @@ func (vs *voiceStreamer) resetTimerLocked() {
 	vs.flushTimer = time.AfterFunc(vs.maxDelay, func() {
 		vs.bufferMu.Lock()
 		defer vs.bufferMu.Unlock()
-		vs.sendBufferLocked()
+		vs.sendLocked(vs.chunker.Flush())
 	})
 }
 
 // Flush sends any remaining text and signals completion.
 func (vs *voiceStreamer) Flush() {
 	vs.bufferMu.Lock()
-	content := strings.TrimSpace(vs.buffer.String())
-	vs.buffer.Reset()
+	if vs.normalizer != nil {
+		vs.sendLocked(vs.chunker.Add(vs.normalizer.Flush()))
+	}
+	content := vs.chunker.Flush()
 	if vs.flushTimer != nil {
 		vs.flushTimer.Stop()
 		vs.flushTimer = nil
@@ -931,6 +1040,8 @@ func (vs *voiceStreamer) Flush() {
 
 // Close waits for all audio to be forwarded, then cleans up.
 func (vs *voiceStreamer) Close() {
+	vs.fillers.Stop()
+
 	// Wait for audio channel to be closed (all audio received)
 	// Don't close vs.done yet - let forwardAudio drain naturally
 	vs.wg.Wait()
@@ -947,6 +1058,16 @@ func (vs *voiceStreamer) forwardAudio() {
 	// Simply drain the audio channel until it's closed
 	// The channel is closed when TTS context receives "done" from Cartesia
 	for chunk := range vs.ttsCtx.Audio() {
+		// Stop any filler, discarding what the client has buffered of it
+		// when it is known to follow the response audio already sent
+		var d time.Duration
+		if vs.bytesPerSecond > 0 {
+			d = time.Duration(len(chunk)) * time.Second / time.Duration(vs.bytesPerSecond)
+		}
+		if vs.fillers.Speak(d) && vs.bytesPerSecond > 0 {
+			vs.sendEvents(LiveAudioFlushEvent{})
+		}
+
 		vs.sendEvents(AudioChunkEvent{
 			Data:   chunk,
 			Format: vs.format,
@@ -998,16 +1119,41 @@ func (rs *RunStream) run(ctx context.Context, svc *MessagesService, req *Message
 	// Set up voice streaming if configured
 	var voiceStream *voiceStreamer
 	if req.Voice != nil && req.Voice.Output != nil && svc.client.voicePipeline != nil {
+		if _, err := svc.client.voicePipeline.TTSProvider(); err != nil {
+			rs.err = fmt.Errorf("voice output: %w", err)
+			return
+		}
+		fillers := cfg.fillerConfig(req.Voice)
+		if err := checkFillers(fillers, req.Voice.Output); err != nil {
+			rs.err = fmt.Errorf("voice output: %w", err)
+			return
+		}
 		format := req.Voice.Output.Format
 		if format == "" {
 			format = "wav"
 		}
 
+		chunker, err := chunk.New(cfg.chunkConfig(req.Voice))
+		if err != nil {
+			rs.err = fmt.Errorf("voice output: chunking: %w", err)
+			return
+		}
 		ttsCtx, err := svc.client.voicePipeline.NewStreamingTTSContext(ctx, req.Voice)
 		if err == nil {
-			voiceStream = newVoiceStreamer(ttsCtx, format, rs.send)
+			voiceStream = newVoiceStreamer(ttsCtx, format, chunker, rs.send)
+			voiceStream.normalizer = cfg.newNormalizer(req.Voice)
+			if fillers.Enabled() {
+				pipeline := svc.client.voicePipeline
+				voiceStream.startFillers(ctx, fillers, func(ctx context.Context, text string) ([]byte, error) {
+					synth, err := pipeline.Synthesize(ctx, text, req.Voice)
+					if err != nil || synth == nil {
+						return nil, err
+					}
+					return synth.Audio, nil
+				}, req.Voice.Output)
+			}
 		}
-		// If TTS setup fails, continue without voice
+		// If the TTS connection fails, continue without voice
 	}
 
 	// Helper to flush voice and wait for all audio before completing
@@ -1023,6 +1169,10 @@ func (rs *RunStream) run(ctx context.Context, svc *MessagesService, req *Message
 
 	stepIndex := 0
 
+	// Usage of the previous turn, used to estimate the cost of the next one
+	var lastUsage types.Usage
+	budgetWarned := false
+
 	// Track tool blocks as they're being built (persists across turns for interrupt recovery)
 	type pendingTool struct {
 		id        string
@@ -1058,9 +1208,22 @@ func (rs *RunStream) run(ctx context.Context, svc *MessagesService, req *Message
 			return
 		}
 
+		if cfg.overBudget(result, lastUsage) {
+			result.StopReason = RunStopBudgetExceeded
+			rs.result = result
+			finishVoice()
+			rs.send(RunCompleteEvent{Result: result})
+			return
+		}
+
 		// Signal step start
 		rs.send(StepStartEvent{Index: stepIndex})
 
+		// Fill the silence if the model is slow
+		if voiceStream != nil {
+			voiceStream.fillers.Wait()
+		}
+
 		// Build request using rs.messages (can be modified by interrupt)
 		rs.mu.RLock()
 		turnReq := &types.MessageRequest{
@@ -1285,6 +1448,16 @@ func (rs *RunStream) run(ctx context.Context, svc *MessagesService, req *Message
 
 		result.Usage = result.Usage.Add(resp.Usage)
 		result.TurnCount++
+		lastUsage = resp.Usage
+		if cfg.budget != nil {
+			cfg.budget.Add(resp.Usage)
+		}
+		if !budgetWarned && cfg.budgetWarning > 0 {
+			if fraction := cfg.budgetFraction(result); fraction >= cfg.budgetWarning {
+				budgetWarned = true
+				rs.send(BudgetWarningEvent{Fraction: fraction, CostUSD: usageCost(result.Usage), Budget: cfg.budget})
+			}
+		}
 
 		step := RunStep{
 			Index:      stepIndex,
@@ -1341,6 +1514,15 @@ func (rs *RunStream) run(ctx context.Context, svc *MessagesService, req *Message
 			return
 		}
 
+		// Fill the silence if the tools are slow
+		if voiceStream != nil {
+			tools := make([]string, len(toolUses))
+			for i, tu := range toolUses {
+				tools[i] = tu.Name
+			}
+			voiceStream.fillers.Wait(tools...)
+		}
+
 		// Execute tools with events
 		toolResults := make([]ToolExecutionResult, len(toolUses))
 		for i, tu := range toolUses {
@@ -1495,6 +1677,21 @@ func (rs *RunStream) ForceInterrupt(transcript string) error {
 	return rs.liveSession.Interrupt(transcript)
 }
 
+// AckPlayback reports how much of the current response's audio has been
+// played, in milliseconds from the start of the response. If the user
+// interrupts, only the words played by then are kept in the history.
+// Returns error if not in live mode.
+func (rs *RunStream) AckPlayback(positionMs int) error {
+	if !rs.isLive {
+		return fmt.Errorf("AckPlayback: not in live mode (use WithLive option)")
+	}
+	if rs.liveSession == nil {
+		return fmt.Errorf("AckPlayback: live session not initialized")
+	}
+	rs.liveSession.AckPlayback(positionMs)
+	return nil
+}
+
 // AudioOutput returns the audio output manager for playing TTS audio.
 // Returns nil if not in live mode.
 // Provides buffered audio chunks and flush signals for smooth playback.
@@ -1580,14 +1777,14 @@ func (rs *RunStream) runLive(ctx context.Context, svc *MessagesService, req *Mes
 	// Get STT provider
 	sttProvider := svc.client.getSTTProvider()
 	if sttProvider == nil {
-		rs.send(LiveErrorEvent{Code: "stt_error", Message: "STT provider not available - ensure CARTESIA_API_KEY is set"})
+		rs.send(LiveErrorEvent{Code: "stt_error", Message: "STT provider not available - set CARTESIA_API_KEY, DEEPGRAM_API_KEY, OPENAI_API_KEY or WHISPER_CPP_URL"})
 		return
 	}
 
 	// Get TTS provider
 	ttsProvider := svc.client.getTTSProvider()
 	if ttsProvider == nil {
-		rs.send(LiveErrorEvent{Code: "tts_error", Message: "TTS provider not available - ensure CARTESIA_API_KEY is set"})
+		rs.send(LiveErrorEvent{Code: "tts_error", Message: "TTS provider not available - set CARTESIA_API_KEY, ELEVENLABS_API_KEY, OPENAI_API_KEY or PIPER_URL"})
 		return
 	}
 
@@ -1628,14 +1825,25 @@ func (rs *RunStream) runLive(ctx context.Context, svc *MessagesService, req *Mes
 // buildLiveConfig converts MessageRequest and LiveConfig to core live.SessionConfig.
 func (rs *RunStream) buildLiveConfig(req *MessageRequest, cfg *runConfig) live.SessionConfig {
 	liveConfig := live.SessionConfig{
-		Model:      req.Model,
-		System:     systemToString(req.System),
-		Tools:      req.Tools,
-		Messages:   req.Messages,
-		Voice:      req.Voice,
-		SampleRate: cfg.liveConfig.SampleRate,
-		Channels:   cfg.liveConfig.Channels,
-		MaxTokens:  req.MaxTokens,
+		Model:         req.Model,
+		System:        systemToString(req.System),
+		Tools:         req.Tools,
+		ToolHandlers:  liveToolHandlers(cfg.toolHandlers, cfg.liveConfig.ToolHandlers),
+		MaxToolRounds: cfg.liveConfig.MaxToolRounds,
+		Messages:      req.Messages,
+		Voice:         req.Voice,
+		SampleRate:    cfg.liveConfig.SampleRate,
+		Channels:      cfg.liveConfig.Channels,
+		InputFormat:   cfg.liveConfig.InputFormat,
+		MaxTokens:     req.MaxTokens,
+		Normalizer:    cfg.liveConfig.Normalizer,
+	}
+	if cfg.voiceOutput != nil {
+		liveConfig.Chunking = cfg.voiceOutput.Chunking
+		liveConfig.Fillers = cfg.voiceOutput.Fillers
+	}
+	if liveConfig.Normalizer == nil {
+		liveConfig.Normalizer = cfg.textNormalizer
 	}
 
 	if req.Temperature != nil {
@@ -1659,6 +1867,8 @@ func (rs *RunStream) buildLiveConfig(req *MessageRequest, cfg *runConfig) live.S
 			SemanticCheck:       cfg.liveConfig.VAD.SemanticCheck,
 			MinWordsForCheck:    cfg.liveConfig.VAD.MinWordsForCheck,
 			EnergyThreshold:     cfg.liveConfig.VAD.EnergyThreshold,
+			TurnCompletePrompts: cfg.liveConfig.VAD.TurnCompletePrompts,
+			Acoustic:            cfg.liveConfig.VAD.Acoustic.coreConfig(),
 		}
 	} else {
 		liveConfig.VAD = live.DefaultVADConfig()
@@ -1693,12 +1903,15 @@ func (rs *RunStream) buildLiveConfig(req *MessageRequest, cfg *runConfig) live.S
 		}
 
 		liveConfig.Interrupt = live.InterruptConfig{
-			Mode:              mode,
-			EnergyThreshold:   cfg.liveConfig.Interrupt.EnergyThreshold,
-			CaptureDurationMs: cfg.liveConfig.Interrupt.CaptureDurationMs,
-			SemanticCheck:     cfg.liveConfig.Interrupt.SemanticCheck,
-			SemanticModel:     cfg.liveConfig.Interrupt.SemanticModel,
-			SavePartial:       savePartial,
+			Mode:                  mode,
+			EnergyThreshold:       cfg.liveConfig.Interrupt.EnergyThreshold,
+			CaptureDurationMs:     cfg.liveConfig.Interrupt.CaptureDurationMs,
+			SemanticCheck:         cfg.liveConfig.Interrupt.SemanticCheck,
+			SemanticModel:         cfg.liveConfig.Interrupt.SemanticModel,
+			SavePartial:           savePartial,
+			Backchannels:          cfg.liveConfig.Interrupt.Backchannels,
+			InterruptCheckPrompts: cfg.liveConfig.Interrupt.InterruptCheckPrompts,
+			EchoSuppression:       cfg.liveConfig.Interrupt.EchoSuppression.coreConfig(),
 		}
 	} else {
 		liveConfig.Interrupt = live.DefaultInterruptConfig()
@@ -1767,6 +1980,23 @@ func (rs *RunStream) convertLiveEvent(event live.Event) RunStreamEvent {
 		}}
 	case *live.MessageStopEvent:
 		return StepCompleteEvent{Index: 0, Response: nil}
+	case *live.ToolUseEvent:
+		input, _ := e.Input.(map[string]any)
+		return ToolCallStartEvent{
+			ID:    e.ID,
+			Name:  e.Name,
+			Input: input,
+		}
+	case *live.ToolResultEvent:
+		result := ToolResultEvent{
+			ID:      e.ID,
+			Name:    e.Name,
+			Content: []types.ContentBlock{types.TextBlock{Type: "text", Text: e.Content}},
+		}
+		if e.IsError {
+			result.Error = errors.New(e.Content)
+		}
+		return result
 	case *live.AudioDeltaEvent:
 		// Push to AudioOutput for buffered playback
 		if rs.audioOutput != nil {
@@ -1784,6 +2014,12 @@ func (rs *RunStream) convertLiveEvent(event live.Event) RunStreamEvent {
 			rs.audioOutput.doFlush()
 		}
 		return LiveAudioFlushEvent{}
+	case *live.FillerStartedEvent:
+		return LiveFillerEvent{
+			Kind: e.Kind,
+			Text: e.Text,
+			Tool: e.Tool,
+		}
 	case *live.GracePeriodStartedEvent:
 		return LiveGracePeriodStartedEvent{
 			Transcript: e.Transcript,
@@ -1814,6 +2050,18 @@ func (rs *RunStream) convertLiveEvent(event live.Event) RunStreamEvent {
 			InterruptTranscript: e.InterruptTranscript,
 			AudioPositionMs:     e.AudioPositionMs,
 		}
+	case *live.TurnLatencyEvent:
+		return LiveTurnLatencyEvent{
+			SpeechEndedAt:        e.SpeechEndedAt,
+			CommittedAt:          e.CommittedAt,
+			EndpointingMs:        e.EndpointingMs,
+			SemanticCheckMs:      e.SemanticCheckMs,
+			GracePeriodMs:        e.GracePeriodMs,
+			InterruptDetectionMs: e.InterruptDetectionMs,
+			LLMFirstTokenMs:      e.LLMFirstTokenMs,
+			TTSFirstAudioMs:      e.TTSFirstAudioMs,
+			MouthToEarMs:         e.MouthToEarMs,
+		}
 	case *live.ErrorEvent:
 		return LiveErrorEvent{
 			Code:    e.Code,
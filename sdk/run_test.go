@@ -3,10 +3,13 @@ package vai
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/vango-go/vai/pkg/core/catalog"
 	"github.com/vango-go/vai/pkg/core/types"
+	"github.com/vango-go/vai/pkg/core/voice/filler"
 )
 
 func TestDefaultRunConfig(t *testing.T) {
@@ -250,6 +253,7 @@ func TestRunStopReason_Constants(t *testing.T) {
 		RunStopMaxToolCalls,
 		RunStopMaxTurns,
 		RunStopMaxTokens,
+		RunStopBudgetExceeded,
 		RunStopTimeout,
 		RunStopCustom,
 		RunStopError,
@@ -262,6 +266,22 @@ func TestRunStopReason_Constants(t *testing.T) {
 	}
 }
 
+func TestRun_UsageCost(t *testing.T) {
+	p := &scriptedProvider{name: "scripted", usage: types.Usage{InputTokens: 1000, OutputTokens: 100, TotalTokens: 1100}}
+	client := newRetryTestClient(p, WithModelCatalog(catalog.New(catalog.Model{
+		ID:      "scripted/model",
+		Pricing: &catalog.ModelPricing{InputPerMillion: 2, OutputPerMillion: 10},
+	})))
+
+	result, err := client.Messages.Run(context.Background(), testRequest("scripted/model"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Usage.CostUSD == nil || math.Abs(*result.Usage.CostUSD-0.003) > 1e-12 {
+		t.Errorf("Usage.CostUSD = %v, want 0.003", result.Usage.CostUSD)
+	}
+}
+
 func TestRunResult_Structure(t *testing.T) {
 	result := RunResult{
 		Steps:         make([]RunStep, 0),
@@ -676,3 +696,27 @@ func TestInterruptConfigAlias(t *testing.T) {
 		t.Errorf("Mode = %q, want %q", ic.Mode, "semantic")
 	}
 }
+
+// TestCheckFillers verifies that stream fillers need valid config and PCM output.
+func TestCheckFillers(t *testing.T) {
+	phrases := filler.Config{Phrases: []string{"One moment."}}
+	pcm := &VoiceOutputConfig{Format: types.VoiceFormatPCM, SampleRate: 24000}
+
+	tests := []struct {
+		name    string
+		cfg     filler.Config
+		out     *VoiceOutputConfig
+		wantErr bool
+	}{
+		{"no fillers", filler.Config{}, &VoiceOutputConfig{Format: "mp3"}, false},
+		{"pcm", phrases, pcm, false},
+		{"mp3", phrases, &VoiceOutputConfig{Format: "mp3", SampleRate: 24000}, true},
+		{"pcm without sample rate", phrases, &VoiceOutputConfig{Format: types.VoiceFormatPCM}, true},
+		{"negative delay", filler.Config{Phrases: phrases.Phrases, Delay: -time.Second}, pcm, true},
+	}
+	for _, tt := range tests {
+		if err := checkFillers(tt.cfg, tt.out); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkFillers() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
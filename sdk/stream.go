@@ -1,6 +1,9 @@
 package vai
 
 import (
+	"encoding/json"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/vango-go/vai/pkg/core"
@@ -15,6 +18,10 @@ type Stream struct {
 	err         error
 	closed      atomic.Bool
 	done        chan struct{}
+
+	// Tool input JSON streamed so far, by content block index
+	mu         sync.Mutex
+	toolInputs map[int]*strings.Builder
 }
 
 // newStreamFromEventStream creates a Stream from a core.EventStream.
@@ -64,9 +71,16 @@ func (s *Stream) readEvents(events chan<- types.StreamEvent) {
 			currentContent[e.Index] = e.ContentBlock
 		case types.ContentBlockDeltaEvent:
 			// Apply delta to current content block
-			if e.Index < len(currentContent) {
+			if d, ok := e.Delta.(types.InputJSONDelta); ok {
+				s.appendToolInput(e.Index, d.PartialJSON)
+			} else if e.Index < len(currentContent) {
 				currentContent[e.Index] = applyDelta(currentContent[e.Index], e.Delta)
 			}
+		case types.ContentBlockStopEvent:
+			// Tool input is complete once its block stops
+			if e.Index < len(currentContent) {
+				currentContent[e.Index] = s.finishToolInput(e.Index, currentContent[e.Index])
+			}
 		case types.MessageDeltaEvent:
 			response.StopReason = e.Delta.StopReason
 			response.Usage = e.Usage
@@ -96,14 +110,6 @@ func applyDelta(block types.ContentBlock, delta types.Delta) types.ContentBlock
 			tb.Text += d.Text
 			return tb
 		}
-	case types.InputJSONDelta:
-		// For tool use blocks, accumulate the partial JSON
-		if tu, ok := block.(types.ToolUseBlock); ok {
-			// Store partial JSON in a way that can be parsed later
-			// For now, just accumulate in a string field
-			_ = tu
-			_ = d.PartialJSON
-		}
 	case types.ThinkingDelta:
 		if tb, ok := block.(types.ThinkingBlock); ok {
 			tb.Thinking += d.Thinking
@@ -113,6 +119,160 @@ func applyDelta(block types.ContentBlock, delta types.Delta) types.ContentBlock
 	return block
 }
 
+// appendToolInput records a fragment of tool input JSON for a content block.
+func (s *Stream) appendToolInput(index int, partial string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.toolInputs == nil {
+		s.toolInputs = make(map[int]*strings.Builder)
+	}
+	b, ok := s.toolInputs[index]
+	if !ok {
+		b = &strings.Builder{}
+		s.toolInputs[index] = b
+	}
+	b.WriteString(partial)
+}
+
+// finishToolInput parses the accumulated input JSON into a tool use block.
+// Blocks without streamed input are returned unchanged.
+func (s *Stream) finishToolInput(index int, block types.ContentBlock) types.ContentBlock {
+	s.mu.Lock()
+	b, ok := s.toolInputs[index]
+	var raw string
+	if ok {
+		raw = b.String()
+	}
+	s.mu.Unlock()
+	if strings.TrimSpace(raw) == "" {
+		return block
+	}
+
+	var input map[string]any
+	if err := json.Unmarshal([]byte(raw), &input); err != nil {
+		return block
+	}
+	switch tb := block.(type) {
+	case types.ToolUseBlock:
+		tb.Input = input
+		return tb
+	case types.ServerToolUseBlock:
+		tb.Input = input
+		return tb
+	}
+	return block
+}
+
+// PartialToolInput returns the input of the tool use block at index as
+// streamed so far, parsed as far as it forms valid JSON. Call it after
+// receiving an input_json_delta event to show a tool call as it is being
+// formed; the result may already include the next fragment. It returns false
+// if no input has been streamed for index.
+func (s *Stream) PartialToolInput(index int) (map[string]any, bool) {
+	s.mu.Lock()
+	b, ok := s.toolInputs[index]
+	var raw string
+	if ok {
+		raw = b.String()
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return ParsePartialJSON(raw)
+}
+
+// ParsePartialJSON parses a JSON object that may be cut off mid-stream.
+// Open strings, arrays and objects are closed, and a trailing key or value
+// that cannot be completed is dropped. It returns false if no object prefix
+// can be recovered.
+func ParsePartialJSON(partial string) (map[string]any, bool) {
+	// Candidate cut points, from longest to shortest prefix
+	cuts := []int{len(partial)}
+	inString, escaped := false, false
+	var boundaries []int
+	for i := 0; i < len(partial); i++ {
+		c := partial[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			boundaries = append(boundaries, i)
+		case '{', '[':
+			boundaries = append(boundaries, i+1)
+		}
+	}
+	for i := len(boundaries) - 1; i >= 0; i-- {
+		cuts = append(cuts, boundaries[i])
+	}
+
+	for _, cut := range cuts {
+		var out map[string]any
+		if err := json.Unmarshal([]byte(closeJSON(partial[:cut])), &out); err == nil && out != nil {
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+// closeJSON appends the quotes and brackets needed to close a JSON prefix.
+func closeJSON(prefix string) string {
+	var closers []byte
+	inString, escaped := false, false
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			if len(closers) > 0 {
+				closers = closers[:len(closers)-1]
+			}
+		}
+	}
+
+	if escaped {
+		// Drop a dangling escape so the closing quote is not escaped
+		prefix = prefix[:len(prefix)-1]
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	if inString {
+		b.WriteByte('"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		b.WriteByte(closers[i])
+	}
+	return b.String()
+}
+
 // Events returns the channel of stream events.
 func (s *Stream) Events() <-chan types.StreamEvent {
 	return s.events
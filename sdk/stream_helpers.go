@@ -23,6 +23,7 @@ type StreamCallbacks struct {
 	OnStepStart    func(index int)                    // New step beginning
 	OnStepComplete func(index int, response *Response) // Step finished
 	OnInterrupted  func(partialText string, behavior InterruptBehavior) // Stream was interrupted
+	OnBudgetWarning func(event BudgetWarningEvent) // Spend crossed the budget warning threshold
 
 	// Errors
 	OnError func(err error)
@@ -112,6 +113,11 @@ func (rs *RunStream) Process(callbacks StreamCallbacks) (string, error) {
 				callbacks.OnInterrupted(e.PartialText, e.Behavior)
 			}
 
+		case BudgetWarningEvent:
+			if callbacks.OnBudgetWarning != nil {
+				callbacks.OnBudgetWarning(e)
+			}
+
 		case RunCompleteEvent:
 			// Run finished, loop will exit
 
@@ -0,0 +1,109 @@
+package vai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vango-go/vai/pkg/core/types"
+)
+
+func toolInputEvents(fragments ...string) []types.StreamEvent {
+	events := []types.StreamEvent{
+		types.MessageStartEvent{Type: "message_start", Message: types.MessageResponse{ID: "msg_1", Role: "assistant"}},
+		types.ContentBlockStartEvent{Type: "content_block_start", Index: 0, ContentBlock: types.TextBlock{Type: "text"}},
+		types.ContentBlockDeltaEvent{Type: "content_block_delta", Index: 0, Delta: types.TextDelta{Type: "text_delta", Text: "Checking."}},
+		types.ContentBlockStopEvent{Type: "content_block_stop", Index: 0},
+		types.ContentBlockStartEvent{Type: "content_block_start", Index: 1, ContentBlock: types.ToolUseBlock{
+			Type: "tool_use", ID: "call_1", Name: "get_weather", Input: map[string]any{},
+		}},
+	}
+	for _, f := range fragments {
+		events = append(events, types.ContentBlockDeltaEvent{
+			Type: "content_block_delta", Index: 1, Delta: types.InputJSONDelta{Type: "input_json_delta", PartialJSON: f},
+		})
+	}
+	return append(events,
+		types.ContentBlockStopEvent{Type: "content_block_stop", Index: 1},
+		types.MessageStopEvent{Type: "message_stop"},
+	)
+}
+
+func TestStream_AccumulatesToolInput(t *testing.T) {
+	stream := newStreamFromEventStream(&mockEventStream{
+		events: toolInputEvents(`{"loc`, `ation": "Par`, `is", "days": [1`, `, 2]}`),
+	})
+	defer stream.Close()
+
+	var partials []map[string]any
+	for event := range stream.Events() {
+		if delta, ok := event.(types.ContentBlockDeltaEvent); ok {
+			if _, ok := delta.Delta.(types.InputJSONDelta); ok {
+				input, ok := stream.PartialToolInput(delta.Index)
+				if !ok {
+					t.Fatal("PartialToolInput() found no input after an input_json_delta")
+				}
+				partials = append(partials, input)
+			}
+		}
+	}
+
+	want := map[string]any{"location": "Paris", "days": []any{float64(1), float64(2)}}
+	if len(partials) != 4 {
+		t.Fatalf("got %d partial inputs, want 4", len(partials))
+	}
+	if !reflect.DeepEqual(partials[3], want) {
+		t.Errorf("last partial input = %v, want %v", partials[3], want)
+	}
+
+	resp := stream.Response()
+	tu, ok := resp.Content[1].(types.ToolUseBlock)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want ToolUseBlock", resp.Content[1])
+	}
+	if !reflect.DeepEqual(tu.Input, want) {
+		t.Errorf("Input = %v, want %v", tu.Input, want)
+	}
+	if text := resp.TextContent(); text != "Checking." {
+		t.Errorf("TextContent() = %q", text)
+	}
+}
+
+func TestStream_ToolInputWithoutDeltas(t *testing.T) {
+	stream := newStreamFromEventStream(&mockEventStream{events: toolInputEvents()})
+	defer stream.Close()
+	for range stream.Events() {
+	}
+
+	tu := stream.Response().Content[1].(types.ToolUseBlock)
+	if tu.Input == nil || len(tu.Input) != 0 {
+		t.Errorf("Input = %v, want the empty input from content_block_start", tu.Input)
+	}
+	if _, ok := stream.PartialToolInput(1); ok {
+		t.Error("PartialToolInput() reported input for a block without deltas")
+	}
+}
+
+func TestParsePartialJSON(t *testing.T) {
+	tests := []struct {
+		partial string
+		want    map[string]any
+		ok      bool
+	}{
+		{``, nil, false},
+		{`{`, map[string]any{}, true},
+		{`{"a": "hel`, map[string]any{"a": "hel"}, true},
+		{`{"a": "x\`, map[string]any{"a": "x"}, true},
+		{`{"a": 1, "b`, map[string]any{"a": float64(1)}, true},
+		{`{"a": 1, "b":`, map[string]any{"a": float64(1)}, true},
+		{`{"a": tr`, map[string]any{}, true},
+		{`{"a": {"b": [true, {"c": "d`, map[string]any{"a": map[string]any{"b": []any{true, map[string]any{"c": "d"}}}}, true},
+		{`{"a": "}{,"`, map[string]any{"a": "}{,"}, true},
+		{`[1, 2`, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePartialJSON(tt.partial)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePartialJSON(%q) = %v, %v; want %v, %v", tt.partial, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -106,6 +106,15 @@ type (
 	// VoiceOutputConfig configures text-to-speech.
 	VoiceOutputConfig = types.VoiceOutputConfig
 
+	// TextNormalization configures how text is rewritten for speech.
+	TextNormalization = types.TextNormalization
+
+	// TextChunking configures how streamed text is split up for TTS.
+	TextChunking = types.TextChunking
+
+	// FillerConfig configures speech played while the agent waits.
+	FillerConfig = types.FillerConfig
+
 	// OutputFormat specifies structured output requirements.
 	OutputFormat = types.OutputFormat
 